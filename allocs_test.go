@@ -0,0 +1,46 @@
+package binpacking
+
+import "testing"
+
+func TestScoringAllocations(t *testing.T) {
+	spaced := NewBin(100, 100, BestAreaFit)
+	if err := spaced.SetSpacing(2); err != nil {
+		t.Fatal(err)
+	}
+	guillotine := NewGuillotineBin(100, 100, BottomLeft, SplitShorterLeftoverAxis)
+	for _, bin := range []*Bin{NewBin(100, 100, nil), spaced, guillotine} {
+		for _, box := range []*Box{NewBox(30, 20, false), NewBox(15, 15, true), NewBox(40, 10, false)} {
+			bin.Insert(box)
+		}
+	}
+	indexed := NewBin(300, 300, nil) // Enough free spaces to be indexed; see freeindex.go
+	for i := 0; len(indexed.FreeSpaces) < minIndexedSpaces; i++ {
+		indexed.Insert(NewBox(float64(5+i%7*3), float64(4+i%5*4), false))
+	}
+	box := NewBox(12, 25, false)
+	options := &TagOptions{ConstrainRotation: true, Placement: BestLongSideFit}
+
+	for _, bin := range []*Bin{NewBin(100, 100, nil), spaced, guillotine, indexed} {
+		if allocs := testing.AllocsPerRun(100, func() { bin.ScoreFor(box) }); allocs != 0 {
+			t.Errorf("ScoreFor: got %v allocations, want 0", allocs)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { bin.ScoreForWith(box, options) }); allocs != 0 {
+			t.Errorf("ScoreForWith: got %v allocations, want 0", allocs)
+		}
+		entry := NewScoreBoardEntry(bin, box)
+		if allocs := testing.AllocsPerRun(100, func() { entry.Calculate() }); allocs != 0 {
+			t.Errorf("Calculate: got %v allocations, want 0", allocs)
+		}
+	}
+
+	t.Run("scores unchanged", func(t *testing.T) {
+		for _, bin := range []*Bin{spaced, guillotine} {
+			for _, o := range []*TagOptions{nil, options} {
+				want := FindBestPlacement(bin.padded(o.candidate(box)), bin.FreeSpaces, o.placement(bin))
+				if got := bin.placementFor(box, o); got != want {
+					t.Errorf("got %+v, want %+v", got, want)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package binpacking
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Anchor constrains which edges of a bin a box must touch, e.g. so that barcoded panels
+// or registration marks end up on an accessible edge of the sheet. The edges are those
+// of the usable area, inside the bin's margins and spacing. Anchors combine with |: a box
+// anchored AnchorLeft|AnchorTop must go into the top-left corner.
+//
+// The MaxRects backend places an anchored box against whichever corner of a free
+// rectangle satisfies the anchor; the Guillotine backend only at the top-left corner, so
+// that its cuts stay straight. The other backends choose positions of their own, which
+// are accepted only if they satisfy the anchor.
+type Anchor uint8
+
+// AnchorNone, the zero value, lets a box go anywhere.
+const AnchorNone Anchor = 0
+
+const (
+	AnchorLeft      Anchor = 1 << iota // Touches the left edge
+	AnchorTop                          // Touches the top edge
+	AnchorRight                        // Touches the right edge
+	AnchorBottom                       // Touches the bottom edge
+	AnchorAnyEdge                      // Touches at least one edge
+	AnchorAnyCorner                    // Touches a vertical and a horizontal edge
+)
+
+// satisfied reports whether a box touching the given edges satisfies the anchor.
+func (a Anchor) satisfied(left, top, right, bottom bool) bool {
+	return (a&AnchorLeft == 0 || left) && (a&AnchorTop == 0 || top) &&
+		(a&AnchorRight == 0 || right) && (a&AnchorBottom == 0 || bottom) &&
+		(a&AnchorAnyEdge == 0 || left || top || right || bottom) &&
+		(a&AnchorAnyCorner == 0 || (left || right) && (top || bottom))
+}
+
+// anchorRule is an Anchor resolved against the usable area of a bin.
+type anchorRule struct {
+	anchor                 Anchor
+	shift                  bool    // Whether boxes may be placed against the far sides of free spaces
+	minX, minY, maxX, maxY float64 // Edges reachable by boxes padded with the bin's spacing
+	tolerance              float64
+}
+
+// anchoring returns the rule placing boxes with the anchor in the bin, or nil if the
+// anchor allows any position. With shift set, positions other than the top-left corner
+// of a free space are considered.
+func (b *Bin) anchoring(anchor Anchor, shift bool) *anchorRule {
+	if anchor == AnchorNone {
+		return nil
+	}
+	// Spacing pads boxes on their right and bottom, and keeps them the spacing away
+	// from the left and top edges.
+	spacing, m := max(b.Spacing, 0), b.Margins
+	return &anchorRule{
+		anchor: anchor, shift: shift,
+		minX: m.Left + spacing, minY: m.Top + spacing, maxX: b.Width - m.Right, maxY: b.Height - m.Bottom,
+		tolerance: b.tolerance(),
+	}
+}
+
+// admits reports whether a box of the given size at (x, y) satisfies the rule.
+func (r *anchorRule) admits(x, y, width, height float64) bool {
+	tol := r.tolerance
+	return r.anchor.satisfied(x <= r.minX+tol, y <= r.minY+tol, x+width >= r.maxX-tol, y+height >= r.maxY-tol)
+}
+
+// position returns the first corner of space, top-left, top-right, bottom-left or
+// bottom-right, at which a box of the given size satisfies the rule, or false if there
+// is none.
+func (r *anchorRule) position(space *FreeSpaceBox, width, height float64) (float64, float64, bool) {
+	xs := [2]float64{space.X, max(space.X, space.X+space.Width-width)}
+	ys := [2]float64{space.Y, max(space.Y, space.Y+space.Height-height)}
+	corners := 1
+	if r.shift {
+		corners = 2
+	}
+	for _, y := range ys[:corners] {
+		for _, x := range xs[:corners] {
+			if r.admits(x, y, width, height) {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// allows reports whether placing box as found by a backend satisfies the rule; a nil
+// rule allows every placement.
+func (r *anchorRule) allows(placement PlacementInfo, box *Box) bool {
+	if r == nil || !placement.Fits {
+		return true
+	}
+	width, height := box.Width, box.Height
+	if placement.NeedsRotation {
+		width, height = height, width
+	}
+	return r.admits(placement.X, placement.Y, width, height)
+}
+
+// anchorNames are the names of the anchors in JobBox.Anchor.
+var anchorNames = map[string]Anchor{
+	"left": AnchorLeft, "top": AnchorTop, "right": AnchorRight, "bottom": AnchorBottom,
+	"edge": AnchorAnyEdge, "corner": AnchorAnyCorner,
+}
+
+// parseAnchor parses a comma-separated list of anchor names, as in JobBox.Anchor. The
+// returned error wraps ErrInvalidJob.
+func parseAnchor(s string) (Anchor, error) {
+	var anchor Anchor
+	if s == "" {
+		return anchor, nil
+	}
+	for _, name := range strings.Split(s, ",") {
+		a, ok := anchorNames[strings.TrimSpace(name)]
+		if !ok {
+			return 0, fmt.Errorf("%w: unknown anchor %q", ErrInvalidJob, name)
+		}
+		anchor |= a
+	}
+	return anchor, nil
+}
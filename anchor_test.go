@@ -0,0 +1,141 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnchor(t *testing.T) {
+	t.Run("MaxRects", func(t *testing.T) {
+		tests := []struct {
+			anchor Anchor
+			wantX  float64
+			wantY  float64
+		}{
+			{AnchorNone, 0, 0},
+			{AnchorRight, 8, 0},
+			{AnchorBottom, 0, 7},
+			{AnchorRight | AnchorBottom, 8, 7},
+			{AnchorTop | AnchorLeft, 0, 0},
+		}
+		for _, tt := range tests {
+			bin := NewBin(10, 10, nil)
+			box := NewBox(2, 3, true)
+			box.Anchor = tt.anchor
+			if !bin.Insert(box) {
+				t.Fatalf("anchor %d: got no fit", tt.anchor)
+			}
+			if box.X != tt.wantX || box.Y != tt.wantY {
+				t.Errorf("anchor %d: got [%g,%g], want [%g,%g]", tt.anchor, box.X, box.Y, tt.wantX, tt.wantY)
+			}
+		}
+	})
+
+	t.Run("margins and spacing", func(t *testing.T) {
+		bin, err := NewBinWith(20, 20, WithMargins(Margins{Left: 1, Top: 1, Right: 2, Bottom: 2}), WithSpacing(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		corner := &Box{Width: 3, Height: 3, ConstrainRotation: true, Anchor: AnchorRight | AnchorBottom}
+		if !bin.Insert(corner) {
+			t.Fatal("got no fit")
+		}
+		if corner.X != 14 || corner.Y != 14 {
+			t.Errorf("got [%g,%g], want [14,14]", corner.X, corner.Y)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("corners and edges", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		for i := range 4 {
+			if !bin.Insert(&Box{Width: 4, Height: 4, Anchor: AnchorAnyCorner}) {
+				t.Fatalf("corner %d: got no fit", i)
+			}
+		}
+		if bin.Insert(&Box{Width: 1, Height: 1, Anchor: AnchorAnyCorner}) {
+			t.Error("got a fifth box placed in a corner")
+		}
+		if !bin.Insert(&Box{Width: 1, Height: 1, Anchor: AnchorAnyEdge}) {
+			t.Error("got no fit on the free edges")
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("other backends", func(t *testing.T) {
+		guillotine := NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis)
+		if guillotine.Insert(&Box{Width: 2, Height: 2, Anchor: AnchorRight}) {
+			t.Error("guillotine: got a box placed away from the top-left corner of a free space")
+		}
+		if !guillotine.Insert(&Box{Width: 10, Height: 2, Anchor: AnchorRight}) {
+			t.Error("guillotine: got no fit for a box spanning the bin")
+		}
+		skyline := NewSkylineBin(10, 10, SkylineOptions{})
+		if skyline.Insert(&Box{Width: 2, Height: 2, Anchor: AnchorBottom}) {
+			t.Error("skyline: got a box placed away from the bottom edge")
+		}
+		if _, ok := skyline.CanFit(&Box{Width: 2, Height: 2, Anchor: AnchorBottom}); ok {
+			t.Error("skyline: got CanFit reporting a fit away from the bottom edge")
+		}
+		if !skyline.Insert(&Box{Width: 2, Height: 2, Anchor: AnchorLeft | AnchorTop}) {
+			t.Error("skyline: got no fit in the top-left corner")
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{
+			Bins:  []JobBin{{Width: 10, Height: 10, Qty: 1}},
+			Boxes: []JobBox{{Width: 2, Height: 2, ID: "mark", Anchor: "right, bottom"}},
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := result.Placements[0]; p.X != 8 || p.Y != 8 {
+			t.Errorf("got [%g,%g], want [8,8]", p.X, p.Y)
+		}
+		job.Boxes[0].Anchor = "middle"
+		if _, err := job.Pack(); !errors.Is(err, ErrInvalidJob) {
+			t.Errorf("got %v, want ErrInvalidJob", err)
+		}
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		// The search improves on the heuristic layout, and must keep the anchored box in
+		// the bottom-right corner while doing so.
+		bin := NewBin(8, 11, nil)
+		box := &Box{Width: 4, Height: 6, ConstrainRotation: true, Anchor: AnchorBottom | AnchorRight}
+		boxes := []*Box{box, NewBox(5, 8, true), NewBox(6, 7, true), NewBox(4, 6, true)}
+		NewExactPacker([]*Bin{bin}, 10*time.Second).Pack(boxes)
+		if !box.Packed || box.X != 4 || box.Y != 5 {
+			t.Errorf("got packed %v at [%g,%g], want [4,5]", box.Packed, box.X, box.Y)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("shelves", func(t *testing.T) {
+		for _, algorithm := range []PackingAlgorithm{AlgorithmShelfNextFit, AlgorithmShelfFirstFit} {
+			// The tall box opens the first shelf; the anchored one fills the shelf below it,
+			// against the bottom edge. The small one has room at the end of both shelves,
+			// neither of which puts it against the bottom edge.
+			bottom := &Box{Width: 10, Height: 4, ConstrainRotation: true, Anchor: AnchorBottom}
+			stray := &Box{Width: 2, Height: 2, ConstrainRotation: true, Anchor: AnchorBottom}
+			bin := NewBin(12, 10, nil)
+			packer := NewPacker([]*Bin{bin})
+			packer.Pack([]*Box{NewBox(10, 6, true), bottom, stray}, PackerOptions{Algorithm: algorithm})
+			if !bottom.Packed || bottom.Y != 6 {
+				t.Errorf("algorithm %d: got packed %v at y %g, want y 6", algorithm, bottom.Packed, bottom.Y)
+			}
+			if stray.Packed {
+				t.Errorf("algorithm %d: got the box placed at [%g,%g], away from the bottom edge", algorithm, stray.X, stray.Y)
+			}
+		}
+	})
+}
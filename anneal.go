@@ -0,0 +1,410 @@
+package binpacking
+
+import (
+	"context"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// ImproveOptions configures Packer.Improve. Zero values select the defaults noted on each field.
+type ImproveOptions struct {
+	Iterations int // Number of moves to try; default 2000
+	// InitialTemperature controls how likely worse solutions are accepted at the start,
+	// in units of area. Default: 5% of the average bin area.
+	InitialTemperature float64
+	Cooling            float64 // Factor applied to the temperature after each move; default 0.995
+	Seed               uint64  // Seed of the random generator, so runs are reproducible
+
+	// KeepApart, Guillotine and TagOptions are the constraints of PackerOptions that
+	// moves must keep, as Pack did; pass the values the packing was made with.
+	KeepApart  []KeepApart
+	Guillotine bool
+	TagOptions map[string]TagOptions
+}
+
+// annealGene is a box in the annealing state. Turned records whether the box is
+// rotated, or mirrored, relative to its orientation when Improve started.
+type annealGene struct {
+	box    *Box
+	turned bool
+}
+
+// annealState is a candidate plan: the boxes of each bin in insertion order, and the
+// boxes that are left unpacked.
+type annealState struct {
+	bins [][]annealGene
+	pool []annealGene
+	used []float64 // Packed area per bin
+}
+
+// Improve post-optimizes the current packing with simulated annealing, reducing waste and
+// the number of bins in use, and packing previously unpacked boxes where possible.
+//
+// Each move swaps two boxes, relocates a box to another bin (or from the unpacked boxes
+// into a bin), or rotates a box. Only the bins a move touches are re-laid out, by
+// inserting their boxes in order into an empty copy of the bin; boxes that no longer fit
+// become unpacked, as do boxes that would break the cuts with options.Guillotine. Moves
+// that put boxes into a bin with ones they must be kept apart from, or split the boxes
+// of a Box.Group between bins, are rejected. Otherwise a move is kept if it improves the
+// plan, or with a probability that falls as the temperature cools. Plans are ranked by
+// unpacked area first, then by the waste of the bins in use, weighted so that nearly
+// empty bins are emptied first.
+//
+// All boxes currently in the packer's bins take part, including ones from earlier calls
+// to Pack, except locked boxes (see Bin.Place), which stay where they are. When the
+// context is cancelled, the best plan found so far is applied. Only bins whose contents
+// changed are rebuilt. The returned result reflects the new state.
+func (p *Packer) Improve(ctx context.Context, options ImproveOptions) *PackResult {
+	iterations := options.Iterations
+	if iterations <= 0 {
+		iterations = 2000
+	}
+	cooling := options.Cooling
+	if cooling <= 0 || cooling >= 1 {
+		cooling = 0.995
+	}
+	rng := rand.New(rand.NewPCG(options.Seed, 0))
+
+	bins := make([]*Bin, 0, len(p.Bins))
+	totalBinArea := 0.0
+	for _, bin := range p.Bins {
+		if bin != nil {
+			bins = append(bins, bin)
+			totalBinArea += bin.Area()
+		}
+	}
+	if len(bins) == 0 {
+		return p.Result()
+	}
+	temperature := options.InitialTemperature
+	if temperature <= 0 {
+		temperature = 0.05 * totalBinArea / float64(len(bins))
+	}
+
+	// Copies of every bin holding only its locked boxes, to lay out candidate contents in.
+	empties := make([]*Bin, len(bins))
+	for b, bin := range bins {
+		empties[b] = bin.scratch()
+		empties[b].reset()
+		for _, box := range bin.Boxes {
+			if box.Locked {
+				copied := *box
+				copied.cluster, copied.Packed = nil, false
+				empties[b].Place(&copied, box.X, box.Y) // Cannot fail: it fitted in the real bin
+			}
+		}
+	}
+
+	// Boxes are inserted as oriented by the plan, with the placement of their tag.
+	tagged := make(map[string]*TagOptions, len(options.TagOptions))
+	for tag, tagOptions := range options.TagOptions {
+		tagOptions.ConstrainRotation = true
+		tagged[tag] = &tagOptions
+	}
+	insertOptions := func(box *Box) *TagOptions {
+		if tagOptions, ok := tagged[box.Tag]; ok {
+			return tagOptions
+		}
+		return fixedOrientation
+	}
+	turnable := func(box *Box) bool {
+		return box.Width != box.Height && !tagOptionsFor(options.TagOptions, box).fixedOrientation(box)
+	}
+	layout := func(b int, genes []annealGene) (fitted, overflow []annealGene, used float64) {
+		bin := empties[b].scratch()
+		used = bin.usedArea() // Locked boxes
+		for _, gene := range genes {
+			copied := trialBox(gene.box)
+			copied.Packed = false
+			if gene.turned {
+				turn(copied)
+			}
+			if options.Guillotine && !keepsGuillotine(bin, copied, insertOptions(copied)) {
+				overflow = append(overflow, gene)
+			} else if bin.InsertWith(copied, insertOptions(copied)) {
+				fitted = append(fitted, gene)
+				used += copied.Area()
+			} else {
+				overflow = append(overflow, gene)
+			}
+		}
+		return fitted, overflow, used
+	}
+
+	// Build the initial state from the current packing.
+	start := annealState{bins: make([][]annealGene, len(bins)), used: make([]float64, len(bins))}
+	for b, bin := range bins {
+		for _, box := range bin.Boxes {
+			start.used[b] += box.Area()
+			if !box.Locked {
+				start.bins[b] = append(start.bins[b], annealGene{box: box})
+			}
+		}
+	}
+	for _, box := range p.UnpackedBoxes {
+		if box != nil && !box.Packed && box.Validate() == nil {
+			start.pool = append(start.pool, annealGene{box: box})
+		}
+	}
+
+	// allowed reports whether the touched bins keep their boxes apart as required and the
+	// boxes of every group share a bin, or are all unpacked.
+	groupBins := make(map[string]int) // Bins of the groups with locked members
+	grouped := false
+	for b, bin := range bins {
+		for _, box := range bin.Boxes {
+			grouped = grouped || box.Group != ""
+			if box.Locked && box.Group != "" {
+				groupBins[box.Group] = b
+			}
+		}
+	}
+	for _, gene := range start.pool {
+		grouped = grouped || gene.box.Group != ""
+	}
+	allowed := func(s *annealState, touched []int) bool {
+		for _, b := range touched {
+			apart := newSeparation(options.KeepApart, empties[b:b+1])
+			for _, gene := range s.bins[b] {
+				if !apart.allows(empties[b], gene.box) {
+					return false
+				}
+				apart.place(empties[b], gene.box)
+			}
+		}
+		if !grouped {
+			return true
+		}
+		seen := maps.Clone(groupBins)
+		for b := -1; b < len(s.bins); b++ {
+			genes := s.pool
+			if b >= 0 {
+				genes = s.bins[b]
+			}
+			for _, gene := range genes {
+				if gene.box.Group == "" {
+					continue
+				}
+				if other, ok := seen[gene.box.Group]; ok && other != b {
+					return false
+				}
+				seen[gene.box.Group] = b
+			}
+		}
+		return true
+	}
+
+	// Unpacked area dominates; within a bin, waste counts less the fuller the bin is, so
+	// moves that drain a nearly empty bin pay off before the bin is actually emptied.
+	unpackedWeight := totalBinArea + 1
+	binEnergy := func(b int, used float64) float64 {
+		if used == 0 {
+			return 0
+		}
+		area := bins[b].Area()
+		return area - used*used/area
+	}
+	energy := func(s *annealState) float64 {
+		e := 0.0
+		for _, gene := range s.pool {
+			e += unpackedWeight * gene.box.Area()
+		}
+		for b, used := range s.used {
+			e += binEnergy(b, used)
+		}
+		return e
+	}
+
+	current := start.clone()
+	currentEnergy := energy(&current)
+	best := current.clone()
+	bestEnergy := currentEnergy
+
+	for iteration := 0; iteration < iterations && ctx.Err() == nil; iteration++ {
+		next := current.clone()
+		touched := next.move(rng, len(bins), turnable)
+		if len(touched) == 0 {
+			temperature *= cooling
+			continue
+		}
+		for _, b := range touched {
+			fitted, overflow, used := layout(b, next.bins[b])
+			next.bins[b], next.used[b] = fitted, used
+			next.pool = append(next.pool, overflow...)
+		}
+		if !allowed(&next, touched) {
+			temperature *= cooling
+			continue
+		}
+		nextEnergy := energy(&next)
+		delta := nextEnergy - currentEnergy
+		if delta < 0 || rng.Float64() < math.Exp(-delta/temperature) {
+			current, currentEnergy = next, nextEnergy
+			if currentEnergy < bestEnergy {
+				best, bestEnergy = current.clone(), currentEnergy
+			}
+		}
+		temperature *= cooling
+	}
+
+	p.applyAnneal(bins, start, best, insertOptions)
+	return p.Result()
+}
+
+// clone returns a copy of the state whose slices can be modified independently.
+func (s *annealState) clone() annealState {
+	clone := annealState{
+		bins: make([][]annealGene, len(s.bins)),
+		pool: slices.Clone(s.pool),
+		used: slices.Clone(s.used),
+	}
+	for b, genes := range s.bins {
+		clone.bins[b] = slices.Clone(genes)
+	}
+	return clone
+}
+
+// move applies a random swap, relocate or rotate move and returns the bins it touched.
+// Only boxes for which turnable reports true are rotated.
+func (s *annealState) move(rng *rand.Rand, binCount int, turnable func(*Box) bool) []int {
+	placed := 0
+	for _, genes := range s.bins {
+		placed += len(genes)
+	}
+	// pick returns the bin and index of the n-th packed box.
+	pick := func(n int) (int, int) {
+		for b, genes := range s.bins {
+			if n < len(genes) {
+				return b, n
+			}
+			n -= len(genes)
+		}
+		return -1, -1
+	}
+
+	switch rng.IntN(3) {
+	case 0: // Swap two packed boxes, possibly within the same bin to change the order
+		if placed < 2 {
+			return nil
+		}
+		b1, i1 := pick(rng.IntN(placed))
+		b2, i2 := pick(rng.IntN(placed))
+		s.bins[b1][i1], s.bins[b2][i2] = s.bins[b2][i2], s.bins[b1][i1]
+		if b1 == b2 {
+			return []int{b1}
+		}
+		return []int{b1, b2}
+
+	case 1: // Relocate a box, packed or not, into another bin
+		if placed+len(s.pool) == 0 {
+			return nil
+		}
+		n := rng.IntN(placed + len(s.pool))
+		var gene annealGene
+		from := -1
+		if n < placed {
+			var i int
+			from, i = pick(n)
+			gene = s.bins[from][i]
+			s.bins[from] = slices.Delete(s.bins[from], i, i+1)
+		} else {
+			i := n - placed
+			gene = s.pool[i]
+			s.pool = slices.Delete(s.pool, i, i+1)
+		}
+		to := rng.IntN(binCount)
+		if to == from {
+			to = (to + 1) % binCount
+		}
+		s.bins[to] = slices.Insert(s.bins[to], rng.IntN(len(s.bins[to])+1), gene)
+		if from < 0 || from == to {
+			return []int{to}
+		}
+		return []int{from, to}
+
+	default: // Rotate a packed box
+		if placed == 0 {
+			return nil
+		}
+		b, i := pick(rng.IntN(placed))
+		gene := &s.bins[b][i]
+		if !turnable(gene.box) {
+			return nil
+		}
+		gene.turned = !gene.turned
+		return []int{b}
+	}
+}
+
+// applyAnneal rebuilds the real bins whose contents differ between start and best,
+// inserting each box with insertOptions, and updates the packer's packed and unpacked
+// boxes accordingly. A box the real bin rejects, as a custom strategy bound to the bin
+// may, is reported as unpacked.
+func (p *Packer) applyAnneal(bins []*Bin, start, best annealState, insertOptions func(*Box) *TagOptions) {
+	for b, bin := range bins {
+		if slices.Equal(start.bins[b], best.bins[b]) {
+			continue
+		}
+		boxes := bin.Boxes
+		for _, box := range boxes {
+			box.Packed = false
+		}
+		bin.reset()
+		for _, box := range boxes {
+			if box.Locked {
+				bin.Place(box, box.X, box.Y) // Locked boxes go back where they were
+			}
+		}
+	}
+	var rejected []*Box
+	for b, bin := range bins {
+		if slices.Equal(start.bins[b], best.bins[b]) {
+			continue
+		}
+		for _, gene := range best.bins[b] {
+			if gene.turned {
+				turn(gene.box)
+			}
+			if bin.InsertWith(gene.box, insertOptions(gene.box)) {
+				continue
+			}
+			if gene.turned {
+				turn(gene.box)
+			}
+			rejected = append(rejected, gene.box)
+		}
+	}
+
+	// Boxes packed by the last Pack stay reported as packed if they still are, followed by
+	// boxes packed for the first time. Boxes that no longer fit are reported as unpacked.
+	packed := make([]*Box, 0, len(p.lastPacked))
+	for _, box := range p.lastPacked {
+		if box.Packed {
+			packed = append(packed, box)
+		}
+	}
+	unpacked := make([]*Box, 0, len(p.UnpackedBoxes))
+	wasUnpacked := make(map[*Box]struct{}, len(p.UnpackedBoxes))
+	for _, box := range p.UnpackedBoxes {
+		wasUnpacked[box] = struct{}{}
+		if box != nil && box.Packed {
+			packed = append(packed, box)
+		} else {
+			unpacked = append(unpacked, box)
+		}
+	}
+	for _, gene := range best.pool {
+		if _, ok := wasUnpacked[gene.box]; !ok {
+			unpacked = append(unpacked, gene.box)
+		}
+	}
+	for _, box := range rejected {
+		if _, ok := wasUnpacked[box]; !ok {
+			unpacked = append(unpacked, box)
+		}
+	}
+	p.lastPacked = packed
+	p.UnpackedBoxes = unpacked
+}
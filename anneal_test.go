@@ -0,0 +1,178 @@
+package binpacking
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestImprove(t *testing.T) {
+	t.Run("empties a nearly empty bin", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		left, right := NewBox(5, 10, false), NewBox(5, 10, false)
+		bins[0].Insert(left)
+		bins[1].Insert(right)
+		packer := NewPacker(bins)
+
+		result := packer.Improve(context.Background(), ImproveOptions{Seed: 1})
+		used := 0
+		for _, group := range result.ByBin() {
+			if len(group.Boxes) > 0 {
+				used++
+			}
+		}
+		if used != 1 {
+			t.Errorf("Bins in use: got %d, want %d", used, 1)
+		}
+		if !left.Packed || !right.Packed {
+			t.Errorf("Both boxes must stay packed")
+		}
+	})
+
+	t.Run("never loses packed area", func(t *testing.T) {
+		boxes := []*Box{
+			NewBox(2, 5, true), NewBox(2, 6, true), NewBox(4, 2, true), NewBox(5, 2, true), NewBox(2, 1, true),
+		}
+		bin := NewBin(8, 8, nil)
+		packer := NewPacker([]*Bin{bin})
+		before := 0.0
+		for _, box := range packer.Pack(boxes, PackerOptions{}) {
+			before += box.Area()
+		}
+
+		result := packer.Improve(context.Background(), ImproveOptions{Iterations: 500, Seed: 2})
+		after := 0.0
+		for _, box := range bin.Boxes {
+			after += box.Area()
+		}
+		if after < before {
+			t.Errorf("Packed area: got %g, want at least %g", after, before)
+		}
+		if len(bin.Boxes)+len(result.Unpacked) != len(boxes) {
+			t.Errorf("Packed plus unpacked: got %d, want %d", len(bin.Boxes)+len(result.Unpacked), len(boxes))
+		}
+		for i, a := range bin.Boxes {
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+				}
+			}
+		}
+	})
+
+	t.Run("keeps boxes apart", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		a, b := NewBox(5, 5, false), NewBox(5, 5, false)
+		a.ID, b.ID = "A", "B"
+		rules := []KeepApart{{A: "A", B: "B"}}
+		packer := NewPacker(bins)
+		packer.Pack([]*Box{a, b}, PackerOptions{KeepApart: rules})
+		if len(bins[0].Boxes) != 1 || len(bins[1].Boxes) != 1 {
+			t.Fatalf("Precondition: got %d/%d boxes per bin, want 1/1", len(bins[0].Boxes), len(bins[1].Boxes))
+		}
+
+		packer.Improve(context.Background(), ImproveOptions{Seed: 1, KeepApart: rules})
+		if !a.Packed || !b.Packed {
+			t.Fatalf("Both boxes must stay packed")
+		}
+		if len(bins[0].Boxes) != 1 || len(bins[1].Boxes) != 1 {
+			t.Errorf("Boxes per bin: got %d/%d, want 1/1", len(bins[0].Boxes), len(bins[1].Boxes))
+		}
+	})
+
+	t.Run("keeps groups together", func(t *testing.T) {
+		// Moving the small group member next to the plain box would empty a bin.
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		large, small, plain := NewBox(5, 10, false), NewBox(3, 3, false), NewBox(5, 10, false)
+		large.Group, small.Group = "g", "g"
+		bins[0].Insert(large)
+		bins[0].Insert(small)
+		bins[1].Insert(plain)
+		packer := NewPacker(bins)
+
+		packer.Improve(context.Background(), ImproveOptions{Seed: 1})
+		together := slices.Contains(bins[0].Boxes, large) == slices.Contains(bins[0].Boxes, small)
+		if !large.Packed || !small.Packed || !together {
+			t.Errorf("Group members: got bins %v/%v, want the same bin", slices.Contains(bins[0].Boxes, large), slices.Contains(bins[0].Boxes, small))
+		}
+	})
+
+	t.Run("keeps the layout cuttable", func(t *testing.T) {
+		newLayout := func() (*Bin, *Packer) {
+			boxes := []*Box{
+				NewBox(2, 1, false), NewBox(5, 4, false), NewBox(5, 5, false), NewBox(3, 3, false),
+				NewBox(4, 3, false), NewBox(3, 5, false), NewBox(4, 3, false),
+			}
+			bin := NewBin(10, 10, nil)
+			packer := NewPacker([]*Bin{bin})
+			packer.Pack(boxes, PackerOptions{Guillotine: true})
+			return bin, packer
+		}
+		plain, packer := newLayout()
+		packer.Improve(context.Background(), ImproveOptions{Seed: 1})
+		if ValidateGuillotine(plain) == nil {
+			t.Fatalf("Precondition: improving without the constraint should break the cuts")
+		}
+
+		bin, packer := newLayout()
+		packer.Improve(context.Background(), ImproveOptions{Seed: 1, Guillotine: true})
+		if err := ValidateGuillotine(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("honors tag options", func(t *testing.T) {
+		// Only a rotation would let the post move into the strip and empty the sheet.
+		post := NewBox(2, 6, false)
+		post.Tag = "post"
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(6, 2, nil)}
+		bins[0].Insert(post)
+		packer := NewPacker(bins)
+
+		packer.Improve(context.Background(), ImproveOptions{Seed: 1, TagOptions: map[string]TagOptions{"post": {ConstrainRotation: true}}})
+		if post.Rotated || !slices.Contains(bins[0].Boxes, post) {
+			t.Errorf("Post: got rotated %v in the sheet %v, want unrotated in the sheet", post.Rotated, slices.Contains(bins[0].Boxes, post))
+		}
+	})
+
+	t.Run("reports boxes the real bin rejects", func(t *testing.T) {
+		// The strategy is bound to the real bin, which rejects a third box; the scratch
+		// copies the plan is made in do not.
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 5, nil)}
+		bins[0].Placement = func(space *FreeSpaceBox, width, height float64) Score {
+			if len(bins[0].Boxes) >= 2 {
+				return NoFit
+			}
+			return BestShortSideFit(space, width, height)
+		}
+		boxes := []*Box{NewBox(5, 5, false), NewBox(5, 5, false), NewBox(5, 5, false)}
+		bins[0].Insert(boxes[0])
+		bins[1].Insert(boxes[1])
+		bins[1].Insert(boxes[2])
+		packer := NewPacker(bins)
+
+		// Hot enough to accept the worse intermediate plan on the way to one bin.
+		result := packer.Improve(context.Background(), ImproveOptions{Seed: 1, InitialTemperature: 100})
+		placed := len(bins[0].Boxes) + len(bins[1].Boxes)
+		if placed+len(result.Unpacked) != len(boxes) {
+			t.Errorf("Packed plus unpacked: got %d+%d, want %d", placed, len(result.Unpacked), len(boxes))
+		}
+		for _, box := range result.Unpacked {
+			if box.Packed {
+				t.Errorf("Unpacked box %s is marked as packed", box.Label())
+			}
+		}
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Insert(NewBox(5, 5, false))
+		packer := NewPacker([]*Bin{bin})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		packer.Improve(ctx, ImproveOptions{})
+		if len(bin.Boxes) != 1 {
+			t.Errorf("Boxes: got %d, want %d", len(bin.Boxes), 1)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Package atlas builds texture atlases: it packs a set of images into one or more pages
+// with the binpacking package and composites them, returning where each sprite ended up.
+//
+//	a, err := atlas.Build(map[string]image.Image{"hero": hero, "coin": coin}, atlas.Options{Width: 1024, Height: 1024})
+//	if err != nil {
+//		return err
+//	}
+//	sprite := a.Sprites["hero"] // sprite.Page, sprite.Rect, sprite.Rotated
+//	png.Encode(w, a.Pages[sprite.Page])
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+	"strings"
+
+	"github.com/acmacalister/binpacking"
+)
+
+// ErrDoesNotFit is returned by Build when some images cannot be placed, because they are
+// larger than a page or because Options.MaxPages pages are full.
+var ErrDoesNotFit = errors.New("atlas: images do not fit")
+
+// Options configures Build. Zero values select the defaults noted on each field.
+type Options struct {
+	Width  int // Width of each page in pixels; default 2048
+	Height int // Height of each page in pixels; default 2048
+	// MaxPages caps the number of pages; zero or negative means as many as needed.
+	MaxPages int
+	// AllowRotation lets sprites be turned by 90 degrees clockwise when that packs
+	// tighter. Rotated sprites are drawn rotated and reported with Sprite.Rotated.
+	AllowRotation bool
+	// Placement is the strategy used on each page; nil uses BestShortSideFit.
+	Placement binpacking.PlacementStrategyFunc
+
+	// Padding is the number of transparent pixels kept around every sprite, so two
+	// neighbours are at least twice that apart, and sprites stay that far from the edges.
+	Padding int
+	// Extrude duplicates the border pixels of every sprite outwards by this many pixels,
+	// inside the padding, so bilinear filtering and mipmapping sample the sprite's own
+	// edge colors instead of bleeding in neighbours or transparency.
+	Extrude int
+	// Sprites overrides Padding and Extrude for individual images by name.
+	Sprites map[string]SpriteOptions
+}
+
+// SpriteOptions are per-image settings of Options.Sprites.
+type SpriteOptions struct {
+	Padding int // Replaces Options.Padding for the image
+	Extrude int // Replaces Options.Extrude for the image
+}
+
+// Sprite is the location of one image in the atlas.
+type Sprite struct {
+	Name string
+	Page int // Index of the page in Atlas.Pages
+	// Rect is the sprite's area in the page. For rotated sprites its width is the image's
+	// height and vice versa.
+	Rect image.Rectangle
+	// Frame is Rect grown by the sprite's extrusion: the area of the page filled from the
+	// image. The padding lies outside it.
+	Frame   image.Rectangle
+	Rotated bool // Whether the image was turned by 90 degrees clockwise
+	// UV is Rect in normalized texture coordinates of the page, with V pointing down; see
+	// binpacking.UVRect. For rotated sprites, the image's top-left corner is at (U1, V0).
+	UV binpacking.UVRect
+}
+
+// Atlas is the result of Build.
+type Atlas struct {
+	Pages   []*image.RGBA     // The composited pages, each Options.Width x Options.Height
+	Sprites map[string]Sprite // Location of every image by name
+}
+
+// Build packs the images into pages and composites them. Images are packed in order of
+// name, so the result is deterministic. Empty images are reported with an empty Rect on
+// page 0 and take no space. If some images do not fit, the returned error wraps
+// ErrDoesNotFit and lists their names; no atlas is returned.
+func Build(images map[string]image.Image, options Options) (*Atlas, error) {
+	width, height := options.Width, options.Height
+	if width <= 0 {
+		width = 2048
+	}
+	if height <= 0 {
+		height = 2048
+	}
+
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	boxes := make([]*binpacking.Box, 0, len(names))
+	atlas := &Atlas{Sprites: make(map[string]Sprite, len(images))}
+	for _, name := range names {
+		size := images[name].Bounds().Size()
+		if size.X <= 0 || size.Y <= 0 {
+			atlas.Sprites[name] = Sprite{Name: name}
+			continue
+		}
+		// The packer places the whole footprint, so gutters take part in the layout.
+		margin := gutter(name, options)
+		if margin < 0 {
+			return nil, fmt.Errorf("atlas: negative padding or extrusion for %s", name)
+		}
+		box := binpacking.NewBoxOf(size.X+2*margin, size.Y+2*margin, !options.AllowRotation)
+		box.ID = name
+		boxes = append(boxes, box)
+	}
+
+	packer := binpacking.NewPacker(nil)
+	packer.Pack(boxes, binpacking.PackerOptions{
+		BinTemplates: []binpacking.BinTemplate{{Width: float64(width), Height: float64(height), Placement: options.Placement}},
+		MaxBins:      options.MaxPages,
+	})
+	if len(packer.UnpackedBoxes) > 0 {
+		unpacked := make([]string, 0, len(packer.UnpackedBoxes))
+		for _, box := range packer.UnpackedBoxes {
+			unpacked = append(unpacked, box.ID)
+		}
+		sort.Strings(unpacked)
+		return nil, fmt.Errorf("%w in %dx%d pages: %s", ErrDoesNotFit, width, height, strings.Join(unpacked, ", "))
+	}
+
+	for page, bin := range packer.Bins {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for _, box := range bin.Boxes {
+			margin := gutter(box.ID, options)
+			x, y, w, h := binpacking.PlacedRect[int](box)
+			rect := image.Rect(x+margin, y+margin, x+w-margin, y+h-margin)
+			src := images[box.ID]
+			if box.Rotated {
+				drawRotated(img, rect, src)
+			} else {
+				draw.Draw(img, rect, src, src.Bounds().Min, draw.Src)
+			}
+			frame := rect.Inset(-extrusion(box.ID, options))
+			extrude(img, rect, frame)
+			uv := binpacking.NewUVRect(float64(rect.Min.X), float64(rect.Min.Y), float64(rect.Dx()), float64(rect.Dy()), float64(width), float64(height))
+			atlas.Sprites[box.ID] = Sprite{Name: box.ID, Page: page, Rect: rect, Frame: frame, Rotated: box.Rotated, UV: uv}
+		}
+		atlas.Pages = append(atlas.Pages, img)
+	}
+	return atlas, nil
+}
+
+// extrusion returns the extrusion of the named image.
+func extrusion(name string, options Options) int {
+	if sprite, ok := options.Sprites[name]; ok {
+		return sprite.Extrude
+	}
+	return options.Extrude
+}
+
+// gutter returns the space reserved on each side of the named image: its extrusion plus
+// its padding, or a negative value if either is negative.
+func gutter(name string, options Options) int {
+	padding, extrude := options.Padding, options.Extrude
+	if sprite, ok := options.Sprites[name]; ok {
+		padding, extrude = sprite.Padding, sprite.Extrude
+	}
+	if padding < 0 || extrude < 0 {
+		return -1
+	}
+	return padding + extrude
+}
+
+// extrude fills the pixels of frame outside rect with the nearest pixel of rect, which
+// repeats the edge rows and columns and fills the corners with the corner pixels.
+func extrude(img *image.RGBA, rect, frame image.Rectangle) {
+	for y := frame.Min.Y; y < frame.Max.Y; y++ {
+		sy := min(max(y, rect.Min.Y), rect.Max.Y-1)
+		for x := frame.Min.X; x < frame.Max.X; x++ {
+			if y >= rect.Min.Y && y < rect.Max.Y && x == rect.Min.X {
+				x = rect.Max.X // Skip the sprite itself
+				if x >= frame.Max.X {
+					break
+				}
+			}
+			sx := min(max(x, rect.Min.X), rect.Max.X-1)
+			img.SetRGBA(x, y, img.RGBAAt(sx, sy))
+		}
+	}
+}
+
+// drawRotated draws src turned by 90 degrees clockwise into rect of dst, whose width is
+// src's height: source pixel (sx, sy) lands at column height-1-sy, row sx.
+func drawRotated(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	for sy := 0; sy < bounds.Dy(); sy++ {
+		for sx := 0; sx < bounds.Dx(); sx++ {
+			dst.Set(rect.Min.X+bounds.Dy()-1-sy, rect.Min.Y+sx, src.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+}
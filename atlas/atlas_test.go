@@ -0,0 +1,148 @@
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sprite returns a w x h image whose pixel colors encode their coordinates, so any
+// misplaced or mis-rotated pixel is detected.
+func sprite(w, h int, id uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), id, 0xff})
+		}
+	}
+	return img
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("packs and composites the images", func(t *testing.T) {
+		images := map[string]image.Image{}
+		for i := 0; i < 12; i++ {
+			images[fmt.Sprintf("s%02d", i)] = sprite(4+i%5, 3+i%4, uint8(i+1))
+		}
+		images["empty"] = image.NewRGBA(image.Rect(0, 0, 0, 0))
+		a, err := Build(images, Options{Width: 16, Height: 16, AllowRotation: true})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		if len(a.Pages) == 0 || len(a.Sprites) != len(images) {
+			t.Fatalf("got %d pages and %d sprites, want at least 1 and %d", len(a.Pages), len(a.Sprites), len(images))
+		}
+		for name, s := range a.Sprites {
+			src := images[name].Bounds().Size()
+			if name == "empty" {
+				if !s.Rect.Empty() {
+					t.Errorf("Empty sprite: got %v, want an empty rect", s.Rect)
+				}
+				continue
+			}
+			size := s.Rect.Size()
+			if s.Rotated {
+				size.X, size.Y = size.Y, size.X
+			}
+			if size != src {
+				t.Errorf("%s: got size %v, want %v", name, size, src)
+			}
+			page := a.Pages[s.Page]
+			uv := s.UV
+			if uv.U0*16 != float64(s.Rect.Min.X) || uv.V0*16 != float64(s.Rect.Min.Y) || uv.U1*16 != float64(s.Rect.Max.X) || uv.V1*16 != float64(s.Rect.Max.Y) {
+				t.Errorf("%s: UV %+v does not match %v in a 16x16 page", name, uv, s.Rect)
+			}
+			if !s.Rect.In(page.Bounds()) {
+				t.Errorf("%s: %v outside the page", name, s.Rect)
+			}
+			for sy := 0; sy < src.Y; sy++ {
+				for sx := 0; sx < src.X; sx++ {
+					x, y := s.Rect.Min.X+sx, s.Rect.Min.Y+sy
+					if s.Rotated {
+						x, y = s.Rect.Min.X+src.Y-1-sy, s.Rect.Min.Y+sx
+					}
+					if got, want := page.RGBAAt(x, y), images[name].At(sx, sy); got != want {
+						t.Fatalf("%s: pixel (%d,%d) is %v, want %v", name, sx, sy, got, want)
+					}
+				}
+			}
+			for other, o := range a.Sprites {
+				if other != name && o.Page == s.Page && o.Rect.Overlaps(s.Rect) {
+					t.Errorf("%s overlaps %s", name, other)
+				}
+			}
+		}
+	})
+
+	t.Run("pads and extrudes sprites", func(t *testing.T) {
+		images := map[string]image.Image{"a": sprite(5, 3, 1), "b": sprite(4, 4, 2), "c": sprite(3, 6, 3), "tight": sprite(2, 2, 4)}
+		options := Options{Width: 40, Height: 40, AllowRotation: true, Padding: 1, Extrude: 2,
+			Sprites: map[string]SpriteOptions{"tight": {}}}
+		a, err := Build(images, options)
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		page := a.Pages[0]
+		for name, s := range a.Sprites {
+			padding, extrude := 1, 2
+			if name == "tight" {
+				padding, extrude = 0, 0
+			}
+			if got, want := s.Frame, s.Rect.Inset(-extrude); got != want {
+				t.Errorf("%s: frame got %v, want %v", name, got, want)
+			}
+			if !s.Frame.Inset(-padding).In(page.Bounds()) {
+				t.Errorf("%s: padded frame %v outside the page", name, s.Frame.Inset(-padding))
+			}
+			// Every extruded pixel repeats the nearest pixel of the sprite.
+			for y := s.Frame.Min.Y; y < s.Frame.Max.Y; y++ {
+				for x := s.Frame.Min.X; x < s.Frame.Max.X; x++ {
+					sx := min(max(x, s.Rect.Min.X), s.Rect.Max.X-1)
+					sy := min(max(y, s.Rect.Min.Y), s.Rect.Max.Y-1)
+					if got, want := page.RGBAAt(x, y), page.RGBAAt(sx, sy); got != want {
+						t.Fatalf("%s: extruded pixel (%d,%d) is %v, want %v", name, x, y, got, want)
+					}
+				}
+			}
+			for other, o := range a.Sprites {
+				otherPadding := 1
+				if other == "tight" {
+					otherPadding = 0
+				}
+				if other != name && s.Frame.Inset(-padding).Overlaps(o.Frame.Inset(-otherPadding)) {
+					t.Errorf("Padded %s overlaps padded %s", name, other)
+				}
+			}
+			// Padding stays transparent.
+			if padding > 0 && s.Frame.Min.X > 0 {
+				if got := page.RGBAAt(s.Frame.Min.X-1, s.Frame.Min.Y); got.A != 0 {
+					t.Errorf("%s: padding pixel is %v, want transparent", name, got)
+				}
+			}
+		}
+	})
+
+	t.Run("uses several pages", func(t *testing.T) {
+		images := map[string]image.Image{"a": sprite(8, 8, 1), "b": sprite(8, 8, 2), "c": sprite(8, 8, 3)}
+		a, err := Build(images, Options{Width: 10, Height: 10})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		if got, want := len(a.Pages), 3; got != want {
+			t.Errorf("Pages: got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("reports images that do not fit", func(t *testing.T) {
+		images := map[string]image.Image{"big": sprite(20, 4, 1), "a": sprite(8, 8, 2), "b": sprite(8, 8, 3)}
+		_, err := Build(images, Options{Width: 10, Height: 10, MaxPages: 1})
+		if !errors.Is(err, ErrDoesNotFit) {
+			t.Fatalf("got %v, want %v", err, ErrDoesNotFit)
+		}
+		if got, want := err.Error(), "atlas: images do not fit in 10x10 pages: b, big"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
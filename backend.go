@@ -0,0 +1,37 @@
+package binpacking
+
+// Backend maintains the free area of a Bin and decides where boxes can be placed.
+// Bins share the same Insert/ScoreFor interface regardless of backend, so the Packer
+// can mix bins using different algorithms.
+//
+// A backend instance holds per-bin state and must not be shared between bins.
+type Backend interface {
+	// FindPlacement returns the best placement for box in bin according to strategy,
+	// without modifying the bin, the box, or the backend.
+	FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo
+	// Place updates the free area after box has been positioned according to placement.
+	Place(bin *Bin, box *Box, placement PlacementInfo)
+}
+
+// backend returns the bin's backend, defaulting to MaxRects.
+func (b *Bin) backend() Backend {
+	if b.Backend == nil {
+		return MaxRectsBackend{}
+	}
+	return b.Backend
+}
+
+// MaxRectsBackend is the default backend. It tracks all maximal free rectangles,
+// which may overlap, and splits every rectangle a placed box intersects.
+type MaxRectsBackend struct{}
+
+// FindPlacement implements Backend.
+func (MaxRectsBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	return bin.findFree(fitSize{box.Width, box.Height, box.ConstrainRotation, bin.tolerance(), bin.TieBreak, bin.anchoring(box.Anchor, true), nil}, strategy)
+}
+
+// Place implements Backend.
+func (MaxRectsBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
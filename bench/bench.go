@@ -0,0 +1,250 @@
+// Package bench measures the binpacking package on the standard instance sets of the
+// two-dimensional bin packing problem, so strategies can be compared on the same inputs
+// as the literature: the classes of Berkey and Wang and of Martello and Vigo, generated
+// here or read from their published files, and instances in the 2DPackLib format.
+//
+//	instances := bench.GenerateSet(bench.Classes, []int{20, 40, 60, 80, 100}, 10, 1)
+//	results := bench.Run(instances, bench.Options{})
+//	bench.WriteReport(os.Stdout, results)
+//
+// Every instance is packed into as many identical bins as needed, opening one bin at a
+// time, and the report gives per class and strategy the bins used against a lower bound,
+// the efficiency and the runtime.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/acmacalister/binpacking"
+)
+
+// Item is an item type of an instance, with the number of copies to pack.
+type Item struct {
+	Width  float64
+	Height float64
+	Demand int
+}
+
+// Instance is a bin packing instance: items to pack into identical bins.
+type Instance struct {
+	Name      string // Name of the instance, unique within its set
+	Class     string // Class the instance belongs to, by which results are grouped
+	BinWidth  float64
+	BinHeight float64
+	Items     []Item
+}
+
+// Count returns the number of items to pack, counting every copy.
+func (in *Instance) Count() int {
+	count := 0
+	for _, item := range in.Items {
+		count += item.Demand
+	}
+	return count
+}
+
+// Area returns the total area of the items, counting every copy.
+func (in *Instance) Area() float64 {
+	area := 0.0
+	for _, item := range in.Items {
+		area += item.Width * item.Height * float64(item.Demand)
+	}
+	return area
+}
+
+// LowerBound returns the continuous lower bound on the number of bins, the total item
+// area divided by the bin area and rounded up.
+func (in *Instance) LowerBound() int {
+	binArea := in.BinWidth * in.BinHeight
+	if binArea <= 0 {
+		return 0
+	}
+	return int(math.Ceil(in.Area()/binArea - 1e-9))
+}
+
+// Boxes returns one box per item copy, rotatable if allowRotation is set.
+func (in *Instance) Boxes(allowRotation bool) []*binpacking.Box {
+	boxes := make([]*binpacking.Box, 0, in.Count())
+	for i, item := range in.Items {
+		for n := 0; n < item.Demand; n++ {
+			box := binpacking.NewBox(item.Width, item.Height, !allowRotation)
+			box.ID = fmt.Sprintf("%d.%d", i+1, n+1)
+			boxes = append(boxes, box)
+		}
+	}
+	return boxes
+}
+
+// Strategy is a packer configuration to measure.
+type Strategy struct {
+	Name string
+	// NewBin creates an empty bin of the instance's size, selecting the backend and the
+	// placement strategy. Nil uses binpacking.NewBin with BestShortSideFit.
+	NewBin func(width, height float64) *binpacking.Bin
+	// Options are passed to Packer.Pack for every bin opened.
+	Options binpacking.PackerOptions
+}
+
+// DefaultStrategies covers the built-in placement strategies and backends.
+var DefaultStrategies = []Strategy{
+	{Name: "maxrects-bssf", NewBin: func(w, h float64) *binpacking.Bin { return binpacking.NewBin(w, h, binpacking.BestShortSideFit) }},
+	{Name: "maxrects-blsf", NewBin: func(w, h float64) *binpacking.Bin { return binpacking.NewBin(w, h, binpacking.BestLongSideFit) }},
+	{Name: "maxrects-baf", NewBin: func(w, h float64) *binpacking.Bin { return binpacking.NewBin(w, h, binpacking.BestAreaFit) }},
+	{Name: "maxrects-bl", NewBin: func(w, h float64) *binpacking.Bin { return binpacking.NewBin(w, h, binpacking.BottomLeft) }},
+	{Name: "maxrects-contact", NewBin: binpacking.NewContactPointBin},
+	{Name: "guillotine-baf", NewBin: func(w, h float64) *binpacking.Bin {
+		return binpacking.NewGuillotineBin(w, h, binpacking.BestAreaFit, binpacking.SplitShorterLeftoverAxis)
+	}},
+	{Name: "skyline", NewBin: func(w, h float64) *binpacking.Bin {
+		return binpacking.NewSkylineBin(w, h, binpacking.SkylineOptions{WasteMap: true})
+	}},
+	{Name: "shelf-ffdh", Options: binpacking.PackerOptions{Algorithm: binpacking.AlgorithmShelfFirstFit}},
+}
+
+// Options configures Run.
+type Options struct {
+	// Strategies are measured in order on every instance; nil uses DefaultStrategies.
+	Strategies []Strategy
+	// AllowRotation lets items be rotated by 90 degrees, the 2BP|R|F variant of the
+	// problem. By default orientations are fixed, as in 2BP|O|F.
+	AllowRotation bool
+}
+
+// Result is the outcome of packing one instance with one strategy.
+type Result struct {
+	Instance   *Instance
+	Strategy   string
+	Bins       int           // Bins used
+	LowerBound int           // binpacking.L2LowerBound of the items, at least Instance.LowerBound
+	Unpacked   int           // Items larger than a bin, which no strategy can pack
+	Efficiency float64       // Percentage of the area of the bins used covered by items
+	Elapsed    time.Duration // Time spent packing
+}
+
+// Run packs every instance with every strategy.
+func Run(instances []*Instance, options Options) []Result {
+	strategies := options.Strategies
+	if strategies == nil {
+		strategies = DefaultStrategies
+	}
+	results := make([]Result, 0, len(instances)*len(strategies))
+	for _, in := range instances {
+		for _, strategy := range strategies {
+			results = append(results, pack(in, strategy, options.AllowRotation))
+		}
+	}
+	return results
+}
+
+// pack packs the instance with the strategy, opening a bin whenever the items left do
+// not fit into the bins used so far.
+func pack(in *Instance, strategy Strategy, allowRotation bool) Result {
+	newBin := strategy.NewBin
+	if newBin == nil {
+		newBin = func(w, h float64) *binpacking.Bin { return binpacking.NewBin(w, h, nil) }
+	}
+	remaining := in.Boxes(allowRotation)
+	result := Result{Instance: in, Strategy: strategy.Name, LowerBound: binpacking.L2LowerBound(in.BinWidth, in.BinHeight, remaining)}
+	packedArea := 0.0
+	start := time.Now()
+	for len(remaining) > 0 {
+		packer := binpacking.NewPacker([]*binpacking.Bin{newBin(in.BinWidth, in.BinHeight)})
+		packed := packer.Pack(remaining, strategy.Options)
+		if len(packed) == 0 {
+			break // The rest is larger than a bin
+		}
+		result.Bins++
+		for _, box := range packed {
+			packedArea += box.Area()
+		}
+		remaining = packer.UnpackedBoxes
+	}
+	result.Elapsed = time.Since(start)
+	result.Unpacked = len(remaining)
+	if result.Bins > 0 {
+		result.Efficiency = 100 * packedArea / (float64(result.Bins) * in.BinWidth * in.BinHeight)
+	}
+	return result
+}
+
+// Summary aggregates the results of one strategy on one class.
+type Summary struct {
+	Class      string
+	Strategy   string
+	Instances  int
+	Bins       int           // Total bins used
+	LowerBound int           // Total of the lower bounds
+	Unpacked   int           // Total items left unpacked
+	Efficiency float64       // Mean efficiency over the instances
+	Elapsed    time.Duration // Total time spent packing
+}
+
+// Ratio returns the bins used relative to the lower bound; 1 is optimal.
+func (s Summary) Ratio() float64 {
+	if s.LowerBound == 0 {
+		return 0
+	}
+	return float64(s.Bins) / float64(s.LowerBound)
+}
+
+// Summarize aggregates results per class and strategy, in the order the classes and
+// strategies first appear. Strategies are also summarized over all classes, with the
+// class "all".
+func Summarize(results []Result) []Summary {
+	var classes, strategies []string
+	sums := make(map[[2]string]*Summary)
+	add := func(class, strategy string, r Result) {
+		key := [2]string{class, strategy}
+		s, ok := sums[key]
+		if !ok {
+			s = &Summary{Class: class, Strategy: strategy}
+			sums[key] = s
+		}
+		s.Instances++
+		s.Bins += r.Bins
+		s.LowerBound += r.LowerBound
+		s.Unpacked += r.Unpacked
+		s.Efficiency += r.Efficiency
+		s.Elapsed += r.Elapsed
+	}
+	for _, r := range results {
+		if !slices.Contains(classes, r.Instance.Class) {
+			classes = append(classes, r.Instance.Class)
+		}
+		if !slices.Contains(strategies, r.Strategy) {
+			strategies = append(strategies, r.Strategy)
+		}
+		add(r.Instance.Class, r.Strategy, r)
+		add("all", r.Strategy, r)
+	}
+	if len(classes) > 1 {
+		classes = append(classes, "all")
+	}
+
+	summaries := make([]Summary, 0, len(classes)*len(strategies))
+	for _, class := range classes {
+		for _, strategy := range strategies {
+			if s, ok := sums[[2]string{class, strategy}]; ok {
+				s.Efficiency /= float64(s.Instances)
+				summaries = append(summaries, *s)
+			}
+		}
+	}
+	return summaries
+}
+
+// WriteReport writes the summaries of the results as an aligned text table.
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "class\tstrategy\tinstances\tbins\tlower bound\tratio\tefficiency\tunpacked\ttime\t")
+	for _, s := range Summarize(results) {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%.3f\t%.2f%%\t%d\t%v\t\n",
+			s.Class, s.Strategy, s.Instances, s.Bins, s.LowerBound, s.Ratio(), s.Efficiency, s.Unpacked, s.Elapsed.Round(time.Microsecond))
+	}
+	return tw.Flush()
+}
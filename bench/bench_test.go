@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, class := range Classes {
+		t.Run(class.Name, func(t *testing.T) {
+			in := Generate(class, 100, 3)
+			if got := Generate(class, 100, 3); got.Name != in.Name || len(got.Items) != 100 || got.Items[42] != in.Items[42] {
+				t.Errorf("got %s with item %+v, want the same instance %s with %+v", got.Name, got.Items[42], in.Name, in.Items[42])
+			}
+			for _, item := range in.Items {
+				if item.Width < 1 || item.Height < 1 || item.Width > in.BinWidth || item.Height > in.BinHeight || item.Demand != 1 {
+					t.Fatalf("got item %+v, want one fitting a %vx%v bin", item, in.BinWidth, in.BinHeight)
+				}
+			}
+		})
+	}
+
+	t.Run("martello-vigo types", func(t *testing.T) {
+		// In class mv3, 70% of the items are of type 3, at least half a bin in both sides.
+		in := Generate(Classes[8], 1000, 1)
+		large := 0
+		for _, item := range in.Items {
+			if item.Width >= 50 && item.Height >= 50 {
+				large++
+			}
+		}
+		if large < 650 || large > 850 {
+			t.Errorf("got %d items of type 3 in 1000, want about 700 and some of the other types", large)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		set := GenerateSet(Classes[:2], []int{20, 40}, 3, 1)
+		if len(set) != 12 || set[0].Class != "bw1" || set[11].Class != "bw2" || set[11].Count() != 40 {
+			t.Errorf("got %d instances, want 12 of bw1 and bw2 in order", len(set))
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	instances := GenerateSet(Classes, []int{20}, 2, 1)
+	results := Run(instances, Options{})
+	if len(results) != len(instances)*len(DefaultStrategies) {
+		t.Fatalf("got %d results, want %d", len(results), len(instances)*len(DefaultStrategies))
+	}
+	for _, r := range results {
+		if r.Unpacked != 0 || r.Bins < r.LowerBound || r.LowerBound < 1 || r.Efficiency <= 0 || r.Efficiency > 100 {
+			t.Errorf("%s with %s: got %+v, want every item packed into at least the lower bound", r.Instance.Name, r.Strategy, r)
+		}
+		if r.Bins == 0 || r.Efficiency > 100*float64(r.LowerBound)/float64(r.Bins)+1e-9 {
+			t.Errorf("%s with %s: got efficiency %v with %d bins, want at most the area ratio", r.Instance.Name, r.Strategy, r.Efficiency, r.Bins)
+		}
+	}
+
+	t.Run("rotation and oversized items", func(t *testing.T) {
+		in := &Instance{Name: "tall", Class: "test", BinWidth: 10, BinHeight: 4, Items: []Item{{Width: 4, Height: 10, Demand: 3}, {Width: 11, Height: 1, Demand: 1}}}
+		strategy := []Strategy{{Name: "default"}}
+		fixed := Run([]*Instance{in}, Options{Strategies: strategy})[0]
+		rotated := Run([]*Instance{in}, Options{Strategies: strategy, AllowRotation: true})[0]
+		if fixed.Bins != 0 || fixed.Unpacked != 4 {
+			t.Errorf("fixed orientation: got %d bins and %d unpacked, want 0 and 4", fixed.Bins, fixed.Unpacked)
+		}
+		if rotated.Bins != 3 || rotated.Unpacked != 1 {
+			t.Errorf("with rotation: got %d bins and %d unpacked, want 3 and 1", rotated.Bins, rotated.Unpacked)
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	instances := append(GenerateSet(Classes[:1], []int{20}, 2, 1), GenerateSet(Classes[6:7], []int{20}, 2, 1)...)
+	strategies := DefaultStrategies[:2]
+	results := Run(instances, Options{Strategies: strategies})
+
+	summaries := Summarize(results)
+	if len(summaries) != 6 {
+		t.Fatalf("got %d summaries, want 2 classes and all, for 2 strategies", len(summaries))
+	}
+	all := summaries[4]
+	if all.Class != "all" || all.Strategy != strategies[0].Name || all.Instances != 4 {
+		t.Errorf("got %+v, want the first strategy over all 4 instances", all)
+	}
+	if want := summaries[0].Bins + summaries[2].Bins; all.Bins != want {
+		t.Errorf("got %d bins over all classes, want %d", all.Bins, want)
+	}
+	if all.Ratio() < 1 {
+		t.Errorf("got ratio %v, want at least 1", all.Ratio())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 7 || !strings.Contains(lines[0], "lower bound") || !strings.Contains(lines[1], "bw1") || !strings.Contains(lines[6], "all") {
+		t.Errorf("got report\n%s\nwant a header and 6 rows", buf.String())
+	}
+}
+
+// BenchmarkStrategies packs the classic test bed of 10 instances of each class and size
+// 20 to 100 with every default strategy, reporting the bins used above the lower bound.
+//
+//	go test ./bench -bench Strategies
+func BenchmarkStrategies(b *testing.B) {
+	instances := GenerateSet(Classes, []int{20, 40, 60, 80, 100}, 10, 1)
+	for _, strategy := range DefaultStrategies {
+		b.Run(strategy.Name, func(b *testing.B) {
+			var summary Summary
+			for i := 0; i < b.N; i++ {
+				summary = Summarize(Run(instances, Options{Strategies: []Strategy{strategy}}))[len(Classes)]
+			}
+			b.ReportMetric(float64(summary.Bins), "bins")
+			b.ReportMetric(summary.Ratio(), "bins/bound")
+			b.ReportMetric(summary.Efficiency, "%efficiency")
+		})
+	}
+}
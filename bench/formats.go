@@ -0,0 +1,196 @@
+package bench
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/acmacalister/binpacking"
+)
+
+// ErrInvalidInstance is returned when an instance file cannot be parsed.
+var ErrInvalidInstance = errors.New("bench: invalid instance")
+
+// parser walks the lines of an instance file that start with a number. The files
+// annotate values with trailing text, as in "20   N. OF ITEMS", which is ignored, as
+// are blank lines and lines of text.
+type parser struct {
+	values [][]float64
+	lines  []int // Line of each entry of values, for error messages
+	next   int
+}
+
+// newParser reads the number lines of r.
+func newParser(r io.Reader) (*parser, error) {
+	p := &parser{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		var numbers []float64
+		for _, field := range strings.Fields(scanner.Text()) {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				break
+			}
+			numbers = append(numbers, v)
+		}
+		if len(numbers) > 0 {
+			p.values = append(p.values, numbers)
+			p.lines = append(p.lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// more reports whether lines are left.
+func (p *parser) more() bool {
+	return p.next < len(p.values)
+}
+
+// numbers returns the numbers of the next line, which must hold at least n of them.
+func (p *parser) numbers(n int, what string) ([]float64, int, error) {
+	if !p.more() {
+		return nil, 0, fmt.Errorf("%w: unexpected end of file, want %s", ErrInvalidInstance, what)
+	}
+	values, line := p.values[p.next], p.lines[p.next]
+	p.next++
+	if len(values) < n {
+		return nil, 0, fmt.Errorf("%w: line %d: got %d numbers, want %s", ErrInvalidInstance, line, len(values), what)
+	}
+	return values, line, nil
+}
+
+// count returns the first number of the next line, which must be a non-negative integer.
+func (p *parser) count(what string) (int, error) {
+	values, line, err := p.numbers(1, what)
+	if err != nil {
+		return 0, err
+	}
+	return toCount(values[0], line, what)
+}
+
+// toCount converts v to a count, rejecting negative and fractional values.
+func toCount(v float64, line int, what string) (int, error) {
+	if v < 0 || v != float64(int(v)) {
+		return 0, fmt.Errorf("%w: line %d: %v is not %s", ErrInvalidInstance, line, v, what)
+	}
+	return int(v), nil
+}
+
+// size returns the numbers at positions i and j of the next line, validated as the
+// width and height of a rectangle.
+func (p *parser) size(n, i, j int, what string) ([]float64, float64, float64, error) {
+	values, line, err := p.numbers(n, what)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if err := binpacking.ValidateDimensions(values[i], values[j]); err != nil {
+		return nil, 0, 0, fmt.Errorf("line %d: %w", line, err)
+	}
+	return values, values[i], values[j], nil
+}
+
+// Read2BP reads a file of the Berkey–Wang and Martello–Vigo instances as distributed
+// by Lodi, Martello and Vigo, such as Class_01.2bp, which holds any number of instances
+// one after the other:
+//
+//	 1   PROBLEM CLASS
+//	20   N. OF ITEMS
+//	 1  1   RELATIVE AND ABSOLUTE N. OF INSTANCE
+//	10 10   HBIN,WBIN
+//	 3  7   H(I),W(I),I=1,...,N
+//	...
+//
+// Heights come before widths. The instances are named after the class, as in Classes,
+// and the absolute instance number, e.g. "bw1-1". The returned error wraps
+// ErrInvalidInstance or, for invalid sizes, binpacking.ErrInvalidDimensions.
+func Read2BP(r io.Reader) ([]*Instance, error) {
+	p, err := newParser(r)
+	if err != nil {
+		return nil, err
+	}
+	var instances []*Instance
+	for p.more() {
+		class, err := p.count("the problem class")
+		if err != nil {
+			return nil, err
+		}
+		n, err := p.count("the number of items")
+		if err != nil {
+			return nil, err
+		}
+		numbers, _, err := p.numbers(2, "the relative and absolute instance numbers")
+		if err != nil {
+			return nil, err
+		}
+		_, binHeight, binWidth, err := p.size(2, 0, 1, "the bin height and width")
+		if err != nil {
+			return nil, err
+		}
+		in := &Instance{
+			Name:  fmt.Sprintf("%s-%v", className(class), numbers[1]),
+			Class: className(class), BinWidth: binWidth, BinHeight: binHeight,
+			Items: make([]Item, 0, n),
+		}
+		for i := 0; i < n; i++ {
+			_, height, width, err := p.size(2, 0, 1, "an item height and width")
+			if err != nil {
+				return nil, err
+			}
+			in.Items = append(in.Items, Item{Width: width, Height: height, Demand: 1})
+		}
+		instances = append(instances, in)
+	}
+	return instances, nil
+}
+
+// className returns the name of a class in the numbering of Lodi, Martello and Vigo.
+func className(class int) string {
+	if class >= 1 && class <= len(Classes) {
+		return Classes[class-1].Name
+	}
+	return fmt.Sprintf("class%d", class)
+}
+
+// Read2DPackLib reads a bin packing instance in the format of 2DPackLib: the number of
+// item types, the bin width and height, and one line per item type with its number,
+// width, height and, optionally, demand, which defaults to 1. Further columns, such as
+// profits, are ignored. The instance gets the given name and class. The returned error
+// wraps ErrInvalidInstance or, for invalid sizes, binpacking.ErrInvalidDimensions.
+func Read2DPackLib(r io.Reader, name, class string) (*Instance, error) {
+	p, err := newParser(r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := p.count("the number of items")
+	if err != nil {
+		return nil, err
+	}
+	_, binWidth, binHeight, err := p.size(2, 0, 1, "the bin width and height")
+	if err != nil {
+		return nil, err
+	}
+	in := &Instance{Name: name, Class: class, BinWidth: binWidth, BinHeight: binHeight, Items: make([]Item, 0, m)}
+	for i := 0; i < m; i++ {
+		values, width, height, err := p.size(3, 1, 2, "an item number, width and height")
+		if err != nil {
+			return nil, err
+		}
+		item := Item{Width: width, Height: height, Demand: 1}
+		if len(values) > 3 {
+			if item.Demand, err = toCount(values[3], p.lines[p.next-1], "a demand"); err != nil {
+				return nil, err
+			}
+		}
+		in.Items = append(in.Items, item)
+	}
+	if p.more() {
+		return nil, fmt.Errorf("%w: line %d: got more than %d items", ErrInvalidInstance, p.lines[p.next], m)
+	}
+	return in, nil
+}
@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/acmacalister/binpacking"
+)
+
+const class1 = `    1                PROBLEM CLASS
+    3                N. OF ITEMS
+    1    1           RELATIVE AND ABSOLUTE N. OF INSTANCE
+   10   10           HBIN,WBIN
+    3    7           H(I),W(I),I=1,...,N
+   10    1
+    5    5
+
+    7                PROBLEM CLASS
+    2                N. OF ITEMS
+    1   61           RELATIVE AND ABSOLUTE N. OF INSTANCE
+  100  100           HBIN,WBIN
+   40   80           H(I),W(I),I=1,...,N
+   90   20
+`
+
+func TestRead2BP(t *testing.T) {
+	instances, err := Read2BP(strings.NewReader(class1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("got %d instances, want 2", len(instances))
+	}
+	first, second := instances[0], instances[1]
+	if first.Name != "bw1-1" || first.Class != "bw1" || first.BinWidth != 10 || first.BinHeight != 10 || len(first.Items) != 3 {
+		t.Errorf("got %+v, want bw1-1 with a 10x10 bin and 3 items", first)
+	}
+	if got, want := first.Items[0], (Item{Width: 7, Height: 3, Demand: 1}); got != want {
+		t.Errorf("got %+v, want %+v: heights come first", got, want)
+	}
+	if second.Name != "mv1-61" || second.Class != "mv1" || second.Count() != 2 || second.Items[1].Width != 20 {
+		t.Errorf("got %+v, want mv1-61 with 2 items", second)
+	}
+	if got := second.LowerBound(); got != 1 {
+		t.Errorf("got lower bound %d, want 1", got)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		truncated := class1[:strings.Index(class1, "   10    1")]
+		if _, err := Read2BP(strings.NewReader(truncated)); !errors.Is(err, ErrInvalidInstance) {
+			t.Errorf("truncated: got %v, want ErrInvalidInstance", err)
+		}
+		fractional := strings.Replace(class1, "    3                N. OF ITEMS", "  2.5                N. OF ITEMS", 1)
+		if _, err := Read2BP(strings.NewReader(fractional)); !errors.Is(err, ErrInvalidInstance) || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("fractional count: got %v, want ErrInvalidInstance at line 2", err)
+		}
+		negative := strings.Replace(class1, "   10    1", "  -10    1", 1)
+		if _, err := Read2BP(strings.NewReader(negative)); !errors.Is(err, binpacking.ErrInvalidDimensions) {
+			t.Errorf("negative size: got %v, want ErrInvalidDimensions", err)
+		}
+	})
+}
+
+func TestRead2DPackLib(t *testing.T) {
+	const file = "3\n100 50\n1 30 20 4 4 0\n2 50 50\n3 10 5 2\n"
+	in, err := Read2DPackLib(strings.NewReader(file), "cl_01_020_01", "cl01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Name != "cl_01_020_01" || in.Class != "cl01" || in.BinWidth != 100 || in.BinHeight != 50 {
+		t.Errorf("got %+v, want a 100x50 bin", in)
+	}
+	want := []Item{{30, 20, 4}, {50, 50, 1}, {10, 5, 2}}
+	if len(in.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(in.Items), len(want))
+	}
+	for i := range want {
+		if in.Items[i] != want[i] {
+			t.Errorf("item %d: got %+v, want %+v", i, in.Items[i], want[i])
+		}
+	}
+	if got := len(in.Boxes(false)); got != 7 {
+		t.Errorf("got %d boxes, want 7", got)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for name, file := range map[string]string{
+			"missing item":    "2\n100 50\n1 30 20\n",
+			"extra item":      "1\n100 50\n1 30 20\n2 30 20\n",
+			"negative demand": "1\n100 50\n1 30 20 -1\n",
+			"no height":       "1\n100 50\n1 30\n",
+		} {
+			if _, err := Read2DPackLib(strings.NewReader(file), name, ""); !errors.Is(err, ErrInvalidInstance) {
+				t.Errorf("%s: got %v, want ErrInvalidInstance", name, err)
+			}
+		}
+	})
+}
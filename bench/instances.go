@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Class is a family of randomly generated instances as defined in the literature.
+type Class struct {
+	Name      string
+	BinWidth  float64
+	BinHeight float64
+	// Item draws the size of one item.
+	Item func(rng *rand.Rand) (width, height float64)
+}
+
+// uniform returns an integer drawn uniformly from [lo, hi].
+func uniform(rng *rand.Rand, lo, hi int) float64 {
+	return float64(lo + rng.IntN(hi-lo+1))
+}
+
+// berkeyWang returns a class of Berkey and Wang (1987): square bins of the given size
+// and item sides drawn uniformly from [1, max].
+func berkeyWang(name string, bin float64, max int) Class {
+	return Class{Name: name, BinWidth: bin, BinHeight: bin, Item: func(rng *rand.Rand) (float64, float64) {
+		return uniform(rng, 1, max), uniform(rng, 1, max)
+	}}
+}
+
+// martelloVigo returns a class of Martello and Vigo (1998): 100 × 100 bins and items of
+// four types, the given one with probability 70% and each other one with 10%.
+//
+//	type 1: width in [2W/3, W], height in [1, H/2]
+//	type 2: width in [1, W/2],  height in [2H/3, H]
+//	type 3: width in [W/2, W],  height in [H/2, H]
+//	type 4: width in [1, W/2],  height in [1, H/2]
+func martelloVigo(name string, major int) Class {
+	return Class{Name: name, BinWidth: 100, BinHeight: 100, Item: func(rng *rand.Rand) (float64, float64) {
+		kind := major
+		if p := rng.IntN(10); p >= 7 {
+			kind = (major+p-7)%4 + 1 // The three other types share the remaining 30%
+		}
+		switch kind {
+		case 1:
+			return uniform(rng, 67, 100), uniform(rng, 1, 50)
+		case 2:
+			return uniform(rng, 1, 50), uniform(rng, 67, 100)
+		case 3:
+			return uniform(rng, 50, 100), uniform(rng, 50, 100)
+		default:
+			return uniform(rng, 1, 50), uniform(rng, 1, 50)
+		}
+	}}
+}
+
+// Classes are the ten classes used throughout the literature since Lodi, Martello and
+// Vigo (1999), in their order: classes 1 to 6 are those of Berkey and Wang, named bw1 to
+// bw6, and classes 7 to 10 those of Martello and Vigo, named mv1 to mv4.
+var Classes = []Class{
+	berkeyWang("bw1", 10, 10),
+	berkeyWang("bw2", 30, 10),
+	berkeyWang("bw3", 40, 35),
+	berkeyWang("bw4", 100, 35),
+	berkeyWang("bw5", 100, 100),
+	berkeyWang("bw6", 300, 100),
+	martelloVigo("mv1", 1),
+	martelloVigo("mv2", 2),
+	martelloVigo("mv3", 3),
+	martelloVigo("mv4", 4),
+}
+
+// Generate returns a random instance of the class with n items. The same seed always
+// gives the same instance. The items are those of the class definition, not of the
+// published files, so bins used are comparable across strategies but not with results
+// reported for the published instances; read those with Read2BP.
+func Generate(class Class, n int, seed uint64) *Instance {
+	rng := rand.New(rand.NewPCG(seed, uint64(n)))
+	in := &Instance{
+		Name:     fmt.Sprintf("%s-n%d-s%d", class.Name, n, seed),
+		Class:    class.Name,
+		BinWidth: class.BinWidth, BinHeight: class.BinHeight,
+		Items: make([]Item, n),
+	}
+	for i := range in.Items {
+		width, height := class.Item(rng)
+		in.Items[i] = Item{Width: width, Height: height, Demand: 1}
+	}
+	return in
+}
+
+// GenerateSet returns count instances of every class and size, the classic test bed
+// being sizes 20, 40, 60, 80 and 100 with 10 instances each. The instances of a class are
+// seeded from seed on.
+func GenerateSet(classes []Class, sizes []int, count int, seed uint64) []*Instance {
+	instances := make([]*Instance, 0, len(classes)*len(sizes)*count)
+	for _, class := range classes {
+		for _, n := range sizes {
+			for i := 0; i < count; i++ {
+				instances = append(instances, Generate(class, n, seed+uint64(i)))
+			}
+		}
+	}
+	return instances
+}
@@ -2,15 +2,84 @@ package binpacking
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 )
 
 // Bin represents a container for packing boxes.
+// A Bin is not safe for concurrent use, not even for queries such as CanFit, which may
+// initialize backend state; SyncBin serializes calls to one.
 type Bin struct {
 	Width      float64
 	Height     float64
 	Boxes      []*Box                // Boxes placed in this bin
 	Placement  PlacementStrategyFunc // Strategy used for finding placement positions
-	FreeSpaces []*FreeSpaceBox       // List of available free rectangles
+	FreeSpaces []*FreeSpaceBox       // List of available free rectangles; replace rather than edit in place
+	Cost       float64               // Optional price of the bin, reported by exporters
+	MaxWeight  float64               // Maximum total Box.Weight; zero or negative means unlimited
+
+	// Name, Material and Thickness describe the stock item the bin stands for, e.g. a
+	// SKU or sheet number, "birch plywood" and 18, so that results can refer to real
+	// stock. They are carried into serialized output but do not affect packing. Name is
+	// unrelated to Label, which describes the bin's size and utilization.
+	Name      string
+	Material  string
+	Thickness float64
+
+	// MaxFreeSpaces caps the number of free rectangles tracked by the bin.
+	// Once exceeded, the bin switches to a bounded maintenance mode that merges
+	// adjacent free spaces and keeps only the largest ones, trading some packing
+	// density for predictable Insert cost. Zero or negative means unlimited.
+	MaxFreeSpaces int
+
+	// Backend maintains the free area and finds placements. Nil uses MaxRectsBackend.
+	Backend Backend
+
+	// MinFreeWidth and MinFreeHeight discard free spaces narrower or shorter than the
+	// thresholds after each placement, so slivers no box can use neither influence scoring
+	// nor slow down pruning, and FreeSpaces reports only usable offcuts. Boxes smaller than
+	// the thresholds cannot use the discarded area. Zero keeps every free space. Skyline
+	// bins only discard waste map gaps, not the area above the skyline.
+	MinFreeWidth  float64
+	MinFreeHeight float64
+
+	// CheckLayout validates every placement against the rest of the bin, as ValidateLayout
+	// does, and panics if the layout is broken. It is a debugging aid for tests of custom
+	// backends and strategies, and costs time linear in the number of boxes per placement.
+	CheckLayout bool
+
+	// Defects are regions marked unusable with AddDefect; they survive emptying the bin.
+	Defects []FreeSpaceBox
+	// Margins is the unusable border set with SetMargins; boxes are placed inside it.
+	Margins Margins
+	// Spacing is the minimum gap, set with SetSpacing, kept between boxes and between
+	// boxes and the edges, margins and defects of the bin, e.g. the saw-blade kerf.
+	Spacing float64
+
+	// Tolerance is the distance by which geometric comparisons may be off and still
+	// succeed, forgiving the rounding errors of fractional sizes: a box fits a free space
+	// up to Tolerance narrower or shorter than itself, rectangles overlapping by no more
+	// than Tolerance do not intersect, free spaces within Tolerance of another are pruned
+	// as contained in it, and leftovers no larger are not kept as free space. Zero, the
+	// default, compares exactly; a good value is a small fraction of the smallest size
+	// that matters, such as 1e-9 for sizes in millimetres.
+	Tolerance float64
+
+	// MergeAdjacent merges free spaces that share a full edge after every change to the
+	// free list, as MergeFreeSpaces does, so fragmented offcuts still take boxes spanning
+	// them. It costs time quadratic in the number of free spaces per placement.
+	MergeAdjacent bool
+
+	// TieBreak selects the free space used when the placement strategy scores several
+	// the same. It applies to the MaxRects and Guillotine backends, which place boxes in
+	// free spaces; the others break ties their own way.
+	TieBreak SpaceTieBreak
+
+	generation uint64     // See Generation
+	index      *freeIndex // Index of FreeSpaces as of the last placement; see freeindex.go
+	compacted  bool       // Set once MaxFreeSpaces has been exceeded
+	pruned     int        // Free spaces removed as redundant so far, reported in debug logs
 }
 
 // NewBin creates a new Bin instance.
@@ -31,6 +100,20 @@ func NewBin(width float64, height float64, placement PlacementStrategyFunc) *Bin
 	}
 }
 
+// NewBinChecked is like NewBin but validates the dimensions first.
+// The returned error wraps ErrInvalidDimensions.
+func NewBinChecked(width float64, height float64, placement PlacementStrategyFunc) (*Bin, error) {
+	if err := ValidateDimensions(width, height); err != nil {
+		return nil, err
+	}
+	return NewBin(width, height, placement), nil
+}
+
+// Validate checks that the bin dimensions are usable for packing.
+func (b *Bin) Validate() error {
+	return ValidateDimensions(b.Width, b.Height)
+}
+
 // Area returns the total area of the bin.
 func (b *Bin) Area() float64 {
 	return b.Width * b.Height
@@ -39,16 +122,31 @@ func (b *Bin) Area() float64 {
 // Efficiency calculates the percentage of the bin's area occupied by packed boxes.
 // Returns a float64 between 0 and 100.
 func (b *Bin) Efficiency() float64 {
-	boxesArea := float64(0)
+	if b.Width == 0 || b.Height == 0 {
+		return 0.0 // Avoid division by zero
+	}
+	// Sum each box's share of the bin rather than dividing total areas, so the
+	// intermediate values stay small and cannot overflow for huge dimensions. Round
+	// boxes count with the area of their circle, as in PackResult.Efficiency.
+	usedFraction := float64(0)
 	for _, box := range b.Boxes {
-		boxesArea += box.Area()
+		usedFraction += box.Area() / b.Width / b.Height
 	}
-	binArea := b.Area()
-	if binArea == 0 {
-		return 0.0 // Avoid division by zero
+	return usedFraction * 100.0
+}
+
+// Weight returns the total weight of the boxes in the bin.
+func (b *Bin) Weight() float64 {
+	total := 0.0
+	for _, box := range b.Boxes {
+		total += box.Weight
 	}
-	// Use float64 for calculation to get percentage
-	return boxesArea * 100.0 / binArea
+	return total
+}
+
+// fitsWeight reports whether adding box keeps the bin within MaxWeight.
+func (b *Bin) fitsWeight(box *Box) bool {
+	return b.MaxWeight <= 0 || b.Weight()+box.Weight <= b.MaxWeight
 }
 
 // Label returns a string representation of the bin including dimensions and efficiency.
@@ -61,13 +159,21 @@ func (b *Bin) Label() string {
 // It updates the bin's state (Boxes, FreeSpaces) if successful.
 // Returns true if the box was successfully packed, false otherwise.
 func (b *Bin) Insert(box *Box) bool {
-	if box.Packed {
+	return b.InsertWith(box, nil)
+}
+
+// InsertWith is like Insert but applies per-box overrides of the bin's settings,
+// such as a different placement strategy. A nil options value behaves like Insert.
+func (b *Bin) InsertWith(box *Box, options *TagOptions) bool {
+	if box.Packed || !b.accepts(box) {
 		return false
 	}
 
-	placement := FindBestPlacement(box, b.FreeSpaces, b.Placement)
+	backend := b.backend()
+	candidate := b.padded(options.candidate(box))
+	placement := backend.FindPlacement(b, candidate, options.placement(b))
 
-	if !placement.Fits {
+	if !placement.Fits || !b.anchoring(box.Anchor, false).allows(placement, candidate) {
 		return false // No suitable placement found
 	}
 
@@ -76,48 +182,272 @@ func (b *Bin) Insert(box *Box) bool {
 	box.Y = placement.Y
 	box.Packed = true
 	if placement.NeedsRotation {
+		turn(box)
+	}
+	if box.cluster != nil {
+		box.cluster.place() // Propagate the placement to the kit's members
+	}
+
+	// Let the backend update its free area representation, including the spacing
+	backend.Place(b, b.padded(box), placement)
+	b.settleFreeSpaces()
+	b.indexFreeSpaces()
+	b.Boxes = append(b.Boxes, box)
+	b.touch()
+	b.checkPlaced(box)
+
+	return true
+}
+
+// InsertChecked is Insert reporting why a box was not placed. The returned error wraps
+// ErrInvalidDimensions for a box of invalid size, ErrAlreadyPacked for a packed box,
+// ErrWrongMaterial if the bin's Material is not one the box accepts, ErrExceedsWeight
+// if the box would take the bin over its MaxWeight, or ErrNoFit if there is no room
+// for it.
+func (b *Bin) InsertChecked(box *Box) error {
+	if err := box.Validate(); err != nil {
+		return err
+	}
+	if box.Packed {
+		return fmt.Errorf("%w: box %s", ErrAlreadyPacked, box.Label())
+	}
+	if !b.fitsMaterial(box) {
+		return fmt.Errorf("%w: box %s is not of the bin's material %q", ErrWrongMaterial, box.Label(), b.Material)
+	}
+	if !b.fitsWeight(box) {
+		return fmt.Errorf("%w: box %s weighs %g, the bin holds %g of %g", ErrExceedsWeight, box.Label(), box.Weight, b.Weight(), b.MaxWeight)
+	}
+	if !b.Insert(box) {
+		return fmt.Errorf("%w: no room for box %s %gx%g in the %gx%g bin", ErrNoFit, box.Label(), box.Width, box.Height, b.Width, b.Height)
+	}
+	return nil
+}
+
+// commit places box at the given position without consulting the backend's placement
+// search, rotating or mirroring it first if requested. The free list is updated with a MaxRects
+// split, so this is only valid for bins using the default backend.
+func (b *Bin) commit(box *Box, x, y float64, rotate, mirror bool) {
+	if rotate {
 		box.Rotate()
 	}
+	if mirror {
+		box.Mirror()
+	}
+	box.X, box.Y = x, y
+	box.Packed = true
+	if box.cluster != nil {
+		box.cluster.place()
+	}
+	b.splitFreeSpaces(box)
+	b.enforceFreeSpaceLimit()
+	b.settleFreeSpaces()
+	b.indexFreeSpaces()
+	b.Boxes = append(b.Boxes, box)
+	b.touch()
+	b.checkPlaced(box)
+}
+
+// scratch returns a copy of the bin that search algorithms can pack into without
+// affecting b. Boxes already in the bin are shared, since packing never moves them.
+// Skyline and grid state is copied; the other built-in backends are stateless and shared.
+// The built-in strategies bound to b, such as ContactPointFit, are bound to the copy, so
+// that it scores against its own contents; other Placement closures are shared.
+func (b *Bin) scratch() *Bin {
+	clone := *b
+	clone.Placement = strategyFor(b.Placement, &clone)
+	clone.Boxes = slices.Clone(b.Boxes)
+	clone.Defects = slices.Clone(b.Defects)
+	clone.FreeSpaces = make([]*FreeSpaceBox, len(b.FreeSpaces))
+	for i, space := range b.FreeSpaces {
+		copied := *space
+		clone.FreeSpaces[i] = &copied
+	}
+	switch backend := b.Backend.(type) {
+	case *SkylineBackend:
+		clone.Backend = backend.clone()
+	case *GridBackend:
+		clone.Backend = backend.clone()
+	}
+	return &clone
+}
+
+// trialBox returns a copy of the box that a trial packing can place without affecting
+// box. A cluster's bounding box is copied without its cluster, so that placing the copy
+// does not move the members of the real cluster.
+func trialBox(box *Box) *Box {
+	copied := *box
+	copied.cluster = nil
+	return &copied
+}
+
+// trialCopies returns scratch copies of the bins and trial copies of the boxes, index
+// for index, for a trial packing that leaves the originals untouched. Nil entries stay
+// nil.
+func trialCopies(bins []*Bin, boxes []*Box) ([]*Bin, []*Box) {
+	scratchBins := make([]*Bin, len(bins))
+	for i, bin := range bins {
+		if bin != nil {
+			scratchBins[i] = bin.scratch()
+		}
+	}
+	copies := make([]*Box, len(boxes))
+	for i, box := range boxes {
+		if box != nil {
+			copies[i] = trialBox(box)
+		}
+	}
+	return scratchBins, copies
+}
+
+// reset removes all boxes from the bin, leaving it as it was when created apart from
+// its margins and defects. The boxes themselves are not modified.
+func (b *Bin) reset() {
+	b.Boxes = make([]*Box, 0)
+	b.FreeSpaces = []*FreeSpaceBox{{Width: b.Width, Height: b.Height}}
+	b.restoreFreeSpace()
+}
 
-	// Split the chosen free space
+// Clear removes all boxes from the bin and marks them unpacked, restoring the free space
+// the bin had when created: the whole bin less its margins, defects and spacing. Settings
+// such as the placement strategy, backend and weight capacity are kept, and the Boxes and
+// FreeSpaces slices keep their capacity, so a cleared bin can be reused for the next
+// request without reallocating. Locked boxes are removed too.
+func (b *Bin) Clear() {
+	for _, box := range b.Boxes {
+		box.Packed = false
+	}
+	clear(b.Boxes)
+	b.Boxes = b.Boxes[:0]
+	clear(b.FreeSpaces)
+	b.FreeSpaces = append(b.FreeSpaces[:0], &FreeSpaceBox{Width: b.Width, Height: b.Height})
+	b.index, b.pruned = nil, 0
+	b.restoreFreeSpace()
+}
+
+// restoreFreeSpace rebuilds the backend state and blocked regions of a bin whose free
+// list has just been reset to the whole bin.
+func (b *Bin) restoreFreeSpace() {
+	b.compacted = false
+	switch backend := b.Backend.(type) {
+	case *SkylineBackend:
+		backend.nodes, backend.waste = nil, nil
+	case *GridBackend:
+		backend.reset(b)
+	}
+	if placer, ok := b.backend().(FixedPlacer); ok {
+		for _, region := range b.blocked() {
+			placer.PlaceFixed(b, b.padded(defectBox(region)))
+		}
+	}
+	b.settleFreeSpaces()
+	b.touch()
+}
+
+// usesMaxRects reports whether the bin uses the default MaxRects backend.
+func (b *Bin) usesMaxRects() bool {
+	switch b.Backend.(type) {
+	case nil, MaxRectsBackend, *MaxRectsBackend:
+		return true
+	}
+	return false
+}
+
+// splitFreeSpaces removes the area of a placed box from every free space it intersects.
+func (b *Bin) splitFreeSpaces(box *Box) {
+	// If the list was pruned at the last placement, only the new spaces need pruning.
+	index := b.currentIndex()
+	tidy := index != nil && index.tidy
 	newFreeSpaces := make([]*FreeSpaceBox, 0, len(b.FreeSpaces)+3) // Estimate capacity
+	var fresh []int                                                // Positions of the new spaces
 
 	for i := 0; i < len(b.FreeSpaces); i++ {
 		currentFreeSpace := b.FreeSpaces[i]
-		if currentFreeSpace == placement.ChosenSpace {
+		// Free spaces overlap each other, so every space the box intersects must be split,
+		// not only the chosen one. Otherwise later boxes could be placed over this one.
+		if intersects(currentFreeSpace, box, b.tolerance()) {
 			// Split this node, potentially adding 0-4 new nodes directly
-			// The split function should probably return the new nodes instead of modifying b.FreeSpaces directly
-			generatedSpaces := b.generateSplits(currentFreeSpace, box) // New helper needed
+			generatedSpaces := b.generateSplits(currentFreeSpace, box)
+			if tidy {
+				for k := range generatedSpaces {
+					fresh = append(fresh, len(newFreeSpaces)+k)
+				}
+			}
 			newFreeSpaces = append(newFreeSpaces, generatedSpaces...)
 		} else {
-			// Keep non-chosen, non-split nodes
+			// Keep nodes untouched by the placement
 			newFreeSpaces = append(newFreeSpaces, currentFreeSpace)
 		}
 	}
 
 	b.FreeSpaces = newFreeSpaces
-	b.pruneFreeList()
-	b.Boxes = append(b.Boxes, box)
-
-	return true
+	if tidy {
+		b.pruneNewSpaces(fresh)
+	} else {
+		b.pruneFreeList()
+	}
 }
 
 // ScoreFor simulates placing the box and returns the score without modifying the bin.
-// It creates a copy of the box to avoid side effects.
-func (b *Bin) ScoreFor(box *Box) float64 {
+// It modifies neither the box nor the bin, and with the MaxRects and Guillotine backends
+// it does not allocate. Returns NoFit if the box cannot be placed.
+func (b *Bin) ScoreFor(box *Box) Score {
+	return b.ScoreForWith(box, nil)
+}
+
+// ScoreForWith is like ScoreFor but applies per-box overrides of the bin's settings.
+// A nil options value behaves like ScoreFor.
+func (b *Bin) ScoreForWith(box *Box, options *TagOptions) Score {
+	return b.placementFor(box, options).Score
+}
+
+// CanFit reports whether Insert would place the box right now, and the placement it
+// would use: the position, the rotation relative to the box's current orientation and
+// the score. Like ScoreFor it modifies neither the box nor the bin. ChosenSpace belongs
+// to the bin's free list and is stale once the bin changes. A packed box never fits.
+func (b *Bin) CanFit(box *Box) (PlacementInfo, bool) {
+	return b.CanFitWith(box, nil)
+}
+
+// CanFitWith is like CanFit but applies per-box overrides of the bin's settings, as
+// InsertWith does. A nil options value behaves like CanFit.
+func (b *Bin) CanFitWith(box *Box, options *TagOptions) (PlacementInfo, bool) {
+	if box.Packed {
+		return PlacementInfo{Score: NoFit}, false
+	}
+	placement := b.placementFor(box, options)
+	return placement, placement.Fits
+}
+
+// placementFor is ScoreForWith returning the whole placement, including the free space
+// it would use.
+func (b *Bin) placementFor(box *Box, options *TagOptions) PlacementInfo {
+	// Placements into bins of another material, or that would overload the bin, are
+	// rejected before any geometry is considered.
+	if !b.accepts(box) {
+		return PlacementInfo{Score: NoFit}
+	}
+	// The backends that search the free list are scored directly on the box's size,
+	// which allocates nothing; scoring runs for every entry after every placement.
+	switch b.Backend.(type) {
+	case nil, MaxRectsBackend, *MaxRectsBackend, *GuillotineBackend:
+		return b.findFree(b.sizeFor(box, options), options.placement(b))
+	}
 	// Create a copy to pass to the placement strategy, so the original box isn't modified.
-	// Assumes NewBox creates a clean copy with dimensions and rotation constraint.
-	copyBox := NewBox(box.Width, box.Height, box.ConstrainRotation)
+	copyBox := b.padded(options.candidate(box))
 	// The placement will find the position but won't modify the original box or bin state.
-	placement := FindBestPlacement(copyBox, b.FreeSpaces, b.Placement)
-	return placement.Score
+	placement := b.backend().FindPlacement(b, copyBox, options.placement(b))
+	if !b.anchoring(box.Anchor, false).allows(placement, copyBox) {
+		return PlacementInfo{Score: NoFit} // Backends searching positions of their own ignore anchors
+	}
+	return placement
 }
 
 // IsLargerThan checks if the bin is large enough to potentially hold the box
 // (considering rotation if allowed by the box).
 func (b *Bin) IsLargerThan(box *Box) bool {
-	canFitOriginal := b.Width >= box.Width && b.Height >= box.Height
-	canFitRotated := !box.ConstrainRotation && b.Height >= box.Width && b.Width >= box.Height
+	tol := b.tolerance()
+	canFitOriginal := b.Width+tol >= box.Width && b.Height+tol >= box.Height
+	canFitRotated := (!box.ConstrainRotation || box.AllowMirror) && b.Height+tol >= box.Width && b.Width+tol >= box.Height
 	return canFitOriginal || canFitRotated
 }
 
@@ -125,20 +455,17 @@ func (b *Bin) IsLargerThan(box *Box) bool {
 func (b *Bin) generateSplits(freeNode *FreeSpaceBox, usedNode *Box) []*FreeSpaceBox {
 	// Based on your original split logic, but appends to a local slice instead of b.FreeSpaces
 	splits := make([]*FreeSpaceBox, 0, 4)
+	tol := b.tolerance() // Leftovers no larger are not kept
 
-	// Separating Axis Theorem (SAT) intersection test.
-	if usedNode.X >= freeNode.X+freeNode.Width ||
-		usedNode.X+usedNode.Width <= freeNode.X ||
-		usedNode.Y >= freeNode.Y+freeNode.Height ||
-		usedNode.Y+usedNode.Height <= freeNode.Y {
-		// Should not happen if called on the chosen node, but check anyway
+	if !intersects(freeNode, usedNode, tol) {
+		// Should not happen if called on an intersecting node, but check anyway
 		return splits // Return empty slice
 	}
 
 	// Try vertical splits (Top/Bottom)
 	if usedNode.X < freeNode.X+freeNode.Width && usedNode.X+usedNode.Width > freeNode.X {
 		// Top
-		if usedNode.Y > freeNode.Y {
+		if usedNode.Y > freeNode.Y+tol {
 			newNode := *freeNode
 			newNode.Height = usedNode.Y - newNode.Y
 			splits = append(splits, &newNode)
@@ -146,7 +473,7 @@ func (b *Bin) generateSplits(freeNode *FreeSpaceBox, usedNode *Box) []*FreeSpace
 		// Bottom
 		usedBottomY := usedNode.Y + usedNode.Height
 		freeBottomY := freeNode.Y + freeNode.Height
-		if usedBottomY < freeBottomY {
+		if usedBottomY < freeBottomY-tol {
 			newNode := *freeNode
 			newNode.Y = usedBottomY
 			newNode.Height = freeBottomY - usedBottomY
@@ -157,7 +484,7 @@ func (b *Bin) generateSplits(freeNode *FreeSpaceBox, usedNode *Box) []*FreeSpace
 	// Try horizontal splits (Left/Right)
 	if usedNode.Y < freeNode.Y+freeNode.Height && usedNode.Y+usedNode.Height > freeNode.Y {
 		// Left
-		if usedNode.X > freeNode.X {
+		if usedNode.X > freeNode.X+tol {
 			newNode := *freeNode
 			newNode.Width = usedNode.X - newNode.X
 			splits = append(splits, &newNode)
@@ -165,7 +492,7 @@ func (b *Bin) generateSplits(freeNode *FreeSpaceBox, usedNode *Box) []*FreeSpace
 		// Right
 		usedRightX := usedNode.X + usedNode.Width
 		freeRightX := freeNode.X + freeNode.Width
-		if usedRightX < freeRightX {
+		if usedRightX < freeRightX-tol {
 			newNode := *freeNode
 			newNode.X = usedRightX
 			newNode.Width = freeRightX - usedRightX
@@ -176,6 +503,15 @@ func (b *Bin) generateSplits(freeNode *FreeSpaceBox, usedNode *Box) []*FreeSpace
 	return splits
 }
 
+// intersects reports whether the box overlaps the free space by more than tol.
+// Uses the Separating Axis Theorem (SAT): rectangles touching only at an edge do not intersect.
+func intersects(freeNode *FreeSpaceBox, usedNode *Box, tol float64) bool {
+	return usedNode.X < freeNode.X+freeNode.Width-tol &&
+		usedNode.X+usedNode.Width > freeNode.X+tol &&
+		usedNode.Y < freeNode.Y+freeNode.Height-tol &&
+		usedNode.Y+usedNode.Height > freeNode.Y+tol
+}
+
 // pruneFreeList removes redundant free spaces (those fully contained within another).
 func (b *Bin) pruneFreeList() {
 	// Create a new list to store non-contained free spaces.
@@ -192,7 +528,9 @@ func (b *Bin) pruneFreeList() {
 				continue // Don't compare with self
 			}
 			rectB := b.FreeSpaces[j]
-			if b.isContainedIn(rectA, rectB) {
+			// Identical rectangles, or ones within Tolerance of each other, contain each
+			// other; keep the first occurrence only.
+			if b.isContainedIn(rectA, rectB) && (j < i || !b.isContainedIn(rectB, rectA)) {
 				isContained = true
 				break // Found a container, no need to check further
 			}
@@ -204,17 +542,160 @@ func (b *Bin) pruneFreeList() {
 		}
 	}
 
+	b.pruned += len(b.FreeSpaces) - len(prunedList)
+	b.FreeSpaces = prunedList
+}
+
+// pruneNewSpaces is pruneFreeList for a list in which only the spaces at the positions
+// given in fresh, in increasing order, are new. The other spaces were pruned before and
+// do not contain one another, so only pairs with a new space need testing, which takes
+// O(n·k) for k new spaces instead of O(n²). The result is the same as pruneFreeList's.
+func (b *Bin) pruneNewSpaces(fresh []int) {
+	prunedList := make([]*FreeSpaceBox, 0, len(b.FreeSpaces))
+	// contained reports whether the space at i is contained in the one at j, keeping the
+	// first of identical spaces like pruneFreeList.
+	contained := func(i, j int) bool {
+		if i == j {
+			return false
+		}
+		rectA, rectB := b.FreeSpaces[i], b.FreeSpaces[j]
+		return b.isContainedIn(rectA, rectB) && (j < i || !b.isContainedIn(rectB, rectA))
+	}
+
+	next := 0 // Index into fresh of the next new space
+	for i, rectA := range b.FreeSpaces {
+		isContained := false
+		if next < len(fresh) && fresh[next] == i {
+			next++
+			// A new space may be contained in any other space.
+			for j := range b.FreeSpaces {
+				if contained(i, j) {
+					isContained = true
+					break
+				}
+			}
+		} else {
+			// An old space may only be contained in a new one.
+			for _, j := range fresh {
+				if contained(i, j) {
+					isContained = true
+					break
+				}
+			}
+		}
+		if !isContained {
+			prunedList = append(prunedList, rectA)
+		}
+	}
+
+	b.pruned += len(b.FreeSpaces) - len(prunedList)
 	b.FreeSpaces = prunedList
 }
 
-// isContainedIn checks if rectA is fully contained within rectB.
+// isContainedIn checks if rectA is fully contained within rectB, up to the bin's Tolerance.
 func (b *Bin) isContainedIn(rectA, rectB *FreeSpaceBox) bool {
 	// Basic nil check for safety, although unlikely if called from pruneFreeList
 	if rectA == nil || rectB == nil {
 		return false
 	}
-	return rectA.X >= rectB.X &&
-		rectA.Y >= rectB.Y &&
-		rectA.X+rectA.Width <= rectB.X+rectB.Width &&
-		rectA.Y+rectA.Height <= rectB.Y+rectB.Height
+	tol := b.tolerance()
+	return rectA.X+tol >= rectB.X &&
+		rectA.Y+tol >= rectB.Y &&
+		rectA.X+rectA.Width <= rectB.X+rectB.Width+tol &&
+		rectA.Y+rectA.Height <= rectB.Y+rectB.Height+tol
+}
+
+// tolerance returns the bin's Tolerance, treating negative and NaN values as zero.
+func (b *Bin) tolerance() float64 {
+	if b.Tolerance > 0 {
+		return b.Tolerance
+	}
+	return 0
+}
+
+// Compacted reports whether the bin has exceeded MaxFreeSpaces and switched to
+// its bounded free-space maintenance mode.
+func (b *Bin) Compacted() bool {
+	return b.compacted
+}
+
+// enforceFreeSpaceLimit keeps the free list within MaxFreeSpaces.
+// The first time the limit is exceeded the bin is marked as compacted; from then
+// on every insert merges adjacent free spaces and drops the smallest ones.
+func (b *Bin) enforceFreeSpaceLimit() {
+	if b.MaxFreeSpaces <= 0 {
+		return // Unlimited
+	}
+	if !b.compacted && len(b.FreeSpaces) <= b.MaxFreeSpaces {
+		return // Still within budget, keep the exact representation
+	}
+	b.compacted = true
+
+	b.mergeFreeList()
+	if len(b.FreeSpaces) <= b.MaxFreeSpaces {
+		return
+	}
+
+	// Keep the largest rectangles; they are the ones most likely to hold future boxes.
+	// A stable sort keeps the result independent of map or scheduling order.
+	sort.SliceStable(b.FreeSpaces, func(i, j int) bool {
+		return b.FreeSpaces[i].Width*b.FreeSpaces[i].Height > b.FreeSpaces[j].Width*b.FreeSpaces[j].Height
+	})
+	b.FreeSpaces = b.FreeSpaces[:b.MaxFreeSpaces]
+}
+
+// mergeFreeList combines free spaces that share a full edge, up to the bin's Tolerance,
+// into a single rectangle, repeating until no more merges are possible, and returns the
+// number of merges.
+func (b *Bin) mergeFreeList() int {
+	var merges int
+	b.FreeSpaces, merges = mergeRects(b.FreeSpaces, b.tolerance())
+	b.pruneFreeList()
+	return merges
+}
+
+// mergeRects merges the rectangles as mergeFreeList does, in place.
+func mergeRects(spaces []*FreeSpaceBox, tol float64) ([]*FreeSpaceBox, int) {
+	merges := 0
+	merged := true
+	for merged {
+		merged = false
+		for i := 0; i < len(spaces) && !merged; i++ {
+			for j := i + 1; j < len(spaces); j++ {
+				rectA, rectB := spaces[i], spaces[j]
+				if union, ok := mergeFreeSpaces(rectA, rectB, tol); ok {
+					spaces[i] = union
+					spaces = append(spaces[:j], spaces[j+1:]...)
+					merged = true
+					merges++
+					break
+				}
+			}
+		}
+	}
+	return spaces, merges
+}
+
+// mergeFreeSpaces returns the union of two free spaces if they share a full edge,
+// i.e. the union is itself a rectangle. Edges and sizes may differ by up to tol; the
+// union then keeps to the narrower of the two, so it covers only free area.
+func mergeFreeSpaces(rectA, rectB *FreeSpaceBox, tol float64) (*FreeSpaceBox, bool) {
+	near := func(a, b float64) bool { return math.Abs(a-b) <= tol }
+	// Same column, stacked vertically
+	if near(rectA.X, rectB.X) && near(rectA.Width, rectB.Width) {
+		if near(rectA.Y+rectA.Height, rectB.Y) || near(rectB.Y+rectB.Height, rectA.Y) {
+			x := max(rectA.X, rectB.X)
+			y := min(rectA.Y, rectB.Y)
+			return &FreeSpaceBox{X: x, Y: y, Width: min(rectA.X+rectA.Width, rectB.X+rectB.Width) - x, Height: max(rectA.Y+rectA.Height, rectB.Y+rectB.Height) - y}, true
+		}
+	}
+	// Same row, side by side
+	if near(rectA.Y, rectB.Y) && near(rectA.Height, rectB.Height) {
+		if near(rectA.X+rectA.Width, rectB.X) || near(rectB.X+rectB.Width, rectA.X) {
+			x := min(rectA.X, rectB.X)
+			y := max(rectA.Y, rectB.Y)
+			return &FreeSpaceBox{X: x, Y: y, Width: max(rectA.X+rectA.Width, rectB.X+rectB.Width) - x, Height: min(rectA.Y+rectA.Height, rectB.Y+rectB.Height) - y}, true
+		}
+	}
+	return nil, false
 }
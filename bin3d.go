@@ -0,0 +1,213 @@
+package binpacking
+
+import (
+	"fmt"
+	"math"
+)
+
+// PlacementStrategy3DFunc scores how well a cuboid of the given extents fits into a
+// specific FreeSpace3D. Lower scores are considered better fits; returning NoFit
+// rejects the placement.
+type PlacementStrategy3DFunc func(freeSpace *FreeSpace3D, width, height, depth float64) Score
+
+// PlacementInfo3D holds the details about the best placement found for a Box3D.
+type PlacementInfo3D struct {
+	Score       Score        // Quality of the placement; NoFit if Fits is false
+	ChosenSpace *FreeSpace3D // Free space the box is placed in; nil if Fits is false
+	X, Y, Z     float64      // Position of the corner closest to the origin
+	Orientation Orientation  // Orientation to apply to the box
+	Fits        bool         // Whether a placement was found
+}
+
+// Bin3D represents a container, such as a shipping container or carton, for packing Box3D items.
+type Bin3D struct {
+	Width      float64                 // Extent along X
+	Height     float64                 // Extent along Y (vertical)
+	Depth      float64                 // Extent along Z
+	Boxes      []*Box3D                // Boxes placed in this bin
+	Placement  PlacementStrategy3DFunc // Strategy used for finding placement positions
+	FreeSpaces []*FreeSpace3D          // Empty maximal spaces
+}
+
+// NewBin3D creates a new Bin3D instance. A nil placement uses BestVolumeFit.
+func NewBin3D(width, height, depth float64, placement PlacementStrategy3DFunc) *Bin3D {
+	if placement == nil {
+		placement = BestVolumeFit
+	}
+	return &Bin3D{
+		Width:      width,
+		Height:     height,
+		Depth:      depth,
+		Boxes:      make([]*Box3D, 0),
+		Placement:  placement,
+		FreeSpaces: []*FreeSpace3D{{Width: width, Height: height, Depth: depth}},
+	}
+}
+
+// Volume returns the total volume of the bin.
+func (b *Bin3D) Volume() float64 {
+	return b.Width * b.Height * b.Depth
+}
+
+// Efficiency calculates the percentage of the bin's volume occupied by packed boxes.
+func (b *Bin3D) Efficiency() float64 {
+	if b.Width == 0 || b.Height == 0 || b.Depth == 0 {
+		return 0.0 // Avoid division by zero
+	}
+	usedFraction := float64(0)
+	for _, box := range b.Boxes {
+		usedFraction += (box.Width / b.Width) * (box.Height / b.Height) * (box.Depth / b.Depth)
+	}
+	return usedFraction * 100.0
+}
+
+// Label returns a string representation of the bin including dimensions and efficiency.
+func (b *Bin3D) Label() string {
+	return fmt.Sprintf("%gx%gx%g %.2f%%", b.Width, b.Height, b.Depth, b.Efficiency())
+}
+
+// Insert attempts to place a box into the bin, trying every allowed orientation.
+// Returns true if the box was successfully packed, false otherwise.
+func (b *Bin3D) Insert(box *Box3D) bool {
+	if box.Packed {
+		return false
+	}
+	placement := FindBestPlacement3D(box, b.FreeSpaces, b.Placement)
+	if !placement.Fits {
+		return false
+	}
+
+	box.Width, box.Height, box.Depth = box.Oriented(placement.Orientation)
+	box.Orientation = placement.Orientation
+	box.X, box.Y, box.Z = placement.X, placement.Y, placement.Z
+	box.Packed = true
+
+	// Split every free space the box intersects into up to six smaller maximal spaces.
+	newFreeSpaces := make([]*FreeSpace3D, 0, len(b.FreeSpaces)+6)
+	for _, space := range b.FreeSpaces {
+		if intersects3D(space, box) {
+			newFreeSpaces = append(newFreeSpaces, splitFreeSpace3D(space, box)...)
+		} else {
+			newFreeSpaces = append(newFreeSpaces, space)
+		}
+	}
+	b.FreeSpaces = pruneFreeSpaces3D(newFreeSpaces)
+	b.Boxes = append(b.Boxes, box)
+	return true
+}
+
+// ScoreFor returns the score of the best placement of box without modifying the bin.
+func (b *Bin3D) ScoreFor(box *Box3D) Score {
+	return FindBestPlacement3D(box, b.FreeSpaces, b.Placement).Score
+}
+
+// FindBestPlacement3D searches the free spaces for the best position and orientation
+// of the box according to the strategy.
+func FindBestPlacement3D(box *Box3D, freeSpaces []*FreeSpace3D, placement PlacementStrategy3DFunc) PlacementInfo3D {
+	best := PlacementInfo3D{Score: NoFit}
+	orientations := box.Orientations()
+	for _, space := range freeSpaces {
+		for _, o := range orientations {
+			w, h, d := box.Oriented(o)
+			if w > space.Width || h > space.Height || d > space.Depth {
+				continue
+			}
+			score := placement(space, w, h, d)
+			if score.Less(best.Score) {
+				best = PlacementInfo3D{
+					Score:       score,
+					ChosenSpace: space,
+					X:           space.X,
+					Y:           space.Y,
+					Z:           space.Z,
+					Orientation: o,
+					Fits:        true,
+				}
+			}
+		}
+	}
+	return best
+}
+
+// BestVolumeFit minimizes the leftover volume of the free space, using the smallest
+// leftover extent as a tie-breaker. Lower scores are better.
+func BestVolumeFit(freeSpace *FreeSpace3D, width, height, depth float64) Score {
+	// Expressed as a sum of leftover slabs to avoid subtracting two huge products.
+	leftOverVolume := (freeSpace.Width-width)*freeSpace.Height*freeSpace.Depth +
+		width*(freeSpace.Height-height)*freeSpace.Depth +
+		width*height*(freeSpace.Depth-depth)
+	shortSide := math.Min(freeSpace.Width-width, math.Min(freeSpace.Height-height, freeSpace.Depth-depth))
+	return NewScoreWithTieBreak(leftOverVolume, shortSide)
+}
+
+// BottomBackLeft mirrors BottomLeft in three dimensions: it minimizes the top of the box
+// (Y + height), breaking ties by Z + X, so boxes settle on the floor first and then
+// towards the back and left walls.
+func BottomBackLeft(freeSpace *FreeSpace3D, width, height, depth float64) Score {
+	return NewScoreWithTieBreak(freeSpace.Y+height, freeSpace.Z+freeSpace.X)
+}
+
+// intersects3D reports whether the box overlaps the free space (touching is not overlapping).
+func intersects3D(space *FreeSpace3D, box *Box3D) bool {
+	return box.X < space.X+space.Width && box.X+box.Width > space.X &&
+		box.Y < space.Y+space.Height && box.Y+box.Height > space.Y &&
+		box.Z < space.Z+space.Depth && box.Z+box.Depth > space.Z
+}
+
+// splitFreeSpace3D returns the maximal spaces of space that remain free around box.
+func splitFreeSpace3D(space *FreeSpace3D, box *Box3D) []*FreeSpace3D {
+	splits := make([]*FreeSpace3D, 0, 6)
+	if box.X > space.X { // Left
+		s := *space
+		s.Width = box.X - space.X
+		splits = append(splits, &s)
+	}
+	if right := box.X + box.Width; right < space.X+space.Width { // Right
+		s := *space
+		s.X, s.Width = right, space.X+space.Width-right
+		splits = append(splits, &s)
+	}
+	if box.Y > space.Y { // Below
+		s := *space
+		s.Height = box.Y - space.Y
+		splits = append(splits, &s)
+	}
+	if top := box.Y + box.Height; top < space.Y+space.Height { // Above
+		s := *space
+		s.Y, s.Height = top, space.Y+space.Height-top
+		splits = append(splits, &s)
+	}
+	if box.Z > space.Z { // Behind
+		s := *space
+		s.Depth = box.Z - space.Z
+		splits = append(splits, &s)
+	}
+	if front := box.Z + box.Depth; front < space.Z+space.Depth { // In front
+		s := *space
+		s.Z, s.Depth = front, space.Z+space.Depth-front
+		splits = append(splits, &s)
+	}
+	return splits
+}
+
+// pruneFreeSpaces3D removes spaces fully contained within another one.
+func pruneFreeSpaces3D(spaces []*FreeSpace3D) []*FreeSpace3D {
+	pruned := make([]*FreeSpace3D, 0, len(spaces))
+	for i, a := range spaces {
+		contained := false
+		for j, c := range spaces {
+			if i == j || (j > i && *a == *c) {
+				continue // Keep the first of identical spaces
+			}
+			if a.X >= c.X && a.Y >= c.Y && a.Z >= c.Z &&
+				a.X+a.Width <= c.X+c.Width && a.Y+a.Height <= c.Y+c.Height && a.Z+a.Depth <= c.Z+c.Depth {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, a)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,89 @@
+package binpacking
+
+// BinOrder selects in which order the best-fit algorithm fills the bins.
+type BinOrder int
+
+const (
+	// BestGlobalFit places each box wherever it fits best over all bins, so every bin
+	// fills at the same time.
+	BestGlobalFit BinOrder = iota
+	// FillFirstBinFully fills the bins one at a time, in order: boxes go into the first
+	// bin until none of the remaining boxes fits it, and only then into the next bin,
+	// which never receives a box afterwards. This closes one sheet before the next is
+	// started, e.g. when sheets are loaded onto the saw one at a time.
+	FillFirstBinFully
+	// RoundRobin places each box into the next bin in turn, the best fitting box for that
+	// bin, skipping bins that none of the remaining boxes fits. This spreads the boxes
+	// evenly over the bins.
+	RoundRobin
+)
+
+// binFocus tracks the bin a RoundRobin run fills next. A nil binFocus allows every bin.
+type binFocus struct {
+	bins    []*Bin
+	current int // Index of the bin taking the next box
+	misses  int // Bins skipped in a row because no box fits them
+}
+
+// newBinFocus returns the focus for the order over bins, or nil if boxes may go into
+// any bin.
+func newBinFocus(order BinOrder, bins []*Bin) *binFocus {
+	if order != RoundRobin || len(bins) < 2 {
+		return nil
+	}
+	return &binFocus{bins: bins}
+}
+
+// allows reports whether a box may go into bin at this step.
+func (f *binFocus) allows(bin *Bin) bool {
+	return f == nil || f.bins[f.current] == bin
+}
+
+// place moves the focus on to the next bin after a box was placed.
+func (f *binFocus) place() {
+	if f != nil {
+		f.current = (f.current + 1) % len(f.bins)
+		f.misses = 0
+	}
+}
+
+// skip moves the focus on to the next bin because no box fits the current one, and
+// reports false once every bin has been skipped in a row, starting the count anew.
+func (f *binFocus) skip() bool {
+	if f == nil {
+		return false
+	}
+	f.current = (f.current + 1) % len(f.bins)
+	f.misses++
+	if f.misses < len(f.bins) {
+		return true
+	}
+	f.misses = 0
+	return false
+}
+
+// packBinByBin is packBestFit with FillFirstBinFully: each bin is packed on its own, in
+// order, with the boxes the bins before it did not take.
+func (p *Packer) packBinByBin(boxesToPack []*Box, options PackerOptions, sequential bool) []*Box {
+	packedBoxes := make([]*Box, 0)
+	remaining := boxesToPack
+	for i, bin := range p.Bins {
+		if len(remaining) == 0 || options.Limit > 0 && int64(len(packedBoxes)) >= options.Limit || options.expired() {
+			break
+		}
+		round := options
+		if options.Limit > 0 {
+			round.Limit = options.Limit - int64(len(packedBoxes))
+		}
+		sub := &Packer{Bins: []*Bin{bin}, events: p.events, Logger: p.Logger, binOffset: p.binOffset + i}
+		packedBoxes = append(packedBoxes, sub.packBestFit(remaining, round, sequential)...)
+		next := make([]*Box, 0, len(remaining))
+		for _, box := range remaining {
+			if !box.Packed {
+				next = append(next, box)
+			}
+		}
+		remaining = next
+	}
+	return packedBoxes
+}
@@ -0,0 +1,82 @@
+package binpacking
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBinOrder(t *testing.T) {
+	squares := func(n int, side float64) []*Box {
+		boxes := make([]*Box, n)
+		for i := range boxes {
+			boxes[i] = NewBox(side, side, false)
+		}
+		return boxes
+	}
+	binsOf := func(placements []BoxPlacement) []int {
+		var got []int
+		for _, placement := range placements {
+			got = append(got, placement.BinIndex)
+		}
+		return got
+	}
+
+	t.Run("FillFirstBinFully", func(t *testing.T) {
+		// The small bin is the tighter fit, so the global best fit starts there.
+		tests := []struct {
+			order BinOrder
+			want  []int
+		}{
+			{BestGlobalFit, []int{1, 0}},
+			{FillFirstBinFully, []int{0, 0}},
+		}
+		for _, tt := range tests {
+			observer := &recordingObserver{}
+			packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(5, 5, nil)})
+			packer.Pack(squares(2, 5), PackerOptions{BinOrder: tt.order, Observer: observer})
+			if got := binsOf(observer.placements); !slices.Equal(got, tt.want) {
+				t.Errorf("order %d: got bins %v, want %v", tt.order, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("FillFirstBinFully sequential", func(t *testing.T) {
+		// A box that does not fit the open bin waits for the next one while the
+		// boxes after it still fill the open bin.
+		boxes := []*Box{NewBox(6, 6, false), NewBox(6, 6, false), NewBox(4, 4, false), NewBox(4, 4, false)}
+		observer := &recordingObserver{}
+		packer := NewPacker([]*Bin{NewBin(10, 6, nil), NewBin(10, 10, nil)})
+		packer.Pack(boxes, NewPackerOptions(WithBinOrder(FillFirstBinFully), WithSortBy(SortAreaDesc), WithObserver(observer)))
+		if got, want := binsOf(observer.placements), []int{0, 0, 1, 1}; !slices.Equal(got, want) {
+			t.Errorf("got bins %v, want %v", got, want)
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("RoundRobin", func(t *testing.T) {
+		observer := &recordingObserver{}
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil), NewBin(2, 2, nil), NewBin(10, 10, nil)}
+		packer := NewPacker(bins)
+		packer.Pack(squares(7, 3), PackerOptions{BinOrder: RoundRobin, Observer: observer})
+		if got, want := binsOf(observer.placements), []int{0, 1, 3, 0, 1, 3, 0}; !slices.Equal(got, want) {
+			t.Errorf("got bins %v, want %v", got, want)
+		}
+		if len(packer.UnpackedBoxes) != 0 {
+			t.Errorf("got %d unpacked boxes, want 0", len(packer.UnpackedBoxes))
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{Bins: []JobBin{{Width: 10, Height: 10, Qty: 2}}, Boxes: []JobBox{{Width: 5, Height: 5}}, Options: JobOptions{BinOrder: "roundrobin"}}
+		if _, err := job.Pack(); err != nil {
+			t.Fatal(err)
+		}
+		job.Options.BinOrder = "sideways"
+		if _, err := job.Pack(); !errors.Is(err, ErrInvalidJob) {
+			t.Errorf("got %v, want ErrInvalidJob", err)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package binpacking
+
+import "slices"
+
+// BottomLeftFillBackend places every box at its bottom-left-most feasible position:
+// the lowest Y at which it fits without overlapping a placed box, and the lowest X at
+// that height. Such a box cannot slide further down or left, so the layout is
+// genuinely bottom-left justified, which suits strip cutting where the used length
+// of the strip should stay as short as possible. Holes left beneath earlier boxes
+// are filled whenever a later box fits into them.
+//
+// Unlike the BottomLeft strategy, which only scores the corners of the tracked free
+// rectangles, the backend derives candidate positions from the placed boxes themselves
+// and is therefore unaffected by Bin.MaxFreeSpaces. The placement strategy is ignored.
+// The bin's FreeSpaces are still kept up to date with a MaxRects split for reporting.
+type BottomLeftFillBackend struct{}
+
+// NewBottomLeftFillBin creates a bin that uses a BottomLeftFillBackend.
+func NewBottomLeftFillBin(width, height float64) *Bin {
+	bin := NewBin(width, height, nil)
+	bin.Backend = BottomLeftFillBackend{}
+	return bin
+}
+
+// FindPlacement implements Backend. The returned score is the position's Y, with X as
+// the tie-breaker; both orientations are tried unless rotation is constrained.
+func (BottomLeftFillBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	// The bottom-left-most position of a box always has its left edge at 0 or at the right
+	// edge of a placed box, and its bottom edge at 0 or at the top edge of a placed box;
+	// otherwise it could slide further. Testing every such combination is exact.
+	occupied := bin.occupied()
+	xs := []float64{0}
+	ys := []float64{0}
+	for _, placed := range occupied {
+		xs = append(xs, placed.X+placed.Width)
+		ys = append(ys, placed.Y+placed.Height)
+	}
+	slices.Sort(xs)
+	slices.Sort(ys)
+	xs = slices.Compact(xs)
+	ys = slices.Compact(ys)
+
+	best := PlacementInfo{Score: NoFit}
+	tol := bin.tolerance()
+	try := func(width, height float64, rotated bool) {
+		// Rows are scanned bottom-up and positions left to right, so the first fit
+		// in this orientation is its bottom-left-most one.
+		for _, y := range ys {
+			if y+height > bin.Height+tol {
+				return
+			}
+			for _, x := range xs {
+				if x+width > bin.Width+tol {
+					break
+				}
+				if blfOverlaps(occupied, x, y, width, height, tol) {
+					continue
+				}
+				if score := NewScoreWithTieBreak(y, x); score.Less(best.Score) {
+					best = PlacementInfo{Score: score, X: x, Y: y, NeedsRotation: rotated, Fits: true}
+				}
+				return
+			}
+		}
+	}
+	try(box.Width, box.Height, false)
+	if !box.ConstrainRotation && box.Width != box.Height {
+		try(box.Height, box.Width, true)
+	}
+	return best
+}
+
+// Place implements Backend.
+func (BottomLeftFillBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
+
+// blfOverlaps reports whether a rectangle at (x, y) of the given size overlaps any of
+// the boxes by more than tol (touching is not overlapping).
+func blfOverlaps(boxes []*Box, x, y, width, height, tol float64) bool {
+	for _, b := range boxes {
+		if x < b.X+b.Width-tol && x+width > b.X+tol && y < b.Y+b.Height-tol && y+height > b.Y+tol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package binpacking
+
+import "testing"
+
+func TestBottomLeftFillBin(t *testing.T) {
+	t.Run("fills holes beneath earlier boxes", func(t *testing.T) {
+		bin := NewBottomLeftFillBin(10, 20)
+		for _, box := range []*Box{NewBox(4, 2, true), NewBox(6, 5, true), NewBox(10, 1, true)} {
+			if !bin.Insert(box) {
+				t.Fatalf("Insert %s: got false, want true", box.Label())
+			}
+		}
+		want := []string{"4x2 at [0,0]", "6x5 at [4,0]", "10x1 at [0,5]"}
+		for i, box := range bin.Boxes {
+			if box.Label() != want[i] {
+				t.Errorf("Box %d: got %s, want %s", i, box.Label(), want[i])
+			}
+		}
+
+		// The 10x1 strip left a 4x3 hole above the first box; a box of that size goes there.
+		hole := NewBox(3, 4, false)
+		bin.Insert(hole)
+		if hole.Label() != "4x3 at [0,2]" {
+			t.Errorf("Hole box: got %s, want %s", hole.Label(), "4x3 at [0,2]")
+		}
+	})
+
+	t.Run("produces bottom-left justified layouts", func(t *testing.T) {
+		bin := NewBottomLeftFillBin(50, 1000)
+		bin.MaxFreeSpaces = 4 // Must not affect the backend
+		boxes := make([]*Box, 0)
+		for i := 0; i < 40; i++ {
+			boxes = append(boxes, NewBox(float64(3+i*7%19), float64(2+i*5%13), false))
+		}
+		NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+		if len(bin.Boxes) != len(boxes) {
+			t.Fatalf("Packed boxes: got %d, want %d", len(bin.Boxes), len(boxes))
+		}
+
+		for i, a := range bin.Boxes {
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+				}
+			}
+			// Every box rests on the floor or another box, and against the left wall or another box.
+			supported, leaning := a.Y == 0, a.X == 0
+			for _, c := range bin.Boxes {
+				if c.Y+c.Height == a.Y && c.X < a.X+a.Width && a.X < c.X+c.Width {
+					supported = true
+				}
+				if c.X+c.Width == a.X && c.Y < a.Y+a.Height && a.Y < c.Y+c.Height {
+					leaning = true
+				}
+			}
+			if !supported || !leaning {
+				t.Errorf("Box %s can still slide: supported %v, leaning %v", a.Label(), supported, leaning)
+			}
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package binpacking
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // FreeSpaceBox represents a rectangular area typically used to track
 // available space in packing algorithms.
@@ -19,6 +22,24 @@ type Box struct {
 	X                 float64 // X-coordinate of the top-left corner
 	Y                 float64 // Y-coordinate of the top-left corner
 	Packed            bool    // Flag indicating if the box has been packed
+	Rotated           bool    // True if the box was rotated from its original orientation
+	Mirrored          bool    // True if the box was turned over; see Box.Mirror
+	AllowMirror       bool    // If true, the box may be turned over when rotation is constrained
+	Locked            bool    // Set by Bin.Place; a locked box is packed around and never moved
+	OrderID           string  // Optional order the box belongs to; see PackerOptions.OrderSplitPenalty
+	Group             string  // Optional group whose boxes must all share one bin, or stay unpacked
+	Tag               string  // Optional class of the box used to select TagOptions
+	Value             float64 // Optional worth of the box; see PackerOptions.Objective
+	Priority          int     // Optional urgency; the best-fit algorithm places higher priorities first
+	Required          bool    // Whether the box must be packed; see Packer.PackRequired
+	Anchor            Anchor  // Optional edges of the bin the box must touch
+	Round             bool    // Whether the box is a circle of diameter Width; see NewCircle
+	Weight            float64 // Optional weight of the box, limited by Bin.MaxWeight
+	Material          string  // Optional Bin.Material the box must go into, or a comma-separated list of them
+	ID                string  // Optional identifier, e.g. a SKU or sprite name, to correlate results
+	Data              any     // Optional user data carried through packing untouched
+
+	cluster *Cluster // Set when the box is the bounding box of a Cluster
 }
 
 // NewBox creates a new Box instance with specified dimensions and rotation constraint.
@@ -32,11 +53,36 @@ func NewBox(width float64, height float64, constrainRotation bool) *Box {
 	}
 }
 
-// Rotate swaps the Width and Height of the Box.
+// NewBoxChecked is like NewBox but validates the dimensions first.
+// The returned error wraps ErrInvalidDimensions.
+func NewBoxChecked(width float64, height float64, constrainRotation bool) (*Box, error) {
+	if err := ValidateDimensions(width, height); err != nil {
+		return nil, err
+	}
+	return NewBox(width, height, constrainRotation), nil
+}
+
+// Validate checks that the box dimensions are usable for packing.
+func (b *Box) Validate() error {
+	return ValidateDimensions(b.Width, b.Height)
+}
+
+// Rotate swaps the Width and Height of the Box and toggles Rotated.
 // This method modifies the receiver Box (b).
 func (b *Box) Rotate() {
 	// Simple swap in Go
 	b.Width, b.Height = b.Height, b.Width
+	b.Rotated = !b.Rotated
+}
+
+// Mirror turns the box over its top-left to bottom-right diagonal, which swaps its Width
+// and Height like Rotate but shows its back face, and toggles Mirrored. A rectangle turned
+// over in place covers the same area, so this is the only mirrored orientation that
+// changes where a box fits. The packer only mirrors boxes with AllowMirror set whose
+// rotation is constrained; it rotates the others, which keeps them face up.
+func (b *Box) Mirror() {
+	b.Width, b.Height = b.Height, b.Width
+	b.Mirrored = !b.Mirrored
 }
 
 // Label returns a formatted string describing the box's dimensions and position.
@@ -47,5 +93,8 @@ func (b *Box) Label() string {
 
 // Area calculates and returns the area of the box (Width * Height).
 func (b *Box) Area() float64 {
+	if b.Round {
+		return math.Pi * b.Radius() * b.Radius()
+	}
 	return b.Width * b.Height
 }
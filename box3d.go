@@ -0,0 +1,118 @@
+package binpacking
+
+import "fmt"
+
+// FreeSpace3D represents an empty maximal space (EMS) inside a Bin3D.
+// X runs along the width, Y along the height (0 is the floor) and Z along the depth.
+type FreeSpace3D struct {
+	X      float64 // X-coordinate of the corner closest to the origin
+	Y      float64 // Y-coordinate of the corner closest to the origin
+	Z      float64 // Z-coordinate of the corner closest to the origin
+	Width  float64 // Extent along X
+	Height float64 // Extent along Y
+	Depth  float64 // Extent along Z
+}
+
+// Orientation identifies one of the six axis-aligned orientations of a Box3D.
+// It describes which original dimension ends up along each axis.
+type Orientation int
+
+// The six axis-aligned orientations, named after the original dimensions that end up
+// along X, Y and Z respectively (W = width, H = height, D = depth).
+const (
+	OrientationWHD Orientation = iota // Original orientation
+	OrientationWDH
+	OrientationHWD
+	OrientationHDW
+	OrientationDWH
+	OrientationDHW
+)
+
+// Box3D represents a cuboid with dimensions, position, and packing status.
+type Box3D struct {
+	Width             float64     // Extent along X
+	Height            float64     // Extent along Y (vertical)
+	Depth             float64     // Extent along Z
+	ConstrainRotation bool        // If true, the box is only packed in its original orientation
+	KeepUpright       bool        // If true, only rotations around the vertical axis are allowed
+	X                 float64     // X-coordinate of the corner closest to the origin
+	Y                 float64     // Y-coordinate of the corner closest to the origin
+	Z                 float64     // Z-coordinate of the corner closest to the origin
+	Packed            bool        // Flag indicating if the box has been packed
+	Orientation       Orientation // Orientation applied when the box was packed
+}
+
+// NewBox3D creates a new Box3D instance with specified dimensions and rotation constraint.
+func NewBox3D(width, height, depth float64, constrainRotation bool) *Box3D {
+	return &Box3D{
+		Width:             width,
+		Height:            height,
+		Depth:             depth,
+		ConstrainRotation: constrainRotation,
+	}
+}
+
+// Volume calculates and returns the volume of the box.
+func (b *Box3D) Volume() float64 {
+	return b.Width * b.Height * b.Depth
+}
+
+// Validate checks that the box dimensions are usable for packing.
+func (b *Box3D) Validate() error {
+	if err := ValidateDimensions(b.Width, b.Height); err != nil {
+		return err
+	}
+	if err := ValidateDimensions(b.Depth, b.Width*b.Height); err != nil {
+		return fmt.Errorf("%w (depth %g)", err, b.Depth)
+	}
+	return nil
+}
+
+// Label returns a formatted string describing the box's dimensions and position.
+func (b *Box3D) Label() string {
+	return fmt.Sprintf("%gx%gx%g at [%g,%g,%g]", b.Width, b.Height, b.Depth, b.X, b.Y, b.Z)
+}
+
+// Orientations returns the orientations the box may be packed in, starting with
+// the original one and skipping duplicates caused by equal dimensions.
+func (b *Box3D) Orientations() []Orientation {
+	if b.ConstrainRotation {
+		return []Orientation{OrientationWHD}
+	}
+	candidates := []Orientation{OrientationWHD, OrientationWDH, OrientationHWD, OrientationHDW, OrientationDWH, OrientationDHW}
+	if b.KeepUpright {
+		candidates = []Orientation{OrientationWHD, OrientationDHW}
+	}
+
+	seen := make(map[[3]float64]struct{}, len(candidates))
+	result := make([]Orientation, 0, len(candidates))
+	for _, o := range candidates {
+		w, h, d := b.Oriented(o)
+		key := [3]float64{w, h, d}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, o)
+	}
+	return result
+}
+
+// Oriented returns the box's extents along X, Y and Z for the given orientation,
+// relative to its current dimensions.
+func (b *Box3D) Oriented(o Orientation) (x, y, z float64) {
+	switch o {
+	case OrientationWDH:
+		return b.Width, b.Depth, b.Height
+	case OrientationHWD:
+		return b.Height, b.Width, b.Depth
+	case OrientationHDW:
+		return b.Height, b.Depth, b.Width
+	case OrientationDWH:
+		return b.Depth, b.Width, b.Height
+	case OrientationDHW:
+		return b.Depth, b.Height, b.Width
+	default:
+		return b.Width, b.Height, b.Depth
+	}
+}
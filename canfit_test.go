@@ -0,0 +1,76 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+// freeSpacesOf copies the bin's free list, to compare it by value.
+func freeSpacesOf(bin *Bin) []FreeSpaceBox {
+	var spaces []FreeSpaceBox
+	for _, space := range bin.FreeSpaces {
+		spaces = append(spaces, *space)
+	}
+	return spaces
+}
+
+func TestCanFit(t *testing.T) {
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(30, 20, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(30, 20, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(30, 20, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(30, 20) },
+		"contact":    func() *Bin { return NewContactPointBin(30, 20) },
+	}
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			bin := newBin()
+			if err := bin.SetSpacing(1); err != nil {
+				t.Fatal(err)
+			}
+			for i, size := range [][2]float64{{12, 8}, {5, 14}, {9, 9}, {14, 5}, {6, 6}, {40, 1}} {
+				box := NewBox(size[0], size[1], false)
+				spaces := freeSpacesOf(bin)
+				placement, ok := bin.CanFit(box)
+				if box.Packed || box.X != 0 || box.Y != 0 || box.Width != size[0] || !slices.Equal(freeSpacesOf(bin), spaces) {
+					t.Fatalf("box %d: CanFit modified the box or the bin", i)
+				}
+				if ok != placement.Fits {
+					t.Errorf("box %d: got %v, want Fits %v", i, ok, placement.Fits)
+				}
+				if inserted := bin.Insert(box); inserted != ok {
+					t.Fatalf("box %d: got CanFit %v, Insert %v", i, ok, inserted)
+				}
+				if !ok {
+					continue
+				}
+				width := size[0]
+				if placement.NeedsRotation {
+					width = size[1]
+				}
+				if box.X != placement.X || box.Y != placement.Y || box.Width != width {
+					t.Errorf("box %d: got %gx%g at [%g,%g], want width %g at [%g,%g]", i, box.Width, box.Height, box.X, box.Y, width, placement.X, placement.Y)
+				}
+			}
+		})
+	}
+
+	t.Run("packed box", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		box := NewBox(2, 2, false)
+		bin.Insert(box)
+		if _, ok := bin.CanFit(box); ok {
+			t.Error("got a fit for a packed box")
+		}
+	})
+
+	t.Run("weight", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.MaxWeight = 5
+		box := NewBox(2, 2, false)
+		box.Weight = 6
+		if placement, ok := bin.CanFit(box); ok || placement.Score != NoFit {
+			t.Errorf("got %v with score %v, want no fit over the weight capacity", ok, placement.Score)
+		}
+	})
+}
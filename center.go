@@ -0,0 +1,57 @@
+package binpacking
+
+import (
+	"math"
+	"reflect"
+)
+
+// CenterFit returns a PlacementStrategyFunc for bin that prefers positions closest to the
+// center of the bin's usable area, so the layout grows outwards from the middle of the
+// sheet: for balanced visual layouts, or to spread the heat of laser cutting evenly. The
+// score is the distance from the box's center to the bin's, breaking ties with the short
+// side fit. Lower scores are better.
+//
+// In a MaxRects bin a box goes at the point of its free space nearest the center rather
+// than at the space's top-left corner; the other backends place boxes at positions of
+// their own, which are scored where they fall. Like ContactPointFit it is bound to a bin:
+//
+//	bin := NewBin(w, h, nil)
+//	bin.Placement = CenterFit(bin)
+//
+// CenterFit is not inlined so that all its strategies share one function, which lets bin
+// serialization recognize and rebind them.
+//
+//go:noinline
+func CenterFit(bin *Bin) PlacementStrategyFunc {
+	return func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+		if bin == nil {
+			return BestShortSideFit(freeSpace, rectWidth, rectHeight)
+		}
+		x, y := bin.centerPosition(freeSpace, rectWidth, rectHeight)
+		cx, cy := bin.center()
+		distance := math.Hypot(x+rectWidth/2-cx, y+rectHeight/2-cy)
+		leftOver := min(math.Abs(freeSpace.Width-rectWidth), math.Abs(freeSpace.Height-rectHeight))
+		return NewScoreWithTieBreak(distance, leftOver)
+	}
+}
+
+// centerFitCode is the code pointer shared by the strategies CenterFit returns.
+var centerFitCode = reflect.ValueOf(CenterFit(nil)).Pointer()
+
+// center returns the center of the bin's usable area, inside its margins.
+func (b *Bin) center() (float64, float64) {
+	m := b.Margins
+	return m.Left + (b.Width-m.Left-m.Right)/2, m.Top + (b.Height-m.Top-m.Bottom)/2
+}
+
+// centerPosition returns where in space CenterFit puts a box of the given size: as near
+// the bin's center as the space allows in a MaxRects bin, else the top-left corner.
+func (b *Bin) centerPosition(space *FreeSpaceBox, width, height float64) (float64, float64) {
+	if !b.usesMaxRects() {
+		return space.X, space.Y
+	}
+	cx, cy := b.center()
+	x := min(max(cx-width/2, space.X), max(space.X, space.X+space.Width-width))
+	y := min(max(cy-height/2, space.Y), max(space.Y, space.Y+space.Height-height))
+	return x, y
+}
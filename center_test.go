@@ -0,0 +1,107 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCenterFit(t *testing.T) {
+	t.Run("first box", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Placement = CenterFit(bin)
+		box := NewBox(2, 4, true)
+		if !bin.Insert(box) {
+			t.Fatal("got no fit")
+		}
+		if box.X != 4 || box.Y != 3 {
+			t.Errorf("got [%g,%g], want [4,3]", box.X, box.Y)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("margins", func(t *testing.T) {
+		bin := NewBin(12, 10, nil)
+		bin.Margins = Margins{Left: 2}
+		bin.Placement = CenterFit(bin)
+		box := NewBox(2, 2, true)
+		bin.Insert(box)
+		if box.X != 6 || box.Y != 4 {
+			t.Errorf("got [%g,%g], want [6,4] in the center of the usable area", box.X, box.Y)
+		}
+	})
+
+	t.Run("center out", func(t *testing.T) {
+		// Each box goes as near the center as the boxes before it allow, so the
+		// boxes get farther from the center in order.
+		bin := NewBin(20, 20, nil)
+		bin.Placement = CenterFit(bin)
+		distance := func(box *Box) float64 {
+			return math.Hypot(box.X+box.Width/2-10, box.Y+box.Height/2-10)
+		}
+		last := -1.0
+		for i := range 12 {
+			box := NewBox(4, 4, true)
+			if !bin.Insert(box) {
+				t.Fatalf("box %d: got no fit", i)
+			}
+			if d := distance(box); d < last-1e-9 {
+				t.Errorf("box %d: got distance %g after %g", i, d, last)
+			} else {
+				last = d
+			}
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("guillotine", func(t *testing.T) {
+		bin := NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis)
+		bin.Placement = CenterFit(bin)
+		for i := range 6 {
+			if !bin.Insert(NewBox(3, 3, true)) {
+				t.Fatalf("box %d: got no fit", i)
+			}
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Placement = CenterFit(bin)
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		if got := placementName(restored.Placement); got != "center-out" {
+			t.Errorf("got placement %q, want center-out", got)
+		}
+		box := NewBox(2, 2, true)
+		restored.Insert(box)
+		if box.X != 4 || box.Y != 4 {
+			t.Errorf("got [%g,%g] in the restored bin, want [4,4]", box.X, box.Y)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		for _, qty := range []int{1, 0} {
+			job := &Job{Bins: []JobBin{{Width: 10, Height: 10, Qty: qty}}, Boxes: []JobBox{{Width: 2, Height: 2}}, Options: JobOptions{Strategy: "center"}}
+			result, err := job.Pack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Placements; len(got) != 1 || got[0].X != 4 || got[0].Y != 4 {
+				t.Errorf("qty %d: got placements %+v, want one at [4,4]", qty, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package binpacking
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestInsertChecked(t *testing.T) {
+	bin := NewBin(10, 10, nil)
+	bin.MaxWeight = 5
+	if err := bin.InsertChecked(NewBox(5, 5, false)); err != nil {
+		t.Fatalf("got %v, want the box placed", err)
+	}
+
+	packed := NewBox(1, 1, false)
+	packed.Packed = true
+	heavy := NewBox(1, 1, false)
+	heavy.Weight = 6
+	tests := []struct {
+		name string
+		box  *Box
+		want error
+	}{
+		{"invalid", NewBox(math.NaN(), 1, false), ErrInvalidDimensions},
+		{"packed", packed, ErrAlreadyPacked},
+		{"heavy", heavy, ErrExceedsWeight},
+		{"too large", NewBox(20, 20, false), ErrNoFit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := bin.InsertChecked(tt.box); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if !errors.Is(ErrExceedsWeight, ErrNoFit) {
+		t.Error("got ErrExceedsWeight not matching ErrNoFit")
+	}
+	if len(bin.Boxes) != 1 {
+		t.Errorf("got %d boxes, want 1", len(bin.Boxes))
+	}
+}
+
+func TestPackChecked(t *testing.T) {
+	newPacker := func() *Packer {
+		bin := NewBin(10, 10, nil)
+		bin.MaxWeight = 5
+		return NewPacker([]*Bin{bin})
+	}
+	if _, err := newPacker().PackChecked([]*Box{NewBox(5, 5, false)}, PackerOptions{}); err != nil {
+		t.Errorf("got %v, want no error", err)
+	}
+
+	heavy := NewBox(2, 2, false)
+	heavy.Weight = 6
+	packed, err := newPacker().PackChecked([]*Box{NewBox(5, 5, false), heavy, NewBox(20, 20, false), NewBox(-1, 1, false)}, PackerOptions{})
+	if len(packed) != 1 {
+		t.Errorf("got %d boxes packed, want 1", len(packed))
+	}
+	for _, want := range []error{ErrExceedsWeight, ErrNoFit, ErrInvalidDimensions} {
+		if !errors.Is(err, want) {
+			t.Errorf("got %v, want it to match %v", err, want)
+		}
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 3 {
+		t.Errorf("got %v, want one error per unpacked box", err)
+	}
+
+	_, err = newPacker().PackChecked([]*Box{NewBox(2, 2, false), NewBox(2, 2, false)}, PackerOptions{Limit: 1})
+	if !errors.Is(err, ErrLimitReached) {
+		t.Errorf("got %v, want ErrLimitReached", err)
+	}
+
+	observer := &recordingObserver{}
+	newPacker().Pack([]*Box{heavy}, PackerOptions{Observer: observer})
+	if !errors.Is(observer.rejected[heavy], ErrExceedsWeight) {
+		t.Errorf("got observer reason %v, want ErrExceedsWeight", observer.rejected[heavy])
+	}
+}
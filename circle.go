@@ -0,0 +1,181 @@
+package binpacking
+
+import (
+	"math"
+)
+
+// NewCircle creates a round box: a circle of the given radius, whose Width and Height are
+// its diameter. Backends pack it as its bounding square, except the CircleBackend, which
+// packs it as a circle. X and Y are the top-left corner of the bounding square, so the
+// center is at Box.Center.
+func NewCircle(radius float64) *Box {
+	box := NewBox(2*radius, 2*radius, true)
+	box.Round = true
+	return box
+}
+
+// Radius returns the radius of a round box, or zero for a rectangle.
+func (b *Box) Radius() float64 {
+	if !b.Round {
+		return 0
+	}
+	return b.Width / 2
+}
+
+// Center returns the center of the box as placed.
+func (b *Box) Center() (float64, float64) {
+	return b.X + b.Width/2, b.Y + b.Height/2
+}
+
+// CircleBackend packs round boxes, created by NewCircle, as circles: each goes to its
+// lowest feasible position, and the leftmost at that height, among those touching two of
+// the edges of the usable area, the circles and the rectangles in the bin. Circles nest
+// into the gaps between the circles below them, as cans or rolls do on a pallet layer,
+// rather than being stacked in a square grid. Rectangles are placed as by the
+// BottomLeftFillBackend, against the bounding squares of the circles, so the two kinds mix
+// in one bin. The placement strategy is ignored.
+//
+// Finding a position tests every pair of obstacles against every box in the bin, so its
+// cost grows with the cube of the number of boxes; it suits layers of a few hundred
+// circles. The bin's FreeSpaces are kept up to date with a MaxRects split of the boxes'
+// bounds for reporting.
+type CircleBackend struct{}
+
+// NewCircleBin creates a bin that uses a CircleBackend.
+func NewCircleBin(width, height float64) *Bin {
+	bin := NewBin(width, height, nil)
+	bin.Backend = CircleBackend{}
+	return bin
+}
+
+// FindPlacement implements Backend. The returned score is the position's Y, with X as
+// the tie-breaker, as with the BottomLeftFillBackend.
+func (CircleBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	if !box.Round {
+		return BottomLeftFillBackend{}.FindPlacement(bin, box, strategy)
+	}
+	spacing, tol := max(bin.Spacing, 0), bin.layoutTolerance()
+	r := (box.Width - spacing) / 2 // The box is padded by the spacing
+	m := bin.Margins
+	minX, minY := m.Left+spacing+r, m.Top+spacing+r
+	maxX, maxY := bin.Width-m.Right-spacing-r, bin.Height-m.Bottom-spacing-r
+	if minX > maxX+tol || minY > maxY+tol {
+		return PlacementInfo{Score: NoFit}
+	}
+
+	// The center of a circle touching two obstacles lies on two of these: lines at the
+	// distance r from the edges and from the sides of rectangles, and circles of radius r
+	// larger than the circles in the bin and around the corners of rectangles.
+	xs, ys := []float64{minX, maxX}, []float64{minY, maxY}
+	var rings []circleRing
+	obstacles := make([]*Box, 0, len(bin.Boxes)+len(bin.Defects))
+	obstacles = append(obstacles, bin.Boxes...)
+	for _, defect := range bin.Defects {
+		obstacles = append(obstacles, defectBox(defect))
+	}
+	for _, other := range obstacles {
+		if other.Round {
+			cx, cy := other.Center()
+			rings = append(rings, circleRing{cx, cy, other.Radius() + spacing + r})
+			continue
+		}
+		left, right := other.X-spacing-r, other.X+other.Width+spacing+r
+		top, bottom := other.Y-spacing-r, other.Y+other.Height+spacing+r
+		xs = append(xs, left, right)
+		ys = append(ys, top, bottom)
+		for _, corner := range [4][2]float64{{other.X, other.Y}, {other.X + other.Width, other.Y},
+			{other.X, other.Y + other.Height}, {other.X + other.Width, other.Y + other.Height}} {
+			rings = append(rings, circleRing{corner[0], corner[1], spacing + r})
+		}
+	}
+
+	best := PlacementInfo{Score: NoFit}
+	trial := &Box{Width: 2 * r, Height: 2 * r, Round: true}
+	try := func(x, y float64) {
+		if x < minX-tol || x > maxX+tol || y < minY-tol || y > maxY+tol {
+			return
+		}
+		score := NewScoreWithTieBreak(y-r, x-r)
+		if !score.Less(best.Score) {
+			return
+		}
+		trial.X, trial.Y = x-r, y-r
+		for _, other := range obstacles {
+			if bin.overlaps(trial, other) {
+				return
+			}
+		}
+		best = PlacementInfo{Score: score, X: x - r, Y: y - r, Fits: true}
+	}
+	for _, y := range ys {
+		for _, x := range xs {
+			try(x, y)
+		}
+	}
+	for i, ring := range rings {
+		for _, x := range xs {
+			if dy := ring.r*ring.r - (x-ring.x)*(x-ring.x); dy >= 0 {
+				try(x, ring.y-math.Sqrt(dy))
+				try(x, ring.y+math.Sqrt(dy))
+			}
+		}
+		for _, y := range ys {
+			if dx := ring.r*ring.r - (y-ring.y)*(y-ring.y); dx >= 0 {
+				try(ring.x-math.Sqrt(dx), y)
+				try(ring.x+math.Sqrt(dx), y)
+			}
+		}
+		for _, other := range rings[i+1:] {
+			for _, p := range ring.intersections(other) {
+				try(p[0], p[1])
+			}
+		}
+	}
+	return best
+}
+
+// Place implements Backend.
+func (CircleBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
+
+// PlaceFixed implements FixedPlacer.
+func (CircleBackend) PlaceFixed(bin *Bin, box *Box) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
+
+// circleRing is a circle on which the center of a circle being placed may lie.
+type circleRing struct {
+	x, y, r float64
+}
+
+// intersections returns the points where the two circles cross, none if they do not.
+func (c circleRing) intersections(other circleRing) [][2]float64 {
+	dx, dy := other.x-c.x, other.y-c.y
+	d := math.Hypot(dx, dy)
+	if d == 0 || d > c.r+other.r || d < math.Abs(c.r-other.r) {
+		return nil
+	}
+	a := (c.r*c.r - other.r*other.r + d*d) / (2 * d) // Distance from c to the chord
+	h := math.Sqrt(max(c.r*c.r-a*a, 0))
+	mx, my := c.x+a*dx/d, c.y+a*dy/d
+	return [][2]float64{{mx - h*dy/d, my + h*dx/d}, {mx + h*dy/d, my - h*dx/d}}
+}
+
+// shapeGap returns the distance between two boxes as the shapes they are, at least one of
+// them round; it is negative when they overlap.
+func shapeGap(box, other *Box) float64 {
+	if !box.Round {
+		box, other = other, box
+	}
+	cx, cy := box.Center()
+	if other.Round {
+		ox, oy := other.Center()
+		return math.Hypot(cx-ox, cy-oy) - box.Radius() - other.Radius()
+	}
+	dx := max(other.X-cx, cx-(other.X+other.Width), 0)
+	dy := max(other.Y-cy, cy-(other.Y+other.Height), 0)
+	return math.Hypot(dx, dy) - box.Radius()
+}
@@ -0,0 +1,163 @@
+package binpacking
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCircleBin(t *testing.T) {
+	t.Run("nesting", func(t *testing.T) {
+		// The second row sits in the gaps of the first, √3 below it.
+		bin := NewCircleBin(6, 10)
+		circles := make([]*Box, 5)
+		for i := range circles {
+			circles[i] = NewCircle(1)
+			if !bin.Insert(circles[i]) {
+				t.Fatalf("circle %d: got no fit", i)
+			}
+		}
+		wants := [][2]float64{{1, 1}, {3, 1}, {5, 1}, {2, 1 + math.Sqrt(3)}, {4, 1 + math.Sqrt(3)}}
+		for i, want := range wants {
+			x, y := circles[i].Center()
+			if math.Abs(x-want[0]) > 1e-9 || math.Abs(y-want[1]) > 1e-9 {
+				t.Errorf("circle %d: got center [%g,%g], want %v", i, x, y, want)
+			}
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("denser than squares", func(t *testing.T) {
+		// Bounding squares fit 50 circles; nested rows fit more.
+		bin := NewCircleBin(11, 20)
+		for bin.Insert(NewCircle(1)) {
+		}
+		if len(bin.Boxes) <= 50 {
+			t.Errorf("got %d circles, want more than 50", len(bin.Boxes))
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("efficiency", func(t *testing.T) {
+		bin := NewCircleBin(10, 10)
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack([]*Box{NewCircle(2), NewBox(3, 3, false)}, PackerOptions{})
+		result := packer.Result()
+		group := result.ByBin()[0]
+		if math.Abs(group.UsedArea-(4*math.Pi+9)) > 1e-9 {
+			t.Fatalf("UsedArea: got %g, want %g", group.UsedArea, 4*math.Pi+9)
+		}
+		want := group.UsedArea / bin.Area() * 100
+		if math.Abs(group.Efficiency-want) > 1e-9 {
+			t.Errorf("BinGroup.Efficiency: got %g, want %g", group.Efficiency, want)
+		}
+		if got := result.Stats().BinEfficiency[0]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("PackStats.BinEfficiency: got %g, want %g", got, want)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		bin := NewCircleBin(20, 20)
+		bin.Spacing = 0.5
+		bin.Margins = Margins{Left: 1, Top: 1}
+		if err := bin.AddDefect(8, 8, 3, 3); err != nil {
+			t.Fatal(err)
+		}
+		boxes := make([]*Box, 0)
+		for i := range 30 {
+			if i%3 == 0 {
+				boxes = append(boxes, NewBox(float64(2+i%4), 3, false))
+			} else {
+				boxes = append(boxes, NewCircle(float64(1+i%3)/2))
+			}
+		}
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack(boxes, PackerOptions{})
+		if len(bin.Boxes) < 20 {
+			t.Errorf("packed only %d boxes", len(bin.Boxes))
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		// Diagonal neighbours overlap as squares but not as circles.
+		bin := NewCircleBin(10, 10)
+		a, b := NewCircle(1), NewCircle(1)
+		a.X, a.Y, a.Packed = 0, 0, true
+		b.X, b.Y, b.Packed = 1.5, 1.5, true
+		bin.Boxes = []*Box{a, b}
+		if err := ValidateLayout(bin); err != nil {
+			t.Errorf("got %v for circles apart", err)
+		}
+		b.X, b.Y = 1, 1
+		if err := ValidateLayout(bin); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("got %v for overlapping circles, want ErrInvalidLayout", err)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin := NewCircleBin(6, 10)
+		bin.Insert(NewCircle(1))
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := restored.Backend.(CircleBackend); !ok || !restored.Boxes[0].Round {
+			t.Fatalf("got backend %T, round %t", restored.Backend, restored.Boxes[0].Round)
+		}
+		circle := NewCircle(1)
+		restored.Insert(circle)
+		if circle.X != 2 || circle.Y != 0 {
+			t.Errorf("got [%g,%g] in the restored bin, want [2,0]", circle.X, circle.Y)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var job Job
+		input := `{"bins":[{"width":6,"height":10}],"boxes":[{"radius":1,"qty":5},{"width":2,"height":1}],"options":{"backend":"circle"}}`
+		if err := json.Unmarshal([]byte(input), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		circles := 0
+		for _, p := range result.Placements {
+			if !p.Packed {
+				t.Errorf("got %+v unpacked", p)
+			}
+			if p.Radius == 1 {
+				circles++
+			}
+		}
+		if circles != 5 {
+			t.Errorf("got %d placements with radius 1, want 5", circles)
+		}
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		bin := NewCircleBin(6, 10)
+		bin.Insert(NewCircle(1))
+		var buf bytes.Buffer
+		if err := WriteSVG(&buf, []*Bin{bin}, SVGOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), `<circle cx="1" cy="1" r="1"`) {
+			t.Errorf("got no circle in %s", buf.String())
+		}
+	})
+}
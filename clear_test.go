@@ -0,0 +1,96 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBinClear(t *testing.T) {
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(50, 40, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(50, 40, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(50, 40, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(50, 40) },
+	}
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			setup := func(bin *Bin) *Bin {
+				if err := bin.SetMargins(Margins{Left: 2}); err != nil {
+					t.Fatal(err)
+				}
+				if err := bin.AddDefect(20, 20, 5, 5); err != nil {
+					t.Fatal(err)
+				}
+				return bin
+			}
+			fill := func(bin *Bin) []*Box {
+				boxes := make([]*Box, 12)
+				for i := range boxes {
+					boxes[i] = NewBox(float64(4+i%3*3), float64(5+i%4*2), false)
+				}
+				NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+				return boxes
+			}
+			wantLayout := setup(newBin())
+			fill(wantLayout)
+
+			bin := setup(newBin())
+			boxes := fill(bin)
+			capacity := cap(bin.Boxes)
+			bin.Clear()
+			if len(bin.Boxes) != 0 || cap(bin.Boxes) != capacity {
+				t.Errorf("got %d boxes and capacity %d, want none and capacity %d", len(bin.Boxes), cap(bin.Boxes), capacity)
+			}
+			for i, box := range boxes {
+				if box.Packed {
+					t.Errorf("box %d: got packed after Clear", i)
+				}
+			}
+			if got, want := freeSpacesOf(bin), freeSpacesOf(setup(newBin())); !slices.Equal(got, want) {
+				t.Errorf("got free spaces %v, want %v", got, want)
+			}
+
+			// Packing again gives the layout of a fresh bin.
+			fill(bin)
+			if got, want := layoutOf([]*Bin{bin}), layoutOf([]*Bin{wantLayout}); !slices.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+			if err := ValidateLayout(bin); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestPackerReset(t *testing.T) {
+	newBoxes := func() []*Box {
+		return []*Box{NewBox(30, 30, false), NewBox(30, 30, false), NewBox(80, 80, false)}
+	}
+	packer := NewPacker([]*Bin{NewBin(40, 40, nil)})
+	options := PackerOptions{BinTemplates: []BinTemplate{{Width: 40, Height: 40}}}
+	packer.Pack(newBoxes(), options)
+	if len(packer.Bins) != 2 || len(packer.UnpackedBoxes) != 1 {
+		t.Fatalf("got %d bins and %d unpacked boxes, want 2 and 1", len(packer.Bins), len(packer.UnpackedBoxes))
+	}
+
+	packer.Reset()
+	result := packer.Result()
+	if len(result.Packed) != 0 || len(result.Unpacked) != 0 || result.BinsUsed != 0 || result.Elapsed != 0 {
+		t.Errorf("got %d packed, %d unpacked, %d bins used, elapsed %v, want an empty result",
+			len(result.Packed), len(result.Unpacked), result.BinsUsed, result.Elapsed)
+	}
+	for i, bin := range packer.Bins {
+		if len(bin.Boxes) != 0 {
+			t.Errorf("bin %d: got %d boxes, want none", i, len(bin.Boxes))
+		}
+	}
+
+	// The template bin opened before is reused rather than a third one opened.
+	packer.Pack(newBoxes(), options)
+	if len(packer.Bins) != 2 || len(packer.lastPacked) != 2 {
+		t.Errorf("got %d bins and %d boxes packed, want 2 and 2", len(packer.Bins), len(packer.lastPacked))
+	}
+	if err := packer.Result().Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,86 @@
+package binpacking
+
+import "slices"
+
+// Clone returns an independent copy of the box. The bounding box of a Cluster is copied
+// with its own cluster and members, so placing the copy moves the copied members only.
+// Data is copied as is, so a pointer in it is shared.
+func (b *Box) Clone() *Box {
+	clone := *b
+	if b.cluster != nil {
+		cluster := &Cluster{Members: make([]*Box, len(b.cluster.Members)), Box: &clone, layout: slices.Clone(b.cluster.layout)}
+		for i, member := range b.cluster.Members {
+			cluster.Members[i] = member.Clone()
+		}
+		clone.cluster = cluster
+	}
+	return &clone
+}
+
+// Clone returns an independent copy of the bin, e.g. to try packing more boxes into it
+// without changing the bin itself: its boxes, free spaces, defects and backend state are
+// copied. The built-in strategies bound to b, such as ContactPointFit, are bound to the
+// copy; a custom Placement closure bound to b still scores against b.
+func (b *Bin) Clone() *Bin {
+	return b.cloneWith(make(map[*Box]*Box))
+}
+
+// cloneWith is Clone taking the copies of boxes made so far from copies, and adding the
+// ones it makes, so boxes shared with other bins or lists are copied once.
+func (b *Bin) cloneWith(copies map[*Box]*Box) *Bin {
+	clone := b.scratch()
+	for i, box := range clone.Boxes {
+		clone.Boxes[i] = cloneBox(box, copies)
+	}
+	if guillotine, ok := b.Backend.(*GuillotineBackend); ok {
+		copied := *guillotine
+		clone.Backend = &copied
+	}
+	clone.index = nil
+	clone.indexFreeSpaces()
+	return clone
+}
+
+// cloneBox returns the copy of box in copies, making it first if there is none.
+func cloneBox(box *Box, copies map[*Box]*Box) *Box {
+	if box == nil {
+		return nil
+	}
+	if clone, ok := copies[box]; ok {
+		return clone
+	}
+	clone := box.Clone()
+	copies[box] = clone
+	return clone
+}
+
+// Clone returns an independent copy of the packer: its bins and their boxes, and the
+// boxes packed and left unpacked by the last call to Pack, so the copy can pack further
+// boxes, e.g. to answer "what if this order is added?", while the packer stays as it is.
+// Result of the copy describes the copied boxes. The Logger is shared.
+func (p *Packer) Clone() *Packer {
+	copies := make(map[*Box]*Box)
+	clone := &Packer{
+		Bins:          make([]*Bin, len(p.Bins)),
+		UnpackedBoxes: make([]*Box, len(p.UnpackedBoxes)),
+		lastElapsed:   p.lastElapsed,
+		lastTimedOut:  p.lastTimedOut,
+		Logger:        p.Logger,
+		binOffset:     p.binOffset,
+	}
+	for i, bin := range p.Bins {
+		if bin != nil {
+			clone.Bins[i] = bin.cloneWith(copies)
+		}
+	}
+	if p.lastPacked != nil {
+		clone.lastPacked = make([]*Box, len(p.lastPacked))
+		for i, box := range p.lastPacked {
+			clone.lastPacked[i] = cloneBox(box, copies)
+		}
+	}
+	for i, box := range p.UnpackedBoxes {
+		clone.UnpackedBoxes[i] = cloneBox(box, copies)
+	}
+	return clone
+}
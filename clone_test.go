@@ -0,0 +1,108 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBoxClone(t *testing.T) {
+	box := NewBox(3, 4, false)
+	box.ID, box.Data = "a", "data"
+	clone := box.Clone()
+	if clone == box || *clone != *box {
+		t.Fatalf("got %+v, want an equal copy of %+v", clone, box)
+	}
+	clone.Rotate()
+	if box.Width != 3 || box.Rotated {
+		t.Error("got the original rotated with the copy")
+	}
+
+	t.Run("cluster", func(t *testing.T) {
+		a, b := NewBox(2, 2, true), NewBox(2, 2, true)
+		b.X = 2
+		cluster := NewCluster([]*Box{a, b}, true)
+		clone := cluster.Box.Clone()
+		bin := NewBin(10, 10, nil)
+		if err := bin.Place(clone, 5, 5); err != nil {
+			t.Fatal(err)
+		}
+		if a.X != 0 || b.X != 2 || a.Y != 0 {
+			t.Errorf("got the original members moved to [%g,%g] and [%g,%g]", a.X, a.Y, b.X, b.Y)
+		}
+		members := clone.cluster.Members
+		if members[0] == a || members[0].X != 5 || members[1].X != 7 || members[0].Y != 5 {
+			t.Errorf("got copied members at [%g,%g] and [%g,%g], want [5,5] and [7,5]", members[0].X, members[0].Y, members[1].X, members[1].Y)
+		}
+	})
+}
+
+func TestBinClone(t *testing.T) {
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(30, 30, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(30, 30, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(30, 30, SkylineOptions{WasteMap: true}) },
+	}
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			bin := newBin()
+			if err := bin.AddDefect(10, 10, 5, 5); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 4; i++ {
+				bin.Insert(NewBox(7, 6, false))
+			}
+			layout, spaces := layoutOf([]*Bin{bin}), freeSpacesOf(bin)
+
+			clone := bin.Clone()
+			if got := layoutOf([]*Bin{clone}); !slices.Equal(got, layout) {
+				t.Errorf("got copy %v, want %v", got, layout)
+			}
+			for i, box := range clone.Boxes {
+				if box == bin.Boxes[i] {
+					t.Fatalf("box %d: got the box shared", i)
+				}
+			}
+			for i := 0; i < 6; i++ {
+				clone.Insert(NewBox(5, 5, false))
+			}
+			clone.Boxes[0].X = 99
+			clone.Defects[0].X = 0
+			if got := layoutOf([]*Bin{bin}); !slices.Equal(got, layout) {
+				t.Errorf("got %v after changing the copy, want %v", got, layout)
+			}
+			if got := freeSpacesOf(bin); !slices.Equal(got, spaces) {
+				t.Errorf("got free spaces %v after changing the copy, want %v", got, spaces)
+			}
+			if bin.Defects[0].X != 10 {
+				t.Error("got the defects shared")
+			}
+			if err := ValidateLayout(bin); err != nil {
+				t.Error(err)
+			}
+			if !bin.Insert(NewBox(5, 5, false)) {
+				t.Error("got no room in the original")
+			}
+		})
+	}
+}
+
+func TestPackerClone(t *testing.T) {
+	packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+	packer.Pack([]*Box{NewBox(6, 6, false), NewBox(20, 20, false)}, PackerOptions{})
+	want := packer.Result().Placements
+
+	clone := packer.Clone()
+	if got := clone.Result().Placements; len(got) != len(want) || got[0].Box == want[0].Box || got[0].X != want[0].X || got[1].Packed {
+		t.Errorf("got placements %v, want copies of %v", got, want)
+	}
+	if err := clone.Result().Validate(); err != nil {
+		t.Error(err)
+	}
+	clone.Pack([]*Box{NewBox(4, 4, false)}, PackerOptions{BinTemplates: []BinTemplate{{Width: 10, Height: 10}}})
+	if len(packer.Bins) != 1 || len(packer.Bins[0].Boxes) != 1 {
+		t.Errorf("got %d bins and %d boxes in the original, want 1 and 1", len(packer.Bins), len(packer.Bins[0].Boxes))
+	}
+	if got := packer.Result().Placements; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package binpacking
+
+// Cluster is a kit of boxes pre-arranged relative to each other that is packed as one
+// rigid unit. The packer only sees the cluster's bounding Box; once that box is placed
+// (and possibly rotated) the members receive their absolute positions automatically.
+//
+// Note: the whole bounding box is reserved in the bin, so gaps between members are not
+// available to other boxes and count towards the bin's efficiency.
+type Cluster struct {
+	Members []*Box // Member boxes; their X/Y become absolute positions once the cluster is placed
+	Box     *Box   // Bounding box packed in place of the members
+
+	layout []FreeSpaceBox // Members' rectangles relative to the cluster origin, unrotated
+}
+
+// NewCluster creates a cluster from members whose X/Y describe their arrangement.
+// The arrangement is normalized so its top-left corner is the cluster origin.
+// If constrainRotation is true the cluster is never rotated as a whole.
+// The bounding box carries the members' total weight and value.
+func NewCluster(members []*Box, constrainRotation bool) *Cluster {
+	c := &Cluster{Members: members, layout: make([]FreeSpaceBox, len(members))}
+
+	minX, minY, maxX, maxY := 0.0, 0.0, 0.0, 0.0
+	for i, m := range members {
+		if i == 0 || m.X < minX {
+			minX = m.X
+		}
+		if i == 0 || m.Y < minY {
+			minY = m.Y
+		}
+		if i == 0 || m.X+m.Width > maxX {
+			maxX = m.X + m.Width
+		}
+		if i == 0 || m.Y+m.Height > maxY {
+			maxY = m.Y + m.Height
+		}
+	}
+	for i, m := range members {
+		c.layout[i] = FreeSpaceBox{X: m.X - minX, Y: m.Y - minY, Width: m.Width, Height: m.Height}
+	}
+
+	c.Box = NewBox(maxX-minX, maxY-minY, constrainRotation)
+	c.Box.cluster = c
+	for _, m := range members {
+		c.Box.Weight += m.Weight
+		c.Box.Value += m.Value
+		c.Box.Priority = max(c.Box.Priority, m.Priority)
+		c.Box.Required = c.Box.Required || m.Required
+	}
+	return c
+}
+
+// Placed reports whether the cluster has been packed into a bin.
+func (c *Cluster) Placed() bool {
+	return c.Box.Packed
+}
+
+// place updates the members from the position and orientation of the packed bounding box.
+func (c *Cluster) place() {
+	// When rotated, the cluster turns 90° clockwise: a member's original Y axis becomes
+	// the X axis measured back from the cluster's original height.
+	originalHeight := c.Box.Height
+	if c.Box.Rotated {
+		originalHeight = c.Box.Width
+	}
+	for i, m := range c.Members {
+		rel := c.layout[i]
+		if c.Box.Rotated {
+			m.X = c.Box.X + originalHeight - (rel.Y + rel.Height)
+			m.Y = c.Box.Y + rel.X
+			m.Width, m.Height = rel.Height, rel.Width
+		} else {
+			m.X = c.Box.X + rel.X
+			m.Y = c.Box.Y + rel.Y
+			m.Width, m.Height = rel.Width, rel.Height
+		}
+		m.Rotated = c.Box.Rotated
+		m.Packed = true
+	}
+}
@@ -0,0 +1,46 @@
+package binpacking
+
+import "testing"
+
+func TestCluster(t *testing.T) {
+	t.Run("places members relative to the cluster", func(t *testing.T) {
+		left := &Box{X: 5, Y: 5, Width: 10, Height: 10}
+		right := &Box{X: 15, Y: 5, Width: 10, Height: 10}
+		cluster := NewCluster([]*Box{left, right}, true)
+		if cluster.Box.Width != 20 || cluster.Box.Height != 10 {
+			t.Fatalf("Cluster size: got %gx%g, want 20x10", cluster.Box.Width, cluster.Box.Height)
+		}
+
+		bin := NewBin(100, 100, nil)
+		bin.Insert(NewBox(30, 30, true))
+		if !bin.Insert(cluster.Box) {
+			t.Fatalf("Insert cluster: got false, want true")
+		}
+		if !cluster.Placed() || !left.Packed || !right.Packed {
+			t.Errorf("Cluster and members must be packed")
+		}
+		if right.X-left.X != 10 || right.Y != left.Y {
+			t.Errorf("Members moved relative to each other: %s, %s", left.Label(), right.Label())
+		}
+		if left.X != cluster.Box.X || left.Y != cluster.Box.Y {
+			t.Errorf("Left member position: got [%g,%g], want [%g,%g]", left.X, left.Y, cluster.Box.X, cluster.Box.Y)
+		}
+	})
+
+	t.Run("rotates the cluster as a whole", func(t *testing.T) {
+		left := &Box{X: 0, Y: 0, Width: 10, Height: 10}
+		right := &Box{X: 10, Y: 0, Width: 10, Height: 10}
+		cluster := NewCluster([]*Box{left, right}, false)
+
+		bin := NewBin(10, 20, nil) // Only fits rotated
+		if !bin.Insert(cluster.Box) {
+			t.Fatalf("Insert cluster: got false, want true")
+		}
+		if left.Label() != "10x10 at [0,0]" || right.Label() != "10x10 at [0,10]" {
+			t.Errorf("Rotated members: got %s and %s", left.Label(), right.Label())
+		}
+		if !left.Rotated || !right.Rotated {
+			t.Errorf("Members must be marked as rotated")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+// Command binpack packs a cut list into stock sheets from the command line, so the
+// library can be used without writing Go.
+//
+// Jobs are given either as a JSON file in the format of binpacking.Job, with the stock,
+// the boxes and optionally the options in one document:
+//
+//	binpack -job job.json
+//
+// or as two CSV files in the formats read by binpacking.ReadBoxesCSV and
+// binpacking.ReadBinsCSV:
+//
+//	binpack -boxes parts.csv -bins sheets.csv -format svg -o layout.svg
+//
+// A bin without a quantity is stock available in any amount. Such bins always use the
+// MaxRects backend; -backend applies to bins with a quantity. Flags override the options
+// of a job file. The placements are written as JSON (the default), CSV or SVG to
+// standard output or the file given with -o. Run binpack -h for the full list of flags.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/acmacalister/binpacking"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return // Usage has been printed
+		}
+		fmt.Fprintln(os.Stderr, "binpack:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments, packs the job and writes the result to stdout or -o.
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("binpack", flag.ContinueOnError)
+	jobPath := flags.String("job", "", "JSON job `file` with bins, boxes and options")
+	boxesPath := flags.String("boxes", "", "CSV `file` of boxes: width,height,qty,id,rotatable")
+	binsPath := flags.String("bins", "", "CSV `file` of bins: width,height,qty,cost")
+	var o binpacking.JobOptions
+	flags.StringVar(&o.Strategy, "strategy", "", "placement strategy: bssf (default), blsf, baf, bl, contact, center, spread or auto")
+	flags.StringVar(&o.Backend, "backend", "", "bin backend: maxrects (default), guillotine, skyline, blf, grid or circle")
+	flags.StringVar(&o.Algorithm, "algorithm", "", "packing algorithm: bestfit (default), nfdh or ffdh")
+	flags.StringVar(&o.Objective, "objective", "", "objective: bestfit (default), maxvalue, prefervalue or mincost")
+	flags.StringVar(&o.Sort, "sort", "", "box order: none (default), area, longest, perimeter or width")
+	flags.StringVar(&o.BinOrder, "binorder", "", "bin fill order: global (default), fill or roundrobin")
+	flags.StringVar(&o.Orders, "orders", "", "order splitting: minimize (default) or nosplit")
+	flags.IntVar(&o.Restarts, "restarts", 0, "number of multi-start restarts")
+	flags.Uint64Var(&o.Seed, "seed", 0, "seed for randomized restarts")
+	flags.BoolVar(&o.Guillotine, "guillotine", false, "only accept guillotine-cuttable layouts")
+	flags.IntVar(&o.MaxBins, "maxbins", 0, "maximum number of bins, including unlimited stock; 0 means no limit")
+	flags.BoolVar(&o.Deterministic, "deterministic", false, "pack the boxes in a canonical order, so the layout does not depend on their order in the input")
+	format := flags.String("format", "json", "output format: json, csv or svg")
+	outPath := flags.String("o", "", "output `file`; standard output if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	switch *format {
+	case "json", "csv", "svg":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var job *binpacking.Job
+	var err error
+	switch {
+	case *jobPath != "" && (*boxesPath != "" || *binsPath != ""):
+		return errors.New("-job cannot be combined with -boxes and -bins")
+	case *jobPath != "":
+		job, err = readJob(*jobPath)
+	case *boxesPath != "" && *binsPath != "":
+		job, err = readCSVJob(*boxesPath, *binsPath)
+	default:
+		return errors.New("either -job or both -boxes and -bins are required")
+	}
+	if err != nil {
+		return err
+	}
+	// Flags given on the command line override the options of the job file.
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "strategy":
+			job.Options.Strategy = o.Strategy
+		case "backend":
+			job.Options.Backend = o.Backend
+		case "algorithm":
+			job.Options.Algorithm = o.Algorithm
+		case "objective":
+			job.Options.Objective = o.Objective
+		case "sort":
+			job.Options.Sort = o.Sort
+		case "binorder":
+			job.Options.BinOrder = o.BinOrder
+		case "orders":
+			job.Options.Orders = o.Orders
+		case "restarts":
+			job.Options.Restarts = o.Restarts
+		case "seed":
+			job.Options.Seed = o.Seed
+		case "guillotine":
+			job.Options.Guillotine = o.Guillotine
+		case "maxbins":
+			job.Options.MaxBins = o.MaxBins
+		case "deterministic":
+			job.Options.Deterministic = o.Deterministic
+		}
+	})
+	result, err := job.Pack()
+	if err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		return write(stdout, *format, result)
+	}
+	f, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	if err := write(f, *format, result); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// write writes the result in the given output format.
+func write(w io.Writer, format string, result *binpacking.PackResult) error {
+	switch format {
+	case "csv":
+		return binpacking.WritePlacementsCSV(w, result)
+	case "svg":
+		return binpacking.WriteSVG(w, usedBins(result.Bins), binpacking.SVGOptions{Labels: true})
+	default:
+		return writeJSON(w, result)
+	}
+}
+
+// readJob reads a JSON job file.
+func readJob(path string) (*binpacking.Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job binpacking.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &job, nil
+}
+
+// readCSVJob reads a boxes and a bins CSV file into a job.
+func readCSVJob(boxesPath, binsPath string) (*binpacking.Job, error) {
+	boxesFile, err := os.Open(boxesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer boxesFile.Close()
+	specs, err := binpacking.ReadBoxesCSV(boxesFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", boxesPath, err)
+	}
+	binsFile, err := os.Open(binsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer binsFile.Close()
+	bins, templates, err := binpacking.ReadBinsCSV(binsFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", binsPath, err)
+	}
+
+	job := &binpacking.Job{}
+	for _, spec := range specs {
+		qty, rotatable := spec.Count, !spec.Box.ConstrainRotation
+		b := spec.Box
+		job.Boxes = append(job.Boxes, binpacking.JobBox{
+			Width: b.Width, Height: b.Height, Qty: &qty, ID: b.ID, Rotatable: &rotatable,
+			Tag: b.Tag, Group: b.Group, Value: b.Value, Weight: b.Weight,
+		})
+	}
+	// The bins are recreated by the job, with the backend it selects.
+	for _, bin := range bins {
+		job.Bins = append(job.Bins, binpacking.JobBin{
+			Width: bin.Width, Height: bin.Height, Qty: 1, Cost: bin.Cost, Spacing: bin.Spacing, MaxWeight: bin.MaxWeight,
+			Name: bin.Name, Material: bin.Material, Thickness: bin.Thickness,
+		})
+	}
+	for _, t := range templates {
+		job.Bins = append(job.Bins, binpacking.JobBin{
+			Width: t.Width, Height: t.Height, Cost: t.Cost, Spacing: t.Spacing, MaxWeight: t.MaxWeight,
+			Name: t.Name, Material: t.Material, Thickness: t.Thickness,
+		})
+	}
+	return job, nil
+}
+
+// usedBins returns the bins holding at least one box.
+func usedBins(bins []*binpacking.Bin) []*binpacking.Bin {
+	used := make([]*binpacking.Bin, 0, len(bins))
+	for _, bin := range bins {
+		if len(bin.Boxes) > 0 {
+			used = append(used, bin)
+		}
+	}
+	return used
+}
+
+// writeJSON writes the result as an indented binpacking.JobResult.
+func writeJSON(w io.Writer, result *binpacking.PackResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(binpacking.NewJobResult(result))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acmacalister/binpacking"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	t.Run("packs a JSON job", func(t *testing.T) {
+		path := writeFile(t, "job.json", `{
+			"bins": [{"width": 10, "height": 10, "qty": 1}],
+			"boxes": [{"width": 5, "height": 10, "qty": 2, "id": "side"}, {"width": 1, "height": 11, "id": "extra", "rotatable": false}]
+		}`)
+		for _, backend := range []string{"maxrects", "guillotine", "skyline", "blf", "grid", "circle"} {
+			var out bytes.Buffer
+			if err := run([]string{"-job", path, "-backend", backend}, &out); err != nil {
+				t.Fatalf("%s: run: %v", backend, err)
+			}
+			var got binpacking.JobResult
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("%s: invalid output: %v\n%s", backend, err, out.String())
+			}
+			if got.BinsUsed != 1 || len(got.Placements) != 3 {
+				t.Fatalf("%s: got %d bins used and %d placements, want 1 and 3", backend, got.BinsUsed, len(got.Placements))
+			}
+			if p := got.Placements[0]; !p.Packed || p.ID != "side" || p.Bin != 1 {
+				t.Errorf("%s: first placement: got %+v", backend, p)
+			}
+			if p := got.Placements[2]; p.Packed || p.ID != "extra" || p.Bin != 0 {
+				t.Errorf("%s: unpacked placement: got %+v", backend, p)
+			}
+		}
+	})
+
+	t.Run("packs CSV files into unlimited stock", func(t *testing.T) {
+		boxes := writeFile(t, "boxes.csv", "width,height,qty,id\n6,6,3,a\n")
+		bins := writeFile(t, "bins.csv", "width,height,qty\n10,10,\n")
+		outPath := filepath.Join(t.TempDir(), "out.csv")
+		if err := run([]string{"-boxes", boxes, "-bins", bins, "-format", "csv", "-o", outPath}, nil); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		data, err := os.ReadFile(outPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "id,packed,bin,x,y,width,height,rotated\n" +
+			"a,true,1,0,0,6,6,false\n" +
+			"a,true,2,0,0,6,6,false\n" +
+			"a,true,3,0,0,6,6,false\n"
+		if got := string(data); got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("writes SVG", func(t *testing.T) {
+		path := writeFile(t, "job.json", `{"bins": [{"width": 10, "height": 10, "qty": 2}], "boxes": [{"width": 4, "height": 4}]}`)
+		var out bytes.Buffer
+		if err := run([]string{"-job", path, "-format", "svg", "-strategy", "contact"}, &out); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		if !strings.HasPrefix(out.String(), "<svg") || strings.Count(out.String(), "<g ") != 1 {
+			t.Errorf("Expected an SVG drawing of the one bin in use, got\n%s", out.String())
+		}
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		path := writeFile(t, "job.json", `{"bins": [], "boxes": []}`)
+		for _, args := range [][]string{
+			{},
+			{"-job", path, "-boxes", path},
+			{"-job", path, "-strategy", "best"},
+			{"-job", path, "-backend", "tree"},
+			{"-job", path, "-format", "pdf"},
+			{"-job", filepath.Join(t.TempDir(), "missing.json")},
+			{"-job", writeFile(t, "bad.json", `{"boxes": [{"width": -1, "height": 1}]}`)},
+		} {
+			if err := run(args, &bytes.Buffer{}); err == nil {
+				t.Errorf("run(%q): got nil error, want an error", args)
+			}
+		}
+	})
+}
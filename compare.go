@@ -0,0 +1,146 @@
+package binpacking
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// StrategyResult is one row of the table returned by CompareStrategies.
+type StrategyResult struct {
+	Name     string                // Name of the strategy, e.g. "best-area-fit", or "custom"
+	Strategy PlacementStrategyFunc // The strategy as given
+
+	Packed     int           // Boxes packed
+	Unpacked   int           // Boxes left unpacked
+	BinsUsed   int           // Bins holding at least one box
+	Efficiency float64       // Percentage of the area of the bins in use occupied by boxes
+	Elapsed    time.Duration // Time the packing took
+
+	// Result is the result of the packing, on copies of the bins and boxes.
+	Result *PackResult
+}
+
+// CompareStrategies packs copies of the bins and boxes once per placement strategy and
+// returns the results ranked best first by options.Metric, then by efficiency, keeping
+// the given order for ties. Without strategies the built-in ones are compared:
+// BestShortSideFit, BestLongSideFit, BestAreaFit, BottomLeft and ContactPointFit, for
+// which any ContactPointFit closure may be given, as it is bound to each copied bin.
+// Every bin and template of a run uses the strategy, replacing their own; Observer and
+// OnProgress are not called. The bins and boxes themselves are not modified.
+func CompareStrategies(bins []*Bin, boxes []*Box, options PackerOptions, strategies ...PlacementStrategyFunc) []StrategyResult {
+	if len(strategies) == 0 {
+		strategies = []PlacementStrategyFunc{BestShortSideFit, BestLongSideFit, BestAreaFit, BottomLeft, ContactPointFit(nil)}
+	}
+	type ranked struct {
+		row   StrategyResult
+		stats runStats
+	}
+	rows := make([]ranked, 0, len(strategies))
+	for _, strategy := range strategies {
+		if strategy == nil {
+			strategy = BestShortSideFit // As for a bin created without one
+		}
+		copies := make(map[*Box]*Box)
+		trialBins := make([]*Bin, len(bins))
+		for i, bin := range bins {
+			if bin != nil {
+				trialBins[i] = bin.cloneWith(copies)
+				trialBins[i].Placement = strategyFor(strategy, trialBins[i])
+			}
+		}
+		trialBoxes := make([]*Box, len(boxes))
+		for i, box := range boxes {
+			trialBoxes[i] = cloneBox(box, copies)
+		}
+		trialOptions := options
+		trialOptions.Observer, trialOptions.OnProgress, trialOptions.AutoPlacement = nil, nil, false
+		trialOptions.BinTemplates = make([]BinTemplate, len(options.BinTemplates))
+		for i, template := range options.BinTemplates {
+			template.Placement = strategy
+			trialOptions.BinTemplates[i] = template
+		}
+
+		packer := NewPacker(trialBins)
+		packed := packer.Pack(trialBoxes, trialOptions)
+		result := packer.Result()
+		rows = append(rows, ranked{
+			row: StrategyResult{
+				Name: placementName(strategy), Strategy: strategy,
+				Packed: len(result.Packed), Unpacked: len(result.Unpacked), BinsUsed: result.BinsUsed,
+				Efficiency: result.Efficiency, Elapsed: result.Elapsed, Result: result,
+			},
+			stats: statsFor(packer.Bins, packed),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if options.Metric.better(a.stats, b.stats) {
+			return true
+		}
+		if options.Metric.better(b.stats, a.stats) {
+			return false
+		}
+		return a.row.Efficiency > b.row.Efficiency
+	})
+	results := make([]StrategyResult, len(rows))
+	for i, row := range rows {
+		results[i] = row.row
+	}
+	return results
+}
+
+// strategyFor returns the strategy for the bin, binding the bin-bound strategies to it.
+func strategyFor(strategy PlacementStrategyFunc, bin *Bin) PlacementStrategyFunc {
+	switch placementName(strategy) {
+	case "contact-point":
+		return ContactPointFit(bin)
+	case "center-out":
+		return CenterFit(bin)
+	case "spread":
+		return SpreadFit(bin)
+	}
+	return strategy
+}
+
+// autoSampleSize is the number of boxes ChooseStrategy packs at most in its trials.
+const autoSampleSize = 200
+
+// ChooseStrategy picks the built-in placement strategy that suits the boxes best: it
+// compares the strategies with CompareStrategies on a sample of up to 200 boxes, taken
+// evenly across the slice, and returns the winner, BestShortSideFit on ties. A chosen
+// ContactPointFit is bound to no bin; bind it with ContactPointFit(bin) for each bin, as
+// Pack does for PackerOptions.AutoPlacement. With a TimeLimit, each trial gets a tenth
+// of it. The bins and boxes are not modified.
+func ChooseStrategy(bins []*Bin, boxes []*Box, options PackerOptions) PlacementStrategyFunc {
+	sample := boxes
+	if len(boxes) > autoSampleSize {
+		sample = make([]*Box, autoSampleSize)
+		for i := range sample {
+			sample[i] = boxes[i*len(boxes)/autoSampleSize]
+		}
+	}
+	if options.TimeLimit > 0 {
+		options.TimeLimit = max(options.TimeLimit/10, time.Nanosecond)
+	}
+	return CompareStrategies(bins, sample, options)[0].Strategy
+}
+
+// applyStrategy sets the strategy chosen for PackerOptions.AutoPlacement on every bin of
+// the packer and returns the templates with it.
+func (p *Packer) applyStrategy(strategy PlacementStrategyFunc, templates []BinTemplate) []BinTemplate {
+	if p.debugging() {
+		p.Logger.Debug("auto placement", slog.String("strategy", placementName(strategy)))
+	}
+	for _, bin := range p.Bins {
+		if bin != nil {
+			bin.Placement = strategyFor(strategy, bin)
+		}
+	}
+	chosen := make([]BinTemplate, len(templates))
+	for i, template := range templates {
+		template.Placement = strategy
+		chosen[i] = template
+	}
+	return chosen
+}
@@ -0,0 +1,113 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestCompareStrategies(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 3))
+	var boxes []*Box
+	for i := 0; i < 40; i++ {
+		boxes = append(boxes, NewBox(float64(2+rng.IntN(15)), float64(2+rng.IntN(15)), false))
+	}
+	bins := []*Bin{NewBin(40, 40, nil)}
+	options := PackerOptions{BinTemplates: []BinTemplate{{Width: 30, Height: 30}}}
+
+	results := CompareStrategies(bins, boxes, options)
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+		if result.Unpacked != 0 || result.Packed != len(boxes) || result.Result == nil {
+			t.Errorf("%s: got %d packed and %d unpacked, want every box packed", result.Name, result.Packed, result.Unpacked)
+		}
+		if err := result.Result.Validate(); err != nil {
+			t.Errorf("%s: %v", result.Name, err)
+		}
+	}
+	slices.Sort(names)
+	if want := []string{"best-area-fit", "best-long-side-fit", "best-short-side-fit", "bottom-left", "contact-point"}; !slices.Equal(names, want) {
+		t.Errorf("got strategies %v, want %v", names, want)
+	}
+	for i := 1; i < len(results); i++ {
+		a, b := results[i-1], results[i]
+		if a.BinsUsed > b.BinsUsed || (a.BinsUsed == b.BinsUsed && a.Efficiency < b.Efficiency) {
+			t.Errorf("got %s (%d bins, %g%%) ranked above %s (%d bins, %g%%)", a.Name, a.BinsUsed, a.Efficiency, b.Name, b.BinsUsed, b.Efficiency)
+		}
+	}
+	if len(bins[0].Boxes) != 0 || slices.ContainsFunc(boxes, func(box *Box) bool { return box.Packed }) {
+		t.Error("got the bins or boxes modified")
+	}
+
+	t.Run("given strategies", func(t *testing.T) {
+		results := CompareStrategies(bins, boxes, PackerOptions{}, BottomLeft, nil)
+		if len(results) != 2 {
+			t.Fatalf("got %d results, want 2", len(results))
+		}
+		for _, result := range results {
+			if result.Name != "bottom-left" && result.Name != "best-short-side-fit" {
+				t.Errorf("got strategy %q", result.Name)
+			}
+		}
+	})
+}
+
+func TestAutoPlacement(t *testing.T) {
+	newBoxes := func() []*Box {
+		rng := rand.New(rand.NewPCG(8, 8))
+		boxes := make([]*Box, 300)
+		for i := range boxes {
+			boxes[i] = NewBox(float64(1+rng.IntN(9)), float64(1+rng.IntN(9)), false)
+		}
+		return boxes
+	}
+
+	t.Run("ChooseStrategy", func(t *testing.T) {
+		boxes := newBoxes()
+		bins := []*Bin{NewBin(60, 60, nil)}
+		chosen := ChooseStrategy(bins, boxes, PackerOptions{})
+		sample := make([]*Box, autoSampleSize)
+		for i := range sample {
+			sample[i] = boxes[i*len(boxes)/autoSampleSize]
+		}
+		if want := CompareStrategies(bins, sample, PackerOptions{})[0].Name; placementName(chosen) != want {
+			t.Errorf("got %s, want %s", placementName(chosen), want)
+		}
+		if len(bins[0].Boxes) != 0 || slices.ContainsFunc(boxes, func(box *Box) bool { return box.Packed }) {
+			t.Error("got the bins or boxes modified")
+		}
+	})
+
+	t.Run("Pack", func(t *testing.T) {
+		bins := []*Bin{NewBin(60, 60, nil), NewContactPointBin(60, 60)}
+		options := PackerOptions{AutoPlacement: true, BinTemplates: []BinTemplate{{Width: 60, Height: 60}}}
+		chosen := placementName(ChooseStrategy(bins, newBoxes(), options))
+		observer := &recordingObserver{}
+		options.Observer = observer
+		packer := NewPacker(bins)
+		packer.Pack(newBoxes(), options)
+		for i, bin := range packer.Bins {
+			if got := placementName(bin.Placement); got != chosen {
+				t.Errorf("bin %d: got strategy %s, want %s", i, got, chosen)
+			}
+		}
+		if len(observer.placements) != len(packer.Result().Packed) {
+			t.Errorf("got %d placements observed, want only the %d of the real run", len(observer.placements), len(packer.Result().Packed))
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{Bins: []JobBin{{Width: 30, Height: 30, Qty: 1}}, Boxes: []JobBox{{Width: 5, Height: 5}}, Options: JobOptions{Strategy: "auto"}}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.BinsUsed != 1 || len(result.Unpacked) != 0 {
+			t.Errorf("got %d bins used and %d unpacked, want 1 and none", result.BinsUsed, len(result.Unpacked))
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package binpacking
+
+import (
+	"slices"
+	"sync"
+)
+
+// SyncBin makes a Bin safe for concurrent use, e.g. by the handlers of an HTTP service
+// sharing stock: every method holds a mutex for the duration of the call, so a CanFit
+// followed by an Insert from another goroutine never sees a half-updated free list.
+// Once wrapped, the bin must only be used through the SyncBin, and it must not also
+// belong to a Packer used concurrently. Boxes given to it must not be modified by other
+// goroutines while it places them.
+type SyncBin struct {
+	mu  sync.Mutex
+	bin *Bin
+}
+
+// NewSyncBin wraps the bin.
+func NewSyncBin(bin *Bin) *SyncBin {
+	return &SyncBin{bin: bin}
+}
+
+// CanFit is Bin.CanFit under the lock. The returned ChosenSpace must not be used, as the
+// bin may change as soon as CanFit returns; Insert decides again.
+func (s *SyncBin) CanFit(box *Box) (PlacementInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bin.CanFit(box)
+}
+
+// Insert is Bin.Insert under the lock.
+func (s *SyncBin) Insert(box *Box) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bin.Insert(box)
+}
+
+// InsertChecked is Bin.InsertChecked under the lock.
+func (s *SyncBin) InsertChecked(box *Box) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bin.InsertChecked(box)
+}
+
+// Place is Bin.Place under the lock.
+func (s *SyncBin) Place(box *Box, x, y float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bin.Place(box, x, y)
+}
+
+// Clear is Bin.Clear under the lock.
+func (s *SyncBin) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bin.Clear()
+}
+
+// Boxes returns a copy of the bin's boxes, in placement order.
+func (s *SyncBin) Boxes() []*Box {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.bin.Boxes)
+}
+
+// Efficiency is Bin.Efficiency under the lock.
+func (s *SyncBin) Efficiency() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bin.Efficiency()
+}
+
+// Do calls f with the bin under the lock, for anything the other methods do not cover.
+// f must not keep the bin or call methods of the SyncBin.
+func (s *SyncBin) Do(f func(bin *Bin)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.bin)
+}
+
+// SyncPacker makes a Packer safe for concurrent use: every method holds a mutex for the
+// duration of the call, so goroutines pack into the shared bins one call at a time.
+// Once wrapped, the packer and its bins must only be used through the SyncPacker.
+// PackerOptions callbacks run under the lock and must not call the SyncPacker.
+type SyncPacker struct {
+	mu     sync.Mutex
+	packer *Packer
+}
+
+// NewSyncPacker wraps the packer.
+func NewSyncPacker(packer *Packer) *SyncPacker {
+	return &SyncPacker{packer: packer}
+}
+
+// Pack is Packer.Pack under the lock.
+func (s *SyncPacker) Pack(boxes []*Box, options PackerOptions) []*Box {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.Pack(boxes, options)
+}
+
+// PackChecked is Packer.PackChecked under the lock.
+func (s *SyncPacker) PackChecked(boxes []*Box, options PackerOptions) ([]*Box, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.PackChecked(boxes, options)
+}
+
+// PackRequired is Packer.PackRequired under the lock.
+func (s *SyncPacker) PackRequired(boxes []*Box, options PackerOptions) ([]*Box, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.PackRequired(boxes, options)
+}
+
+// PackOrders is Packer.PackOrders under the lock.
+func (s *SyncPacker) PackOrders(orders []*Order, options PackerOptions) []*Box {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.PackOrders(orders, options)
+}
+
+// PackResult packs the boxes and returns the result of that call, taken before another
+// goroutine can pack. Its Placements and statistics are values; the bins and boxes it
+// refers to must only be read through Do.
+func (s *SyncPacker) PackResult(boxes []*Box, options PackerOptions) *PackResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packer.Pack(boxes, options)
+	return s.packer.Result()
+}
+
+// CanFit reports whether Pack would place the box into one of the bins right now, and
+// the index of the first bin with room for it.
+func (s *SyncPacker) CanFit(box *Box) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, bin := range s.packer.Bins {
+		if bin == nil {
+			continue
+		}
+		if _, ok := bin.CanFit(box); ok {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// AddBin is Packer.AddBin under the lock.
+func (s *SyncPacker) AddBin(bin *Bin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packer.AddBin(bin)
+}
+
+// RemoveBin is Packer.RemoveBin under the lock.
+func (s *SyncPacker) RemoveBin(bin *Bin) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.RemoveBin(bin)
+}
+
+// Reset is Packer.Reset under the lock.
+func (s *SyncPacker) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packer.Reset()
+}
+
+// BinUsage is Packer.BinUsage under the lock. The groups' Boxes and Offcuts are copies,
+// their Bin must only be read through Do.
+func (s *SyncPacker) BinUsage() []BinGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.packer.BinUsage()
+}
+
+// Do calls f with the packer under the lock, for anything the other methods do not
+// cover. f must not keep the packer or call methods of the SyncPacker.
+func (s *SyncPacker) Do(f func(packer *Packer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.packer)
+}
@@ -0,0 +1,72 @@
+package binpacking
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncBin(t *testing.T) {
+	bin := NewSyncBin(NewBin(40, 40, nil))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	inserted := 0
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				box := NewBox(4, 5, true)
+				if _, ok := bin.CanFit(box); ok && bin.Insert(box) {
+					mu.Lock()
+					inserted++
+					mu.Unlock()
+				}
+				bin.Efficiency()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(bin.Boxes()); got != inserted || got != 80 {
+		t.Errorf("got %d boxes, %d inserted, want 80", got, inserted)
+	}
+	bin.Do(func(bin *Bin) {
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSyncPacker(t *testing.T) {
+	packer := NewSyncPacker(NewPacker(nil))
+	options := PackerOptions{BinTemplates: []BinTemplate{{Width: 20, Height: 20}}}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				result := packer.PackResult([]*Box{NewBox(10, 10, false), NewBox(5, 5, false)}, options)
+				if len(result.Placements) != 2 || !result.Placements[0].Packed {
+					t.Errorf("got %v, want both boxes placed", result.Placements)
+				}
+				if _, ok := packer.CanFit(NewBox(30, 30, false)); ok {
+					t.Error("got a fit for a box larger than any bin")
+				}
+				packer.BinUsage()
+			}
+		}()
+	}
+	wg.Wait()
+	packer.Do(func(p *Packer) {
+		if err := p.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+		boxes := 0
+		for _, bin := range p.EachBin() {
+			boxes += len(bin.Boxes)
+		}
+		if boxes != 80 {
+			t.Errorf("got %d boxes in the bins, want 80", boxes)
+		}
+	})
+}
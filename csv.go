@@ -0,0 +1,309 @@
+package binpacking
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCSV is returned when a cut-list CSV file cannot be parsed.
+var ErrInvalidCSV = errors.New("binpacking: invalid CSV")
+
+// Default column orders of cut-list files without a header row.
+var (
+	boxColumns = []string{"width", "height", "qty", "id", "rotatable"}
+	binColumns = []string{"width", "height", "qty", "cost"}
+)
+
+// csvAliases maps alternative header names, as exported by common cut-list tools, to
+// the column names used here.
+var csvAliases = map[string]string{
+	"w":        "width",
+	"h":        "height",
+	"quantity": "qty", "count": "qty", "q": "qty",
+	"name": "id", "label": "id", "part": "id",
+	"rotate": "rotatable", "rotation": "rotatable",
+	"price": "cost",
+	"kerf":  "spacing",
+}
+
+// csvTable holds the data rows of a cut-list file and the column index of each known column.
+type csvTable struct {
+	rows    [][]string
+	lines   []int // Line of each row in the file, for error messages
+	columns map[string]int
+}
+
+// readCSVTable reads a cut-list file. The first row is a header if its first field is not
+// a number; otherwise the columns are taken in the default order. Blank rows and lines
+// starting with '#' are skipped. Columns are matched case-insensitively and unknown
+// ones are ignored.
+func readCSVTable(r io.Reader, defaults []string) (*csvTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	table := &csvTable{columns: make(map[string]int)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // Blank row, e.g. ",,," from a spreadsheet
+		}
+		if len(table.rows) == 0 && len(table.columns) == 0 {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(record[0]), 64); err != nil {
+				for i, name := range record {
+					name = strings.ToLower(strings.TrimSpace(name))
+					if alias, ok := csvAliases[name]; ok {
+						name = alias
+					}
+					if _, seen := table.columns[name]; !seen {
+						table.columns[name] = i
+					}
+				}
+				if _, ok := table.columns["width"]; !ok {
+					return nil, fmt.Errorf("%w: line %d: no width column", ErrInvalidCSV, line)
+				}
+				if _, ok := table.columns["height"]; !ok {
+					return nil, fmt.Errorf("%w: line %d: no height column", ErrInvalidCSV, line)
+				}
+				continue
+			}
+			for i, name := range defaults {
+				table.columns[name] = i
+			}
+		}
+		table.rows = append(table.rows, record)
+		table.lines = append(table.lines, line)
+	}
+	return table, nil
+}
+
+// field returns the trimmed value of the named column in row i, or "" if the row or the
+// file lacks the column.
+func (t *csvTable) field(i int, name string) string {
+	column, ok := t.columns[name]
+	if !ok || column >= len(t.rows[i]) {
+		return ""
+	}
+	return strings.TrimSpace(t.rows[i][column])
+}
+
+// number parses the named column of row i, returning fallback for an empty field.
+func (t *csvTable) number(i int, name string, fallback float64) (float64, error) {
+	value := t.field(i, name)
+	if value == "" {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: line %d: %s %q is not a number", ErrInvalidCSV, t.lines[i], name, value)
+	}
+	return f, nil
+}
+
+// count parses the named column of row i as a non-negative integer, returning fallback
+// for an empty field.
+func (t *csvTable) count(i int, name string, fallback int) (int, error) {
+	value := t.field(i, name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: line %d: %s %q is not a count", ErrInvalidCSV, t.lines[i], name, value)
+	}
+	return n, nil
+}
+
+// flag parses the named column of row i as a boolean, accepting yes/no and y/n besides
+// the forms of strconv.ParseBool, and returning fallback for an empty field.
+func (t *csvTable) flag(i int, name string, fallback bool) (bool, error) {
+	value := strings.ToLower(t.field(i, name))
+	switch value {
+	case "":
+		return fallback, nil
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: line %d: %s %q is not a boolean", ErrInvalidCSV, t.lines[i], name, value)
+	}
+	return v, nil
+}
+
+// size parses and validates the width and height of row i.
+func (t *csvTable) size(i int) (float64, float64, error) {
+	width, err := t.number(i, "width", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	height, err := t.number(i, "height", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := ValidateDimensions(width, height); err != nil {
+		return 0, 0, fmt.Errorf("line %d: %w", t.lines[i], err)
+	}
+	return width, height, nil
+}
+
+// ReadBoxesCSV reads a cut list with the columns width, height, qty, id and rotatable,
+// in that order or in any order given by a header row, and returns one BoxSpec per row.
+// Only width and height are required: qty defaults to 1, and rotatable to true.
+// Header names are case-insensitive, common aliases such as "quantity" and "name" are
+// understood, and optional tag, group, value, weight and material columns fill the
+// matching Box fields. Rows with a quantity of zero are skipped. The returned error wraps
+// ErrInvalidCSV or, for invalid sizes, ErrInvalidDimensions.
+func ReadBoxesCSV(r io.Reader) ([]BoxSpec, error) {
+	table, err := readCSVTable(r, boxColumns)
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]BoxSpec, 0, len(table.rows))
+	for i := range table.rows {
+		width, height, err := table.size(i)
+		if err != nil {
+			return nil, err
+		}
+		qty, err := table.count(i, "qty", 1)
+		if err != nil {
+			return nil, err
+		}
+		rotatable, err := table.flag(i, "rotatable", true)
+		if err != nil {
+			return nil, err
+		}
+		value, err := table.number(i, "value", 0)
+		if err != nil {
+			return nil, err
+		}
+		weight, err := table.number(i, "weight", 0)
+		if err != nil {
+			return nil, err
+		}
+		if qty == 0 {
+			continue
+		}
+		specs = append(specs, BoxSpec{Count: qty, Box: Box{
+			Width: width, Height: height, ConstrainRotation: !rotatable,
+			ID: table.field(i, "id"), Tag: table.field(i, "tag"), Group: table.field(i, "group"), Material: table.field(i, "material"),
+			Value: value, Weight: weight,
+		}})
+	}
+	return specs, nil
+}
+
+// ReadBinsCSV reads stock sheets with the columns width, height, qty and cost, in that
+// order or in any order given by a header row, and optional spacing (alias kerf),
+// maxweight, material and thickness columns and an id (alias name) column for Bin.Name.
+// A row with a quantity creates that many bins; a row with an empty
+// quantity, or "unlimited", describes stock available in any amount and is returned as a
+// BinTemplate, to be passed in PackerOptions.BinTemplates. The returned error wraps
+// ErrInvalidCSV or, for invalid sizes, ErrInvalidDimensions.
+func ReadBinsCSV(r io.Reader) ([]*Bin, []BinTemplate, error) {
+	table, err := readCSVTable(r, binColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+	bins := make([]*Bin, 0, len(table.rows))
+	templates := make([]BinTemplate, 0)
+	for i := range table.rows {
+		width, height, err := table.size(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		cost, err := table.number(i, "cost", 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		spacing, err := table.number(i, "spacing", 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ValidateDimensions(spacing, 0); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", table.lines[i], err)
+		}
+		maxWeight, err := table.number(i, "maxweight", 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		thickness, err := table.number(i, "thickness", 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		template := BinTemplate{
+			Width: width, Height: height, Cost: cost, MaxWeight: maxWeight, Spacing: spacing,
+			Name: table.field(i, "id"), Material: table.field(i, "material"), Thickness: thickness,
+		}
+		if qty := strings.ToLower(table.field(i, "qty")); qty == "" || qty == "unlimited" {
+			templates = append(templates, template)
+			continue
+		}
+		qty, err := table.count(i, "qty", 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		for n := 0; n < qty; n++ {
+			bins = append(bins, template.NewBin())
+		}
+	}
+	return bins, templates, nil
+}
+
+// WritePlacementsCSV writes the placements of a result as CSV with a header row and the
+// columns id, packed, bin, x, y, width, height and rotated. Bins are numbered from 1 like
+// the sheets written by WriteXLSX; unpacked boxes have empty bin and position fields.
+func WritePlacementsCSV(w io.Writer, result *PackResult) error {
+	writer := csv.NewWriter(w)
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	if err := writer.Write([]string{"id", "packed", "bin", "x", "y", "width", "height", "rotated"}); err != nil {
+		return err
+	}
+	for _, placement := range result.Placements {
+		record := []string{placement.ID, strconv.FormatBool(placement.Packed), "", "", "",
+			format(placement.Width), format(placement.Height), strconv.FormatBool(placement.Rotated)}
+		if placement.Packed {
+			record[2], record[3], record[4] = strconv.Itoa(placement.BinIndex+1), format(placement.X), format(placement.Y)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PackCSV reads a boxes CSV and a bins CSV as described by ReadBoxesCSV and ReadBinsCSV,
+// packs the boxes into the stock like Packer.PackSpecs, with unlimited stock rows added
+// to options.BinTemplates, and writes the placements CSV to w.
+func PackCSV(boxes, bins io.Reader, w io.Writer, options PackerOptions) (*PackResult, error) {
+	specs, err := ReadBoxesCSV(boxes)
+	if err != nil {
+		return nil, err
+	}
+	stock, templates, err := ReadBinsCSV(bins)
+	if err != nil {
+		return nil, err
+	}
+	options.BinTemplates = append(templates, options.BinTemplates...)
+	packer := NewPacker(stock)
+	packer.PackSpecs(specs, options)
+	result := packer.Result()
+	if err := WritePlacementsCSV(w, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
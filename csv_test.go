@@ -0,0 +1,103 @@
+package binpacking
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadBoxesCSV(t *testing.T) {
+	t.Run("reads a header in any order with aliases", func(t *testing.T) {
+		input := "Name,Quantity,Height,Width,Rotatable,Tag\nshelf,2,30,60,no,oak\n,,,,,\n# comment\nback,1,60,60,yes,\n"
+		specs, err := ReadBoxesCSV(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ReadBoxesCSV: %v", err)
+		}
+		if len(specs) != 2 {
+			t.Fatalf("Specs: got %d, want %d", len(specs), 2)
+		}
+		got := specs[0]
+		if got.Count != 2 || got.Box.Width != 60 || got.Box.Height != 30 || got.Box.ID != "shelf" || !got.Box.ConstrainRotation || got.Box.Tag != "oak" {
+			t.Errorf("First spec: got %+v", got)
+		}
+		if specs[1].Box.ConstrainRotation {
+			t.Errorf("Second spec: got ConstrainRotation %v, want %v", true, false)
+		}
+	})
+
+	t.Run("uses the default columns without a header", func(t *testing.T) {
+		specs, err := ReadBoxesCSV(strings.NewReader("10,20,3,a,false\n5,5\n"))
+		if err != nil {
+			t.Fatalf("ReadBoxesCSV: %v", err)
+		}
+		if len(specs) != 2 || specs[0].Count != 3 || specs[0].Box.ID != "a" || !specs[0].Box.ConstrainRotation {
+			t.Errorf("First spec: got %+v", specs[0])
+		}
+		if specs[1].Count != 1 || specs[1].Box.ConstrainRotation {
+			t.Errorf("Second spec: got %+v, want one rotatable box", specs[1])
+		}
+	})
+
+	t.Run("reports invalid rows", func(t *testing.T) {
+		tests := map[string]struct {
+			input string
+			want  error
+		}{
+			"no width column": {"height,qty\n1,2\n", ErrInvalidCSV},
+			"not a number":    {"width,height\n1,abc\n", ErrInvalidCSV},
+			"negative count":  {"1,1,-2\n", ErrInvalidCSV},
+			"not a boolean":   {"1,1,1,a,maybe\n", ErrInvalidCSV},
+			"negative width":  {"-1,1\n", ErrInvalidDimensions},
+		}
+		for name, tt := range tests {
+			if _, err := ReadBoxesCSV(strings.NewReader(tt.input)); !errors.Is(err, tt.want) {
+				t.Errorf("%s: got %v, want %v", name, err, tt.want)
+			}
+		}
+	})
+}
+
+func TestReadBinsCSV(t *testing.T) {
+	input := "width,height,qty,cost,kerf\n100,50,2,10,\n200,100,unlimited,30,0.5\n"
+	bins, templates, err := ReadBinsCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadBinsCSV: %v", err)
+	}
+	if len(bins) != 2 || bins[0] == bins[1] || bins[0].Width != 100 || bins[0].Cost != 10 {
+		t.Errorf("Bins: got %d, want 2 distinct 100x50 bins costing 10", len(bins))
+	}
+	if len(templates) != 1 || templates[0].Width != 200 || templates[0].Spacing != 0.5 || templates[0].Cost != 30 {
+		t.Errorf("Templates: got %+v", templates)
+	}
+}
+
+func TestPackCSV(t *testing.T) {
+	boxes := "id,width,height,qty\na,6,4,2\nb,20,20,1\n"
+	bins := "width,height,qty\n10,10,1\n"
+	var out bytes.Buffer
+	result, err := PackCSV(strings.NewReader(boxes), strings.NewReader(bins), &out, PackerOptions{})
+	if err != nil {
+		t.Fatalf("PackCSV: %v", err)
+	}
+	if len(result.Packed) != 2 {
+		t.Errorf("Packed: got %d, want %d", len(result.Packed), 2)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Reading output: %v", err)
+	}
+	if got, want := strings.Join(records[0], ","), "id,packed,bin,x,y,width,height,rotated"; got != want {
+		t.Errorf("Header: got %v, want %v", got, want)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Rows: got %d, want %d", len(records), 4)
+	}
+	if records[1][0] != "a" || records[1][1] != "true" || records[1][2] != "1" {
+		t.Errorf("Packed row: got %v", records[1])
+	}
+	if got := records[3]; got[0] != "b" || got[1] != "false" || got[2] != "" || got[3] != "" {
+		t.Errorf("Unpacked row: got %v", got)
+	}
+}
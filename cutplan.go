@@ -0,0 +1,161 @@
+package binpacking
+
+import "fmt"
+
+// CutDirection is the orientation of a guillotine cut.
+type CutDirection int
+
+const (
+	// CutVertical is a cut parallel to the Y axis, at an X position.
+	CutVertical CutDirection = iota
+	// CutHorizontal is a cut parallel to the X axis, at a Y position.
+	CutHorizontal
+)
+
+// String returns "vertical" or "horizontal".
+func (d CutDirection) String() string {
+	if d == CutHorizontal {
+		return "horizontal"
+	}
+	return "vertical"
+}
+
+// Cut is one straight edge-to-edge cut through a piece.
+type Cut struct {
+	Direction CutDirection
+	Position  float64      // X of a vertical cut or Y of a horizontal one, where the kerf starts
+	Kerf      float64      // Width of material the cut removes; the bin's Spacing
+	Piece     FreeSpaceBox // The piece being cut
+}
+
+// CutNode is a piece of material in a cut plan. A node is either cut into two children,
+// or is a leaf holding exactly one box, or is an offcut holding none.
+type CutNode struct {
+	Piece    FreeSpaceBox // Region of the bin covered by this piece
+	Cut      *Cut         // The cut splitting the piece; nil for leaves
+	Children []*CutNode   // The pieces before and after the cut, in that order
+	Box      *Box         // The box the leaf piece yields; nil for offcuts and cut pieces
+}
+
+// CutPlan returns the cut tree of the bin: the full sheet at the root, cut recursively
+// until every box is a piece of its own. Cuts follow box edges; edges of pieces that
+// would still need trimming get a cut of their own, so every leaf with a box matches it
+// exactly. The plan is depth-first: a piece is cut completely before its successor.
+// The returned error wraps ErrNotGuillotine if the layout cannot be cut this way.
+func (b *Bin) CutPlan() (*CutNode, error) {
+	if err := ValidateGuillotine(b); err != nil {
+		return nil, err
+	}
+	return planCuts(FreeSpaceBox{Width: b.Width, Height: b.Height}, b.Boxes, max(b.Spacing, 0)), nil
+}
+
+// Cuts returns the cuts of the plan in the order a saw operator makes them.
+func (n *CutNode) Cuts() []Cut {
+	cuts := make([]Cut, 0)
+	var walk func(node *CutNode)
+	walk = func(node *CutNode) {
+		if node.Cut == nil {
+			return
+		}
+		cuts = append(cuts, *node.Cut)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return cuts
+}
+
+// Leaves returns the uncut pieces of the plan, boxes and offcuts, in cutting order.
+func (n *CutNode) Leaves() []*CutNode {
+	leaves := make([]*CutNode, 0)
+	var walk func(node *CutNode)
+	walk = func(node *CutNode) {
+		if node.Cut == nil {
+			leaves = append(leaves, node)
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return leaves
+}
+
+// String describes the cut, e.g. "vertical cut at x=40 through 100x50 at [0,0]".
+func (c Cut) String() string {
+	axis := "x"
+	if c.Direction == CutHorizontal {
+		axis = "y"
+	}
+	return fmt.Sprintf("%s cut at %s=%g through %gx%g at [%g,%g]", c.Direction, axis, c.Position, c.Piece.Width, c.Piece.Height, c.Piece.X, c.Piece.Y)
+}
+
+// planCuts builds the cut tree of piece, given the boxes inside it, which must be
+// guillotine-separable. Every cut removes kerf worth of material after its position.
+func planCuts(piece FreeSpaceBox, boxes []*Box, kerf float64) *CutNode {
+	node := &CutNode{Piece: piece}
+	if len(boxes) == 0 {
+		return node // Offcut
+	}
+	if len(boxes) == 1 {
+		box := boxes[0]
+		if box.X == piece.X && box.Y == piece.Y && box.Width == piece.Width && box.Height == piece.Height {
+			node.Box = box
+			return node
+		}
+	}
+
+	if len(boxes) > 1 {
+		for _, direction := range []CutDirection{CutVertical, CutHorizontal} {
+			first, second, ok := guillotineCut(boxes, direction == CutVertical)
+			if !ok {
+				continue
+			}
+			position := 0.0
+			for _, box := range first {
+				if direction == CutVertical {
+					position = max(position, box.X+box.Width)
+				} else {
+					position = max(position, box.Y+box.Height)
+				}
+			}
+			return node.split(direction, position, kerf, first, second)
+		}
+	}
+
+	// A single box with surplus material: trim one side of it at a time, keeping the box
+	// in whichever piece holds it.
+	box := boxes[0]
+	switch {
+	case box.X > piece.X:
+		return node.split(CutVertical, max(box.X-kerf, piece.X), kerf, nil, boxes)
+	case box.X+box.Width < piece.X+piece.Width:
+		return node.split(CutVertical, box.X+box.Width, kerf, boxes, nil)
+	case box.Y > piece.Y:
+		return node.split(CutHorizontal, max(box.Y-kerf, piece.Y), kerf, nil, boxes)
+	case box.Y+box.Height < piece.Y+piece.Height:
+		return node.split(CutHorizontal, box.Y+box.Height, kerf, boxes, nil)
+	}
+	node.Box = box // Closer to an edge than the kerf; nothing left to trim
+	return node
+}
+
+// split cuts the node's piece at position and plans both resulting pieces.
+func (n *CutNode) split(direction CutDirection, position, kerf float64, first, second []*Box) *CutNode {
+	piece := n.Piece
+	n.Cut = &Cut{Direction: direction, Position: position, Kerf: kerf, Piece: piece}
+	before, after := piece, piece
+	if direction == CutVertical {
+		before.Width = position - piece.X
+		after.X = min(position+kerf, piece.X+piece.Width)
+		after.Width = piece.X + piece.Width - after.X
+	} else {
+		before.Height = position - piece.Y
+		after.Y = min(position+kerf, piece.Y+piece.Height)
+		after.Height = piece.Y + piece.Height - after.Y
+	}
+	n.Children = []*CutNode{planCuts(before, first, kerf), planCuts(after, second, kerf)}
+	return n
+}
@@ -0,0 +1,75 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCutPlan(t *testing.T) {
+	t.Run("cuts every box free", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		a, b, c := NewBox(6, 4, true), NewBox(4, 4, true), NewBox(8, 6, true)
+		bin.Place(a, 0, 0)
+		bin.Place(b, 6, 0)
+		bin.Place(c, 0, 4)
+		plan, err := bin.CutPlan()
+		if err != nil {
+			t.Fatalf("CutPlan: %v", err)
+		}
+		cuts := plan.Cuts()
+		if len(cuts) == 0 || cuts[0].Direction != CutHorizontal || cuts[0].Position != 4 {
+			t.Fatalf("First cut: got %v, want horizontal cut at y=4", cuts)
+		}
+		found := make(map[*Box]bool)
+		offcutArea := 0.0
+		for _, leaf := range plan.Leaves() {
+			if leaf.Box == nil {
+				offcutArea += leaf.Piece.Width * leaf.Piece.Height
+				continue
+			}
+			found[leaf.Box] = true
+		}
+		if len(found) != 3 {
+			t.Errorf("Boxes cut free: got %d, want %d", len(found), 3)
+		}
+		if offcutArea != 100-24-16-48 {
+			t.Errorf("Offcut area: got %v, want %v", offcutArea, 100-24-16-48)
+		}
+	})
+
+	t.Run("accounts for the kerf", func(t *testing.T) {
+		bin := NewBin(10, 4, nil)
+		bin.SetSpacing(1)
+		NewPacker([]*Bin{bin}).Pack([]*Box{NewBox(4, 2, true), NewBox(4, 2, true)}, PackerOptions{})
+		plan, err := bin.CutPlan()
+		if err != nil {
+			t.Fatalf("CutPlan: %v", err)
+		}
+		total := 0.0
+		for _, leaf := range plan.Leaves() {
+			total += leaf.Piece.Width * leaf.Piece.Height
+		}
+		kerf := 0.0
+		for _, cut := range plan.Cuts() {
+			if cut.Direction == CutVertical {
+				kerf += cut.Kerf * cut.Piece.Height
+			} else {
+				kerf += cut.Kerf * cut.Piece.Width
+			}
+		}
+		if total+kerf != 40 {
+			t.Errorf("Pieces plus kerf: got %v, want %v", total+kerf, 40)
+		}
+	})
+
+	t.Run("rejects uncuttable layouts", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Place(NewBox(6, 4, true), 0, 0)
+		bin.Place(NewBox(4, 6, true), 6, 0)
+		bin.Place(NewBox(6, 4, true), 4, 6)
+		bin.Place(NewBox(4, 6, true), 0, 4)
+		if _, err := bin.CutPlan(); !errors.Is(err, ErrNotGuillotine) {
+			t.Errorf("CutPlan: got %v, want ErrNotGuillotine", err)
+		}
+	})
+}
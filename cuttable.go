@@ -0,0 +1,92 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNotGuillotine is returned by ValidateGuillotine when a layout cannot be cut apart
+// with straight edge-to-edge cuts.
+var ErrNotGuillotine = errors.New("binpacking: layout is not guillotine-cuttable")
+
+// ValidateGuillotine checks that the boxes in the bin can be separated by a sequence of
+// guillotine cuts: straight cuts running from one edge of the current piece to the
+// opposite edge, as made by a panel saw. This holds for every layout of the guillotine
+// and shelf algorithms, but not necessarily for MaxRects or skyline layouts.
+// The returned error wraps ErrNotGuillotine and names a piece that cannot be cut.
+func ValidateGuillotine(bin *Bin) error {
+	if stuck := uncuttable(bin.Boxes); stuck != nil {
+		return fmt.Errorf("%w: %d boxes in %gx%g at [%g,%g] have no straight cut between them",
+			ErrNotGuillotine, len(stuck)-1, stuck[0].Width, stuck[0].Height, stuck[0].X, stuck[0].Y)
+	}
+	return nil
+}
+
+// uncuttable recursively cuts the boxes apart and returns the boxes of the first piece
+// that has no guillotine cut, or nil if all of them can be separated. The returned slice
+// starts with a stand-in box covering that piece.
+func uncuttable(boxes []*Box) []*Box {
+	if len(boxes) <= 1 {
+		return nil
+	}
+	for _, vertical := range []bool{true, false} {
+		if first, second, ok := guillotineCut(boxes, vertical); ok {
+			if stuck := uncuttable(first); stuck != nil {
+				return stuck
+			}
+			return uncuttable(second)
+		}
+	}
+	// Report the bounding box of the stuck boxes.
+	left, top, right, bottom := boxes[0].X, boxes[0].Y, boxes[0].X+boxes[0].Width, boxes[0].Y+boxes[0].Height
+	for _, box := range boxes[1:] {
+		left, top = min(left, box.X), min(top, box.Y)
+		right, bottom = max(right, box.X+box.Width), max(bottom, box.Y+box.Height)
+	}
+	return append([]*Box{{X: left, Y: top, Width: right - left, Height: bottom - top}}, boxes...)
+}
+
+// guillotineCut looks for a straight cut, vertical (at some X) or horizontal (at some Y),
+// that crosses no box and has boxes on both sides, and returns the two sides.
+func guillotineCut(boxes []*Box, vertical bool) (first, second []*Box, ok bool) {
+	start := func(box *Box) float64 {
+		if vertical {
+			return box.X
+		}
+		return box.Y
+	}
+	end := func(box *Box) float64 {
+		if vertical {
+			return box.X + box.Width
+		}
+		return box.Y + box.Height
+	}
+	// Sweep the boxes by their start; a cut fits wherever every box seen so far ends
+	// before the next box starts.
+	sorted := slices.Clone(boxes)
+	slices.SortStableFunc(sorted, func(a, b *Box) int {
+		switch {
+		case start(a) < start(b):
+			return -1
+		case start(a) > start(b):
+			return 1
+		}
+		return 0
+	})
+	reach := end(sorted[0])
+	for i := 1; i < len(sorted); i++ {
+		if start(sorted[i]) >= reach {
+			return sorted[:i], sorted[i:], true
+		}
+		reach = max(reach, end(sorted[i]))
+	}
+	return nil, nil, false
+}
+
+// keepsGuillotine reports whether inserting box into bin, as InsertWith would, leaves
+// the layout guillotine-cuttable. The bin and box are not modified.
+func keepsGuillotine(bin *Bin, box *Box, options *TagOptions) bool {
+	scratch := bin.scratch()
+	return scratch.InsertWith(trialBox(box), options) && uncuttable(scratch.Boxes) == nil
+}
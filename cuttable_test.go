@@ -0,0 +1,55 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateGuillotine(t *testing.T) {
+	place := func(bin *Bin, boxes ...[4]float64) {
+		for _, b := range boxes {
+			bin.Place(NewBox(b[2], b[3], true), b[0], b[1])
+		}
+	}
+
+	t.Run("accepts cuttable layouts", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		place(bin, [4]float64{0, 0, 6, 4}, [4]float64{6, 0, 4, 4}, [4]float64{0, 4, 3, 6}, [4]float64{3, 4, 7, 3})
+		if err := ValidateGuillotine(bin); err != nil {
+			t.Errorf("ValidateGuillotine: got %v, want nil", err)
+		}
+	})
+
+	t.Run("rejects a pinwheel", func(t *testing.T) {
+		// Four boxes around a centre square; every straight cut crosses a box.
+		bin := NewBin(10, 10, nil)
+		place(bin, [4]float64{0, 0, 6, 4}, [4]float64{6, 0, 4, 6}, [4]float64{4, 6, 6, 4}, [4]float64{0, 4, 4, 6})
+		if err := ValidateGuillotine(bin); !errors.Is(err, ErrNotGuillotine) {
+			t.Errorf("ValidateGuillotine: got %v, want ErrNotGuillotine", err)
+		}
+	})
+
+	t.Run("enforces cuttable layouts when packing", func(t *testing.T) {
+		newBoxes := func() []*Box {
+			boxes := make([]*Box, 0)
+			for i := 0; i < 15; i++ {
+				boxes = append(boxes, NewBox(float64(1+(i*7+3)%5), float64(1+(i*3+6)%6), false))
+			}
+			return boxes
+		}
+		plain := NewBin(10, 10, nil)
+		NewPacker([]*Bin{plain}).Pack(newBoxes(), PackerOptions{})
+		if ValidateGuillotine(plain) == nil {
+			t.Fatalf("Precondition: the plain MaxRects layout should not be cuttable")
+		}
+
+		bin := NewBin(10, 10, nil)
+		packed := NewPacker([]*Bin{bin}).Pack(newBoxes(), PackerOptions{Guillotine: true})
+		if err := ValidateGuillotine(bin); err != nil {
+			t.Errorf("ValidateGuillotine: %v", err)
+		}
+		if len(packed) == 0 {
+			t.Errorf("Packed: got none")
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package binpacking
+
+// Jobs often hold thousands of copies of a few parts. Copies of a box score the same in
+// a bin, so the scoreboard scores one entry per group of identical boxes and bin, and
+// copies the result to the entries of the other boxes in the group. The scores are kept
+// in a cache keyed by the bin's Generation, so an entry scored against a bin that has not
+// changed since an identical box was scored, as when a sequential run adds the next box,
+// is served from the cache. Entries stay one per box, so BestFit and the layout are
+// exactly as if every entry had been scored on its own. This relies on strategies scoring
+// from the bin and the box's size alone, as all built-in ones do.
+
+// boxSpec is what a box's placement score depends on: its size, whether it may rotate,
+// its weight, checked against Bin.MaxWeight, its Tag, which selects its TagOptions, its
+// anchor, whether it is round, whether it may be mirrored and the materials it accepts.
+type boxSpec struct {
+	width, height     float64
+	weight            float64
+	constrainRotation bool
+	tag               string
+	anchor            Anchor
+	round             bool
+	allowMirror       bool
+	materials         string
+}
+
+// specOf returns the spec of the box.
+func specOf(box *Box) boxSpec {
+	return boxSpec{box.Width, box.Height, box.Weight, box.ConstrainRotation, box.Tag, box.Anchor, box.Round, box.AllowMirror, box.Material}
+}
+
+// scoreTwin is an entry scored by copying the score of an identical one.
+type scoreTwin struct {
+	entry, from *ScoreBoardEntry
+}
+
+// specID returns the number of the box's spec in the scoreboard, numbering new specs
+// from one.
+func (sb *ScoreBoard) specID(box *Box) int32 {
+	if sb.specs == nil {
+		sb.specs = make(map[boxSpec]int32)
+		sb.specList = make([]boxSpec, 1) // Spec numbers start from one
+	}
+	spec := specOf(box)
+	id, ok := sb.specs[spec]
+	if !ok {
+		id = int32(len(sb.specList))
+		sb.specs[spec] = id
+		sb.specList = append(sb.specList, spec)
+	}
+	return id
+}
+
+// dedupe splits the entries to score into those that must be scored and those that
+// need not: entries whose score is in the cache get it right away, and entries for a
+// box identical to one of an entry being scored for the same bin are kept in sb.twins to
+// copy its score. The bins must not change until copyTwins has run.
+func (sb *ScoreBoard) dedupe(entries []*ScoreBoardEntry) []*ScoreBoardEntry {
+	if len(sb.specList) == 0 {
+		return entries // No entry has a spec
+	}
+	if sb.cache == nil {
+		sb.cache = make(map[scoreKey]*cachedScore)
+	}
+	unique := sb.unique[:0]
+	for _, entry := range entries {
+		if entry.spec <= 0 || entry.Bin == nil || entry.Box == nil || specOf(entry.Box) != sb.specList[entry.spec] {
+			unique = append(unique, entry) // Not created by the scoreboard, or the box changed
+			continue
+		}
+		key := scoreKey{entry.Bin, entry.spec, entry.Options != nil}
+		cached := sb.cache[key]
+		switch {
+		case cached == nil:
+			cached = &cachedScore{}
+			sb.cache[key] = cached
+		case cached.from != nil:
+			sb.twins = append(sb.twins, scoreTwin{entry, cached.from})
+			continue
+		case cached.validFor(entry.Bin):
+			entry.Score, entry.space, entry.spaceScore = cached.score, cached.space, cached.spaceScore
+			continue
+		}
+		cached.from = entry
+		sb.scoring = append(sb.scoring, cached)
+		unique = append(unique, entry)
+	}
+	sb.unique = unique
+	return unique
+}
+
+// copyTwins caches the scores of the entries scored for the cache and gives the twins
+// found by dedupe the scores of their identical entries.
+func (sb *ScoreBoard) copyTwins() {
+	for _, cached := range sb.scoring {
+		from, bin := cached.from, cached.from.Bin
+		*cached = cachedScore{
+			generation: bin.generation, spaces: len(bin.FreeSpaces), first: firstSpace(bin), boxes: len(bin.Boxes),
+			score: from.Score, spaceScore: from.spaceScore, space: from.space,
+		}
+	}
+	for _, twin := range sb.twins {
+		twin.entry.Score, twin.entry.space, twin.entry.spaceScore = twin.from.Score, twin.from.space, twin.from.spaceScore
+	}
+	clear(sb.scoring)
+	sb.scoring = sb.scoring[:0]
+	clear(sb.twins)
+	sb.twins = sb.twins[:0]
+	clear(sb.unique)
+	sb.unique = sb.unique[:0]
+}
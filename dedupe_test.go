@@ -0,0 +1,97 @@
+package binpacking
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestScoreDedupe(t *testing.T) {
+	countingBins := func(calls *int) []*Bin {
+		strategy := func(space *FreeSpaceBox, width, height float64) Score {
+			*calls++
+			return BestShortSideFit(space, width, height)
+		}
+		return []*Bin{NewBin(10, 10, strategy), NewBin(20, 5, strategy)}
+	}
+	copies := func(n int, width, height float64) []*Box {
+		boxes := make([]*Box, n)
+		for i := range boxes {
+			boxes[i] = NewBox(width, height, false)
+		}
+		return boxes
+	}
+
+	t.Run("scored once", func(t *testing.T) {
+		var one, many int
+		NewScoreBoard(countingBins(&one), copies(1, 3, 4))
+		board := NewScoreBoard(countingBins(&many), copies(500, 3, 4))
+		if many != one {
+			t.Errorf("got %d strategy calls for 500 copies, want %d as for one", many, one)
+		}
+		for _, entry := range board.Entries {
+			if want := entry.Bin.ScoreFor(entry.Box); entry.Score != want {
+				t.Fatalf("got score %v, want %v", entry.Score, want)
+			}
+		}
+	})
+
+	t.Run("different boxes", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.MaxWeight = 5
+		heavy, light := NewBox(3, 4, false), NewBox(3, 4, false)
+		heavy.Weight = 10
+		tagged := NewBox(3, 4, false)
+		tagged.Tag = "fixed"
+		board := NewScoreBoardWithTags([]*Bin{bin}, []*Box{light, heavy, tagged},
+			map[string]TagOptions{"fixed": {Placement: BottomLeft, ConstrainRotation: true}})
+		for _, entry := range board.Entries {
+			if want := bin.ScoreForWith(entry.Box, entry.Options); entry.Score != want {
+				t.Errorf("box %v: got score %v, want %v", entry.Box, entry.Score, want)
+			}
+		}
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(20, 5, nil)}
+		boxes := append(copies(400, 3, 4), copies(400, 2, 6)...)
+		board := NewParallelScoreBoard(bins, boxes, nil, 4)
+		for _, entry := range board.Entries {
+			if want := entry.Bin.ScoreFor(entry.Box); entry.Score != want {
+				t.Fatalf("got score %v, want %v", entry.Score, want)
+			}
+		}
+	})
+
+	t.Run("Pack", func(t *testing.T) {
+		// Distinct tags keep the copies from being deduplicated without changing how
+		// they score, so both runs must produce the same layout.
+		layout := func(distinct bool) []FreeSpaceBox {
+			sizes := [][2]float64{{3, 4}, {5, 2}, {2, 2}}
+			boxes := make([]*Box, 300)
+			for i := range boxes {
+				boxes[i] = NewBox(sizes[i%3][0], sizes[i%3][1], false)
+				if distinct {
+					boxes[i].Tag = strconv.Itoa(i)
+				}
+			}
+			bins := []*Bin{NewBin(30, 30, nil), NewBin(25, 20, nil), NewBin(30, 30, nil)}
+			NewPacker(bins).Pack(boxes, PackerOptions{})
+			var placed []FreeSpaceBox
+			for _, bin := range bins {
+				for _, box := range bin.Boxes {
+					placed = append(placed, FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height})
+				}
+			}
+			return placed
+		}
+		got, want := layout(false), layout(true)
+		if len(got) != len(want) {
+			t.Fatalf("got %d boxes placed, want %d", len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("box %d: got %v, want %v", i, got[i], want[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package binpacking
+
+import "fmt"
+
+// AddDefect marks a rectangular region of the bin, such as a knot, damage or a pre-cut
+// hole, as unusable. The free area is split around it immediately, so no box is ever
+// placed over a defect. Parts of the region outside the bin are ignored. Defects are not
+// boxes: they do not appear in Boxes and do not count towards Efficiency.
+//
+// The returned error wraps ErrInvalidDimensions for invalid sizes and ErrPlacement if
+// the region overlaps a box already in the bin or the backend does not implement FixedPlacer.
+func (b *Bin) AddDefect(x, y, width, height float64) error {
+	if err := ValidateDimensions(width, height); err != nil {
+		return err
+	}
+	left, top := max(x, 0), max(y, 0)
+	defect := FreeSpaceBox{X: left, Y: top, Width: min(x+width, b.Width) - left, Height: min(y+height, b.Height) - top}
+	if defect.Width <= 0 || defect.Height <= 0 {
+		return nil // Entirely outside the bin
+	}
+	for _, box := range b.Boxes {
+		if rectsOverlap(&defect, &FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}, b.tolerance()) {
+			return fmt.Errorf("%w: defect %gx%g at [%g,%g] overlaps %s", ErrPlacement, defect.Width, defect.Height, defect.X, defect.Y, box.Label())
+		}
+	}
+	placer, ok := b.backend().(FixedPlacer)
+	if !ok {
+		return fmt.Errorf("%w: backend %T does not support defects", ErrPlacement, b.backend())
+	}
+	placer.PlaceFixed(b, b.padded(defectBox(defect)))
+	b.settleFreeSpaces()
+	b.Defects = append(b.Defects, defect)
+	b.touch()
+	return nil
+}
+
+// defectBox returns a packed stand-in box covering a defect, for code that treats
+// defects like placed boxes.
+func defectBox(defect FreeSpaceBox) *Box {
+	return &Box{X: defect.X, Y: defect.Y, Width: defect.Width, Height: defect.Height, ConstrainRotation: true, Packed: true, Locked: true}
+}
+
+// blocked returns the regions of the bin that are unusable regardless of its contents:
+// the margin strips followed by the defects. With spacing, zero-width strips along the
+// left and top edges are included, which padding turns into the gap to those edges.
+func (b *Bin) blocked() []FreeSpaceBox {
+	strips := b.marginStrips()
+	if b.Spacing > 0 {
+		strips = append(strips, FreeSpaceBox{Height: b.Height}, FreeSpaceBox{Width: b.Width})
+	}
+	if len(strips) == 0 {
+		return b.Defects
+	}
+	return append(strips, b.Defects...)
+}
+
+// occupied returns the boxes in the bin followed by stand-ins for its margins and
+// defects, all padded by the spacing, i.e. every region a new padded box must not overlap.
+func (b *Bin) occupied() []*Box {
+	blocked := b.blocked()
+	if len(blocked) == 0 && b.Spacing <= 0 {
+		return b.Boxes
+	}
+	occupied := make([]*Box, 0, len(b.Boxes)+len(blocked))
+	for _, box := range b.Boxes {
+		occupied = append(occupied, b.padded(box))
+	}
+	for _, region := range blocked {
+		occupied = append(occupied, b.padded(defectBox(region)))
+	}
+	return occupied
+}
@@ -0,0 +1,57 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddDefect(t *testing.T) {
+	bins := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(10, 10, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(10, 10, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(10, 10) },
+	}
+	for name, newBin := range bins {
+		t.Run("never places boxes over defects with "+name, func(t *testing.T) {
+			bin := newBin()
+			if err := bin.AddDefect(2, 2, 3, 3); err != nil {
+				t.Fatalf("AddDefect: %v", err)
+			}
+			boxes := make([]*Box, 0)
+			for i := 0; i < 25; i++ {
+				boxes = append(boxes, NewBox(2, 2, true))
+			}
+			NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+			defect := bin.Defects[0]
+			for _, box := range bin.Boxes {
+				if rectsOverlap(&defect, &FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}, 0) {
+					t.Errorf("Box %s overlaps the defect", box.Label())
+				}
+			}
+			if len(bin.Boxes) == 0 {
+				t.Errorf("No boxes packed around the defect")
+			}
+		})
+	}
+
+	t.Run("survives emptying the bin", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.AddDefect(0, 0, 10, 5)
+		bin.reset()
+		if bin.Insert(NewBox(10, 6, true)) {
+			t.Errorf("Insert over the defect after reset: got true, want false")
+		}
+	})
+
+	t.Run("clips to the bin and rejects overlaps", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Insert(NewBox(4, 4, true))
+		if err := bin.AddDefect(8, 8, 5, 5); err != nil || bin.Defects[0].Width != 2 {
+			t.Errorf("Clipped defect: got %v, %+v", err, bin.Defects)
+		}
+		if err := bin.AddDefect(3, 3, 2, 2); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Overlapping defect: got %v, want ErrPlacement", err)
+		}
+	})
+}
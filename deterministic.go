@@ -0,0 +1,45 @@
+package binpacking
+
+import (
+	"cmp"
+	"slices"
+)
+
+// CanonicalOrder sorts the boxes into the order PackerOptions.Deterministic packs them
+// in: largest area first, then widest, then by every other field that can influence
+// packing (rotation, mirroring, ID, Tag, Group, OrderID, Value, Priority, Required, Weight and Material), so the order
+// depends on the boxes alone and not on how they were listed. Boxes equal in all of these
+// keep their relative order, which only matters if their Data differ or they are clusters
+// with different members.
+func CanonicalOrder(boxes []*Box) {
+	slices.SortStableFunc(boxes, func(a, b *Box) int {
+		return cmp.Or(
+			cmp.Compare(b.Area(), a.Area()),
+			cmp.Compare(b.Width, a.Width),
+			cmp.Compare(b.Height, a.Height),
+			compareBool(a.ConstrainRotation, b.ConstrainRotation),
+			compareBool(a.AllowMirror, b.AllowMirror),
+			cmp.Compare(a.ID, b.ID),
+			cmp.Compare(a.Tag, b.Tag),
+			cmp.Compare(a.Group, b.Group),
+			cmp.Compare(a.OrderID, b.OrderID),
+			cmp.Compare(b.Value, a.Value),
+			cmp.Compare(b.Priority, a.Priority),
+			compareBool(b.Required, a.Required),
+			cmp.Compare(a.Weight, b.Weight),
+			cmp.Compare(a.Material, b.Material),
+		)
+	})
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,102 @@
+package binpacking
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+// layoutOf describes where every box of the bins ended up, in placement order.
+func layoutOf(bins []*Bin) []string {
+	var layout []string
+	for i, bin := range bins {
+		for _, box := range bin.Boxes {
+			layout = append(layout, fmt.Sprintf("%d %s %gx%g at [%g,%g]", i, box.ID, box.Width, box.Height, box.X, box.Y))
+		}
+	}
+	return layout
+}
+
+func TestDeterministic(t *testing.T) {
+	// Many equal sizes, so that placements tie all the time.
+	newBoxes := func() []*Box {
+		rng := rand.New(rand.NewPCG(4, 4))
+		boxes := make([]*Box, 80)
+		for i := range boxes {
+			boxes[i] = NewBox(float64(5*(1+rng.IntN(4))), float64(5*(1+rng.IntN(4))), i%3 == 0)
+			boxes[i].ID = fmt.Sprintf("box%d", i)
+		}
+		return boxes
+	}
+	pack := func(boxes []*Box, options PackerOptions) *Packer {
+		packer := NewPacker([]*Bin{NewBin(50, 50, nil)})
+		options.BinTemplates = []BinTemplate{{Width: 40, Height: 60}}
+		packer.Pack(boxes, options)
+		return packer
+	}
+
+	t.Run("CurrentBoxes keeps the entry order", func(t *testing.T) {
+		boxes := newBoxes()
+		for round := 0; round < 10; round++ {
+			board := NewScoreBoard([]*Bin{NewBin(50, 50, nil)}, boxes)
+			if got := board.CurrentBoxes(); !slices.Equal(got, boxes) {
+				t.Fatalf("round %d: got the boxes in another order", round)
+			}
+			bin := NewBin(60, 60, nil)
+			board.AddBin(bin)
+			var added []*Box
+			for _, entry := range board.Entries {
+				if entry.Bin == bin {
+					added = append(added, entry.Box)
+				}
+			}
+			if !slices.Equal(added, boxes) {
+				t.Fatalf("round %d: AddBin added the entries in another order", round)
+			}
+		}
+	})
+
+	t.Run("same input, same layout", func(t *testing.T) {
+		want := layoutOf(pack(newBoxes(), PackerOptions{}).Bins)
+		for round := 0; round < 5; round++ {
+			if got := layoutOf(pack(newBoxes(), PackerOptions{}).Bins); !slices.Equal(got, want) {
+				t.Fatalf("round %d: got a different layout", round)
+			}
+		}
+	})
+
+	t.Run("input order does not matter", func(t *testing.T) {
+		for _, restarts := range []int{0, 3} {
+			options := PackerOptions{Deterministic: true, Restarts: restarts, Seed: 9}
+			want := layoutOf(pack(newBoxes(), options).Bins)
+			rng := rand.New(rand.NewPCG(1, 1))
+			for round := 0; round < 5; round++ {
+				boxes := newBoxes()
+				rng.Shuffle(len(boxes), func(i, j int) { boxes[i], boxes[j] = boxes[j], boxes[i] })
+				if got := layoutOf(pack(boxes, options).Bins); !slices.Equal(got, want) {
+					t.Fatalf("restarts %d, round %d: got a different layout", restarts, round)
+				}
+			}
+		}
+	})
+
+	t.Run("time limit ignored", func(t *testing.T) {
+		packer := pack(newBoxes(), PackerOptions{Deterministic: true, TimeLimit: time.Nanosecond})
+		result := packer.Result()
+		if result.TimedOut || len(result.Unpacked) != 0 {
+			t.Errorf("got timed out %v with %d boxes unpacked, want every box packed", result.TimedOut, len(result.Unpacked))
+		}
+	})
+
+	t.Run("CanonicalOrder", func(t *testing.T) {
+		a, b, c, d := NewBox(2, 5, false), NewBox(5, 2, false), NewBox(5, 2, true), NewBox(20, 1, false)
+		b.ID = "b"
+		boxes := []*Box{c, a, d, b}
+		CanonicalOrder(boxes)
+		if want := []*Box{d, b, c, a}; !slices.Equal(boxes, want) {
+			t.Errorf("got %v, want %v", boxes, want)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package binpacking
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DXFLayering selects how WriteDXF assigns boxes to layers.
+type DXFLayering int
+
+const (
+	// DXFLayerPerBox puts every box on a layer of its own, named after Box.ID when set.
+	DXFLayerPerBox DXFLayering = iota
+	// DXFLayerPerGroup puts boxes on a layer per Box.Group; ungrouped boxes share one layer.
+	DXFLayerPerGroup
+)
+
+// DXFOptions controls the drawing written by WriteDXF.
+type DXFOptions struct {
+	Layering DXFLayering // How boxes are assigned to layers
+}
+
+// WriteDXF writes the bin's layout to w as an ASCII DXF (AutoCAD R12) drawing for CAD and
+// CAM software. The sheet outline is on layer SHEET, defects on layer DEFECTS and every
+// box is a closed polyline on the layer chosen by options.Layering. DXF's Y axis points
+// up, so the layout is mirrored vertically to keep the bin's top-left corner top-left;
+// the drawing spans (0,0) to (Width,Height) in the bin's units.
+func WriteDXF(w io.Writer, bin *Bin, options DXFOptions) error {
+	layers := []string{"SHEET"}
+	if len(bin.Defects) > 0 {
+		layers = append(layers, "DEFECTS")
+	}
+	boxLayers := make([]string, len(bin.Boxes))
+	seen := map[string]bool{"SHEET": true, "DEFECTS": true}
+	for i, box := range bin.Boxes {
+		name := dxfBoxLayer(box, i, options.Layering)
+		boxLayers[i] = name
+		if !seen[name] {
+			seen[name] = true
+			layers = append(layers, name)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	pair := func(code int, value string) {
+		fmt.Fprintf(bw, "%d\n%s\n", code, value)
+	}
+	number := func(code int, value float64) {
+		pair(code, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	rect := func(layer string, x, y, width, height float64) {
+		// Corners counter-clockwise, starting bottom-left in DXF coordinates.
+		bottom := bin.Height - (y + height)
+		pair(0, "POLYLINE")
+		pair(8, layer)
+		pair(66, "1")
+		pair(70, "1") // Closed
+		for _, corner := range [][2]float64{{x, bottom}, {x + width, bottom}, {x + width, bottom + height}, {x, bottom + height}} {
+			pair(0, "VERTEX")
+			pair(8, layer)
+			number(10, corner[0])
+			number(20, corner[1])
+		}
+		pair(0, "SEQEND")
+		pair(8, layer)
+	}
+
+	pair(0, "SECTION")
+	pair(2, "HEADER")
+	pair(9, "$ACADVER")
+	pair(1, "AC1009")
+	pair(9, "$EXTMIN")
+	number(10, 0)
+	number(20, 0)
+	pair(9, "$EXTMAX")
+	number(10, bin.Width)
+	number(20, bin.Height)
+	pair(0, "ENDSEC")
+
+	pair(0, "SECTION")
+	pair(2, "TABLES")
+	pair(0, "TABLE")
+	pair(2, "LAYER")
+	pair(70, strconv.Itoa(len(layers)))
+	for i, name := range layers {
+		pair(0, "LAYER")
+		pair(2, name)
+		pair(70, "0")
+		pair(62, strconv.Itoa(1+i%255)) // Distinct ACI color per layer
+		pair(6, "CONTINUOUS")
+	}
+	pair(0, "ENDTAB")
+	pair(0, "ENDSEC")
+
+	pair(0, "SECTION")
+	pair(2, "ENTITIES")
+	rect("SHEET", 0, 0, bin.Width, bin.Height)
+	for _, defect := range bin.Defects {
+		rect("DEFECTS", defect.X, defect.Y, defect.Width, defect.Height)
+	}
+	for i, box := range bin.Boxes {
+		rect(boxLayers[i], box.X, box.Y, box.Width, box.Height)
+	}
+	pair(0, "ENDSEC")
+	pair(0, "EOF")
+	return bw.Flush()
+}
+
+// dxfBoxLayer returns the layer name of the box at the given placement index.
+func dxfBoxLayer(box *Box, index int, layering DXFLayering) string {
+	var name string
+	switch layering {
+	case DXFLayerPerGroup:
+		name = box.Group
+		if name == "" {
+			name = "UNGROUPED"
+		}
+	default:
+		name = box.ID
+		if name == "" {
+			name = fmt.Sprintf("BOX_%d", index+1)
+		}
+	}
+	// Layer names may not contain these characters in DXF.
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>/\":;?*|=,`+"`", r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
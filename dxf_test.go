@@ -0,0 +1,52 @@
+package binpacking
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDXF(t *testing.T) {
+	bin := NewBin(100, 50, nil)
+	bin.AddDefect(90, 0, 5, 5)
+	bin.Place(&Box{Width: 40, Height: 20, ID: "door/left", Group: "cabinet"}, 0, 0)
+	bin.Place(&Box{Width: 30, Height: 20, Group: "cabinet"}, 40, 0)
+	bin.Place(NewBox(10, 10, true), 0, 30)
+
+	t.Run("writes a layer per box", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteDXF(&buf, bin, DXFOptions{}); err != nil {
+			t.Fatalf("WriteDXF: %v", err)
+		}
+		out := buf.String()
+		if got := strings.Count(out, "\nPOLYLINE\n"); got != 5 {
+			t.Errorf("Polylines: got %d, want %d (sheet, defect, three boxes)", got, 5)
+		}
+		for _, layer := range []string{"SHEET", "DEFECTS", "door_left", "BOX_2", "BOX_3"} {
+			if !strings.Contains(out, "\n2\n"+layer+"\n") {
+				t.Errorf("Layer table does not define %s", layer)
+			}
+		}
+		// The first box is at the top of the sheet, i.e. DXF Y from 30 to 50.
+		if !strings.Contains(out, "8\ndoor_left\n10\n0\n20\n30\n") {
+			t.Errorf("First box is not mirrored to the top of the sheet:\n%s", out)
+		}
+		if !strings.HasSuffix(out, "0\nEOF\n") {
+			t.Errorf("Drawing does not end with EOF")
+		}
+	})
+
+	t.Run("writes a layer per group", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteDXF(&buf, bin, DXFOptions{Layering: DXFLayerPerGroup}); err != nil {
+			t.Fatalf("WriteDXF: %v", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "\n2\ncabinet\n") || !strings.Contains(out, "\n2\nUNGROUPED\n") {
+			t.Errorf("Layer table lacks the group layers:\n%s", out)
+		}
+		if got := strings.Count(out, "\n0\nLAYER\n"); got != 4 {
+			t.Errorf("Layers: got %d, want %d", got, 4)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package binpacking
+
+import "slices"
+
+// AddBin appends a bin to the packer's bins. Called while Pack runs, e.g. from a
+// PackObserver, the bin is also added to the running scoreboard, so the remaining
+// boxes are scored against it and may be placed in it from the next step on; trial
+// layouts already started, such as Restarts, do not see it. A nil bin is ignored.
+func (p *Packer) AddBin(bin *Bin) {
+	if bin == nil {
+		return
+	}
+	p.Bins = append(p.Bins, bin)
+	if p.events != nil {
+		p.events.index[bin] = len(p.Bins) - 1
+	}
+	if p.board != nil {
+		p.board.AddBin(bin)
+	}
+}
+
+// RemoveBin removes an empty bin from the packer's bins, and from the running
+// scoreboard if called while Pack runs, so no box is placed in it afterwards. The
+// bins after it move down by one index. RemoveBin reports whether the bin was
+// removed: bins holding boxes are kept, as their boxes are part of the packer's
+// results, and so are bins the packer does not have.
+func (p *Packer) RemoveBin(bin *Bin) bool {
+	index := slices.Index(p.Bins, bin)
+	if bin == nil || index < 0 || len(bin.Boxes) > 0 {
+		return false
+	}
+	// The running scoreboard may share the slice, so the remaining bins are copied.
+	p.Bins = slices.Delete(slices.Clone(p.Bins), index, index+1)
+	if p.events != nil {
+		delete(p.events.index, bin)
+		for i, other := range p.Bins[index:] {
+			p.events.index[other] = index + i
+		}
+	}
+	if p.board != nil {
+		p.board.RemoveBin(bin)
+	}
+	return true
+}
+
+// BinUsage returns the current usage of every bin of the packer, in packer order: its
+// boxes, efficiency, used and wasted area, cost and free rectangles, as in
+// PackResult.ByBin. Unlike Result it does not depend on the last call to Pack.
+func (p *Packer) BinUsage() []BinGroup {
+	return (&PackResult{Bins: p.Bins}).ByBin()
+}
@@ -0,0 +1,120 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+// placingObserver calls placed for every box placed, besides recording the events.
+type placingObserver struct {
+	recordingObserver
+	placed func(bin *Bin, box *Box, placement BoxPlacement)
+}
+
+func (o *placingObserver) OnBoxPlaced(bin *Bin, box *Box, placement BoxPlacement) {
+	o.recordingObserver.OnBoxPlaced(bin, box, placement)
+	o.placed(bin, box, placement)
+}
+
+func TestPackerAddBin(t *testing.T) {
+	t.Run("between calls", func(t *testing.T) {
+		packer := NewPacker(nil)
+		packer.AddBin(NewBin(10, 10, nil))
+		packer.AddBin(nil)
+		if len(packer.Bins) != 1 {
+			t.Fatalf("got %d bins, want 1", len(packer.Bins))
+		}
+		if packed := packer.Pack([]*Box{NewBox(10, 10, false)}, PackerOptions{}); len(packed) != 1 {
+			t.Errorf("got %d boxes packed, want 1", len(packed))
+		}
+	})
+
+	t.Run("while packing", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		added := NewBin(10, 10, nil)
+		observer := &placingObserver{}
+		observer.placed = func(bin *Bin, box *Box, placement BoxPlacement) {
+			if len(packer.Bins) == 1 {
+				packer.AddBin(added) // The first bin is full once its box is placed
+			}
+		}
+		boxes := []*Box{NewBox(10, 10, false), NewBox(10, 10, false)}
+		packed := packer.Pack(boxes, PackerOptions{Observer: observer})
+		if len(packed) != 2 || len(added.Boxes) != 1 {
+			t.Fatalf("got %d boxes packed, %d in the added bin, want 2 and 1", len(packed), len(added.Boxes))
+		}
+		if index := observer.placements[1].BinIndex; index != 1 {
+			t.Errorf("got the second box reported in bin %d, want 1", index)
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestPackerRemoveBin(t *testing.T) {
+	t.Run("between calls", func(t *testing.T) {
+		a, b, c := NewBin(10, 10, nil), NewBin(10, 10, nil), NewBin(10, 10, nil)
+		packer := NewPacker([]*Bin{a, b, c})
+		packer.Pack([]*Box{NewBox(10, 10, false)}, PackerOptions{})
+		if packer.RemoveBin(a) {
+			t.Error("got a bin holding a box removed")
+		}
+		if packer.RemoveBin(NewBin(10, 10, nil)) || packer.RemoveBin(nil) {
+			t.Error("got a bin the packer does not have removed")
+		}
+		if !packer.RemoveBin(b) {
+			t.Fatal("got the empty bin kept")
+		}
+		if want := []*Bin{a, c}; !slices.Equal(packer.Bins, want) {
+			t.Errorf("got bins %v, want %v", packer.Bins, want)
+		}
+	})
+
+	t.Run("while packing", func(t *testing.T) {
+		first, spare := NewBin(10, 10, nil), NewBin(10, 10, nil)
+		packer := NewPacker([]*Bin{first, spare, NewBin(10, 10, nil)})
+		observer := &placingObserver{}
+		observer.placed = func(bin *Bin, box *Box, placement BoxPlacement) {
+			packer.RemoveBin(spare)
+		}
+		boxes := []*Box{NewBox(10, 10, false), NewBox(10, 10, false), NewBox(10, 10, false)}
+		packed := packer.Pack(boxes, PackerOptions{Observer: observer})
+		if len(packed) != 2 || len(spare.Boxes) != 0 || len(packer.Bins) != 2 {
+			t.Fatalf("got %d boxes packed, %d in the removed bin, %d bins, want 2, 0 and 2", len(packed), len(spare.Boxes), len(packer.Bins))
+		}
+		if index := observer.placements[1].BinIndex; index != 1 {
+			t.Errorf("got the second box reported in bin %d, want 1", index)
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("ScoreBoard", func(t *testing.T) {
+		a, b := NewBin(10, 10, nil), NewBin(20, 20, nil)
+		bins := []*Bin{a, b}
+		board := NewScoreBoard(bins, []*Box{NewBox(5, 5, false), NewBox(15, 15, false)})
+		board.RemoveBin(b)
+		if len(board.Entries) != 2 || !slices.Equal(board.Bins, []*Bin{a}) {
+			t.Fatalf("got %d entries and %d bins, want 2 and 1", len(board.Entries), len(board.Bins))
+		}
+		if !slices.Equal(bins, []*Bin{a, b}) {
+			t.Error("got the caller's slice modified")
+		}
+		if best := board.BestFit(); best == nil || best.Bin != a || best.Box.Width != 5 {
+			t.Errorf("got best fit %+v, want the small box in the remaining bin", best)
+		}
+	})
+}
+
+func TestPackerBinUsage(t *testing.T) {
+	packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+	packer.Pack([]*Box{NewBox(5, 10, false)}, PackerOptions{})
+	packer.Reset()
+	packer.Bins[1].Insert(NewBox(10, 10, false))
+	usage := packer.BinUsage()
+	if len(usage) != 2 || len(usage[0].Boxes) != 0 || usage[1].Efficiency != 100 || usage[1].UsedArea != 100 {
+		t.Errorf("got %+v, want an empty first bin and a full second one", usage)
+	}
+}
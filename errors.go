@@ -0,0 +1,31 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidDimensions is returned when a bin or box has a width or height that is
+// negative, NaN, infinite, or whose area overflows float64.
+var ErrInvalidDimensions = errors.New("binpacking: invalid dimensions")
+
+// ValidateDimensions checks that width and height describe a usable rectangle.
+// Zero is allowed; negative, NaN and infinite values are rejected, as are
+// dimensions whose product overflows to infinity.
+// The returned error wraps ErrInvalidDimensions.
+func ValidateDimensions(width, height float64) error {
+	if math.IsNaN(width) || math.IsNaN(height) {
+		return fmt.Errorf("%w: %gx%g is NaN", ErrInvalidDimensions, width, height)
+	}
+	if math.IsInf(width, 0) || math.IsInf(height, 0) {
+		return fmt.Errorf("%w: %gx%g is infinite", ErrInvalidDimensions, width, height)
+	}
+	if width < 0 || height < 0 {
+		return fmt.Errorf("%w: %gx%g is negative", ErrInvalidDimensions, width, height)
+	}
+	if math.IsInf(width*height, 0) {
+		return fmt.Errorf("%w: area of %gx%g overflows", ErrInvalidDimensions, width, height)
+	}
+	return nil
+}
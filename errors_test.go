@@ -0,0 +1,47 @@
+package binpacking
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidateDimensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height float64
+		wantErr       bool
+	}{
+		{"valid", 10, 20, false},
+		{"zero", 0, 0, false},
+		{"negative", -1, 20, true},
+		{"NaN", math.NaN(), 20, true},
+		{"infinite", 10, math.Inf(1), true},
+		{"overflowing area", math.MaxFloat64, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDimensions(tt.width, tt.height)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDimensions(%g, %g): got %v, want error %v", tt.width, tt.height, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidDimensions) {
+				t.Errorf("error %v does not wrap ErrInvalidDimensions", err)
+			}
+		})
+	}
+
+	t.Run("Pack reports invalid boxes as unpacked", func(t *testing.T) {
+		bad := &Box{Width: math.NaN(), Height: 10}
+		good := NewBox(10, 10, false)
+		packer := NewPacker([]*Bin{NewBin(100, 100, nil)})
+		packed := packer.Pack([]*Box{bad, good}, PackerOptions{})
+
+		if len(packed) != 1 || packed[0] != good {
+			t.Errorf("Packed boxes: got %d, want only the valid box", len(packed))
+		}
+		if len(packer.UnpackedBoxes) != 1 || packer.UnpackedBoxes[0] != bad {
+			t.Errorf("Unpacked boxes: got %d, want only the invalid box", len(packer.UnpackedBoxes))
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Reasons passed to PackObserver.OnBoxRejected besides the Box.Validate error of a box
+// with invalid dimensions.
+var (
+	// ErrNoFit means the box fits in none of the bins available to it.
+	ErrNoFit = errors.New("binpacking: box fits in no bin")
+	// ErrLimitReached means PackerOptions.Limit boxes had already been packed.
+	ErrLimitReached = errors.New("binpacking: packing limit reached")
+	// ErrTimeLimit means PackerOptions.TimeLimit ran out before the box was packed.
+	ErrTimeLimit = errors.New("binpacking: time limit reached")
+	// ErrExceedsWeight means the box has room in a bin but would take it over its
+	// MaxWeight. It wraps ErrNoFit, so errors.Is(err, ErrNoFit) holds for it too.
+	ErrExceedsWeight = fmt.Errorf("%w: bin weight capacity exceeded", ErrNoFit)
+	// ErrWrongMaterial means the box may not go into the bin because the bin's Material is
+	// not one the box accepts. It wraps ErrNoFit.
+	ErrWrongMaterial = fmt.Errorf("%w: bin material does not match", ErrNoFit)
+	// ErrAlreadyPacked means the box is marked packed, so it is not placed again.
+	ErrAlreadyPacked = errors.New("binpacking: box already packed")
+)
+
+// PackObserver receives the decisions of Packer.Pack as they are made, e.g. to write an
+// audit log or animate the packing. Trial layouts of Restarts, bin templates and groups
+// are not reported, only the boxes and bins of the packer itself; the calls are made on
+// the goroutine running Pack.
+type PackObserver interface {
+	// OnBinOpened is called when a bin is created from PackerOptions.BinTemplates and
+	// appended to the packer's bins, before any box is placed in it.
+	OnBinOpened(bin *Bin)
+	// OnBoxPlaced is called after box has been placed in bin, with its placement as it
+	// would appear in PackResult.Placements. A box that ObjectiveMinCost moves to a
+	// cheaper bin afterwards is reported again with its new bin.
+	OnBoxPlaced(bin *Bin, box *Box, placement BoxPlacement)
+	// OnBoxRejected is called once packing is done for each box left unpacked, with the
+	// reason: the Box.Validate error, ErrLimitReached, ErrTimeLimit, ErrExceedsWeight or
+	// ErrNoFit.
+	OnBoxRejected(box *Box, reason error)
+}
+
+// packEvents reports what a call to Pack does to PackerOptions.OnProgress and to
+// PackerOptions.Observer. Only the packer Pack was called on has one; the scratch
+// packers of trial layouts do not, so nothing happening on trial bins is reported.
+type packEvents struct {
+	report   func(placed, total int, efficiency float64)
+	observer PackObserver
+	index    map[*Bin]int      // Index of each bin in the packer's Bins
+	placed   int               // Boxes placed so far in this call to Pack
+	total    int               // Boxes this call to Pack tries to place
+	boxArea  float64           // Area of all boxes in the bins in use
+	binArea  float64           // Area of the bins in use
+	inUse    map[*Bin]struct{} // Bins holding at least one box
+}
+
+// newPackEvents returns the events of packing total boxes into bins, counting the boxes
+// already in the bins towards the efficiency, or nil if options asks for no reports.
+func newPackEvents(options PackerOptions, bins []*Bin, total int) *packEvents {
+	if options.OnProgress == nil && options.Observer == nil {
+		return nil
+	}
+	events := &packEvents{
+		report: options.OnProgress, observer: options.Observer, total: total,
+		index: make(map[*Bin]int, len(bins)), inUse: make(map[*Bin]struct{}),
+	}
+	for i, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		events.index[bin] = i
+		if len(bin.Boxes) == 0 {
+			continue
+		}
+		events.inUse[bin] = struct{}{}
+		events.binArea += bin.Area()
+		for _, box := range bin.Boxes {
+			events.boxArea += box.Area()
+		}
+	}
+	return events
+}
+
+// open records that bin was appended to the packer's bins at index. All methods of
+// packEvents do nothing on nil events.
+func (e *packEvents) open(bin *Bin, index int) {
+	if e == nil {
+		return
+	}
+	e.index[bin] = index
+	if e.observer != nil {
+		e.observer.OnBinOpened(bin)
+	}
+}
+
+// place records that box was placed in bin and reports the progress.
+func (e *packEvents) place(bin *Bin, box *Box) {
+	if e == nil {
+		return
+	}
+	if _, ok := e.inUse[bin]; !ok {
+		e.inUse[bin] = struct{}{}
+		e.binArea += bin.Area()
+	}
+	e.boxArea += box.Area()
+	e.placed++
+	e.notify(bin, box)
+	if e.report != nil {
+		e.report(e.placed, e.total, e.boxArea/e.binArea*100)
+	}
+}
+
+// move records that an already placed box was moved to bin.
+func (e *packEvents) move(bin *Bin, box *Box) {
+	if e == nil {
+		return
+	}
+	e.notify(bin, box)
+}
+
+// notify passes the placement of box in bin to the observer.
+func (e *packEvents) notify(bin *Bin, box *Box) {
+	if e.observer == nil {
+		return
+	}
+	e.observer.OnBoxPlaced(bin, box, BoxPlacement{
+		Box: box, ID: box.ID, Packed: true, BinIndex: e.index[bin],
+		X: box.X, Y: box.Y, Width: box.Width, Height: box.Height, Rotated: box.Rotated,
+		UV: NewUVRect(box.X, box.Y, box.Width, box.Height, bin.Width, bin.Height),
+	})
+}
+
+// reject reports that box was left unpacked for reason.
+func (e *packEvents) reject(box *Box, reason error) {
+	if e == nil || e.observer == nil {
+		return
+	}
+	e.observer.OnBoxRejected(box, reason)
+}
@@ -0,0 +1,122 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingObserver records the events of a call to Pack in order.
+type recordingObserver struct {
+	events     []string
+	placements []BoxPlacement
+	rejected   map[*Box]error
+}
+
+func (o *recordingObserver) OnBinOpened(bin *Bin) {
+	o.events = append(o.events, "open")
+}
+
+func (o *recordingObserver) OnBoxPlaced(bin *Bin, box *Box, placement BoxPlacement) {
+	o.events = append(o.events, "place "+box.ID)
+	o.placements = append(o.placements, placement)
+}
+
+func (o *recordingObserver) OnBoxRejected(box *Box, reason error) {
+	o.events = append(o.events, "reject "+box.ID)
+	if o.rejected == nil {
+		o.rejected = make(map[*Box]error)
+	}
+	o.rejected[box] = reason
+}
+
+func TestPackObserver(t *testing.T) {
+	t.Run("placements match the result", func(t *testing.T) {
+		observer := &recordingObserver{}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.Pack([]*Box{
+			{ID: "a", Width: 10, Height: 6}, {ID: "b", Width: 10, Height: 6}, {ID: "c", Width: 4, Height: 10},
+		}, PackerOptions{Observer: observer})
+		result := packer.Result()
+		if len(observer.placements) != len(result.Packed) {
+			t.Fatalf("got %d placements, want %d", len(observer.placements), len(result.Packed))
+		}
+		for i, placement := range observer.placements {
+			if placement != result.Placements[i] {
+				t.Errorf("placement %d: got %+v, want %+v", i, placement, result.Placements[i])
+			}
+		}
+	})
+
+	t.Run("template bins are opened before boxes are placed in them", func(t *testing.T) {
+		observer := &recordingObserver{}
+		packer := NewPacker(nil)
+		packer.Pack([]*Box{
+			{ID: "a", Width: 10, Height: 10}, {ID: "b", Width: 10, Height: 10, Group: "g"},
+		}, PackerOptions{Observer: observer, BinTemplates: []BinTemplate{{Width: 10, Height: 10}}})
+		want := []string{"open", "place b", "open", "place a"}
+		if len(observer.events) != len(want) {
+			t.Fatalf("got %v, want %v", observer.events, want)
+		}
+		for i := range want {
+			if observer.events[i] != want[i] {
+				t.Errorf("got %v, want %v", observer.events, want)
+				break
+			}
+		}
+		for _, placement := range observer.placements {
+			if got := packer.Bins[placement.BinIndex]; len(got.Boxes) != 1 || got.Boxes[0] != placement.Box {
+				t.Errorf("got bin %d for %s, want the bin holding it", placement.BinIndex, placement.ID)
+			}
+		}
+	})
+
+	t.Run("rejections carry the reason", func(t *testing.T) {
+		tooBig := &Box{ID: "big", Width: 20, Height: 20}
+		invalid := &Box{ID: "invalid", Width: -1, Height: 1}
+		observer := &recordingObserver{}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack([]*Box{tooBig, invalid, {ID: "ok", Width: 5, Height: 5}}, PackerOptions{Observer: observer})
+		if err := observer.rejected[tooBig]; !errors.Is(err, ErrNoFit) {
+			t.Errorf("got %v, want %v", err, ErrNoFit)
+		}
+		if err := observer.rejected[invalid]; !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("got %v, want %v", err, ErrInvalidDimensions)
+		}
+		if len(observer.rejected) != 2 {
+			t.Errorf("got %d rejections, want 2", len(observer.rejected))
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		boxes := []*Box{{ID: "a", Width: 2, Height: 2}, {ID: "b", Width: 2, Height: 2}}
+		observer := &recordingObserver{}
+		NewPacker([]*Bin{NewBin(10, 10, nil)}).Pack(boxes, PackerOptions{Observer: observer, Limit: 1})
+		for _, err := range observer.rejected {
+			if !errors.Is(err, ErrLimitReached) {
+				t.Errorf("got %v, want %v", err, ErrLimitReached)
+			}
+		}
+
+		observer = &recordingObserver{}
+		boxes = []*Box{{ID: "a", Width: 2, Height: 2}, {ID: "b", Width: 2, Height: 2}}
+		NewPacker([]*Bin{NewBin(10, 10, nil)}).Pack(boxes, PackerOptions{Observer: observer, TimeLimit: time.Nanosecond})
+		if len(observer.rejected) != 2 {
+			t.Fatalf("got %d rejections, want 2", len(observer.rejected))
+		}
+		for _, err := range observer.rejected {
+			if !errors.Is(err, ErrTimeLimit) {
+				t.Errorf("got %v, want %v", err, ErrTimeLimit)
+			}
+		}
+	})
+
+	t.Run("trial layouts are not reported", func(t *testing.T) {
+		observer := &recordingObserver{}
+		boxes := []*Box{{ID: "a", Width: 6, Height: 6}, {ID: "b", Width: 4, Height: 4}, {ID: "c", Width: 4, Height: 6}}
+		NewPacker([]*Bin{NewBin(10, 10, nil)}).Pack(boxes, PackerOptions{Observer: observer, Restarts: 4})
+		if len(observer.placements) != 3 {
+			t.Errorf("got %v, want the three final placements", observer.events)
+		}
+	})
+}
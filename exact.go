@@ -0,0 +1,521 @@
+package binpacking
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// maxExactPatterns caps the number of candidate coordinates per axis. Boxes with many
+// distinct non-integer sizes can make the normal pattern set grow exponentially; the
+// search is skipped in that case and the heuristic result is kept.
+const maxExactPatterns = 1 << 14
+
+// DefaultExactTimeLimit is the search time of an ExactPacker whose TimeLimit is zero.
+const DefaultExactTimeLimit = 10 * time.Second
+
+// ExactPacker packs small jobs optimally with branch-and-bound. The objective is to
+// maximize the packed area, then to minimize the number of bins holding boxes. Jobs of a
+// few boxes, or whose boxes all fit, are usually solved quickly. When the boxes cover
+// more area than the bins hold, proving a layout optimal can take longer than any
+// practical time limit already for ten boxes of mixed sizes, so the search stops after
+// TimeLimit, DefaultExactTimeLimit unless set.
+//
+// The search first packs a copy of the job with the scoreboard heuristic and uses that
+// as the incumbent. It then tries every box, bin, orientation and position on the
+// normal patterns of the job: coordinates that are a sum of box sides, optionally
+// starting at the edge of a box already in the bin. Every packing can be shifted down
+// and left onto such coordinates, so the search is exhaustive. Anchored boxes are tried
+// on those of the coordinates that satisfy their anchor and against the far edges.
+// Branches whose area upper bound cannot beat the incumbent are pruned: the packed area
+// plus the smaller of the remaining box area and the free area that one of the remaining
+// boxes could still cover. Identical boxes are tried in one order only.
+//
+// If TimeLimit expires first, the best layout found so far is used, which is never
+// worse than the heuristic result, and Optimal reports false.
+//
+// Like the shelf algorithms, ExactPacker computes positions itself, so only bins using
+// the default MaxRects backend and no Spacing take part. Boxes already in a bin are respected.
+type ExactPacker struct {
+	Bins          []*Bin        // Bins available for packing
+	UnpackedBoxes []*Box        // Boxes that could not be packed in the last call to Pack
+	TimeLimit     time.Duration // Maximum search time; zero uses DefaultExactTimeLimit, negative means no limit
+	Optimal       bool          // Whether the last call to Pack proved its layout optimal
+}
+
+// NewExactPacker creates an ExactPacker for the given bins and search time limit.
+func NewExactPacker(bins []*Bin, timeLimit time.Duration) *ExactPacker {
+	packerBins := make([]*Bin, len(bins))
+	copy(packerBins, bins)
+	return &ExactPacker{
+		Bins:          packerBins,
+		UnpackedBoxes: make([]*Box, 0),
+		TimeLimit:     timeLimit,
+	}
+}
+
+// noOverrides applies no per-tag settings, so the search turns each box if its own
+// fields let it: by rotating it or, with AllowMirror, by mirroring it.
+var noOverrides *TagOptions
+
+// exactPlacement is the position chosen for a box during the search.
+type exactPlacement struct {
+	packed   bool
+	bin      int
+	x, y     float64
+	rotated  bool
+	mirrored bool
+}
+
+// exactRect is an occupied area of a bin during the search.
+type exactRect struct {
+	x, y, width, height float64
+}
+
+// exactSearch holds the state of one branch-and-bound run.
+type exactSearch struct {
+	bins      []*Bin
+	boxes     []*Box    // Sorted by area, largest first
+	remaining []float64 // remaining[i] is the total area of boxes[i:]
+	rects     [][]exactRect
+	xs, ys    [][]float64 // Candidate coordinates per bin, ascending
+	used      []int       // Number of boxes per bin, including boxes packed before
+	weights   []float64   // Total box weight per bin, including boxes packed before
+	freeArea  float64
+
+	current    []exactPlacement
+	packedArea float64
+
+	best     []exactPlacement
+	bestArea float64
+	bestBins int
+
+	deadline time.Time
+	nodes    int
+	aborted  bool
+}
+
+// Pack packs the boxes and returns the ones that were placed. Boxes that are nil,
+// already packed or have invalid dimensions are handled like in Packer.Pack.
+func (p *ExactPacker) Pack(boxes []*Box) []*Box {
+	packedBoxes := make([]*Box, 0)
+	p.Optimal = false
+
+	boxesToPack := make([]*Box, 0, len(boxes))
+	invalidBoxes := make([]*Box, 0)
+	for _, box := range boxes {
+		if box == nil || box.Packed {
+			continue
+		}
+		if box.Validate() != nil {
+			invalidBoxes = append(invalidBoxes, box)
+			continue
+		}
+		boxesToPack = append(boxesToPack, box)
+	}
+	bins := make([]*Bin, 0, len(p.Bins))
+	for _, bin := range p.Bins {
+		if bin != nil && bin.usesMaxRects() && bin.Spacing <= 0 {
+			bins = append(bins, bin)
+		}
+	}
+	if len(boxesToPack) == 0 || len(bins) == 0 {
+		p.UnpackedBoxes = append(boxesToPack, invalidBoxes...)
+		p.Optimal = len(boxesToPack) == 0
+		return packedBoxes
+	}
+
+	s := newExactSearch(bins, boxesToPack)
+	switch {
+	case p.TimeLimit > 0:
+		s.deadline = time.Now().Add(p.TimeLimit)
+	case p.TimeLimit == 0:
+		s.deadline = time.Now().Add(DefaultExactTimeLimit)
+	}
+	s.seed()
+	if s.xs != nil {
+		s.search(0)
+		p.Optimal = !s.aborted
+	}
+
+	// Apply the best layout to the real bins and boxes.
+	p.UnpackedBoxes = make([]*Box, 0, len(invalidBoxes))
+	for i, box := range s.boxes {
+		placement := s.best[i]
+		if !placement.packed {
+			p.UnpackedBoxes = append(p.UnpackedBoxes, box)
+			continue
+		}
+		bins[placement.bin].commit(box, placement.x, placement.y, placement.rotated, placement.mirrored)
+		packedBoxes = append(packedBoxes, box)
+	}
+	p.UnpackedBoxes = append(p.UnpackedBoxes, invalidBoxes...)
+	return packedBoxes
+}
+
+// newExactSearch prepares the search state, including the normal patterns of each bin.
+// The patterns are left nil if they grow beyond maxExactPatterns.
+func newExactSearch(bins []*Bin, boxes []*Box) *exactSearch {
+	sorted := slices.Clone(boxes)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Area() > sorted[j].Area() })
+
+	s := &exactSearch{
+		bins:      bins,
+		boxes:     sorted,
+		remaining: make([]float64, len(sorted)+1),
+		rects:     make([][]exactRect, len(bins)),
+		xs:        make([][]float64, len(bins)),
+		ys:        make([][]float64, len(bins)),
+		used:      make([]int, len(bins)),
+		weights:   make([]float64, len(bins)),
+		current:   make([]exactPlacement, len(sorted)),
+		best:      make([]exactPlacement, len(sorted)),
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		s.remaining[i] = s.remaining[i+1] + sorted[i].Area()
+	}
+
+	limit := 0.0
+	for _, bin := range bins {
+		limit = max(limit, bin.Width, bin.Height)
+	}
+	xSums, ySums := exactSubsetSums(sorted, limit, false), exactSubsetSums(sorted, limit, true)
+
+	for b, bin := range bins {
+		s.freeArea += bin.Area()
+		xEdges, yEdges := []float64{0}, []float64{0}
+		for _, box := range bin.occupied() {
+			s.rects[b] = append(s.rects[b], exactRect{box.X, box.Y, box.Width, box.Height})
+			s.freeArea -= box.Area()
+			xEdges = append(xEdges, box.X+box.Width)
+			yEdges = append(yEdges, box.Y+box.Height)
+		}
+		s.used[b] = len(bin.Boxes)
+		s.weights[b] = bin.Weight()
+		s.xs[b] = exactPatterns(xEdges, xSums, bin.Width)
+		s.ys[b] = exactPatterns(yEdges, ySums, bin.Height)
+		if s.xs[b] == nil || s.ys[b] == nil || xSums == nil || ySums == nil {
+			s.xs, s.ys = nil, nil // Too many patterns; keep the heuristic result
+			break
+		}
+	}
+	return s
+}
+
+// exactSubsetSums returns every sum of box sides up to limit, where each box contributes
+// nothing, its width or its height (the latter only if it may rotate). With vertical set,
+// the sums are of heights instead. It returns nil if there are more than maxExactPatterns.
+func exactSubsetSums(boxes []*Box, limit float64, vertical bool) map[float64]struct{} {
+	sums := map[float64]struct{}{0: {}}
+	for _, box := range boxes {
+		side, other := box.Width, box.Height
+		if vertical {
+			side, other = other, side
+		}
+		sides := []float64{side}
+		if !noOverrides.fixedOrientation(box) {
+			sides = append(sides, other)
+		}
+		next := make(map[float64]struct{}, len(sums)*2)
+		for sum := range sums {
+			next[sum] = struct{}{}
+			for _, side := range sides {
+				if sum+side <= limit {
+					next[sum+side] = struct{}{}
+				}
+			}
+		}
+		if len(next) > maxExactPatterns {
+			return nil
+		}
+		sums = next
+	}
+	return sums
+}
+
+// exactPatterns combines the edges a box may rest against with the subset sums,
+// returning the sorted coordinates below size, or nil if there are too many.
+func exactPatterns(edges []float64, sums map[float64]struct{}, size float64) []float64 {
+	if sums == nil {
+		return nil
+	}
+	set := make(map[float64]struct{}, len(sums))
+	for _, edge := range edges {
+		for sum := range sums {
+			if v := edge + sum; v < size {
+				set[v] = struct{}{}
+			}
+		}
+		if len(set) > maxExactPatterns {
+			return nil
+		}
+	}
+	patterns := make([]float64, 0, len(set))
+	for v := range set {
+		patterns = append(patterns, v)
+	}
+	slices.Sort(patterns)
+	return patterns
+}
+
+// seed packs copies of the boxes into copies of the bins with the scoreboard heuristic
+// and records the result as the incumbent.
+func (s *exactSearch) seed() {
+	clones, copies := trialCopies(s.bins, s.boxes)
+	owner := make(map[*Bin]int, len(clones))
+	for b, clone := range clones {
+		owner[clone] = b
+	}
+	index := make(map[*Box]int, len(copies))
+	for i, copied := range copies {
+		index[copied] = i
+	}
+	NewPacker(clones).Pack(copies, PackerOptions{})
+
+	binsUsed := 0
+	for _, clone := range clones {
+		for _, copied := range clone.Boxes {
+			i, ok := index[copied]
+			if !ok {
+				continue // Packed before this run
+			}
+			s.best[i] = exactPlacement{
+				packed:   true,
+				bin:      owner[clone],
+				x:        copied.X,
+				y:        copied.Y,
+				rotated:  copied.Rotated != s.boxes[i].Rotated,
+				mirrored: copied.Mirrored != s.boxes[i].Mirrored,
+			}
+			s.bestArea += copied.Area()
+		}
+		if len(clone.Boxes) > 0 {
+			binsUsed++
+		}
+	}
+	s.bestBins = binsUsed
+}
+
+// binsUsed returns the number of bins holding at least one box in the current branch.
+func (s *exactSearch) binsUsed() int {
+	n := 0
+	for _, used := range s.used {
+		if used > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// search tries every placement of boxes[i:] given the placements of boxes[:i].
+func (s *exactSearch) search(i int) {
+	s.nodes++
+	if !s.deadline.IsZero() && s.nodes%1024 == 0 && time.Now().After(s.deadline) {
+		s.aborted = true
+	}
+	if s.aborted {
+		return
+	}
+
+	binsUsed := s.binsUsed()
+	if s.packedArea > s.bestArea || (s.packedArea == s.bestArea && binsUsed < s.bestBins) {
+		copy(s.best, s.current)
+		s.bestArea, s.bestBins = s.packedArea, binsUsed
+	}
+	if i == len(s.boxes) {
+		return
+	}
+	// Bound: even packing every remaining box cannot beat the incumbent.
+	if s.pruned(s.packedArea+min(s.remaining[i], s.freeArea), binsUsed) ||
+		s.pruned(s.packedArea+min(s.remaining[i], s.reachableArea(i)), binsUsed) {
+		return
+	}
+
+	box := s.boxes[i]
+	// Identical boxes are interchangeable: once one is left out, so are the ones after it,
+	// and those placed go in the order of their positions.
+	sameAsPrevious := i > 0 && specOf(s.boxes[i-1]) == specOf(box)
+	if !sameAsPrevious {
+		s.placeBox(i, box, exactPlacement{bin: -1})
+	} else if s.current[i-1].packed {
+		s.placeBox(i, box, s.current[i-1])
+	}
+	if s.aborted {
+		return
+	}
+	s.current[i] = exactPlacement{}
+	s.search(i + 1)
+}
+
+// pruned reports whether a branch whose packed area cannot exceed bound, using binsUsed
+// bins so far, cannot beat the incumbent.
+func (s *exactSearch) pruned(bound float64, binsUsed int) bool {
+	return bound < s.bestArea || (bound == s.bestArea && binsUsed >= s.bestBins)
+}
+
+// reachableArea returns an upper bound on the free area the boxes[i:] can cover: the
+// free area, on the grid of the edges of the occupied rectangles, whose horizontal and
+// vertical free runs are long enough for one of the boxes, in an orientation it may
+// take. Every point of a placed box lies on runs at least as long as its sides.
+func (s *exactSearch) reachableArea(i int) float64 {
+	fits := func(across, down float64) bool {
+		for _, box := range s.boxes[i:] {
+			if box.Width <= across && box.Height <= down || !noOverrides.fixedOrientation(box) && box.Height <= across && box.Width <= down {
+				return true
+			}
+		}
+		return false
+	}
+	area := 0.0
+	for b, bin := range s.bins {
+		xs, ys := []float64{0, bin.Width}, []float64{0, bin.Height}
+		for _, r := range s.rects[b] {
+			xs = append(xs, r.x, r.x+r.width)
+			ys = append(ys, r.y, r.y+r.height)
+		}
+		slices.Sort(xs)
+		slices.Sort(ys)
+		xs, ys = slices.Compact(xs), slices.Compact(ys)
+		cols, rows := len(xs)-1, len(ys)-1
+		free := make([]bool, cols*rows)
+		for row := range rows {
+			for col := range cols {
+				free[row*cols+col] = !s.overlaps(b, xs[col], ys[row], xs[col+1]-xs[col], ys[row+1]-ys[row])
+			}
+		}
+		// Length of the free run through each cell, along rows and along columns.
+		across, down := make([]float64, len(free)), make([]float64, len(free))
+		for row := range rows {
+			for start := 0; start < cols; {
+				end := start
+				for end < cols && free[row*cols+end] == free[row*cols+start] {
+					end++
+				}
+				for col := start; col < end; col++ {
+					across[row*cols+col] = xs[end] - xs[start]
+				}
+				start = end
+			}
+		}
+		for col := range cols {
+			for start := 0; start < rows; {
+				end := start
+				for end < rows && free[end*cols+col] == free[start*cols+col] {
+					end++
+				}
+				for row := start; row < end; row++ {
+					down[row*cols+col] = ys[end] - ys[start]
+				}
+				start = end
+			}
+		}
+		for cell, ok := range free {
+			if ok && fits(across[cell], down[cell]) {
+				row, col := cell/cols, cell%cols
+				area += (xs[col+1] - xs[col]) * (ys[row+1] - ys[row])
+			}
+		}
+	}
+	return area
+}
+
+// placeBox branches over every bin, orientation and position of boxes[i]. Positions up
+// to the placement after, in the order bin, y, x, are skipped; a bin of -1 skips none.
+func (s *exactSearch) placeBox(i int, box *Box, after exactPlacement) {
+	area := box.Area()
+	for b, bin := range s.bins {
+		if b < after.bin {
+			continue
+		}
+		if s.used[b] == 0 && s.hasEquivalentEmptyBin(b) {
+			continue // An identical empty bin earlier in the list was already tried
+		}
+		if bin.MaxWeight > 0 && s.weights[b]+box.Weight > bin.MaxWeight || !bin.fitsMaterial(box) {
+			continue
+		}
+		rule := bin.anchoring(box.Anchor, true)
+		for _, turned := range []bool{false, true} {
+			width, height := box.Width, box.Height
+			if turned {
+				if noOverrides.fixedOrientation(box) || box.Width == box.Height {
+					break
+				}
+				width, height = height, width
+			}
+			xs, ys := s.xs[b], s.ys[b]
+			if rule != nil {
+				// The normal patterns push boxes towards the top-left; a box anchored to
+				// the right or bottom edge also needs the positions against those edges.
+				xs, ys = withCoordinate(xs, rule.maxX-width), withCoordinate(ys, rule.maxY-height)
+			}
+			for _, y := range ys {
+				if y+height > bin.Height {
+					break
+				}
+				for _, x := range xs {
+					if x+width > bin.Width {
+						break
+					}
+					if b == after.bin && (y < after.y || y == after.y && x <= after.x) {
+						continue
+					}
+					if rule != nil && !rule.admits(x, y, width, height) || s.overlaps(b, x, y, width, height) {
+						continue
+					}
+					s.rects[b] = append(s.rects[b], exactRect{x, y, width, height})
+					s.used[b]++
+					s.weights[b] += box.Weight
+					s.freeArea -= area
+					s.packedArea += area
+					s.current[i] = exactPlacement{packed: true, bin: b, x: x, y: y, rotated: turned && !box.ConstrainRotation, mirrored: turned && box.ConstrainRotation}
+
+					s.search(i + 1)
+
+					s.rects[b] = s.rects[b][:len(s.rects[b])-1]
+					s.used[b]--
+					s.weights[b] -= box.Weight
+					s.freeArea += area
+					s.packedArea -= area
+					if s.aborted {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// withCoordinate returns the sorted coordinates with v added, if it is not negative and
+// not among them yet, without modifying coords.
+func withCoordinate(coords []float64, v float64) []float64 {
+	i, found := slices.BinarySearch(coords, v)
+	if found || v < 0 {
+		return coords
+	}
+	return slices.Insert(slices.Clip(coords), i, v)
+}
+
+// hasEquivalentEmptyBin reports whether an empty bin of the same size, margins, spacing,
+// tolerance, weight limit and material precedes bin b. Bins with defects are never
+// equivalent.
+func (s *exactSearch) hasEquivalentEmptyBin(b int) bool {
+	bin := s.bins[b]
+	for j, other := range s.bins[:b] {
+		if s.used[j] == 0 && other.Width == bin.Width && other.Height == bin.Height &&
+			other.Margins == bin.Margins && other.Spacing == bin.Spacing && other.Tolerance == bin.Tolerance &&
+			other.MaxWeight == bin.MaxWeight && other.Material == bin.Material && len(other.Defects)+len(bin.Defects) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps reports whether the rectangle overlaps an occupied area of bin b.
+func (s *exactSearch) overlaps(b int, x, y, width, height float64) bool {
+	for _, r := range s.rects[b] {
+		if x < r.x+r.width && x+width > r.x && y < r.y+r.height && y+height > r.y {
+			return true
+		}
+	}
+	return false
+}
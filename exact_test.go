@@ -0,0 +1,125 @@
+package binpacking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExactPacker(t *testing.T) {
+	newBoxes := func() []*Box {
+		return []*Box{
+			NewBox(2, 5, true), NewBox(2, 6, true), NewBox(4, 2, true), NewBox(5, 2, true), NewBox(2, 1, true),
+		}
+	}
+
+	t.Run("finds layouts the heuristic misses", func(t *testing.T) {
+		heuristic := NewPacker([]*Bin{NewBin(8, 8, nil)})
+		if packed := heuristic.Pack(newBoxes(), PackerOptions{}); len(packed) == 5 {
+			t.Fatalf("Heuristic packed every box; the instance no longer exercises the search")
+		}
+
+		boxes := newBoxes()
+		bin := NewBin(8, 8, nil)
+		packer := NewExactPacker([]*Bin{bin}, 0)
+		packed := packer.Pack(boxes)
+		if len(packed) != len(boxes) || len(packer.UnpackedBoxes) != 0 {
+			t.Fatalf("Packed boxes: got %d, want %d", len(packed), len(boxes))
+		}
+		if !packer.Optimal {
+			t.Errorf("Optimal: got %v, want %v", packer.Optimal, true)
+		}
+		for i, a := range bin.Boxes {
+			if a.X+a.Width > bin.Width || a.Y+a.Height > bin.Height {
+				t.Errorf("Box %s outside the bin", a.Label())
+			}
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+				}
+			}
+		}
+	})
+
+	t.Run("prefers fewer bins", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		packer := NewExactPacker(bins, 0)
+		packer.Pack([]*Box{NewBox(5, 10, false), NewBox(10, 5, false)})
+		if len(bins[0].Boxes) != 2 || len(bins[1].Boxes) != 0 {
+			t.Errorf("Boxes per bin: got %d and %d, want 2 and 0", len(bins[0].Boxes), len(bins[1].Boxes))
+		}
+	})
+
+	t.Run("tells bins with margins apart", func(t *testing.T) {
+		// The trimmed bin is too small for all boxes; only the plain one holds them.
+		trimmed, plain := NewBin(8, 8, nil), NewBin(8, 8, nil)
+		if err := trimmed.SetMargins(Margins{Left: 4}); err != nil {
+			t.Fatal(err)
+		}
+		packer := NewExactPacker([]*Bin{trimmed, plain}, 0)
+		packer.Pack(newBoxes())
+		if len(trimmed.Boxes) != 0 || len(plain.Boxes) != 5 || !packer.Optimal {
+			t.Errorf("Boxes per bin: got %d and %d, optimal %v, want 0 and 5 proven optimal", len(trimmed.Boxes), len(plain.Boxes), packer.Optimal)
+		}
+	})
+
+	t.Run("identical boxes", func(t *testing.T) {
+		// Only eight of the twelve boxes fit. Without breaking the symmetry between the
+		// boxes, proving that takes seconds.
+		boxes := make([]*Box, 12)
+		for i := range boxes {
+			boxes[i] = NewBox(3, 4, false)
+		}
+		bin := NewBin(10, 10, nil)
+		packer := NewExactPacker([]*Bin{bin}, time.Second)
+		packer.Pack(boxes)
+		if !packer.Optimal || len(bin.Boxes) != 8 {
+			t.Errorf("got %d boxes, optimal %v, want 8 proven optimal", len(bin.Boxes), packer.Optimal)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("mirrors boxes with constrained rotation", func(t *testing.T) {
+		box := NewBox(4, 10, true)
+		box.AllowMirror = true
+		bin := NewBin(10, 4, nil)
+		packer := NewExactPacker([]*Bin{bin}, 0)
+		if packed := packer.Pack([]*Box{box}); len(packed) != 1 {
+			t.Fatalf("Packed boxes: got %d, want %d", len(packed), 1)
+		}
+		if !box.Mirrored || box.Rotated || box.Width != 10 || box.Height != 4 {
+			t.Errorf("got %s mirrored %v rotated %v, want 10x4 mirrored, not rotated", box.Label(), box.Mirrored, box.Rotated)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("falls back to the heuristic on timeout", func(t *testing.T) {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 18; i++ {
+			boxes = append(boxes, NewBox(float64(3+i*7%11), float64(2+i*5%9), false))
+		}
+		copies := make([]*Box, len(boxes))
+		for i, box := range boxes {
+			copies[i] = NewBox(box.Width, box.Height, false)
+		}
+		heuristicArea := 0.0
+		for _, box := range NewPacker([]*Bin{NewBin(30, 30, nil)}).Pack(copies, PackerOptions{}) {
+			heuristicArea += box.Area()
+		}
+
+		packer := NewExactPacker([]*Bin{NewBin(30, 30, nil)}, 1)
+		exactArea := 0.0
+		for _, box := range packer.Pack(boxes) {
+			exactArea += box.Area()
+		}
+		if packer.Optimal {
+			t.Errorf("Optimal: got %v, want %v", packer.Optimal, false)
+		}
+		if exactArea < heuristicArea {
+			t.Errorf("Packed area: got %g, want at least %g", exactArea, heuristicArea)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPlacement is returned by Bin.Place when a box cannot be put at the requested position.
+var ErrPlacement = errors.New("binpacking: invalid placement")
+
+// FixedPlacer is implemented by backends that support boxes at caller-chosen positions,
+// as placed by Bin.Place. All built-in backends implement it.
+type FixedPlacer interface {
+	// PlaceFixed removes the area of box, already positioned inside bin and overlapping
+	// no other box, from the backend's free area.
+	PlaceFixed(bin *Bin, box *Box)
+}
+
+// Place puts box into the bin at the given top-left corner, as oriented, and locks it:
+// the packer packs around it and never moves it, e.g. for partial layouts or reserved
+// regions of a sheet. The returned error wraps ErrPlacement if the box is already packed,
+// lies outside the bin, overlaps a box or defect in the bin (or comes closer than Spacing), exceeds the bin's weight capacity or
+// the backend does not implement FixedPlacer, and ErrInvalidDimensions if the box's
+// dimensions are invalid.
+func (b *Bin) Place(box *Box, x, y float64) error {
+	if err := box.Validate(); err != nil {
+		return err
+	}
+	if box.Packed {
+		return fmt.Errorf("%w: box %s is already packed", ErrPlacement, box.Label())
+	}
+	// With spacing, the box and everything in the bin are padded on their right and bottom
+	// sides; padded rectangles that do not overlap are at least the spacing apart.
+	width, height := box.Width+max(b.Spacing, 0), box.Height+max(b.Spacing, 0)
+	tol := b.tolerance()
+	if x < -tol || y < -tol || x+width > b.Width+tol || y+height > b.Height+tol {
+		return fmt.Errorf("%w: %gx%g at [%g,%g] is outside the %gx%g bin", ErrPlacement, box.Width, box.Height, x, y, b.Width, b.Height)
+	}
+	area := FreeSpaceBox{X: x, Y: y, Width: width, Height: height}
+	for _, other := range b.occupied() {
+		if rectsOverlap(&area, &FreeSpaceBox{X: other.X, Y: other.Y, Width: other.Width, Height: other.Height}, tol) {
+			return fmt.Errorf("%w: %gx%g at [%g,%g] overlaps %s", ErrPlacement, box.Width, box.Height, x, y, other.Label())
+		}
+	}
+	if !b.fitsMaterial(box) {
+		return fmt.Errorf("%w: box is not of the bin's material %q", ErrPlacement, b.Material)
+	}
+	if !b.fitsWeight(box) {
+		return fmt.Errorf("%w: box exceeds the bin's weight capacity of %g", ErrPlacement, b.MaxWeight)
+	}
+	placer, ok := b.backend().(FixedPlacer)
+	if !ok {
+		return fmt.Errorf("%w: backend %T does not support fixed placements", ErrPlacement, b.backend())
+	}
+
+	box.X, box.Y = x, y
+	box.Packed, box.Locked = true, true
+	if box.cluster != nil {
+		box.cluster.place()
+	}
+	placer.PlaceFixed(b, b.padded(box))
+	b.settleFreeSpaces()
+	b.Boxes = append(b.Boxes, box)
+	b.touch()
+	return nil
+}
+
+// rectsOverlap reports whether two rectangles share a region more than tol wide and tall.
+func rectsOverlap(a, b *FreeSpaceBox, tol float64) bool {
+	return a.X < b.X+b.Width-tol && b.X < a.X+a.Width-tol && a.Y < b.Y+b.Height-tol && b.Y < a.Y+a.Height-tol
+}
+
+// PlaceFixed implements FixedPlacer.
+func (MaxRectsBackend) PlaceFixed(bin *Bin, box *Box) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
+
+// PlaceFixed implements FixedPlacer.
+func (BottomLeftFillBackend) PlaceFixed(bin *Bin, box *Box) {
+	bin.splitFreeSpaces(box)
+	bin.enforceFreeSpaceLimit()
+}
+
+// PlaceFixed implements FixedPlacer. Free rectangles the box intersects are cut into
+// disjoint pieces around it, so the free list stays free of overlaps.
+func (g *GuillotineBackend) PlaceFixed(bin *Bin, box *Box) {
+	bin.FreeSpaces = subtractDisjoint(bin.FreeSpaces, box, bin.tolerance())
+	if g.Merge {
+		bin.mergeFreeList()
+	}
+}
+
+// PlaceFixed implements FixedPlacer. The skyline is raised to the bottom edge of the box
+// wherever it lies below. Gaps left beneath the box always join the waste map, even if
+// WasteMap is disabled, as a box floating above the skyline would otherwise lose them.
+func (s *SkylineBackend) PlaceFixed(bin *Bin, box *Box) {
+	s.ensureNodes(bin)
+	s.waste = subtractDisjoint(s.waste, box, bin.tolerance())
+
+	left, right := box.X, box.X+box.Width
+	top := box.Y + box.Height
+	newNodes := make([]skylineNode, 0, len(s.nodes)+2)
+	for _, n := range s.nodes {
+		nodeRight := n.X + n.Width
+		if nodeRight <= left || n.X >= right || n.Y >= top {
+			newNodes = append(newNodes, n) // Untouched segment
+			continue
+		}
+		if n.X < left {
+			newNodes = append(newNodes, skylineNode{X: n.X, Y: n.Y, Width: left - n.X})
+		}
+		x := max(n.X, left)
+		width := min(nodeRight, right) - x
+		if n.Y < box.Y {
+			s.waste = append(s.waste, &FreeSpaceBox{X: x, Y: n.Y, Width: width, Height: box.Y - n.Y})
+		}
+		newNodes = append(newNodes, skylineNode{X: x, Y: top, Width: width})
+		if nodeRight > right {
+			newNodes = append(newNodes, skylineNode{X: right, Y: n.Y, Width: nodeRight - right})
+		}
+	}
+
+	merged := newNodes[:0]
+	for _, n := range newNodes {
+		if last := len(merged) - 1; last >= 0 && merged[last].Y == n.Y {
+			merged[last].Width += n.Width
+			continue
+		}
+		merged = append(merged, n)
+	}
+	s.nodes = merged
+	s.syncFreeSpaces(bin)
+}
+
+// subtractDisjoint removes the area of box from the rectangles, cutting each one it
+// intersects into up to four disjoint pieces: full-height strips left and right of the
+// box, and the parts above and below it in between. Overlaps and pieces no larger than
+// tol are ignored.
+func subtractDisjoint(spaces []*FreeSpaceBox, box *Box, tol float64) []*FreeSpaceBox {
+	used := FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}
+	result := make([]*FreeSpaceBox, 0, len(spaces)+3)
+	for _, space := range spaces {
+		if !rectsOverlap(space, &used, tol) {
+			result = append(result, space)
+			continue
+		}
+		left, right := max(space.X, used.X), min(space.X+space.Width, used.X+used.Width)
+		if left > space.X+tol {
+			result = append(result, &FreeSpaceBox{X: space.X, Y: space.Y, Width: left - space.X, Height: space.Height})
+		}
+		if spaceRight := space.X + space.Width; spaceRight > right+tol {
+			result = append(result, &FreeSpaceBox{X: right, Y: space.Y, Width: spaceRight - right, Height: space.Height})
+		}
+		if used.Y > space.Y+tol {
+			result = append(result, &FreeSpaceBox{X: left, Y: space.Y, Width: right - left, Height: used.Y - space.Y})
+		}
+		if bottom, spaceBottom := used.Y+used.Height, space.Y+space.Height; spaceBottom > bottom+tol {
+			result = append(result, &FreeSpaceBox{X: left, Y: bottom, Width: right - left, Height: spaceBottom - bottom})
+		}
+	}
+	return result
+}
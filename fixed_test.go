@@ -0,0 +1,74 @@
+package binpacking
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBinPlace(t *testing.T) {
+	bins := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(10, 10, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(10, 10, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(10, 10) },
+	}
+	for name, newBin := range bins {
+		t.Run("packs around locked boxes with "+name, func(t *testing.T) {
+			bin := newBin()
+			reserved := NewBox(4, 4, true)
+			if err := bin.Place(reserved, 3, 3); err != nil {
+				t.Fatalf("Place: %v", err)
+			}
+			boxes := make([]*Box, 0)
+			for i := 0; i < 12; i++ {
+				boxes = append(boxes, NewBox(3, 3, true))
+			}
+			NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+			if reserved.X != 3 || reserved.Y != 3 || !reserved.Locked {
+				t.Errorf("Locked box moved to %s", reserved.Label())
+			}
+			for i, a := range bin.Boxes {
+				for _, c := range bin.Boxes[i+1:] {
+					if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+						t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+					}
+				}
+			}
+			if len(bin.Boxes) < 5 {
+				t.Errorf("Packed around the reserved region: got %d boxes, want at least %d", len(bin.Boxes), 5)
+			}
+		})
+	}
+
+	t.Run("rejects invalid placements", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		if err := bin.Place(NewBox(5, 5, true), 0, 0); err != nil {
+			t.Fatalf("Place: %v", err)
+		}
+		for _, tc := range []struct {
+			box  *Box
+			x, y float64
+		}{
+			{NewBox(5, 5, true), 4, 4},  // Overlaps
+			{NewBox(5, 5, true), 6, 0},  // Outside
+			{NewBox(5, 5, true), -1, 5}, // Outside
+		} {
+			if err := bin.Place(tc.box, tc.x, tc.y); !errors.Is(err, ErrPlacement) {
+				t.Errorf("Place at [%g,%g]: got %v, want ErrPlacement", tc.x, tc.y, err)
+			}
+		}
+	})
+
+	t.Run("improve keeps locked boxes", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		reserved := NewBox(2, 2, true)
+		bin.Place(reserved, 8, 8)
+		packer := NewPacker([]*Bin{bin, NewBin(10, 10, nil)})
+		packer.Pack([]*Box{NewBox(5, 5, false), NewBox(5, 5, false), NewBox(6, 4, false)}, PackerOptions{})
+		packer.Improve(context.Background(), ImproveOptions{Iterations: 300, Seed: 1})
+		if reserved.X != 8 || reserved.Y != 8 || !reserved.Packed || len(bin.Boxes) == 0 || bin.Boxes[0] != reserved {
+			t.Errorf("Locked box: got %s, want 2x2 at [8,8] in its bin", reserved.Label())
+		}
+	})
+}
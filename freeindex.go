@@ -0,0 +1,214 @@
+package binpacking
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// minIndexedSpaces is the number of free spaces below which a bin's free list is simply
+// scanned, since sorting it after every placement would cost more than it saves.
+const minIndexedSpaces = 32
+
+// freeIndex orders a bin's free list by width, height and top edge, so that the best
+// placement under a built-in strategy can be found by visiting the spaces in order of a
+// lower bound of their score and stopping once the bound exceeds the best score found,
+// instead of scoring every space. An index is built after each placement, describes the
+// free list as it was then and is never modified, so scoring may read it concurrently.
+type freeIndex struct {
+	spaces []*FreeSpaceBox // The indexed free list, to tell whether it has been replaced since
+
+	// tidy records that no space of the list contains another, as after pruneFreeList,
+	// so that pruning after the next split only needs to consider the new spaces.
+	tidy bool
+
+	byWidth  []freeRef
+	byHeight []freeRef
+	byY      []freeRef
+}
+
+// freeRef is a free space and its position in the free list, which decides ties as in
+// a scan of the list.
+type freeRef struct {
+	space    *FreeSpaceBox
+	position int
+}
+
+// indexFreeSpaces indexes the bin's free list after a placement. Only the MaxRects and
+// Guillotine backends score their free list directly; see placementFor.
+func (b *Bin) indexFreeSpaces() {
+	switch b.Backend.(type) {
+	case nil, MaxRectsBackend, *MaxRectsBackend, *GuillotineBackend:
+	default:
+		b.index = nil
+		return
+	}
+	index := &freeIndex{spaces: b.FreeSpaces, tidy: b.usesMaxRects()}
+	if len(b.FreeSpaces) >= minIndexedSpaces {
+		refs := make([]freeRef, 3*len(b.FreeSpaces))
+		index.byWidth = refs[:len(b.FreeSpaces)]
+		index.byHeight = refs[len(b.FreeSpaces) : 2*len(b.FreeSpaces)]
+		index.byY = refs[2*len(b.FreeSpaces):]
+		for i, space := range b.FreeSpaces {
+			ref := freeRef{space, i}
+			index.byWidth[i], index.byHeight[i], index.byY[i] = ref, ref, ref
+		}
+		byKey := func(key func(*FreeSpaceBox) float64) func(a, b freeRef) int {
+			return func(a, b freeRef) int {
+				if ka, kb := key(a.space), key(b.space); ka != kb {
+					if ka < kb {
+						return -1
+					}
+					return 1
+				}
+				return a.position - b.position
+			}
+		}
+		slices.SortFunc(index.byWidth, byKey(func(s *FreeSpaceBox) float64 { return s.Width }))
+		slices.SortFunc(index.byHeight, byKey(func(s *FreeSpaceBox) float64 { return s.Height }))
+		slices.SortFunc(index.byY, byKey(func(s *FreeSpaceBox) float64 { return s.Y }))
+	}
+	b.index = index
+}
+
+// currentIndex returns the bin's index if it still describes FreeSpaces, i.e. the free
+// list has not been replaced or shortened since the last placement.
+func (b *Bin) currentIndex() *freeIndex {
+	index := b.index
+	if index == nil || len(index.spaces) != len(b.FreeSpaces) {
+		return nil
+	}
+	if len(b.FreeSpaces) > 0 && &index.spaces[0] != &b.FreeSpaces[0] {
+		return nil
+	}
+	return index
+}
+
+// findFree is findBestFit over the bin's free list, using its index when there is one.
+// The index finds the earliest of equally scored spaces, so other tie-breaking rules scan.
+func (b *Bin) findFree(box fitSize, placement PlacementStrategyFunc) PlacementInfo {
+	if box.anchor == nil && b.usesMaxRects() {
+		switch reflect.ValueOf(placement).Pointer() {
+		case centerFitCode:
+			box.position = b.centerPosition
+		case spreadFitCode:
+			box.position = b.spreadPosition
+		}
+	}
+	if index := b.currentIndex(); index != nil && index.byWidth != nil && box.tieBreak == TieFirstSpace && box.anchor == nil {
+		if info, ok := index.find(box, placement); ok {
+			return info
+		}
+	}
+	return findBestFit(box, b.FreeSpaces, placement)
+}
+
+// Code pointers of the strategies the index has bounds for.
+var (
+	bestShortSideFitCode = reflect.ValueOf(BestShortSideFit).Pointer()
+	bestLongSideFitCode  = reflect.ValueOf(BestLongSideFit).Pointer()
+	bestAreaFitCode      = reflect.ValueOf(BestAreaFit).Pointer()
+	bottomLeftCode       = reflect.ValueOf(BottomLeft).Pointer()
+)
+
+// indexSearch is a search of the index for the best placement of one box. It finds the
+// placement a scan of the free list finds: the lowest score, earliest in the list on ties,
+// unrotated before rotated.
+type indexSearch struct {
+	placement PlacementStrategyFunc
+	tolerance float64 // By which a space may fall short of the box, as in findBestFit
+	best      PlacementInfo
+	position  int
+}
+
+// find returns the best placement of the box, or false if the index has no bounds for
+// the strategy.
+func (index *freeIndex) find(box fitSize, placement PlacementStrategyFunc) (PlacementInfo, bool) {
+	if placement == nil {
+		return PlacementInfo{}, false
+	}
+	code := reflect.ValueOf(placement).Pointer()
+	switch code {
+	case bestShortSideFitCode, bestLongSideFitCode, bestAreaFitCode, bottomLeftCode:
+	default:
+		return PlacementInfo{}, false
+	}
+	search := indexSearch{placement: placement, tolerance: box.tolerance, best: PlacementInfo{Score: NoFit}}
+	search.orientation(index, code, box.width, box.height, false)
+	if !box.constrainRotation && box.width != box.height {
+		search.orientation(index, code, box.height, box.width, true)
+	}
+	return search.best, true
+}
+
+// orientation searches the placements of the box in one orientation.
+func (search *indexSearch) orientation(index *freeIndex, code uintptr, width, height float64, rotated bool) {
+	switch code {
+	case bestShortSideFitCode:
+		// The short side leftover is the width or the height leftover, whichever is
+		// smaller; a space beating the best has one of them below the best score.
+		search.sweepWidth(index.byWidth, width, height, rotated, func(s *FreeSpaceBox) float64 { return s.Width - width })
+		search.sweepHeight(index.byHeight, width, height, rotated, func(s *FreeSpaceBox) float64 { return s.Height - height })
+	case bestLongSideFitCode:
+		// The long side leftover is at least the width leftover.
+		search.sweepWidth(index.byWidth, width, height, rotated, func(s *FreeSpaceBox) float64 { return s.Width - width })
+	case bestAreaFitCode:
+		// The leftover area is at least the strip beside the box, (W-w)·h.
+		search.sweepWidth(index.byWidth, width, height, rotated, func(s *FreeSpaceBox) float64 { return (s.Width - width) * height })
+	case bottomLeftCode:
+		// The score is the far edge Y+h itself.
+		search.sweep(index.byY, 0, width, height, rotated, func(s *FreeSpaceBox) float64 { return s.Y + height })
+	}
+}
+
+// sweepWidth sweeps the spaces at least width wide, narrowest first.
+func (search *indexSearch) sweepWidth(refs []freeRef, width, height float64, rotated bool, bound func(*FreeSpaceBox) float64) {
+	start := sort.Search(len(refs), func(i int) bool { return refs[i].space.Width+search.tolerance >= width })
+	search.sweep(refs, start, width, height, rotated, bound)
+}
+
+// sweepHeight sweeps the spaces at least height tall, shortest first.
+func (search *indexSearch) sweepHeight(refs []freeRef, width, height float64, rotated bool, bound func(*FreeSpaceBox) float64) {
+	start := sort.Search(len(refs), func(i int) bool { return refs[i].space.Height+search.tolerance >= height })
+	search.sweep(refs, start, width, height, rotated, bound)
+}
+
+// sweep scores the spaces of refs from start on, in which order bound does not
+// decrease, until the bound exceeds the primary score of the best placement. The bound
+// is sanitized like scores, so spaces whose score saturates still take part in ties.
+func (search *indexSearch) sweep(refs []freeRef, start int, width, height float64, rotated bool, bound func(*FreeSpaceBox) float64) {
+	for _, ref := range refs[start:] {
+		space := ref.space
+		if search.best.Fits && sanitizeScore(bound(space)) > search.best.Score.Primary {
+			return
+		}
+		if space.Width+search.tolerance < width || space.Height+search.tolerance < height {
+			continue
+		}
+		score := search.placement(space, width, height)
+		if !search.better(score, ref.position, rotated) {
+			continue
+		}
+		search.best = PlacementInfo{
+			Score:         score,
+			ChosenSpace:   space,
+			X:             space.X,
+			Y:             space.Y,
+			NeedsRotation: rotated,
+			Fits:          true,
+		}
+		search.position = ref.position
+	}
+}
+
+// better reports whether a placement beats the best one, breaking ties like a scan.
+func (search *indexSearch) better(score Score, position int, rotated bool) bool {
+	best := search.best
+	if score.Less(best.Score) {
+		return true
+	}
+	if !best.Fits || best.Score.Less(score) {
+		return false
+	}
+	return position < search.position || position == search.position && !rotated && best.NeedsRotation
+}
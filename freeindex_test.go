@@ -0,0 +1,70 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFreeIndex(t *testing.T) {
+	strategies := map[string]PlacementStrategyFunc{
+		"bssf": BestShortSideFit, "blsf": BestLongSideFit, "baf": BestAreaFit, "bl": BottomLeft,
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(7, 7))
+			indexed := NewBin(300, 300, strategy)
+			scanned := NewBin(300, 300, strategy) // Without an index, pruning the whole list
+			for step := 0; step < 400; step++ {
+				if step > 0 && indexed.currentIndex() == nil {
+					t.Fatalf("step %d: free list not indexed", step)
+				}
+				// Small integer sizes, so that many spaces tie.
+				for query := 0; query < 20; query++ {
+					size := fitSize{float64(1 + rng.IntN(40)), float64(1 + rng.IntN(40)), rng.IntN(4) == 0, 0, TieFirstSpace, nil, nil}
+					for name, other := range strategies {
+						got := indexed.findFree(size, other)
+						want := findBestFit(size, indexed.FreeSpaces, other)
+						if got != want {
+							t.Fatalf("step %d: %s for %+v: got %+v, want %+v", step, name, size, got, want)
+						}
+					}
+				}
+
+				w, h := float64(1+rng.IntN(25)), float64(1+rng.IntN(25))
+				scanned.index = nil
+				got, want := indexed.Insert(NewBox(w, h, false)), scanned.Insert(NewBox(w, h, false))
+				if got != want {
+					t.Fatalf("step %d: Insert: got %v, want %v", step, got, want)
+				}
+				if len(indexed.FreeSpaces) != len(scanned.FreeSpaces) {
+					t.Fatalf("step %d: got %d free spaces, want %d", step, len(indexed.FreeSpaces), len(scanned.FreeSpaces))
+				}
+				for i := range indexed.FreeSpaces {
+					if *indexed.FreeSpaces[i] != *scanned.FreeSpaces[i] {
+						t.Fatalf("step %d: free space %d: got %+v, want %+v", step, i, *indexed.FreeSpaces[i], *scanned.FreeSpaces[i])
+					}
+				}
+			}
+			if len(indexed.FreeSpaces) < minIndexedSpaces {
+				t.Errorf("got %d free spaces, want at least %d so the index is used", len(indexed.FreeSpaces), minIndexedSpaces)
+			}
+		})
+	}
+
+	t.Run("replaced free list", func(t *testing.T) {
+		bin := NewBin(300, 300, nil)
+		rng := rand.New(rand.NewPCG(8, 8))
+		for len(bin.FreeSpaces) < minIndexedSpaces {
+			bin.Insert(NewBox(float64(1+rng.IntN(25)), float64(1+rng.IntN(25)), false))
+		}
+		bin.FreeSpaces = bin.FreeSpaces[:len(bin.FreeSpaces)-1]
+		if bin.currentIndex() != nil {
+			t.Error("got an index for a shortened free list, want none")
+		}
+		bin.FreeSpaces = []*FreeSpaceBox{{X: 10, Y: 10, Width: 5, Height: 5}}
+		if got, want := bin.ScoreFor(NewBox(5, 5, false)), NewScoreWithTieBreak(0, 0); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+)
+
+// GAOptions configures a GAPacker. Zero values select the defaults noted on each field.
+type GAOptions struct {
+	PopulationSize int     // Number of candidate solutions per generation; default 30
+	Generations    int     // Number of generations to evolve; default 50
+	MutationRate   float64 // Probability of mutating each gene; default 0.05
+	Seed           uint64  // Seed of the random generator, so runs are reproducible
+
+	// TimeLimit bounds the time spent evolving. Once it has passed, no further solutions
+	// are evaluated and the best one found so far is applied; the area-descending greedy
+	// solution is always evaluated. Zero or negative means no limit.
+	TimeLimit time.Duration
+}
+
+// GAPacker packs boxes with a genetic algorithm. Each candidate solution is an insertion
+// order plus an orientation per box; it is decoded by inserting the boxes in that order,
+// each into the first bin where Bin.Insert succeeds, using the bins' own backends and
+// placement strategies. Solutions are ranked by packed area and then by the total area
+// of the bins they use, so the search both fits more boxes and recovers waste.
+//
+// The first solution of the initial population is the classic area-descending order,
+// and the best solution always survives, so the result is never worse than that greedy
+// first-fit packing. Decoding works on scratch copies of the bins; only the winning
+// solution is applied to Bins.
+type GAPacker struct {
+	Bins          []*Bin    // Bins available for packing
+	UnpackedBoxes []*Box    // Boxes that could not be packed in the last call to Pack
+	Options       GAOptions // Evolution parameters
+	TimedOut      bool      // Whether the last call to Pack stopped at Options.TimeLimit
+}
+
+// NewGAPacker creates a GAPacker for the given bins.
+func NewGAPacker(bins []*Bin, options GAOptions) *GAPacker {
+	packerBins := make([]*Bin, len(bins))
+	copy(packerBins, bins)
+	return &GAPacker{
+		Bins:          packerBins,
+		UnpackedBoxes: make([]*Box, 0),
+		Options:       options,
+	}
+}
+
+// chromosome is a candidate solution: the box insertion order and whether each box
+// (indexed like the input, not like the order) is turned by 90 degrees.
+type chromosome struct {
+	order   []int
+	rotate  []bool
+	fitness packingFitness
+}
+
+// packingFitness is the quality of a decoded solution.
+type packingFitness struct {
+	packedArea  float64 // Total area of the packed boxes; higher is better
+	usedBinArea float64 // Total area of bins holding boxes; lower is better
+}
+
+// better reports whether f is a strictly better solution than other.
+func (f packingFitness) better(other packingFitness) bool {
+	if f.packedArea != other.packedArea {
+		return f.packedArea > other.packedArea
+	}
+	return f.usedBinArea < other.usedBinArea
+}
+
+// fixedOrientation inserts a box exactly as oriented by the chromosome.
+var fixedOrientation = &TagOptions{ConstrainRotation: true}
+
+// Pack packs the boxes and returns the ones that were placed. Boxes that are nil,
+// already packed or have invalid dimensions are handled like in Packer.Pack.
+func (p *GAPacker) Pack(boxes []*Box) []*Box {
+	packedBoxes := make([]*Box, 0)
+	p.TimedOut = false
+	var deadline time.Time
+	if p.Options.TimeLimit > 0 {
+		deadline = time.Now().Add(p.Options.TimeLimit)
+	}
+	expired := func() bool {
+		p.TimedOut = p.TimedOut || !deadline.IsZero() && time.Now().After(deadline)
+		return p.TimedOut
+	}
+
+	boxesToPack := make([]*Box, 0, len(boxes))
+	invalidBoxes := make([]*Box, 0)
+	for _, box := range boxes {
+		if box == nil || box.Packed {
+			continue
+		}
+		if box.Validate() != nil {
+			invalidBoxes = append(invalidBoxes, box)
+			continue
+		}
+		boxesToPack = append(boxesToPack, box)
+	}
+	bins := make([]*Bin, 0, len(p.Bins))
+	for _, bin := range p.Bins {
+		if bin != nil {
+			bins = append(bins, bin)
+		}
+	}
+	if len(boxesToPack) == 0 || len(bins) == 0 {
+		p.UnpackedBoxes = append(boxesToPack, invalidBoxes...)
+		return packedBoxes
+	}
+
+	populationSize := p.Options.PopulationSize
+	if populationSize <= 0 {
+		populationSize = 30
+	}
+	generations := p.Options.Generations
+	if generations <= 0 {
+		generations = 50
+	}
+	mutationRate := p.Options.MutationRate
+	if mutationRate <= 0 {
+		mutationRate = 0.05
+	}
+	rng := rand.New(rand.NewPCG(p.Options.Seed, 0))
+
+	// Which boxes can actually be turned; other rotation genes are ignored.
+	rotatable := make([]bool, len(boxesToPack))
+	for i, box := range boxesToPack {
+		rotatable[i] = !box.ConstrainRotation && box.Width != box.Height
+	}
+	evaluate := func(c *chromosome) {
+		c.fitness = decodeChromosome(bins, boxesToPack, c, nil)
+	}
+
+	// Initial population: the area-descending greedy order, then random solutions.
+	population := make([]*chromosome, populationSize)
+	greedy := &chromosome{order: make([]int, len(boxesToPack)), rotate: make([]bool, len(boxesToPack))}
+	for i := range greedy.order {
+		greedy.order[i] = i
+	}
+	sort.SliceStable(greedy.order, func(i, j int) bool {
+		return boxesToPack[greedy.order[i]].Area() > boxesToPack[greedy.order[j]].Area()
+	})
+	evaluate(greedy)
+	population[0] = greedy
+	for i := 1; i < populationSize; i++ {
+		if expired() {
+			population = population[:i]
+			break
+		}
+		c := &chromosome{order: rng.Perm(len(boxesToPack)), rotate: make([]bool, len(boxesToPack))}
+		for j := range c.rotate {
+			c.rotate[j] = rotatable[j] && rng.IntN(2) == 1
+		}
+		evaluate(c)
+		population[i] = c
+	}
+
+	best := population[0]
+	for _, c := range population {
+		if c.fitness.better(best.fitness) {
+			best = c
+		}
+	}
+
+	// Tournament selection of size three.
+	selectParent := func() *chromosome {
+		winner := population[rng.IntN(len(population))]
+		for k := 0; k < 2; k++ {
+			if c := population[rng.IntN(len(population))]; c.fitness.better(winner.fitness) {
+				winner = c
+			}
+		}
+		return winner
+	}
+
+	for generation := 0; generation < generations && !expired(); generation++ {
+		next := make([]*chromosome, 0, populationSize)
+		next = append(next, best) // Elitism
+		for len(next) < populationSize && !expired() {
+			child := crossover(selectParent(), selectParent(), rng)
+			mutate(child, rotatable, mutationRate, rng)
+			evaluate(child)
+			if child.fitness.better(best.fitness) {
+				best = child
+			}
+			next = append(next, child)
+		}
+		population = next
+	}
+
+	// Apply the winning solution to the real bins and boxes.
+	decodeChromosome(bins, boxesToPack, best, &packedBoxes)
+	packedSet := make(map[*Box]struct{}, len(packedBoxes))
+	for _, box := range packedBoxes {
+		packedSet[box] = struct{}{}
+	}
+	p.UnpackedBoxes = make([]*Box, 0, len(boxesToPack)-len(packedBoxes)+len(invalidBoxes))
+	for _, box := range boxesToPack {
+		if _, ok := packedSet[box]; !ok {
+			p.UnpackedBoxes = append(p.UnpackedBoxes, box)
+		}
+	}
+	p.UnpackedBoxes = append(p.UnpackedBoxes, invalidBoxes...)
+	return packedBoxes
+}
+
+// decodeChromosome inserts the boxes in the chromosome's order, each into the first bin
+// that accepts it. With a nil packed slice, scratch copies of the bins and boxes are used
+// and nothing observable changes; otherwise the real bins and boxes are packed and the
+// packed boxes are appended to *packed.
+func decodeChromosome(bins []*Bin, boxes []*Box, c *chromosome, packed *[]*Box) packingFitness {
+	targets := bins
+	if packed == nil {
+		targets, _ = trialCopies(bins, nil)
+	}
+
+	var fitness packingFitness
+	used := make([]bool, len(targets))
+	for i, bin := range targets {
+		used[i] = len(bin.Boxes) > 0
+	}
+	for _, index := range c.order {
+		box := boxes[index]
+		if packed == nil {
+			box = trialBox(box)
+		}
+		rotate := c.rotate[index] && !box.ConstrainRotation && box.Width != box.Height
+		if rotate {
+			box.Rotate()
+		}
+		inserted := false
+		for i, bin := range targets {
+			if bin.InsertWith(box, fixedOrientation) {
+				used[i] = true
+				inserted = true
+				break
+			}
+		}
+		if !inserted {
+			if rotate {
+				box.Rotate() // Leave unpacked boxes as they were given
+			}
+			continue
+		}
+		fitness.packedArea += box.Area()
+		if packed != nil {
+			*packed = append(*packed, box)
+		}
+	}
+	for i, bin := range targets {
+		if used[i] {
+			fitness.usedBinArea += bin.Area()
+		}
+	}
+	return fitness
+}
+
+// crossover combines two parents with order crossover (OX1) for the insertion order and
+// uniform crossover for the orientations.
+func crossover(a, b *chromosome, rng *rand.Rand) *chromosome {
+	n := len(a.order)
+	child := &chromosome{order: make([]int, 0, n), rotate: make([]bool, n)}
+
+	// Keep a random slice of a's order in place and fill the rest in b's order.
+	start, end := rng.IntN(n), rng.IntN(n)
+	if start > end {
+		start, end = end, start
+	}
+	kept := make(map[int]struct{}, end-start+1)
+	for _, gene := range a.order[start : end+1] {
+		kept[gene] = struct{}{}
+	}
+	rest := make([]int, 0, n)
+	for _, gene := range b.order {
+		if _, ok := kept[gene]; !ok {
+			rest = append(rest, gene)
+		}
+	}
+	child.order = append(child.order, rest[:start]...)
+	child.order = append(child.order, a.order[start:end+1]...)
+	child.order = append(child.order, rest[start:]...)
+
+	for i := range child.rotate {
+		if rng.IntN(2) == 0 {
+			child.rotate[i] = a.rotate[i]
+		} else {
+			child.rotate[i] = b.rotate[i]
+		}
+	}
+	return child
+}
+
+// mutate swaps order genes and flips orientation genes, each with the given probability.
+func mutate(c *chromosome, rotatable []bool, rate float64, rng *rand.Rand) {
+	c.order = slices.Clone(c.order)
+	for i := range c.order {
+		if rng.Float64() < rate {
+			j := rng.IntN(len(c.order))
+			c.order[i], c.order[j] = c.order[j], c.order[i]
+		}
+	}
+	for i := range c.rotate {
+		if rotatable[i] && rng.Float64() < rate {
+			c.rotate[i] = !c.rotate[i]
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package binpacking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGAPacker(t *testing.T) {
+	newBoxes := func() []*Box {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 30; i++ {
+			boxes = append(boxes, NewBox(float64(3+i*7%17), float64(2+i*5%13), false))
+		}
+		return boxes
+	}
+	packedArea := func(boxes []*Box) float64 {
+		area := 0.0
+		for _, box := range boxes {
+			area += box.Area()
+		}
+		return area
+	}
+
+	t.Run("never loses to the greedy order", func(t *testing.T) {
+		// Greedy first-fit in area-descending order, decoded the same way.
+		greedy := NewGAPacker([]*Bin{NewBin(40, 40, nil), NewBin(40, 40, nil)}, GAOptions{PopulationSize: 1, Generations: 1})
+		greedyArea := packedArea(greedy.Pack(newBoxes()))
+
+		bins := []*Bin{NewBin(40, 40, nil), NewBin(40, 40, nil)}
+		packer := NewGAPacker(bins, GAOptions{PopulationSize: 20, Generations: 20, Seed: 7})
+		boxes := newBoxes()
+		packed := packer.Pack(boxes)
+		if area := packedArea(packed); area < greedyArea {
+			t.Errorf("Packed area: got %g, want at least %g", area, greedyArea)
+		}
+		if len(packed)+len(packer.UnpackedBoxes) != len(boxes) {
+			t.Errorf("Packed plus unpacked: got %d, want %d", len(packed)+len(packer.UnpackedBoxes), len(boxes))
+		}
+		for _, bin := range bins {
+			for i, a := range bin.Boxes {
+				for _, c := range bin.Boxes[i+1:] {
+					if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+						t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("is reproducible for a seed", func(t *testing.T) {
+		layout := func() []string {
+			bin := NewBin(40, 40, nil)
+			NewGAPacker([]*Bin{bin}, GAOptions{PopulationSize: 10, Generations: 10, Seed: 3}).Pack(newBoxes())
+			labels := make([]string, 0, len(bin.Boxes))
+			for _, box := range bin.Boxes {
+				labels = append(labels, box.Label())
+			}
+			return labels
+		}
+		first, second := layout(), layout()
+		if len(first) != len(second) {
+			t.Fatalf("Packed boxes: got %d and %d, want equal counts", len(first), len(second))
+		}
+		for i := range first {
+			if first[i] != second[i] {
+				t.Errorf("Box %d: got %s and %s, want identical layouts", i, first[i], second[i])
+			}
+		}
+	})
+
+	t.Run("stops at the time limit", func(t *testing.T) {
+		greedy := NewGAPacker([]*Bin{NewBin(40, 40, nil)}, GAOptions{PopulationSize: 1, Generations: 1})
+		greedyArea := packedArea(greedy.Pack(newBoxes()))
+
+		packer := NewGAPacker([]*Bin{NewBin(40, 40, nil)}, GAOptions{Generations: 1 << 30, TimeLimit: 20 * time.Millisecond})
+		start := time.Now()
+		packed := packer.Pack(newBoxes())
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Pack took %v, want about the 20ms time limit", elapsed)
+		}
+		if !packer.TimedOut {
+			t.Errorf("TimedOut: got %v, want %v", packer.TimedOut, true)
+		}
+		if area := packedArea(packed); area < greedyArea {
+			t.Errorf("Packed area: got %g, want at least %g", area, greedyArea)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package binpacking
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// GridOptions configures a bin created by NewGridBin.
+type GridOptions struct {
+	Resolution float64 // Width and height of a cell; zero uses 1
+	BestFit    bool    // Score the free windows with Placement instead of taking the first
+	// Mask blocks cells regardless of the bin's contents; see GridBackend.Mask.
+	Mask [][]bool
+	// Placement scores free windows when BestFit is set. Nil uses BestShortSideFit.
+	Placement PlacementStrategyFunc
+}
+
+// GridBackend tracks the free area of a bin as an occupancy grid: a bitset of square cells
+// Resolution units wide, each either free or occupied. Boxes are rasterized to the cells
+// they cover and placed on cell boundaries, which makes packing exact at the resolution of
+// the grid, e.g. for pixel-accurate sprite sheets, and lets any shape of unusable area be
+// blocked with a mask. Finding a placement scans the grid, so its cost grows with the
+// number of cells rather than with the number of free rectangles.
+//
+// By default each box takes the first free window in row-major order from the top-left
+// corner. With BestFit the free windows pushed against an occupied cell or the edge of
+// the bin on their left and top are scored with the placement strategy, against the
+// largest free rectangle containing the window.
+//
+// Cells the bin covers only partly, along its right and bottom edges, are never used.
+// The bin's FreeSpaces are kept up to date for reporting: disjoint rectangles covering
+// the free cells.
+type GridBackend struct {
+	Resolution float64 // Width and height of a cell; zero uses 1
+	BestFit    bool    // Whether free windows are scored with the placement strategy
+	// Mask blocks cells regardless of the bin's contents, indexed [row][column]: a true
+	// cell is unusable. Cells beyond the rows and columns of the mask are not blocked.
+	// The mask is read when the grid is built, at the first placement or when the bin
+	// is cleared.
+	Mask [][]bool
+
+	cols, rows int
+	stride     int      // Words per row
+	cells      []uint64 // Occupancy bits in row-major order; set bits are occupied
+}
+
+// NewGridBin creates a bin that uses a GridBackend.
+func NewGridBin(width, height float64, options GridOptions) *Bin {
+	bin := NewBin(width, height, options.Placement)
+	grid := &GridBackend{Resolution: options.Resolution, BestFit: options.BestFit, Mask: options.Mask}
+	bin.Backend = grid
+	grid.reset(bin)
+	return bin
+}
+
+// FindPlacement implements Backend. Both orientations are tried unless rotation is
+// constrained; without BestFit the score is the window's Y, with X as the tie-breaker.
+func (g *GridBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	g.ensureCells(bin)
+	res := g.resolution()
+	best := PlacementInfo{Score: NoFit}
+	try := func(width, height float64, rotated bool) {
+		w, h := g.span(width, bin), g.span(height, bin)
+		for r := 0; r+h <= g.rows; r++ {
+			for c := 0; c+w <= g.cols; {
+				if blocked := g.blockedAt(c, r, w, h); blocked >= 0 {
+					c = blocked + 1 // Every window covering the occupied cell fails too
+					continue
+				}
+				x, y := float64(c)*res, float64(r)*res
+				if !g.BestFit {
+					if score := NewScoreWithTieBreak(y, x); score.Less(best.Score) {
+						best = PlacementInfo{Score: score, X: x, Y: y, NeedsRotation: rotated, Fits: true}
+					}
+					return // The first window is the best one in this orientation
+				}
+				// The window touches an occupied cell or the edge on its left, as
+				// the windows to its right do only past the next occupied cell.
+				next := g.cols
+				for row := r; row < r+h; row++ {
+					next = min(next, g.find(row, c+w, next, true))
+				}
+				if r == 0 || g.find(r-1, c, c+w, true) < c+w {
+					if score := strategy(g.extent(c, r, w, h), width, height); score.Less(best.Score) {
+						best = PlacementInfo{Score: score, X: x, Y: y, NeedsRotation: rotated, Fits: true}
+					}
+				}
+				c = next + 1
+			}
+		}
+	}
+	try(box.Width, box.Height, false)
+	if !box.ConstrainRotation && box.Width != box.Height {
+		try(box.Height, box.Width, true)
+	}
+	return best
+}
+
+// Place implements Backend.
+func (g *GridBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	g.PlaceFixed(bin, box)
+}
+
+// PlaceFixed implements FixedPlacer. Every cell the box covers, even partly, is occupied.
+func (g *GridBackend) PlaceFixed(bin *Bin, box *Box) {
+	g.ensureCells(bin)
+	res, tol := g.resolution(), bin.tolerance()
+	c0 := max(int(math.Floor((box.X+tol)/res)), 0)
+	c1 := min(int(math.Ceil((box.X+box.Width-tol)/res)), g.cols)
+	r0 := max(int(math.Floor((box.Y+tol)/res)), 0)
+	r1 := min(int(math.Ceil((box.Y+box.Height-tol)/res)), g.rows)
+	for r := r0; r < r1; r++ {
+		g.fill(r, c0, c1)
+	}
+	g.syncFreeSpaces(bin)
+}
+
+// resolution returns the cell size, defaulting to 1.
+func (g *GridBackend) resolution() float64 {
+	if g.Resolution > 0 {
+		return g.Resolution
+	}
+	return 1
+}
+
+// reset rebuilds an empty grid for bin, with only the mask blocked.
+func (g *GridBackend) reset(bin *Bin) {
+	g.cells = nil
+	g.ensureCells(bin)
+	g.syncFreeSpaces(bin)
+}
+
+// dimensions sets the size of the grid from the size of bin.
+func (g *GridBackend) dimensions(bin *Bin) {
+	res, tol := g.resolution(), bin.tolerance()
+	g.cols = int(math.Floor((bin.Width + tol) / res))
+	g.rows = int(math.Floor((bin.Height + tol) / res))
+	g.stride = (g.cols + 63) / 64
+}
+
+// ensureCells builds the grid for backends not created by NewGridBin, or after a reset.
+func (g *GridBackend) ensureCells(bin *Bin) {
+	if g.cells != nil {
+		return
+	}
+	g.dimensions(bin)
+	g.cells = make([]uint64, g.stride*g.rows)
+	for r, row := range g.Mask[:min(len(g.Mask), g.rows)] {
+		for c, blocked := range row[:min(len(row), g.cols)] {
+			if blocked {
+				g.fill(r, c, c+1)
+			}
+		}
+	}
+}
+
+// span returns the number of cells a length occupies.
+func (g *GridBackend) span(length float64, bin *Bin) int {
+	return max(int(math.Ceil((length-bin.tolerance())/g.resolution())), 1)
+}
+
+// find returns the first column in [c, end) of row r whose cell is occupied, or free if
+// occupied is false, and end if there is none.
+func (g *GridBackend) find(r, c, end int, occupied bool) int {
+	row := g.cells[r*g.stride : (r+1)*g.stride]
+	for c < end {
+		word := row[c/64]
+		if !occupied {
+			word = ^word
+		}
+		word >>= c % 64
+		span := min(64-c%64, end-c)
+		if span < 64 {
+			word &= 1<<span - 1
+		}
+		if word != 0 {
+			return c + bits.TrailingZeros64(word)
+		}
+		c += span
+	}
+	return end
+}
+
+// fill marks the cells in columns [c0, c1) of row r occupied.
+func (g *GridBackend) fill(r, c0, c1 int) {
+	row := g.cells[r*g.stride : (r+1)*g.stride]
+	for c := c0; c < c1; {
+		span := min(64-c%64, c1-c)
+		mask := ^uint64(0)
+		if span < 64 {
+			mask = 1<<span - 1
+		}
+		row[c/64] |= mask << (c % 64)
+		c += span
+	}
+}
+
+// blockedAt returns an occupied column of the w by h cell window at column c and row r,
+// or -1 if the window is free.
+func (g *GridBackend) blockedAt(c, r, w, h int) int {
+	for row := r; row < r+h; row++ {
+		if blocked := g.find(row, c, c+w, true); blocked < c+w {
+			return blocked
+		}
+	}
+	return -1
+}
+
+// findLast returns the last occupied column in [start, c) of row r, and start-1 if
+// there is none.
+func (g *GridBackend) findLast(r, start, c int) int {
+	row := g.cells[r*g.stride : (r+1)*g.stride]
+	for c > start {
+		last := c - 1
+		word := row[last/64] << (63 - last%64) // Bit last is now the top bit
+		span := min(last%64+1, c-start)
+		if span < 64 {
+			word &= ^uint64(0) << (64 - span)
+		}
+		if word != 0 {
+			return last - bits.LeadingZeros64(word)
+		}
+		c -= span
+	}
+	return start - 1
+}
+
+// extent returns the largest free rectangle containing the free w by h cell window at
+// column c and row r that spans the window's rows and columns: first widened as far as
+// every row of the window is free, then lengthened as far as the widened columns are.
+func (g *GridBackend) extent(c, r, w, h int) *FreeSpaceBox {
+	left, right := 0, g.cols
+	for row := r; row < r+h; row++ {
+		left = max(left, g.findLast(row, left, c)+1)
+		right = min(right, g.find(row, c+w, right, true))
+	}
+	top, bottom := r, r+h
+	for top > 0 && g.find(top-1, left, right, true) == right {
+		top--
+	}
+	for bottom < g.rows && g.find(bottom, left, right, true) == right {
+		bottom++
+	}
+	res := g.resolution()
+	return &FreeSpaceBox{
+		X: float64(left) * res, Y: float64(top) * res,
+		Width: float64(right-left) * res, Height: float64(bottom-top) * res,
+	}
+}
+
+// syncFreeSpaces refreshes the bin's FreeSpaces from the grid: each run of free cells in
+// a row starts a rectangle, which grows downwards over the rows with the same run.
+func (g *GridBackend) syncFreeSpaces(bin *Bin) {
+	type growing struct {
+		space *FreeSpaceBox
+		row   int // First row of the rectangle
+	}
+	res := g.resolution()
+	spaces := make([]*FreeSpaceBox, 0)
+	var open map[[2]int]growing // Rectangles reaching the previous row, by run of columns
+	for r := range g.rows {
+		next := make(map[[2]int]growing, len(open))
+		for c := g.find(r, 0, g.cols, false); c < g.cols; c = g.find(r, c, g.cols, false) {
+			end := g.find(r, c, g.cols, true)
+			run := [2]int{c, end}
+			rect, ok := open[run]
+			if !ok {
+				rect = growing{space: &FreeSpaceBox{X: float64(c) * res, Y: float64(r) * res, Width: float64(end-c) * res}, row: r}
+				spaces = append(spaces, rect.space)
+			}
+			rect.space.Height = float64(r+1-rect.row) * res
+			next[run] = rect
+			c = end
+		}
+		open = next
+	}
+	bin.FreeSpaces = spaces
+}
+
+// clone returns a copy of the backend with its own grid. The mask is shared.
+func (g *GridBackend) clone() *GridBackend {
+	clone := *g
+	clone.cells = slices.Clone(g.cells)
+	return &clone
+}
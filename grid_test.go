@@ -0,0 +1,134 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGridBin(t *testing.T) {
+	for _, opts := range []GridOptions{{}, {BestFit: true}, {Resolution: 2}, {Resolution: 0.5, BestFit: true}} {
+		bin := NewGridBin(64, 64, opts)
+		if err := bin.AddDefect(30, 30, 3, 5); err != nil {
+			t.Fatal(err)
+		}
+		boxes := make([]*Box, 0)
+		for i := 0; i < 60; i++ {
+			boxes = append(boxes, NewBox(float64(2+i*7%13)+0.3, float64(1+i*11%9), false))
+		}
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack(boxes, PackerOptions{})
+
+		if len(bin.Boxes) < 20 {
+			t.Errorf("%+v: packed only %d boxes", opts, len(bin.Boxes))
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Errorf("%+v: %v", opts, err)
+		}
+		for _, space := range bin.FreeSpaces {
+			for _, box := range bin.Boxes {
+				if intersects(space, box, 0) {
+					t.Errorf("%+v: free space %+v overlaps box %s", opts, *space, box.Label())
+				}
+			}
+		}
+	}
+
+	t.Run("first fit", func(t *testing.T) {
+		bin := NewGridBin(10, 10, GridOptions{})
+		wants := [][2]float64{{0, 0}, {3, 0}, {6, 0}, {0, 3}}
+		for i, want := range wants {
+			box := NewBox(2.5, 3, true)
+			if !bin.Insert(box) {
+				t.Fatalf("box %d: got no fit", i)
+			}
+			if box.X != want[0] || box.Y != want[1] {
+				t.Errorf("box %d: got [%g,%g], want %v", i, box.X, box.Y, want)
+			}
+		}
+		want := []FreeSpaceBox{{X: 9, Width: 1, Height: 3}, {X: 3, Y: 3, Width: 7, Height: 3}, {Y: 6, Width: 10, Height: 4}}
+		got := freeSpacesOf(bin)
+		if len(got) != len(want) {
+			t.Fatalf("got free spaces %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("free space %d: got %v, want %v", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("resolution", func(t *testing.T) {
+		bin := NewGridBin(10, 10, GridOptions{Resolution: 4})
+		if bin.Insert(NewBox(9, 1, true)) {
+			t.Error("got a box placed on the partly covered edge cells")
+		}
+		for i := range 4 {
+			if !bin.Insert(NewBox(1, 1, true)) {
+				t.Fatalf("box %d: got no fit in a 2x2 grid", i)
+			}
+		}
+		if bin.Insert(NewBox(1, 1, true)) {
+			t.Error("got a fifth box placed in a 2x2 grid")
+		}
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		// Everything but an L-shaped region along the left and bottom edges is blocked.
+		mask := make([][]bool, 4)
+		for r := range mask {
+			mask[r] = []bool{false, r < 3, r < 3, r < 3}
+		}
+		bin := NewGridBin(4, 4, GridOptions{Mask: mask})
+		wide, tall := NewBox(4, 1, true), NewBox(1, 3, true)
+		if !bin.Insert(wide) || !bin.Insert(tall) {
+			t.Fatal("got no fit in the unmasked cells")
+		}
+		if wide.Y != 3 || tall.X != 0 || tall.Y != 0 {
+			t.Errorf("got [%g,%g] and [%g,%g], want [0,3] and [0,0]", wide.X, wide.Y, tall.X, tall.Y)
+		}
+		if bin.Insert(NewBox(1, 1, true)) {
+			t.Error("got a box placed on a masked cell")
+		}
+		bin.Clear()
+		if len(bin.FreeSpaces) != 2 {
+			t.Errorf("got %d free spaces after Clear, want the 2 of the unmasked region", len(bin.FreeSpaces))
+		}
+	})
+
+	t.Run("best fit", func(t *testing.T) {
+		bin := NewGridBin(10, 10, GridOptions{BestFit: true, Placement: BestAreaFit})
+		if err := bin.Place(NewBox(3, 10, true), 4, 0); err != nil {
+			t.Fatal(err)
+		}
+		box := NewBox(3, 3, true)
+		bin.Insert(box)
+		if box.X != 7 {
+			t.Errorf("got X %g, want 7 in the smaller free area", box.X)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin := NewGridBin(10, 10, GridOptions{Resolution: 2})
+		bin.Insert(NewBox(4, 4, true))
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		box := NewBox(4, 4, true)
+		restored.Insert(box)
+		if box.X != 4 || box.Y != 0 {
+			t.Errorf("got [%g,%g] in the restored bin, want [4,0]", box.X, box.Y)
+		}
+		clone := restored.Clone()
+		inClone, inBin := NewBox(2, 2, true), NewBox(2, 2, true)
+		clone.Insert(inClone)
+		restored.Insert(inBin)
+		if inBin.X != inClone.X || inBin.Y != inClone.Y {
+			t.Errorf("got [%g,%g] after packing the clone, want [%g,%g]", inBin.X, inBin.Y, inClone.X, inClone.Y)
+		}
+	})
+}
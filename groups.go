@@ -0,0 +1,160 @@
+package binpacking
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// boxGroup is the set of boxes sharing a Box.Group, in input order.
+type boxGroup struct {
+	boxes []*Box
+	area  float64
+}
+
+// packGroups places the boxes of each Box.Group together into a single bin, before the
+// ungrouped boxes are packed. Groups are taken largest total area first; each goes into
+// the bin it fills most tightly, or, if none of the packer's bins can hold it, into a new
+// bin opened from the cheapest (then smallest) template that can. A group that fits
+// nowhere, would join a box it must be kept apart from (options.KeepApart), or would
+// exceed options.Limit, is left unpacked as a whole.
+// It returns the packed boxes and the ungrouped boxes, which the caller packs normally.
+func (p *Packer) packGroups(boxesToPack []*Box, options PackerOptions) (packed, ungrouped []*Box) {
+	packed = make([]*Box, 0)
+	groups := make([]*boxGroup, 0)
+	byName := make(map[string]*boxGroup)
+	for _, box := range boxesToPack {
+		if box.Group == "" {
+			ungrouped = append(ungrouped, box)
+			continue
+		}
+		group := byName[box.Group]
+		if group == nil {
+			group = &boxGroup{}
+			byName[box.Group] = group
+			groups = append(groups, group)
+		}
+		group.boxes = append(group.boxes, box)
+		group.area += box.Area()
+	}
+	if len(groups) == 0 {
+		return packed, boxesToPack
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].area > groups[j].area })
+	apart := newSeparation(options.KeepApart, p.Bins)
+
+	for _, group := range groups {
+		if options.expired() {
+			break // The remaining groups are reported as unpacked
+		}
+		if options.Limit > 0 && int64(len(packed)+len(group.boxes)) > options.Limit {
+			continue
+		}
+		sort.SliceStable(group.boxes, func(i, j int) bool { return group.boxes[i].Area() > group.boxes[j].Area() })
+
+		var target, trial *Bin
+		var copies []*Box
+		bestFree := 0.0
+		for _, bin := range p.Bins {
+			if bin == nil || !apart.allowsTogether(bin, group.boxes) {
+				continue
+			}
+			if scratch, placed, ok := trialGroup(bin, group.boxes, options); ok {
+				if free := bin.Area() - scratch.usedArea(); target == nil || free < bestFree {
+					target, trial, copies, bestFree = bin, scratch, placed, free
+				}
+			}
+		}
+		if target == nil && len(options.BinTemplates) > 0 && (options.MaxBins <= 0 || len(p.Bins) < options.MaxBins) {
+			var best *BinTemplate
+			for i := range options.BinTemplates {
+				template := &options.BinTemplates[i]
+				if best != nil && (template.Cost > best.Cost || (template.Cost == best.Cost && template.Width*template.Height >= best.Width*best.Height)) {
+					continue
+				}
+				bin := template.NewBin()
+				if !apart.allowsTogether(bin, group.boxes) {
+					break // The group's own boxes conflict; no bin can hold it
+				}
+				if scratch, placed, ok := trialGroup(bin, group.boxes, options); ok {
+					best, target, trial, copies = template, bin, scratch, placed
+				}
+			}
+			if target != nil {
+				p.Bins = append(p.Bins, target)
+				p.events.open(target, len(p.Bins)-1)
+			}
+		}
+		if target == nil {
+			if p.debugging() {
+				p.Logger.Debug("group does not fit", slog.String("group", group.boxes[0].Group), slog.Int("boxes", len(group.boxes)))
+			}
+			continue // The group stays together, unpacked
+		}
+		target.adopt(trial, copies, group.boxes)
+		for _, box := range group.boxes {
+			apart.place(target, box)
+			p.events.place(target, box)
+		}
+		packed = append(packed, group.boxes...)
+	}
+	return packed, ungrouped
+}
+
+// trialGroup inserts copies of the boxes, in order, into a scratch copy of bin and
+// reports whether all of them fit (keeping the layout cuttable if options.Guillotine is
+// set), returning the scratch bin and the placed copies.
+func trialGroup(bin *Bin, boxes []*Box, options PackerOptions) (*Bin, []*Box, bool) {
+	scratch := bin.scratch()
+	copies := make([]*Box, len(boxes))
+	for i, box := range boxes {
+		copies[i] = trialBox(box)
+		if !scratch.InsertWith(copies[i], tagOptionsFor(options.TagOptions, box)) {
+			return nil, nil, false
+		}
+	}
+	if options.Guillotine && uncuttable(scratch.Boxes) != nil {
+		return nil, nil, false
+	}
+	return scratch, copies, true
+}
+
+// adopt makes b take over the state of trial, a scratch copy of b into which copies of
+// boxes were inserted, and moves the real boxes to the copies' positions. This commits
+// a trial packing exactly, even for strategies whose results depend on the real bin.
+func (b *Bin) adopt(trial *Bin, copies, boxes []*Box) {
+	for i, box := range boxes {
+		placed := copies[i]
+		if placed.Rotated != box.Rotated {
+			box.Rotate()
+		}
+		if placed.Mirrored != box.Mirrored {
+			box.Mirror()
+		}
+		box.X, box.Y, box.Packed = placed.X, placed.Y, true
+		if box.cluster != nil {
+			box.cluster.place()
+		}
+	}
+	b.FreeSpaces = trial.FreeSpaces
+	switch backend := b.Backend.(type) {
+	case *SkylineBackend:
+		*backend = *trial.Backend.(*SkylineBackend) // Keep the caller's backend pointer valid
+	case *GridBackend:
+		*backend = *trial.Backend.(*GridBackend)
+	}
+	b.compacted = trial.compacted
+	b.Boxes = append(b.Boxes, boxes...)
+	b.touch()
+	for _, box := range boxes {
+		b.checkPlaced(box)
+	}
+}
+
+// usedArea returns the total area of the boxes in the bin.
+func (b *Bin) usedArea() float64 {
+	used := 0.0
+	for _, box := range b.Boxes {
+		used += box.Area()
+	}
+	return used
+}
@@ -0,0 +1,69 @@
+package binpacking
+
+import "testing"
+
+func TestGroups(t *testing.T) {
+	grouped := func(group string, boxes ...*Box) []*Box {
+		for _, box := range boxes {
+			box.Group = group
+		}
+		return boxes
+	}
+	binOf := func(bins []*Bin, box *Box) *Bin {
+		for _, bin := range bins {
+			for _, placed := range bin.Boxes {
+				if placed == box {
+					return bin
+				}
+			}
+		}
+		return nil
+	}
+
+	t.Run("keeps a group in one bin", func(t *testing.T) {
+		// Each bin holds two of the boxes; without the group the three boxes of the
+		// order would be split.
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 15, nil)}
+		order := grouped("order-1", NewBox(10, 5, true), NewBox(10, 5, true), NewBox(10, 5, true))
+		packer := NewPacker(bins)
+		packed := packer.Pack(append(order, NewBox(10, 10, true)), PackerOptions{})
+		if len(packed) != 4 {
+			t.Fatalf("Packed: got %d, want %d", len(packed), 4)
+		}
+		for _, box := range order {
+			if binOf(bins, box) != bins[1] {
+				t.Errorf("Box %s is not in the group's bin", box.Label())
+			}
+		}
+	})
+
+	t.Run("leaves a group unpacked as a whole", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		order := grouped("order-1", NewBox(10, 5, true), NewBox(10, 5, true), NewBox(10, 5, true))
+		packer := NewPacker([]*Bin{bin})
+		packed := packer.Pack(append(order, NewBox(5, 5, true)), PackerOptions{})
+		if len(packed) != 1 || len(packer.UnpackedBoxes) != 3 {
+			t.Errorf("Packed: got %d packed/%d unpacked, want 1/3", len(packed), len(packer.UnpackedBoxes))
+		}
+		for _, box := range order {
+			if box.Packed {
+				t.Errorf("Box %s of the unpackable group was packed", box.Label())
+			}
+		}
+	})
+
+	t.Run("opens a template bin for a group", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		order := grouped("order-1", NewBox(10, 10, true), NewBox(10, 10, true))
+		packed := packer.Pack(order, PackerOptions{BinTemplates: []BinTemplate{
+			{Width: 10, Height: 30, Cost: 1},
+			{Width: 10, Height: 20, Cost: 1},
+		}})
+		if len(packed) != 2 || len(packer.Bins) != 2 {
+			t.Fatalf("Packed: got %d boxes in %d bins, want 2 in 2", len(packed), len(packer.Bins))
+		}
+		if got := packer.Bins[1].Height; got != 20 {
+			t.Errorf("Template: got height %v, want %v", got, 20)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package binpacking
+
+import "fmt"
+
+// GrowOptions configures PackGrowing. Zero values select the defaults noted on each field.
+type GrowOptions struct {
+	MinWidth  float64 // Starting width; default 1
+	MinHeight float64 // Starting height; default 1
+	MaxWidth  float64 // Largest width; zero or negative means no limit
+	MaxHeight float64 // Largest height; zero or negative means no limit
+	// PowerOfTwo rounds the starting size up to powers of two, so every size tried is one,
+	// as required by many GPU texture formats.
+	PowerOfTwo bool
+	// Square keeps width and height equal, starting from the larger of the minimums.
+	Square bool
+	// Placement is the strategy of the bin; nil uses BestShortSideFit.
+	Placement PlacementStrategyFunc
+}
+
+// GrowResult is the outcome of PackGrowing: the result of the final packing and the bin
+// size it settled on.
+type GrowResult struct {
+	*PackResult
+	Width  float64 // Final width of the bin, also Bins[0].Width
+	Height float64 // Final height of the bin, also Bins[0].Height
+}
+
+// PackGrowing packs all boxes into a single bin that starts at the minimum size and doubles
+// until everything fits, instead of leaving boxes unpacked. Each step doubles the shorter
+// side, or both sides for square bins; a side that reached its maximum stops growing, and
+// a square bin stops when either side does. Sizes
+// whose area is below the boxes' total area are skipped without packing. Trial packings use
+// copies of the boxes, so only the final packing touches them.
+//
+// Boxes that are nil, already packed or have invalid dimensions are handled like in
+// Packer.Pack; options.BinTemplates and options.MaxBins are ignored. If the boxes still do not fit at the maximum size, the returned error wraps
+// ErrNoBinSize.
+func PackGrowing(boxes []*Box, grow GrowOptions, options PackerOptions) (*GrowResult, error) {
+	width, height := max(grow.MinWidth, 1), max(grow.MinHeight, 1)
+	if grow.Square {
+		width = max(width, height)
+		height = width
+	}
+	if grow.PowerOfTwo {
+		width, height = nextPowerOfTwo(width), nextPowerOfTwo(height)
+	}
+	maxWidth, maxHeight := grow.MaxWidth, grow.MaxHeight
+	if maxWidth > 0 {
+		width = min(width, maxWidth)
+	}
+	if maxHeight > 0 {
+		height = min(height, maxHeight)
+	}
+
+	valid := make([]*Box, 0, len(boxes))
+	totalArea := 0.0
+	for _, box := range boxes {
+		if box != nil && !box.Packed && box.Validate() == nil {
+			valid = append(valid, box)
+			totalArea += box.Area()
+		}
+	}
+	fits := func(width, height float64) bool {
+		if width*height < totalArea {
+			return false
+		}
+		_, copies := trialCopies(nil, valid)
+		trial := options
+		trial.OnProgress, trial.Observer = nil, nil // Only the final packing is reported
+		packed := NewPacker([]*Bin{NewBin(width, height, grow.Placement)}).Pack(copies, trial)
+		return len(packed) == len(copies)
+	}
+
+	// canGrow reports whether a side may still grow; power-of-two sides never exceed
+	// the limit rather than being clamped to it.
+	canGrow := func(v, limit float64) bool {
+		if limit <= 0 {
+			return true
+		}
+		if grow.PowerOfTwo {
+			return 2*v <= limit
+		}
+		return v < limit
+	}
+	options.BinTemplates, options.MaxBins = nil, 0 // Everything goes into the one bin
+	for !fits(width, height) {
+		growWidth, growHeight := canGrow(width, maxWidth), canGrow(height, maxHeight)
+		switch {
+		case !growWidth && !growHeight, grow.Square && !(growWidth && growHeight):
+			return nil, fmt.Errorf("%w within %gx%g", ErrNoBinSize, width, height)
+		case grow.Square:
+			// Both sides grow together.
+		case growWidth && (width <= height || !growHeight):
+			growHeight = false
+		default:
+			growWidth = false
+		}
+		if growWidth {
+			width = doubleUpTo(width, maxWidth)
+		}
+		if growHeight {
+			height = doubleUpTo(height, maxHeight)
+		}
+	}
+
+	packer := NewPacker([]*Bin{NewBin(width, height, grow.Placement)})
+	packer.Pack(boxes, options)
+	return &GrowResult{PackResult: packer.Result(), Width: width, Height: height}, nil
+}
+
+// doubleUpTo doubles v, capped at limit when limit is positive.
+func doubleUpTo(v, limit float64) float64 {
+	if limit > 0 {
+		return min(2*v, limit)
+	}
+	return 2 * v
+}
@@ -0,0 +1,69 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPackGrowing(t *testing.T) {
+	sprites := func() []*Box {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 20; i++ {
+			boxes = append(boxes, NewBox(float64(3+i%7), float64(2+i%5), false))
+		}
+		return boxes
+	}
+
+	t.Run("grows power-of-two sides until everything fits", func(t *testing.T) {
+		boxes := sprites()
+		result, err := PackGrowing(boxes, GrowOptions{MinWidth: 3, MinHeight: 3, PowerOfTwo: true}, PackerOptions{})
+		if err != nil {
+			t.Fatalf("PackGrowing: %v", err)
+		}
+		if len(result.Unpacked) != 0 || len(result.Packed) != len(boxes) {
+			t.Errorf("Packed: got %d of %d boxes", len(result.Packed), len(boxes))
+		}
+		if result.Width != nextPowerOfTwo(result.Width) || result.Height != nextPowerOfTwo(result.Height) {
+			t.Errorf("Size: got %gx%g, want powers of two", result.Width, result.Height)
+		}
+		if result.Bins[0].Width != result.Width || result.Bins[0].Height != result.Height {
+			t.Errorf("Bin: got %gx%g, want %gx%g", result.Bins[0].Width, result.Bins[0].Height, result.Width, result.Height)
+		}
+		// The next smaller step must not have held everything.
+		smaller := GrowOptions{MinWidth: result.Width, MinHeight: result.Height / 2, MaxWidth: result.Width, MaxHeight: result.Height / 2, PowerOfTwo: true}
+		if result.Width > result.Height {
+			smaller = GrowOptions{MinWidth: result.Width / 2, MinHeight: result.Height, MaxWidth: result.Width / 2, MaxHeight: result.Height, PowerOfTwo: true}
+		}
+		if _, err := PackGrowing(sprites(), smaller, PackerOptions{}); err == nil {
+			t.Errorf("A smaller bin than %gx%g also fits", result.Width, result.Height)
+		}
+	})
+
+	t.Run("keeps square bins square", func(t *testing.T) {
+		result, err := PackGrowing(sprites(), GrowOptions{PowerOfTwo: true, Square: true}, PackerOptions{})
+		if err != nil {
+			t.Fatalf("PackGrowing: %v", err)
+		}
+		if result.Width != result.Height || result.Width != 32 {
+			t.Errorf("Size: got %gx%g, want %dx%d", result.Width, result.Height, 32, 32)
+		}
+	})
+
+	t.Run("leaves the boxes alone until the final packing", func(t *testing.T) {
+		box := NewBox(5, 1, true)
+		result, err := PackGrowing([]*Box{box, nil}, GrowOptions{MinWidth: 1, MinHeight: 1}, PackerOptions{})
+		if err != nil {
+			t.Fatalf("PackGrowing: %v", err)
+		}
+		if result.Width != 8 || result.Height != 4 || !box.Packed { // 1x1, 2x1, 2x2, 4x2, 4x4, 8x4
+			t.Errorf("got %gx%g with box %s, want 8x4 with the box packed", result.Width, result.Height, box.Label())
+		}
+	})
+
+	t.Run("reports when the maximum is too small", func(t *testing.T) {
+		_, err := PackGrowing(sprites(), GrowOptions{MaxWidth: 16, MaxHeight: 16, PowerOfTwo: true}, PackerOptions{})
+		if !errors.Is(err, ErrNoBinSize) {
+			t.Errorf("got %v, want %v", err, ErrNoBinSize)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package binpacking
+
+// GuillotineSplitRule decides how the leftover of a free rectangle is cut in two after
+// a box is placed in its top-left corner. Every rule produces guillotine-feasible layouts;
+// they differ in the shape of the leftover pieces.
+type GuillotineSplitRule int
+
+const (
+	// SplitShorterLeftoverAxis (SLAS) cuts along the axis with the shorter leftover.
+	SplitShorterLeftoverAxis GuillotineSplitRule = iota
+	// SplitLongerLeftoverAxis (LLAS) cuts along the axis with the longer leftover.
+	SplitLongerLeftoverAxis
+	// SplitShorterAxis (SAS) cuts along the shorter side of the free rectangle.
+	SplitShorterAxis
+	// SplitLongerAxis (LAS) cuts along the longer side of the free rectangle.
+	SplitLongerAxis
+	// SplitMinimizeArea (MINAS) makes the smaller of the two leftovers as small as possible.
+	SplitMinimizeArea
+	// SplitMaximizeArea (MAXAS) makes the larger of the two leftovers as large as possible.
+	SplitMaximizeArea
+)
+
+// GuillotineBackend keeps a list of disjoint free rectangles and splits only the one a box
+// is placed in, using a straight edge-to-edge cut. The resulting layouts can always be cut
+// with a panel saw.
+type GuillotineBackend struct {
+	SplitRule GuillotineSplitRule // Rule used to split the leftover of the chosen rectangle
+	Merge     bool                // Merge free rectangles that share a full edge after each placement
+}
+
+// NewGuillotineBin creates a bin that uses a GuillotineBackend with the given split rule.
+// A nil placement uses BestShortSideFit (the "choice" heuristic picking the free rectangle).
+func NewGuillotineBin(width, height float64, placement PlacementStrategyFunc, rule GuillotineSplitRule) *Bin {
+	bin := NewBin(width, height, placement)
+	bin.Backend = &GuillotineBackend{SplitRule: rule}
+	return bin
+}
+
+// FindPlacement implements Backend.
+func (g *GuillotineBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	return bin.findFree(fitSize{box.Width, box.Height, box.ConstrainRotation, bin.tolerance(), bin.TieBreak, bin.anchoring(box.Anchor, false), nil}, strategy)
+}
+
+// Place implements Backend.
+func (g *GuillotineBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	chosen := placement.ChosenSpace
+	newFreeSpaces := make([]*FreeSpaceBox, 0, len(bin.FreeSpaces)+1)
+	for _, space := range bin.FreeSpaces {
+		if space == chosen {
+			newFreeSpaces = append(newFreeSpaces, g.split(chosen, box, bin.tolerance())...)
+		} else {
+			newFreeSpaces = append(newFreeSpaces, space)
+		}
+	}
+	bin.FreeSpaces = newFreeSpaces
+	if g.Merge {
+		bin.mergeFreeList()
+	}
+}
+
+// split cuts the leftover of free around the placed box into at most two rectangles,
+// dropping those no wider or taller than tol.
+func (g *GuillotineBackend) split(free *FreeSpaceBox, box *Box, tol float64) []*FreeSpaceBox {
+	leftoverWidth := free.Width - box.Width
+	leftoverHeight := free.Height - box.Height
+
+	var horizontal bool // Cut across the full width of the free rectangle, below the box
+	switch g.SplitRule {
+	case SplitShorterLeftoverAxis:
+		horizontal = leftoverWidth <= leftoverHeight
+	case SplitLongerLeftoverAxis:
+		horizontal = leftoverWidth > leftoverHeight
+	case SplitShorterAxis:
+		horizontal = free.Width <= free.Height
+	case SplitLongerAxis:
+		horizontal = free.Width > free.Height
+	case SplitMinimizeArea:
+		horizontal = box.Width*leftoverHeight > leftoverWidth*box.Height
+	case SplitMaximizeArea:
+		horizontal = box.Width*leftoverHeight <= leftoverWidth*box.Height
+	}
+
+	below := &FreeSpaceBox{X: free.X, Y: box.Y + box.Height, Height: leftoverHeight}
+	right := &FreeSpaceBox{X: box.X + box.Width, Y: free.Y, Width: leftoverWidth}
+	if horizontal {
+		below.Width = free.Width
+		right.Height = box.Height
+	} else {
+		below.Width = box.Width
+		right.Height = free.Height
+	}
+
+	splits := make([]*FreeSpaceBox, 0, 2)
+	for _, s := range []*FreeSpaceBox{below, right} {
+		if s.Width > tol && s.Height > tol {
+			splits = append(splits, s)
+		}
+	}
+	return splits
+}
@@ -0,0 +1,46 @@
+package binpacking
+
+import "testing"
+
+func TestGuillotineBin(t *testing.T) {
+	rules := []GuillotineSplitRule{
+		SplitShorterLeftoverAxis, SplitLongerLeftoverAxis, SplitShorterAxis,
+		SplitLongerAxis, SplitMinimizeArea, SplitMaximizeArea,
+	}
+	for _, rule := range rules {
+		bin := NewGuillotineBin(100, 80, nil, rule)
+		boxes := make([]*Box, 0)
+		for i := 0; i < 25; i++ {
+			boxes = append(boxes, NewBox(float64(5+i*7%23), float64(4+i*5%17), false))
+		}
+		NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+
+		if len(bin.Boxes) == 0 {
+			t.Errorf("Rule %d: no boxes packed", rule)
+		}
+		// Free rectangles of a guillotine bin never overlap each other or a box.
+		for i, a := range bin.FreeSpaces {
+			for _, c := range bin.FreeSpaces[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("Rule %d: free spaces overlap: %+v and %+v", rule, *a, *c)
+				}
+			}
+			for _, box := range bin.Boxes {
+				if intersects(a, box, 0) {
+					t.Errorf("Rule %d: free space %+v overlaps box %s", rule, *a, box.Label())
+				}
+			}
+		}
+		// Free area plus used area must account for the whole bin.
+		total := 0.0
+		for _, space := range bin.FreeSpaces {
+			total += space.Width * space.Height
+		}
+		for _, box := range bin.Boxes {
+			total += box.Area()
+		}
+		if total != bin.Area() {
+			t.Errorf("Rule %d: free plus used area: got %g, want %g", rule, total, bin.Area())
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package binpacking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// JobHandler is an http.Handler that packs a Job posted as JSON and responds with the
+// JobResult as JSON. Errors are reported as {"error": "..."} with status 400 for invalid
+// jobs, 405 for methods other than POST, 413 for jobs exceeding the limits, 422 when a
+// required box could not be packed and 503 when packing exceeds the timeout. Zero values select the defaults noted on each field.
+//
+//	mux.Handle("POST /pack", &binpacking.JobHandler{Timeout: 5 * time.Second})
+type JobHandler struct {
+	MaxBodyBytes int64         // Largest accepted request body; default 1 MiB
+	MaxBoxes     int           // Most box instances per job, counting quantities; default 10000
+	MaxBins      int           // Most bins per job, including bins opened from unlimited stock; default 1000
+	MaxRestarts  int           // Most JobOptions.Restarts; default 16
+	Timeout      time.Duration // Longest time spent packing; default 10s
+}
+
+// ServeHTTP implements http.Handler.
+func (h *JobHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJobError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	maxBody := h.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = 1 << 20
+	}
+	maxBoxes := h.MaxBoxes
+	if maxBoxes <= 0 {
+		maxBoxes = 10000
+	}
+	maxBins := h.MaxBins
+	if maxBins <= 0 {
+		maxBins = 1000
+	}
+	maxRestarts := h.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = 16
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	var job Job
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&job); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJobError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", maxBody))
+			return
+		}
+		writeJobError(w, http.StatusBadRequest, fmt.Errorf("decoding job: %w", err))
+		return
+	}
+	if err := job.Validate(); err != nil {
+		writeJobError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch {
+	case job.BoxCount() > maxBoxes:
+		writeJobError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("job has %d boxes, more than the limit of %d", job.BoxCount(), maxBoxes))
+		return
+	case job.BinCount() > maxBins:
+		writeJobError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("job has %d bins, more than the limit of %d", job.BinCount(), maxBins))
+		return
+	case job.Options.Restarts > maxRestarts:
+		writeJobError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("job has %d restarts, more than the limit of %d", job.Options.Restarts, maxRestarts))
+		return
+	}
+	// Unlimited stock could otherwise open a bin per box.
+	if job.Options.MaxBins <= 0 || job.Options.MaxBins > maxBins {
+		job.Options.MaxBins = maxBins
+	}
+
+	// Packing is not interruptible, so it runs in its own goroutine and the response gives
+	// up at the deadline. The limits above bound the work left running in that case.
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	type packed struct {
+		result *JobResult
+		err    error
+	}
+	done := make(chan packed, 1)
+	go func() {
+		result, err := job.Pack()
+		if result == nil {
+			done <- packed{err: err} // Cannot happen: the job has been validated
+			return
+		}
+		done <- packed{NewJobResult(result), err}
+	}()
+	select {
+	case packed := <-done:
+		switch {
+		case errors.Is(packed.err, ErrRequired):
+			writeJobError(w, http.StatusUnprocessableEntity, packed.err)
+			return
+		case packed.result == nil:
+			writeJobError(w, http.StatusBadRequest, ErrInvalidJob)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(packed.result)
+	case <-ctx.Done():
+		writeJobError(w, http.StatusServiceUnavailable, fmt.Errorf("packing did not finish within %v", timeout))
+	}
+}
+
+// writeJobError writes err as a JSON error response with the given status.
+func writeJobError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,100 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobHandler(t *testing.T) {
+	post := func(h http.Handler, method, body string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, "/pack", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var decoded map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("Response is not JSON: %v\n%s", err, rec.Body.String())
+		}
+		return rec, decoded
+	}
+
+	t.Run("packs a job", func(t *testing.T) {
+		body := `{"bins": [{"width": 10, "height": 10, "qty": 1}],
+			"boxes": [{"width": 5, "height": 5, "qty": 4, "id": "a"}, {"width": 20, "height": 1, "id": "long"}],
+			"options": {"strategy": "baf", "backend": "guillotine"}}`
+		rec := httptest.NewRecorder()
+		(&JobHandler{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pack", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Status: got %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		if got, want := rec.Header().Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("Content-Type: got %v, want %v", got, want)
+		}
+		var result JobResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result.BinsUsed != 1 || result.Efficiency != 100 || len(result.Placements) != 5 {
+			t.Errorf("Result: got %+v", result)
+		}
+		if p := result.Placements[4]; p.ID != "long" || p.Packed {
+			t.Errorf("Unpacked placement: got %+v", p)
+		}
+	})
+
+	t.Run("caps unlimited stock", func(t *testing.T) {
+		body := `{"bins": [{"width": 10, "height": 10}], "boxes": [{"width": 10, "height": 10, "qty": 5}]}`
+		rec := httptest.NewRecorder()
+		(&JobHandler{MaxBins: 3}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pack", strings.NewReader(body)))
+		var result JobResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := result.BinsUsed, 3; got != want {
+			t.Errorf("Bins used: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		tests := map[string]struct {
+			handler *JobHandler
+			method  string
+			body    string
+			want    int
+		}{
+			"wrong method":      {&JobHandler{}, http.MethodGet, "", http.StatusMethodNotAllowed},
+			"malformed JSON":    {&JobHandler{}, http.MethodPost, "{", http.StatusBadRequest},
+			"unknown field":     {&JobHandler{}, http.MethodPost, `{"bin": []}`, http.StatusBadRequest},
+			"invalid size":      {&JobHandler{}, http.MethodPost, `{"boxes": [{"width": -1, "height": 1}]}`, http.StatusBadRequest},
+			"unknown backend":   {&JobHandler{}, http.MethodPost, `{"options": {"backend": "tree"}}`, http.StatusBadRequest},
+			"body too large":    {&JobHandler{MaxBodyBytes: 10}, http.MethodPost, `{"bins": [], "boxes": []}`, http.StatusRequestEntityTooLarge},
+			"too many boxes":    {&JobHandler{MaxBoxes: 2}, http.MethodPost, `{"boxes": [{"width": 1, "height": 1, "qty": 3}]}`, http.StatusRequestEntityTooLarge},
+			"too many bins":     {&JobHandler{MaxBins: 2}, http.MethodPost, `{"bins": [{"width": 1, "height": 1, "qty": 3}]}`, http.StatusRequestEntityTooLarge},
+			"too many restarts": {&JobHandler{}, http.MethodPost, `{"options": {"restarts": 100}}`, http.StatusRequestEntityTooLarge},
+		}
+		for name, tt := range tests {
+			rec, decoded := post(tt.handler, tt.method, tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("%s: got status %d, want %d", name, rec.Code, tt.want)
+			}
+			if msg, _ := decoded["error"].(string); msg == "" {
+				t.Errorf("%s: got no error message in %v", name, decoded)
+			}
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		var boxes []string
+		for i := 0; i < 2000; i++ {
+			boxes = append(boxes, `{"width": 1, "height": 1}`)
+		}
+		body := `{"bins": [{"width": 100, "height": 100, "qty": 1}], "boxes": [` + strings.Join(boxes, ",") + `]}`
+		rec, _ := post(&JobHandler{Timeout: time.Nanosecond}, http.MethodPost, body)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Status: got %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+}
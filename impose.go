@@ -0,0 +1,193 @@
+package binpacking
+
+import (
+	"fmt"
+	"io"
+)
+
+// ImpositionOptions configures Impose. All lengths are in the units of the sheet.
+type ImpositionOptions struct {
+	Gripper float64 // Unprintable strip along the top edge, where the press grips the sheet
+	Margin  float64 // Unprintable strip along the other edges
+	Bleed   float64 // Printed area around each page, beyond its trim
+	Gutter  float64 // Gap between the bleeds of neighbouring pages
+	// MarkLength is the length of the crop marks drawn at the corners of each page's trim;
+	// zero draws none.
+	MarkLength float64
+	// MarkOffset is the gap between a page's trim and its crop marks, so the marks stay
+	// clear of the printed area; zero uses Bleed.
+	MarkOffset float64
+	// Options configures the packing of each sheet; see Packer.Pack.
+	Options PackerOptions
+}
+
+// Imposition is the result of Impose: the press sheets, each holding the pages laid out
+// on it, and the pages that fit no sheet.
+type Imposition struct {
+	Sheets   []*ImposedSheet
+	Unplaced []*Box // Pages too large for the printable area of an empty sheet
+}
+
+// ImposedSheet is one press sheet of an Imposition.
+type ImposedSheet struct {
+	// Bin holds one box per page, the size of its bleed, with the gripper and margins as
+	// its Margins and the gutter as its Spacing.
+	Bin      *Bin
+	Pages    []ImposedPage
+	CutMarks []CutMark // Crop marks, each drawn once
+}
+
+// ImposedPage is a page placed on a sheet.
+type ImposedPage struct {
+	Page    *Box         // The page as given, of trim size; it is not modified
+	Trim    FreeSpaceBox // Where the page is cut out of the sheet
+	Bleed   FreeSpaceBox // The printed area, the trim grown by the bleed on every side
+	Rotated bool         // Whether the page was rotated onto the sheet
+}
+
+// CutMark is a crop mark, a line from (X1, Y1) to (X2, Y2) continuing one edge of a
+// page's trim.
+type CutMark struct {
+	X1, Y1, X2, Y2 float64
+}
+
+// Impose lays the pages out on press sheets of the given size, for print imposition: each
+// page, a box of its trim size, is printed with the bleed around it, and the bleeds are
+// packed into the printable area of the sheet, inside the gripper and margins, the gutter
+// apart. Sheets are filled one at a time, as many as the pages need. Crop marks continue
+// the trim's edges outwards from each corner; marks that would run into the bleed of
+// another page or off the sheet are left out.
+//
+// The pages keep their rotation settings. The returned error wraps ErrInvalidDimensions
+// if the sheet size or one of the lengths of options is invalid, or the gripper and
+// margins leave no room.
+func Impose(sheetWidth, sheetHeight float64, pages []*Box, options ImpositionOptions) (*Imposition, error) {
+	if err := ValidateDimensions(sheetWidth, sheetHeight); err != nil {
+		return nil, err
+	}
+	lengths := []float64{options.Gripper, options.Margin, options.Bleed, options.Gutter, options.MarkLength, options.MarkOffset}
+	for _, length := range lengths {
+		if err := ValidateDimensions(length, 0); err != nil {
+			return nil, fmt.Errorf("imposition: %w", err)
+		}
+	}
+	newSheet := func() (*Bin, error) {
+		// The spacing keeps the gutter from the margins too, which the margins make up for.
+		sheet := NewBin(sheetWidth, sheetHeight, nil)
+		margin := max(options.Margin-options.Gutter, 0)
+		margins := Margins{Top: max(options.Gripper-options.Gutter, 0), Right: margin, Bottom: margin, Left: margin}
+		if err := sheet.SetMargins(margins); err != nil {
+			return nil, err
+		}
+		return sheet, sheet.SetSpacing(options.Gutter)
+	}
+
+	bled := make([]*Box, len(pages))
+	pageOf := make(map[*Box]*Box, len(pages))
+	for i, page := range pages {
+		bled[i] = NewBox(page.Width+2*options.Bleed, page.Height+2*options.Bleed, page.ConstrainRotation)
+		bled[i].ID, bled[i].Data = page.ID, page.Data
+		pageOf[bled[i]] = page
+	}
+	sheet, err := newSheet() // Validates the margins even without pages
+	if err != nil {
+		return nil, err
+	}
+	imposition := &Imposition{Unplaced: make([]*Box, 0)}
+	for len(bled) > 0 {
+		if len(sheet.Boxes) > 0 {
+			sheet, _ = newSheet() // Succeeds like the first one
+		}
+		packer := NewPacker([]*Bin{sheet})
+		packer.Pack(bled, options.Options)
+		if len(sheet.Boxes) == 0 {
+			break // Nothing left fits an empty sheet
+		}
+		imposition.Sheets = append(imposition.Sheets, options.impose(sheet, pageOf))
+		bled = packer.UnpackedBoxes
+	}
+	for _, box := range bled {
+		imposition.Unplaced = append(imposition.Unplaced, pageOf[box])
+	}
+	return imposition, nil
+}
+
+// impose records the pages packed onto sheet and their crop marks.
+func (o ImpositionOptions) impose(sheet *Bin, pageOf map[*Box]*Box) *ImposedSheet {
+	imposed := &ImposedSheet{Bin: sheet, Pages: make([]ImposedPage, 0, len(sheet.Boxes)), CutMarks: make([]CutMark, 0)}
+	for _, box := range sheet.Boxes {
+		imposed.Pages = append(imposed.Pages, ImposedPage{
+			Page: pageOf[box],
+			Trim: FreeSpaceBox{
+				X: box.X + o.Bleed, Y: box.Y + o.Bleed,
+				Width: box.Width - 2*o.Bleed, Height: box.Height - 2*o.Bleed,
+			},
+			Bleed:   FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height},
+			Rotated: box.Rotated,
+		})
+	}
+	if o.MarkLength <= 0 {
+		return imposed
+	}
+	offset := o.MarkOffset
+	if offset <= 0 {
+		offset = o.Bleed
+	}
+	seen := make(map[CutMark]bool)
+	for _, page := range imposed.Pages {
+		t := page.Trim
+		for _, x := range []float64{t.X, t.X + t.Width} {
+			for _, y := range []float64{t.Y, t.Y + t.Height} {
+				// One mark continues the horizontal edge sideways, the other the vertical
+				// edge upwards or downwards, both away from the page.
+				dx, dy := -1.0, -1.0
+				if x > t.X {
+					dx = 1
+				}
+				if y > t.Y {
+					dy = 1
+				}
+				marks := []CutMark{
+					{x + dx*offset, y, x + dx*(offset+o.MarkLength), y},
+					{x, y + dy*offset, x, y + dy*(offset+o.MarkLength)},
+				}
+				for _, mark := range marks {
+					if !seen[mark] && imposed.clear(mark) {
+						seen[mark] = true
+						imposed.CutMarks = append(imposed.CutMarks, mark)
+					}
+				}
+			}
+		}
+	}
+	return imposed
+}
+
+// clear reports whether the crop mark lies on the sheet and outside the bleed of every
+// page.
+func (s *ImposedSheet) clear(mark CutMark) bool {
+	x0, x1 := min(mark.X1, mark.X2), max(mark.X1, mark.X2)
+	y0, y1 := min(mark.Y1, mark.Y2), max(mark.Y1, mark.Y2)
+	if x0 < 0 || y0 < 0 || x1 > s.Bin.Width || y1 > s.Bin.Height {
+		return false
+	}
+	for _, page := range s.Pages {
+		b := page.Bleed
+		if x0 < b.X+b.Width && x1 > b.X && y0 < b.Y+b.Height && y1 > b.Y {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteSVG writes the sheets to w as with WriteSVG, each page drawn as its bleed and
+// the crop marks as black lines.
+func (im *Imposition) WriteSVG(w io.Writer, options SVGOptions) error {
+	bins := make([]*Bin, len(im.Sheets))
+	options.Lines = make([][]CutMark, len(im.Sheets))
+	for i, sheet := range im.Sheets {
+		bins[i] = sheet.Bin
+		options.Lines[i] = sheet.CutMarks
+	}
+	return WriteSVG(w, bins, options)
+}
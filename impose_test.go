@@ -0,0 +1,108 @@
+package binpacking
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImpose(t *testing.T) {
+	t.Run("layout", func(t *testing.T) {
+		pages := make([]*Box, 12)
+		for i := range pages {
+			pages[i] = NewBox(20, 30, false)
+		}
+		options := ImpositionOptions{Gripper: 10, Margin: 5, Bleed: 3, Gutter: 4, MarkLength: 5}
+		imposition, err := Impose(100, 70, pages, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		placed := 0
+		for i, sheet := range imposition.Sheets {
+			for j, page := range sheet.Pages {
+				b, tr := page.Bleed, page.Trim
+				if b.X < 5 || b.Y < 10 || b.X+b.Width > 95 || b.Y+b.Height > 65 {
+					t.Errorf("sheet %d page %d: bleed %+v outside the printable area", i, j, b)
+				}
+				if tr.X != b.X+3 || tr.Y != b.Y+3 || tr.Width != b.Width-6 || tr.Height != b.Height-6 {
+					t.Errorf("sheet %d page %d: got trim %+v for bleed %+v", i, j, tr, b)
+				}
+				for _, other := range sheet.Pages[j+1:] {
+					o := other.Bleed
+					gapX := max(o.X-(b.X+b.Width), b.X-(o.X+o.Width))
+					gapY := max(o.Y-(b.Y+b.Height), b.Y-(o.Y+o.Height))
+					if max(gapX, gapY) < 4-1e-9 {
+						t.Errorf("sheet %d: bleeds %+v and %+v are closer than the gutter", i, b, o)
+					}
+				}
+			}
+			for _, mark := range sheet.CutMarks {
+				if !sheet.clear(mark) {
+					t.Errorf("sheet %d: mark %+v crosses a bleed or leaves the sheet", i, mark)
+				}
+			}
+			placed += len(sheet.Pages)
+		}
+		if placed != len(pages) || len(imposition.Sheets) < 2 {
+			t.Errorf("got %d pages on %d sheets, want all %d on several", placed, len(imposition.Sheets), len(pages))
+		}
+		if slices.ContainsFunc(pages, func(page *Box) bool { return page.Packed }) {
+			t.Error("got pages modified")
+		}
+	})
+
+	t.Run("cut marks", func(t *testing.T) {
+		options := ImpositionOptions{Gripper: 10, Margin: 5, Bleed: 3, MarkLength: 5}
+		imposition, err := Impose(100, 100, []*Box{NewBox(20, 20, true)}, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet := imposition.Sheets[0]
+		if got := sheet.Pages[0].Trim; got != (FreeSpaceBox{X: 8, Y: 13, Width: 20, Height: 20}) {
+			t.Errorf("got trim %+v, want 20x20 at [8,13]", got)
+		}
+		if len(sheet.CutMarks) != 8 {
+			t.Fatalf("got %d marks, want 8", len(sheet.CutMarks))
+		}
+		for _, want := range []CutMark{{5, 13, 0, 13}, {8, 10, 8, 5}, {31, 33, 36, 33}, {28, 36, 28, 41}} {
+			if !slices.Contains(sheet.CutMarks, want) {
+				t.Errorf("got marks %v, want %v among them", sheet.CutMarks, want)
+			}
+		}
+	})
+
+	t.Run("unplaced", func(t *testing.T) {
+		large := NewBox(95, 20, true)
+		imposition, err := Impose(100, 100, []*Box{large, NewBox(10, 10, true)}, ImpositionOptions{Margin: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(imposition.Sheets) != 1 || !slices.Equal(imposition.Unplaced, []*Box{large}) {
+			t.Errorf("got %d sheets and unplaced %v, want 1 sheet and the large page", len(imposition.Sheets), imposition.Unplaced)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, options := range []ImpositionOptions{{Bleed: -1}, {Gripper: 60, Margin: 50}} {
+			if _, err := Impose(100, 100, nil, options); !errors.Is(err, ErrInvalidDimensions) {
+				t.Errorf("%+v: got %v, want ErrInvalidDimensions", options, err)
+			}
+		}
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		imposition, err := Impose(100, 100, []*Box{NewBox(20, 20, true)}, ImpositionOptions{Gripper: 10, Margin: 10, Bleed: 3, MarkLength: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := imposition.WriteSVG(&buf, SVGOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(buf.String(), "<line "); got != 8 {
+			t.Errorf("got %d lines, want the 8 crop marks", got)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRecalculateBinIncremental(t *testing.T) {
+	boxes := func(seed uint64, n int) []*Box {
+		rng := rand.New(rand.NewPCG(seed, 3))
+		boxes := make([]*Box, n)
+		for i := range boxes {
+			boxes[i] = &Box{Width: float64(1 + rng.IntN(12)), Height: float64(1 + rng.IntN(12)), Weight: float64(rng.IntN(4))}
+		}
+		return boxes
+	}
+	// run packs like the packer does, checking after every placement that each entry has
+	// the score a full recalculation gives.
+	run := func(t *testing.T, board *ScoreBoard) {
+		t.Helper()
+		for step := 0; ; step++ {
+			for _, entry := range board.Entries {
+				if got, want := entry.Score, entry.Bin.ScoreForWith(entry.Box, entry.Options); got != want {
+					t.Fatalf("step %d: box %vx%v in bin %p: got %v, want %v", step, entry.Box.Width, entry.Box.Height, entry.Bin, got, want)
+				}
+			}
+			best := board.BestFit()
+			if best == nil {
+				return
+			}
+			if !best.Bin.InsertWith(best.Box, best.Options) {
+				t.Fatalf("step %d: best entry does not fit", step)
+			}
+			board.RemoveBox(best.Box)
+			board.RecalculateBin(best.Bin)
+		}
+	}
+
+	t.Run("strategies", func(t *testing.T) {
+		bins := []*Bin{NewBin(40, 40, BestShortSideFit), NewBin(40, 30, BestLongSideFit), NewBin(30, 30, BestAreaFit), NewBin(35, 25, BottomLeft)}
+		contact := NewBin(30, 30, nil)
+		contact.Placement = ContactPointFit(contact)
+		run(t, NewScoreBoard(append(bins, contact), boxes(1, 80)))
+	})
+
+	t.Run("tag options", func(t *testing.T) {
+		bins := []*Bin{NewBin(40, 40, nil), NewBin(30, 30, BestAreaFit)}
+		list := boxes(2, 60)
+		for i, box := range list {
+			box.Tag = []string{"", "grain", "area"}[i%3]
+		}
+		tags := map[string]TagOptions{"grain": {ConstrainRotation: true}, "area": {Placement: BottomLeft}}
+		run(t, NewScoreBoardWithTags(bins, list, tags))
+	})
+
+	t.Run("weight and spacing", func(t *testing.T) {
+		heavy := NewBin(40, 40, nil)
+		heavy.MaxWeight = 30
+		spaced := NewBin(40, 40, BestAreaFit)
+		if err := spaced.SetSpacing(1); err != nil {
+			t.Fatal(err)
+		}
+		run(t, NewScoreBoard([]*Bin{heavy, spaced}, boxes(3, 60)))
+	})
+
+	t.Run("compacted and other backends", func(t *testing.T) {
+		compact := NewBin(40, 40, nil)
+		compact.MaxFreeSpaces = 4
+		bins := []*Bin{compact, NewGuillotineBin(30, 30, nil, SplitShorterLeftoverAxis), NewSkylineBin(30, 30, SkylineOptions{WasteMap: true})}
+		run(t, NewScoreBoard(bins, boxes(4, 60)))
+	})
+
+	t.Run("score assigned directly", func(t *testing.T) {
+		bin := NewBin(40, 40, nil)
+		board := NewScoreBoard([]*Bin{bin}, boxes(5, 20))
+		vetoed := board.Entries[0]
+		vetoed.Score = NoFit
+		bin.Insert(board.Entries[1].Box)
+		board.RemoveBox(board.Entries[1].Box)
+		board.RecalculateBin(bin)
+		if got, want := vetoed.Score, bin.ScoreFor(vetoed.Box); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		run(t, board)
+	})
+
+	t.Run("entries changed directly", func(t *testing.T) {
+		bin := NewBin(40, 40, nil)
+		list := boxes(6, 30)
+		board := NewScoreBoard([]*Bin{bin}, list[:20])
+		for _, box := range list[20:] {
+			entry := NewScoreBoardEntry(bin, box)
+			entry.Calculate()
+			board.Entries = append(board.Entries, entry)
+		}
+		run(t, board)
+	})
+}
@@ -0,0 +1,66 @@
+package binpacking
+
+import "iter"
+
+// Placements yields where each box of the last call to Pack ended up, like
+// PackResult.Placements but without building the slice or the summary statistics: the
+// packed boxes in placement order, followed by the unpacked ones. Each record is taken
+// when it is yielded.
+func (p *Packer) Placements() iter.Seq[BoxPlacement] {
+	return func(yield func(BoxPlacement) bool) {
+		binIndex := make(map[*Box]int)
+		for i, bin := range p.Bins {
+			if bin == nil {
+				continue
+			}
+			for _, box := range bin.Boxes {
+				binIndex[box] = i
+			}
+		}
+		for _, box := range p.lastPacked {
+			if !yield(p.packedPlacement(box, binIndex)) {
+				return
+			}
+		}
+		for _, box := range p.UnpackedBoxes {
+			if box != nil && !yield(unpackedPlacement(box)) {
+				return
+			}
+		}
+	}
+}
+
+// EachBin yields the index and bin of each of the packer's bins, skipping nil bins.
+func (p *Packer) EachBin() iter.Seq2[int, *Bin] {
+	return func(yield func(int, *Bin) bool) {
+		for i, bin := range p.Bins {
+			if bin != nil && !yield(i, bin) {
+				return
+			}
+		}
+	}
+}
+
+// EachBox yields the index and box of each box in the bin, in placement order, without
+// exposing the Boxes slice. Boxes placed while iterating are not yielded.
+func (b *Bin) EachBox() iter.Seq2[int, *Box] {
+	return func(yield func(int, *Box) bool) {
+		for i, box := range b.Boxes {
+			if !yield(i, box) {
+				return
+			}
+		}
+	}
+}
+
+// EachEntry yields the scoreboard's entries, skipping nil ones. The scoreboard must not
+// be modified while iterating.
+func (sb *ScoreBoard) EachEntry() iter.Seq[*ScoreBoardEntry] {
+	return func(yield func(*ScoreBoardEntry) bool) {
+		for _, entry := range sb.Entries {
+			if entry != nil && !yield(entry) {
+				return
+			}
+		}
+	}
+}
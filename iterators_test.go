@@ -0,0 +1,61 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	packer := NewPacker([]*Bin{NewBin(10, 10, nil), nil, NewBin(10, 10, nil)})
+	boxes := []*Box{NewBox(10, 10, false), NewBox(6, 6, false), NewBox(20, 20, false)}
+	packer.Pack(boxes, PackerOptions{})
+
+	t.Run("Placements", func(t *testing.T) {
+		got := slices.Collect(packer.Placements())
+		if want := packer.Result().Placements; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		for placement := range packer.Placements() {
+			if placement.Box != boxes[0] {
+				t.Errorf("got %v first, want the first box", placement.Box)
+			}
+			break
+		}
+	})
+
+	t.Run("EachBin", func(t *testing.T) {
+		var indexes []int
+		for i, bin := range packer.EachBin() {
+			if bin != packer.Bins[i] {
+				t.Errorf("bin %d: got another bin", i)
+			}
+			indexes = append(indexes, i)
+		}
+		if want := []int{0, 2}; !slices.Equal(indexes, want) {
+			t.Errorf("got %v, want %v", indexes, want)
+		}
+	})
+
+	t.Run("EachBox", func(t *testing.T) {
+		var got []*Box
+		for _, bin := range packer.EachBin() {
+			for i, box := range bin.EachBox() {
+				if bin.Boxes[i] != box {
+					t.Errorf("box %d: got another box", i)
+				}
+				got = append(got, box)
+			}
+		}
+		if want := boxes[:2]; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("EachEntry", func(t *testing.T) {
+		board := NewScoreBoard([]*Bin{NewBin(10, 10, nil)}, []*Box{NewBox(2, 2, false), NewBox(3, 3, false)})
+		board.Entries = append(board.Entries, nil)
+		if got := slices.Collect(board.EachEntry()); !slices.Equal(got, board.Entries[:2]) {
+			t.Errorf("got %v, want the two entries", got)
+		}
+	})
+}
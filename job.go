@@ -0,0 +1,375 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidJob is returned when a Job cannot be run, e.g. because it names an unknown
+// strategy or has a negative quantity.
+var ErrInvalidJob = errors.New("binpacking: invalid job")
+
+// Job is a self-contained packing job that can be decoded from JSON, as accepted by
+// JobHandler and the binpack command:
+//
+//	{
+//	  "bins":    [{"width": 2440, "height": 1220, "qty": 4, "cost": 30, "spacing": 3}],
+//	  "boxes":   [{"width": 600, "height": 400, "qty": 6, "id": "shelf", "rotatable": false}],
+//	  "options": {"strategy": "baf", "backend": "guillotine", "restarts": 8}
+//	}
+type Job struct {
+	Bins    []JobBin   `json:"bins"`
+	Boxes   []JobBox   `json:"boxes"`
+	Options JobOptions `json:"options"`
+}
+
+// JobBin is a stock sheet of a Job. Qty bins are created; a zero quantity means the stock
+// is available in any amount and is used as a BinTemplate, which always has the MaxRects
+// backend.
+type JobBin struct {
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+	Qty       int     `json:"qty"`
+	Cost      float64 `json:"cost"`
+	Spacing   float64 `json:"spacing"`
+	MaxWeight float64 `json:"maxWeight"`
+	Name      string  `json:"name,omitempty"` // Stock item, e.g. a SKU; see Bin.Name
+	Material  string  `json:"material,omitempty"`
+	Thickness float64 `json:"thickness,omitempty"`
+}
+
+// JobBox is a line of the cut list of a Job. Qty defaults to 1 and Rotatable to true.
+type JobBox struct {
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+	Qty       *int    `json:"qty,omitempty"`
+	ID        string  `json:"id,omitempty"`
+	Rotatable *bool   `json:"rotatable,omitempty"`
+	Tag       string  `json:"tag,omitempty"`
+	Group     string  `json:"group,omitempty"`
+	Value     float64 `json:"value,omitempty"`
+	Weight    float64 `json:"weight,omitempty"`
+	Priority  int     `json:"priority,omitempty"`
+	Required  bool    `json:"required,omitempty"`
+	Anchor    string  `json:"anchor,omitempty"`   // Comma-separated left, top, right, bottom, edge or corner
+	Radius    float64 `json:"radius,omitempty"`   // Makes the box a circle; width and height are ignored
+	Mirror    bool    `json:"mirror,omitempty"`   // Lets a box that may not rotate be turned over instead
+	Order     string  `json:"order,omitempty"`    // Order the box belongs to; see JobOptions.Orders
+	Material  string  `json:"material,omitempty"` // Material of the bins that may take the box; see Box.Material
+}
+
+// size returns the width and height of the box, the diameter for a circle.
+func (b JobBox) size() (float64, float64) {
+	if b.Radius != 0 {
+		return 2 * b.Radius, 2 * b.Radius
+	}
+	return b.Width, b.Height
+}
+
+// JobOptions selects the algorithms of a Job by name. Empty names select the defaults.
+type JobOptions struct {
+	Strategy   string `json:"strategy"`   // bssf (default), blsf, baf, bl, contact, center, spread or auto
+	Backend    string `json:"backend"`    // maxrects (default), guillotine, skyline, blf, grid or circle
+	Algorithm  string `json:"algorithm"`  // bestfit (default), nfdh or ffdh
+	Objective  string `json:"objective"`  // bestfit (default), maxvalue, prefervalue or mincost
+	Sort       string `json:"sort"`       // none (default), area, longest, perimeter or width
+	BinOrder   string `json:"binOrder"`   // global (default), fill or roundrobin
+	Restarts   int    `json:"restarts"`   // See PackerOptions.Restarts
+	Seed       uint64 `json:"seed"`       // See PackerOptions.Seed
+	Guillotine bool   `json:"guillotine"` // See PackerOptions.Guillotine
+	MaxBins    int    `json:"maxBins"`    // See PackerOptions.MaxBins
+	Orders     string `json:"orders"`     // minimize (default) or nosplit; see Packer.PackOrders
+
+	Deterministic bool `json:"deterministic"` // See PackerOptions.Deterministic
+}
+
+// JobResult is the JSON form of a PackResult, as returned by JobHandler.
+type JobResult struct {
+	BinsUsed     int            `json:"binsUsed"`
+	PackedArea   float64        `json:"packedArea"`
+	UnpackedArea float64        `json:"unpackedArea"`
+	Efficiency   float64        `json:"efficiency"`
+	LowerBound   int            `json:"lowerBound"`
+	Gap          float64        `json:"gap"`
+	Bins         []JobBinResult `json:"bins"`
+	Placements   []JobPlacement `json:"placements"`
+	Orders       []JobOrder     `json:"orders,omitempty"`
+}
+
+// JobOrder mirrors an OrderResult, with the bins numbered from 1.
+type JobOrder struct {
+	ID       string `json:"id"`
+	Bins     []int  `json:"bins"`
+	Packed   int    `json:"packed"`
+	Unpacked int    `json:"unpacked"`
+}
+
+// JobBinResult summarizes one bin of a JobResult.
+type JobBinResult struct {
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Boxes      int     `json:"boxes"`
+	Efficiency float64 `json:"efficiency"`
+	Name       string  `json:"name,omitempty"`
+	Material   string  `json:"material,omitempty"`
+	Thickness  float64 `json:"thickness,omitempty"`
+}
+
+// JobPlacement mirrors a row of WritePlacementsCSV: Bin is numbered from 1 and omitted,
+// like the position, when the box is unpacked.
+type JobPlacement struct {
+	ID       string  `json:"id,omitempty"`
+	Order    string  `json:"order,omitempty"`
+	Packed   bool    `json:"packed"`
+	Bin      int     `json:"bin,omitempty"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Rotated  bool    `json:"rotated"`
+	Mirrored bool    `json:"mirrored,omitempty"`
+	Radius   float64 `json:"radius,omitempty"` // Of a circle, whose center is at x+radius, y+radius
+}
+
+var jobStrategies = map[string]PlacementStrategyFunc{
+	"": BestShortSideFit, "bssf": BestShortSideFit, "blsf": BestLongSideFit,
+	"baf": BestAreaFit, "bl": BottomLeft, "contact": nil, "center": CenterFit(nil), "spread": SpreadFit(nil), "auto": nil,
+}
+
+var jobAlgorithms = map[string]PackingAlgorithm{
+	"": AlgorithmBestFit, "bestfit": AlgorithmBestFit,
+	"nfdh": AlgorithmShelfNextFit, "ffdh": AlgorithmShelfFirstFit,
+}
+
+var jobSorts = map[string]BoxOrder{
+	"": SortNone, "none": SortNone, "area": SortAreaDesc, "longest": SortLongestSideDesc,
+	"perimeter": SortPerimeterDesc, "width": SortWidthDesc,
+}
+
+var jobBinOrders = map[string]BinOrder{
+	"": BestGlobalFit, "global": BestGlobalFit, "fill": FillFirstBinFully, "roundrobin": RoundRobin,
+}
+
+var jobObjectives = map[string]Objective{
+	"": ObjectiveBestFit, "bestfit": ObjectiveBestFit, "maxvalue": ObjectiveMaxValue,
+	"prefervalue": ObjectivePreferValue, "mincost": ObjectiveMinCost,
+}
+
+// Validate checks the job's sizes, quantities and option names without packing it.
+// The returned error wraps ErrInvalidJob or ErrInvalidDimensions.
+func (j *Job) Validate() error {
+	for i, bin := range j.Bins {
+		if err := ValidateDimensions(bin.Width, bin.Height); err != nil {
+			return fmt.Errorf("bin %d: %w", i+1, err)
+		}
+		if err := ValidateDimensions(bin.Spacing, 0); err != nil {
+			return fmt.Errorf("bin %d spacing: %w", i+1, err)
+		}
+		if bin.Qty < 0 {
+			return fmt.Errorf("%w: bin %d: negative quantity %d", ErrInvalidJob, i+1, bin.Qty)
+		}
+	}
+	for i, box := range j.Boxes {
+		if err := ValidateDimensions(box.size()); err != nil {
+			return fmt.Errorf("box %d: %w", i+1, err)
+		}
+		if _, err := parseAnchor(box.Anchor); err != nil {
+			return fmt.Errorf("box %d: %w", i+1, err)
+		}
+		if box.Qty != nil && *box.Qty < 0 {
+			return fmt.Errorf("%w: box %d: negative quantity %d", ErrInvalidJob, i+1, *box.Qty)
+		}
+	}
+	o := j.Options
+	if _, ok := jobStrategies[o.Strategy]; !ok {
+		return fmt.Errorf("%w: unknown strategy %q", ErrInvalidJob, o.Strategy)
+	}
+	switch o.Backend {
+	case "", "maxrects", "guillotine", "skyline", "blf", "grid", "circle":
+	default:
+		return fmt.Errorf("%w: unknown backend %q", ErrInvalidJob, o.Backend)
+	}
+	if _, ok := jobAlgorithms[o.Algorithm]; !ok {
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidJob, o.Algorithm)
+	}
+	if _, ok := jobObjectives[o.Objective]; !ok {
+		return fmt.Errorf("%w: unknown objective %q", ErrInvalidJob, o.Objective)
+	}
+	if _, ok := jobSorts[o.Sort]; !ok {
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidJob, o.Sort)
+	}
+	if _, ok := jobBinOrders[o.BinOrder]; !ok {
+		return fmt.Errorf("%w: unknown bin order %q", ErrInvalidJob, o.BinOrder)
+	}
+	switch o.Orders {
+	case "", "minimize", "nosplit":
+	default:
+		return fmt.Errorf("%w: unknown order mode %q", ErrInvalidJob, o.Orders)
+	}
+	return nil
+}
+
+// BoxCount returns the number of box instances the job packs.
+func (j *Job) BoxCount() int {
+	count := 0
+	for _, box := range j.Boxes {
+		if box.Qty == nil {
+			count++
+		} else {
+			count += max(*box.Qty, 0)
+		}
+	}
+	return count
+}
+
+// BinCount returns the number of bins the job creates up front, not counting bins
+// opened from unlimited stock.
+func (j *Job) BinCount() int {
+	count := 0
+	for _, bin := range j.Bins {
+		count += max(bin.Qty, 0)
+	}
+	return count
+}
+
+// Pack validates the job, creates its bins and packs its boxes, returning the packer's
+// result. The returned error wraps ErrInvalidJob or ErrInvalidDimensions, with a nil
+// result, or ErrRequired if required boxes were left unpacked, as with
+// Packer.PackRequired, together with the result.
+func (j *Job) Pack() (*PackResult, error) {
+	if err := j.Validate(); err != nil {
+		return nil, err
+	}
+	o := j.Options
+	options := PackerOptions{
+		Algorithm: jobAlgorithms[o.Algorithm], Objective: jobObjectives[o.Objective], SortBy: jobSorts[o.Sort],
+		BinOrder: jobBinOrders[o.BinOrder],
+		Restarts: o.Restarts, Seed: o.Seed, Guillotine: o.Guillotine, MaxBins: o.MaxBins,
+		Deterministic: o.Deterministic, AutoPlacement: o.Strategy == "auto",
+	}
+	bins := make([]*Bin, 0, j.BinCount())
+	for _, stock := range j.Bins {
+		template := BinTemplate{
+			Width: stock.Width, Height: stock.Height, Cost: stock.Cost, MaxWeight: stock.MaxWeight, Spacing: stock.Spacing,
+			Name: stock.Name, Material: stock.Material, Thickness: stock.Thickness,
+		}
+		if stock.Qty == 0 {
+			template.Placement = jobStrategies[o.Strategy] // Nil for contact, which needs a bin, and auto; templates bind CenterFit and SpreadFit
+			options.BinTemplates = append(options.BinTemplates, template)
+			continue
+		}
+		for n := 0; n < stock.Qty; n++ {
+			bin, err := j.newBin(template)
+			if err != nil {
+				return nil, err
+			}
+			bins = append(bins, bin)
+		}
+	}
+	specs := make([]BoxSpec, 0, len(j.Boxes))
+	for _, box := range j.Boxes {
+		anchor, _ := parseAnchor(box.Anchor) // Validated above
+		qty := 1
+		if box.Qty != nil {
+			qty = *box.Qty
+		}
+		width, height := box.size()
+		specs = append(specs, BoxSpec{Count: qty, Box: Box{
+			Width: width, Height: height, ConstrainRotation: box.Rotatable != nil && !*box.Rotatable, Round: box.Radius != 0,
+			AllowMirror: box.Mirror,
+			ID:          box.ID, Tag: box.Tag, Group: box.Group, Value: box.Value, Weight: box.Weight,
+			Priority: box.Priority, Required: box.Required, Anchor: anchor, OrderID: box.Order, Material: box.Material,
+		}})
+	}
+	packer := NewPacker(bins)
+	packer.PackOrders(j.orders(specs), options)
+	return packer.Result(), packer.requiredError(options)
+}
+
+// orders expands the specs and collects the instances into orders by their OrderID, in
+// the order the orders first appear, the boxes without one into an order with no ID.
+func (j *Job) orders(specs []BoxSpec) []*Order {
+	orders := make([]*Order, 0)
+	byID := make(map[string]*Order)
+	for _, box := range ExpandSpecs(specs) {
+		order := byID[box.OrderID]
+		if order == nil {
+			order = &Order{ID: box.OrderID, NoSplit: j.Options.Orders == "nosplit"}
+			byID[box.OrderID] = order
+			orders = append(orders, order)
+		}
+		order.Boxes = append(order.Boxes, box)
+	}
+	return orders
+}
+
+// newBin creates a bin of the template's size and settings with the job's backend and
+// strategy.
+func (j *Job) newBin(t BinTemplate) (*Bin, error) {
+	placement := jobStrategies[j.Options.Strategy]
+	var bin *Bin
+	switch j.Options.Backend {
+	case "guillotine":
+		bin = NewGuillotineBin(t.Width, t.Height, placement, SplitShorterLeftoverAxis)
+	case "skyline":
+		bin = NewSkylineBin(t.Width, t.Height, SkylineOptions{WasteMap: true, Placement: placement})
+	case "blf":
+		bin = NewBottomLeftFillBin(t.Width, t.Height)
+	case "circle":
+		bin = NewCircleBin(t.Width, t.Height)
+	case "grid":
+		bin = NewGridBin(t.Width, t.Height, GridOptions{Placement: placement})
+	default:
+		bin = NewBin(t.Width, t.Height, placement)
+	}
+	switch j.Options.Strategy {
+	case "contact":
+		bin.Placement = ContactPointFit(bin)
+	case "center":
+		bin.Placement = CenterFit(bin)
+	case "spread":
+		bin.Placement = SpreadFit(bin)
+	}
+	bin.Cost, bin.MaxWeight = t.Cost, t.MaxWeight
+	bin.Name, bin.Material, bin.Thickness = t.Name, t.Material, t.Thickness
+	if err := bin.SetSpacing(t.Spacing); err != nil {
+		return nil, err
+	}
+	return bin, nil
+}
+
+// NewJobResult converts a PackResult to its JSON form.
+func NewJobResult(result *PackResult) *JobResult {
+	r := &JobResult{
+		BinsUsed: result.BinsUsed, PackedArea: result.PackedArea,
+		UnpackedArea: result.UnpackedArea, Efficiency: result.Efficiency,
+		LowerBound: result.LowerBound, Gap: result.Gap,
+		Bins:       make([]JobBinResult, 0, len(result.Bins)),
+		Placements: make([]JobPlacement, 0, len(result.Placements)),
+	}
+	for _, bin := range result.Bins {
+		if bin == nil {
+			r.Bins = append(r.Bins, JobBinResult{}) // Keeps the bin numbers of the placements
+			continue
+		}
+		r.Bins = append(r.Bins, JobBinResult{
+			Width: bin.Width, Height: bin.Height, Boxes: len(bin.Boxes), Efficiency: bin.Efficiency(),
+			Name: bin.Name, Material: bin.Material, Thickness: bin.Thickness,
+		})
+	}
+	for _, p := range result.Placements {
+		placement := JobPlacement{ID: p.ID, Order: p.OrderID, Packed: p.Packed, Width: p.Width, Height: p.Height, Rotated: p.Rotated, Mirrored: p.Mirrored, Radius: p.Radius}
+		if p.Packed {
+			placement.Bin, placement.X, placement.Y = p.BinIndex+1, p.X, p.Y
+		}
+		r.Placements = append(r.Placements, placement)
+	}
+	for _, order := range result.Orders() {
+		bins := make([]int, len(order.Bins))
+		for i, bin := range order.Bins {
+			bins[i] = bin + 1
+		}
+		r.Orders = append(r.Orders, JobOrder{ID: order.ID, Bins: bins, Packed: order.Packed, Unpacked: order.Unpacked})
+	}
+	return r
+}
@@ -0,0 +1,244 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedState is returned when a bin cannot be serialized, e.g. because it uses
+// a custom Backend.
+var ErrUnsupportedState = errors.New("binpacking: state cannot be serialized")
+
+// placementNames maps the built-in placement strategies to their names in JSON.
+// ContactPointFit, CenterFit and SpreadFit are bound to their bin and are recreated for
+// the restored bin.
+var placementNames = []struct {
+	name     string
+	strategy PlacementStrategyFunc
+}{
+	{"best-short-side-fit", BestShortSideFit},
+	{"best-long-side-fit", BestLongSideFit},
+	{"best-area-fit", BestAreaFit},
+	{"bottom-left", BottomLeft},
+	{"contact-point", ContactPointFit(nil)},
+	{"center-out", CenterFit(nil)},
+	{"spread", SpreadFit(nil)},
+}
+
+// placementName returns the name of a built-in strategy, or "custom" for other ones.
+// Functions are identified by their code, so every ContactPointFit closure matches.
+func placementName(strategy PlacementStrategyFunc) string {
+	if strategy == nil {
+		return ""
+	}
+	code := reflect.ValueOf(strategy).Pointer()
+	for _, known := range placementNames {
+		if reflect.ValueOf(known.strategy).Pointer() == code {
+			return known.name
+		}
+	}
+	return "custom"
+}
+
+// binJSON is the serialized form of a Bin.
+type binJSON struct {
+	Width         float64
+	Height        float64
+	Placement     string `json:",omitempty"`
+	Cost          float64
+	MaxWeight     float64
+	Name          string  `json:",omitempty"`
+	Material      string  `json:",omitempty"`
+	Thickness     float64 `json:",omitempty"`
+	MaxFreeSpaces int
+	MinFreeWidth  float64
+	MinFreeHeight float64
+	Spacing       float64
+	TieBreak      SpaceTieBreak `json:",omitempty"`
+	MergeAdjacent bool          `json:",omitempty"`
+	Margins       Margins
+	Defects       []FreeSpaceBox
+	Boxes         []*Box
+	FreeSpaces    []*FreeSpaceBox
+	Backend       *backendJSON `json:",omitempty"`
+	Compacted     bool         `json:",omitempty"`
+}
+
+// backendJSON is the serialized form of a built-in Backend and its state.
+type backendJSON struct {
+	Type       string              // "maxrects", "guillotine", "skyline", "bottom-left-fill" or "grid"
+	SplitRule  GuillotineSplitRule `json:",omitempty"`
+	Merge      bool                `json:",omitempty"`
+	Heuristic  SkylineHeuristic    `json:",omitempty"`
+	WasteMap   bool                `json:",omitempty"`
+	Nodes      []skylineNode       `json:",omitempty"`
+	Waste      []*FreeSpaceBox     `json:",omitempty"`
+	Resolution float64             `json:",omitempty"`
+	BestFit    bool                `json:",omitempty"`
+	Mask       [][]bool            `json:",omitempty"`
+	Cells      []uint64            `json:",omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The bin's boxes, free spaces and backend state are
+// included, so a partially packed bin can be restored with UnmarshalJSON and packed further.
+// Built-in placement strategies are stored by name; custom ones are recorded as "custom"
+// and restored as BestShortSideFit. Box.Data is encoded with encoding/json, and cluster
+// membership is not preserved. Custom backends cannot be serialized; the error wraps
+// ErrUnsupportedState.
+func (b *Bin) MarshalJSON() ([]byte, error) {
+	data := binJSON{
+		Width: b.Width, Height: b.Height, Placement: placementName(b.Placement),
+		Cost: b.Cost, MaxWeight: b.MaxWeight, MaxFreeSpaces: b.MaxFreeSpaces,
+		Name: b.Name, Material: b.Material, Thickness: b.Thickness,
+		MinFreeWidth: b.MinFreeWidth, MinFreeHeight: b.MinFreeHeight,
+		Spacing: b.Spacing, TieBreak: b.TieBreak, MergeAdjacent: b.MergeAdjacent, Margins: b.Margins, Defects: b.Defects,
+		Boxes: b.Boxes, FreeSpaces: b.FreeSpaces, Compacted: b.compacted,
+	}
+	switch backend := b.Backend.(type) {
+	case nil, MaxRectsBackend, *MaxRectsBackend:
+	case BottomLeftFillBackend, *BottomLeftFillBackend:
+		data.Backend = &backendJSON{Type: "bottom-left-fill"}
+	case CircleBackend, *CircleBackend:
+		data.Backend = &backendJSON{Type: "circle"}
+	case *GuillotineBackend:
+		data.Backend = &backendJSON{Type: "guillotine", SplitRule: backend.SplitRule, Merge: backend.Merge}
+	case *SkylineBackend:
+		data.Backend = &backendJSON{
+			Type: "skyline", Heuristic: backend.Heuristic, WasteMap: backend.WasteMap,
+			SplitRule: backend.split.SplitRule, Nodes: backend.nodes, Waste: backend.waste,
+		}
+	case *GridBackend:
+		data.Backend = &backendJSON{
+			Type: "grid", Resolution: backend.Resolution, BestFit: backend.BestFit,
+			Mask: backend.Mask, Cells: backend.cells,
+		}
+	default:
+		return nil, fmt.Errorf("%w: custom backend %T", ErrUnsupportedState, b.Backend)
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring a bin written by MarshalJSON.
+func (b *Bin) UnmarshalJSON(raw []byte) error {
+	var data binJSON
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	restored := Bin{
+		Width: data.Width, Height: data.Height, Placement: BestShortSideFit,
+		Cost: data.Cost, MaxWeight: data.MaxWeight, MaxFreeSpaces: data.MaxFreeSpaces,
+		Name: data.Name, Material: data.Material, Thickness: data.Thickness,
+		MinFreeWidth: data.MinFreeWidth, MinFreeHeight: data.MinFreeHeight,
+		Spacing: data.Spacing, TieBreak: data.TieBreak, MergeAdjacent: data.MergeAdjacent, Margins: data.Margins, Defects: data.Defects,
+		Boxes: data.Boxes, FreeSpaces: data.FreeSpaces, compacted: data.Compacted,
+	}
+	if restored.Boxes == nil {
+		restored.Boxes = make([]*Box, 0)
+	}
+	for _, known := range placementNames {
+		if known.name == data.Placement {
+			restored.Placement = known.strategy
+		}
+	}
+	if data.Backend != nil {
+		switch data.Backend.Type {
+		case "maxrects":
+		case "bottom-left-fill":
+			restored.Backend = BottomLeftFillBackend{}
+		case "circle":
+			restored.Backend = CircleBackend{}
+		case "guillotine":
+			restored.Backend = &GuillotineBackend{SplitRule: data.Backend.SplitRule, Merge: data.Backend.Merge}
+		case "skyline":
+			restored.Backend = &SkylineBackend{
+				Heuristic: data.Backend.Heuristic, WasteMap: data.Backend.WasteMap,
+				nodes: data.Backend.Nodes, waste: data.Backend.Waste,
+				split: GuillotineBackend{SplitRule: data.Backend.SplitRule},
+			}
+		case "grid":
+			grid := &GridBackend{Resolution: data.Backend.Resolution, BestFit: data.Backend.BestFit, Mask: data.Backend.Mask}
+			if data.Backend.Cells != nil {
+				grid.dimensions(&restored)
+				if len(data.Backend.Cells) != grid.stride*grid.rows {
+					return fmt.Errorf("%w: grid of %d words, want %d", ErrUnsupportedState, len(data.Backend.Cells), grid.stride*grid.rows)
+				}
+				grid.cells = data.Backend.Cells
+			}
+			restored.Backend = grid
+		default:
+			return fmt.Errorf("%w: unknown backend %q", ErrUnsupportedState, data.Backend.Type)
+		}
+	}
+	*b = restored
+	switch data.Placement { // Bound to the restored bin
+	case "contact-point":
+		b.Placement = ContactPointFit(b)
+	case "center-out":
+		b.Placement = CenterFit(b)
+	case "spread":
+		b.Placement = SpreadFit(b)
+	}
+	return nil
+}
+
+// boxRef locates a packed box by bin and insertion index.
+type boxRef struct {
+	Bin   int
+	Index int
+}
+
+// packerJSON is the serialized form of a Packer. Boxes are stored once, inside their
+// bins or in Unpacked, and the boxes packed by the last call to Pack refer to them.
+type packerJSON struct {
+	Bins       []*Bin
+	Unpacked   []*Box
+	LastPacked []boxRef
+}
+
+// MarshalJSON implements json.Marshaler. The bins, the unpacked boxes and which boxes the
+// last call to Pack placed are stored, so Packer.Result and further calls to Pack behave
+// the same after UnmarshalJSON. The caveats of Bin.MarshalJSON apply.
+func (p *Packer) MarshalJSON() ([]byte, error) {
+	refs := make(map[*Box]boxRef)
+	for i, bin := range p.Bins {
+		if bin == nil {
+			continue
+		}
+		for j, box := range bin.Boxes {
+			refs[box] = boxRef{Bin: i, Index: j}
+		}
+	}
+	data := packerJSON{Bins: p.Bins, Unpacked: p.UnpackedBoxes, LastPacked: make([]boxRef, 0, len(p.lastPacked))}
+	for _, box := range p.lastPacked {
+		if ref, ok := refs[box]; ok {
+			data.LastPacked = append(data.LastPacked, ref)
+		}
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring a packer written by MarshalJSON.
+func (p *Packer) UnmarshalJSON(raw []byte) error {
+	var data packerJSON
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	restored := Packer{Bins: data.Bins, UnpackedBoxes: data.Unpacked, lastPacked: make([]*Box, 0, len(data.LastPacked))}
+	if restored.Bins == nil {
+		restored.Bins = make([]*Bin, 0)
+	}
+	if restored.UnpackedBoxes == nil {
+		restored.UnpackedBoxes = make([]*Box, 0)
+	}
+	for _, ref := range data.LastPacked {
+		if ref.Bin < 0 || ref.Bin >= len(restored.Bins) || restored.Bins[ref.Bin] == nil ||
+			ref.Index < 0 || ref.Index >= len(restored.Bins[ref.Bin].Boxes) {
+			return fmt.Errorf("%w: packed box %d of bin %d does not exist", ErrUnsupportedState, ref.Index, ref.Bin)
+		}
+		restored.lastPacked = append(restored.lastPacked, restored.Bins[ref.Bin].Boxes[ref.Index])
+	}
+	*p = restored
+	return nil
+}
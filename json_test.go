@@ -0,0 +1,119 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	bins := map[string]func() *Bin{
+		"maxrects":      func() *Bin { return NewBin(20, 20, BestAreaFit) },
+		"guillotine":    func() *Bin { return NewGuillotineBin(20, 20, nil, SplitMinimizeArea) },
+		"skyline":       func() *Bin { return NewSkylineBin(20, 20, SkylineOptions{WasteMap: true}) },
+		"blf":           func() *Bin { return NewBottomLeftFillBin(20, 20) },
+		"contact-point": func() *Bin { return NewContactPointBin(20, 20) },
+	}
+	boxSet := func() []*Box {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 24; i++ {
+			boxes = append(boxes, NewBox(float64(1+(i*7+3)%5), float64(1+(i*3+6)%6), false))
+		}
+		return boxes
+	}
+	for name, newBin := range bins {
+		t.Run("resumes packing with "+name, func(t *testing.T) {
+			first, rest := boxSet(), boxSet()
+			original := NewPacker([]*Bin{newBin(), newBin()})
+			original.Pack(first[:12], PackerOptions{})
+
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var restored Packer
+			if err := json.Unmarshal(data, &restored); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got, want := len(restored.Result().Packed), len(original.Result().Packed); got != want {
+				t.Errorf("Packed boxes after restore: got %d, want %d", got, want)
+			}
+			for i, box := range restored.Result().Packed {
+				if !containsBox(restored.Bins[0].Boxes, box) && !containsBox(restored.Bins[1].Boxes, box) {
+					t.Errorf("Packed box %d is not one of the restored bins' boxes", i)
+				}
+			}
+
+			// Both packers must continue identically.
+			original.Pack(first[12:], PackerOptions{})
+			restored.Pack(rest[12:], PackerOptions{})
+			for b := range original.Bins {
+				got, want := restored.Bins[b].Boxes, original.Bins[b].Boxes
+				if len(got) != len(want) {
+					t.Fatalf("Bin %d: got %d boxes, want %d", b, len(got), len(want))
+				}
+				for i := range want {
+					if got[i].Label() != want[i].Label() {
+						t.Errorf("Bin %d box %d: got %s, want %s", b, i, got[i].Label(), want[i].Label())
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("keeps bin settings and box fields", func(t *testing.T) {
+		bin := NewBin(30, 30, BottomLeft)
+		bin.Cost, bin.MinFreeWidth = 4.5, 1
+		if err := bin.SetMargins(Margins{Top: 1, Left: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if err := bin.AddDefect(10, 10, 2, 2); err != nil {
+			t.Fatal(err)
+		}
+		box := &Box{Width: 3, Height: 4, ID: "sku-1", Tag: "oak", Data: "note"}
+		if !bin.Insert(box) {
+			t.Fatal("Insert failed")
+		}
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if restored.Cost != 4.5 || restored.MinFreeWidth != 1 || restored.Margins != bin.Margins || len(restored.Defects) != 1 {
+			t.Errorf("Settings: got %+v", restored)
+		}
+		if got, want := placementName(restored.Placement), "bottom-left"; got != want {
+			t.Errorf("Placement: got %v, want %v", got, want)
+		}
+		got := restored.Boxes[0]
+		if got.ID != "sku-1" || got.Tag != "oak" || got.Data != "note" || !got.Packed || got.Label() != box.Label() {
+			t.Errorf("Box: got %+v, want %+v", got, box)
+		}
+		if len(restored.FreeSpaces) != len(bin.FreeSpaces) {
+			t.Errorf("Free spaces: got %d, want %d", len(restored.FreeSpaces), len(bin.FreeSpaces))
+		}
+	})
+
+	t.Run("rejects custom backends", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Backend = customBackend{}
+		if _, err := json.Marshal(bin); !errors.Is(err, ErrUnsupportedState) {
+			t.Errorf("Marshal: got %v, want %v", err, ErrUnsupportedState)
+		}
+	})
+}
+
+// customBackend is a Backend unknown to the serializer.
+type customBackend struct{ MaxRectsBackend }
+
+func containsBox(boxes []*Box, box *Box) bool {
+	for _, b := range boxes {
+		if b == box {
+			return true
+		}
+	}
+	return false
+}
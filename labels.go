@@ -0,0 +1,111 @@
+package binpacking
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// PartLabel describes one placed box for label printing.
+type PartLabel struct {
+	PartID   string  // Box.ID if set, otherwise "bin-sequence"; printed and encoded in the barcode
+	BinIndex int     // Zero-based index of the bin (sheet) in the list passed to PartLabels
+	Sequence int     // One-based placement order of the box within its bin, locked boxes not counted
+	X        float64 // X-coordinate of the top-left corner of the placed box
+	Y        float64 // Y-coordinate of the top-left corner of the placed box
+	Width    float64 // Width of the box as placed
+	Height   float64 // Height of the box as placed
+	Rotated  bool    // True if the box was rotated for this placement
+	Box      *Box    // The placed box itself, for templates needing more fields
+}
+
+// BinNumber returns the one-based bin number, which is what operators see on the sheet.
+func (l PartLabel) BinNumber() int {
+	return l.BinIndex + 1
+}
+
+// LabelOptions controls label generation in WriteZPL.
+type LabelOptions struct {
+	// Template renders a single PartLabel. Nil uses DefaultZPLTemplate.
+	// Templates may call the "zpl" function to escape free text for a ^FH field.
+	Template *template.Template
+}
+
+// labelFuncs are the template functions available to label templates.
+var labelFuncs = template.FuncMap{"zpl": zplEscape}
+
+// DefaultZPLTemplate prints a 4x2 inch label with the part ID, bin, sequence,
+// position, size and rotation, plus a Code 128 barcode of the part ID.
+var DefaultZPLTemplate = template.Must(template.New("zpl").Funcs(labelFuncs).Parse(`^XA
+^CI28
+^CF0,50
+^FO40,30^FH\^FD{{zpl .PartID}}^FS
+^CF0,30
+^FO40,95^FDBin {{.BinNumber}}  Seq {{.Sequence}}^FS
+^FO40,135^FDPos {{printf "%g" .X}},{{printf "%g" .Y}}  Size {{printf "%g" .Width}}x{{printf "%g" .Height}}^FS
+^FO40,175^FDRotated: {{if .Rotated}}yes{{else}}no{{end}}^FS
+^FO40,225^BCN,100,Y,N,N^FH\^FD{{zpl .PartID}}^FS
+^XZ
+`))
+
+// NewLabelTemplate parses a custom label template with the label helper functions available.
+func NewLabelTemplate(text string) (*template.Template, error) {
+	return template.New("label").Funcs(labelFuncs).Parse(text)
+}
+
+// PartLabels lists a label for every placed box, bin by bin in placement order, which is
+// not necessarily the order the parts are cut in. Locked boxes (see Bin.Place) were
+// placed before this job and get no label; the sequence numbers skip them.
+func PartLabels(bins []*Bin) []PartLabel {
+	labels := make([]PartLabel, 0)
+	for binIndex, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		sequence := 0
+		for _, box := range bin.Boxes {
+			if box.Locked {
+				continue
+			}
+			sequence++
+			partID := box.ID
+			if partID == "" {
+				partID = fmt.Sprintf("%d-%d", binIndex+1, sequence)
+			}
+			labels = append(labels, PartLabel{
+				PartID:   partID,
+				BinIndex: binIndex,
+				Sequence: sequence,
+				X:        box.X,
+				Y:        box.Y,
+				Width:    box.Width,
+				Height:   box.Height,
+				Rotated:  box.Rotated,
+				Box:      box,
+			})
+		}
+	}
+	return labels
+}
+
+// WriteZPL renders a label for every placed box in bins to w, in the order of
+// PartLabels, ready to be sent to a ZPL label printer.
+func WriteZPL(w io.Writer, bins []*Bin, options LabelOptions) error {
+	tmpl := options.Template
+	if tmpl == nil {
+		tmpl = DefaultZPLTemplate
+	}
+	for _, label := range PartLabels(bins) {
+		if err := tmpl.Execute(w, label); err != nil {
+			return fmt.Errorf("binpacking: rendering label %s: %w", label.PartID, err)
+		}
+	}
+	return nil
+}
+
+// zplEscape hex-encodes the characters that have a special meaning in ZPL so the
+// value can be used in a field introduced with ^FH\.
+func zplEscape(s string) string {
+	return strings.NewReplacer(`\`, `\5C`, `^`, `\5E`, `~`, `\7E`).Replace(s)
+}
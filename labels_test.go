@@ -0,0 +1,70 @@
+package binpacking
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteZPL(t *testing.T) {
+	bin1 := NewBin(100, 50, nil)
+	bin2 := NewBin(50, 50, nil)
+	packer := NewPacker([]*Bin{bin1, bin2})
+	packer.Pack([]*Box{NewBox(40, 40, false), NewBox(15, 10, false), NewBox(50, 45, false)}, PackerOptions{})
+
+	t.Run("renders one label per placed box", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteZPL(&buf, packer.Bins, LabelOptions{}); err != nil {
+			t.Fatalf("WriteZPL: %v", err)
+		}
+		if got := strings.Count(buf.String(), "^XA"); got != 3 {
+			t.Errorf("Label count: got %d, want %d", got, 3)
+		}
+		if !strings.Contains(buf.String(), "Bin 2  Seq 1") {
+			t.Errorf("Labels do not reference the second bin:\n%s", buf.String())
+		}
+	})
+
+	t.Run("uses box IDs as part IDs", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Insert(&Box{Width: 5, Height: 5, ID: "SKU-42", Data: 7})
+		bin.Insert(NewBox(5, 5, false))
+		labels := PartLabels([]*Bin{bin})
+		if labels[0].PartID != "SKU-42" || labels[1].PartID != "1-2" {
+			t.Errorf("Part IDs: got %q and %q, want %q and %q", labels[0].PartID, labels[1].PartID, "SKU-42", "1-2")
+		}
+		if labels[0].Box.Data != 7 {
+			t.Errorf("Data: got %v, want %v", labels[0].Box.Data, 7)
+		}
+	})
+
+	t.Run("skips pre-placed boxes", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		if err := bin.Place(&Box{Width: 5, Height: 5, ID: "OFFCUT"}, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		bin.Insert(NewBox(5, 5, false))
+		labels := PartLabels([]*Bin{bin})
+		if len(labels) != 1 {
+			t.Fatalf("Label count: got %d, want %d", len(labels), 1)
+		}
+		if labels[0].PartID != "1-1" || labels[0].Sequence != 1 {
+			t.Errorf("Label: got %q sequence %d, want %q sequence %d", labels[0].PartID, labels[0].Sequence, "1-1", 1)
+		}
+	})
+
+	t.Run("supports custom templates", func(t *testing.T) {
+		tmpl, err := NewLabelTemplate("{{.PartID}};{{zpl \"a^b\"}}\n")
+		if err != nil {
+			t.Fatalf("NewLabelTemplate: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := WriteZPL(&buf, packer.Bins, LabelOptions{Template: tmpl}); err != nil {
+			t.Fatalf("WriteZPL: %v", err)
+		}
+		want := "1-1;a\\5Eb\n1-2;a\\5Eb\n2-1;a\\5Eb\n"
+		if buf.String() != want {
+			t.Errorf("Custom labels: got %q, want %q", buf.String(), want)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package binpacking
+
+import (
+	"context"
+	"log/slog"
+)
+
+// debugging reports whether the packer logs debug records.
+func (p *Packer) debugging() bool {
+	return p.Logger != nil && p.Logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// boxAttr describes a box in debug logs.
+func boxAttr(box *Box) slog.Attr {
+	return slog.Group("box", "id", box.ID, "width", box.Width, "height", box.Height)
+}
+
+// scoreAttr describes a score in debug logs.
+func scoreAttr(score Score) slog.Attr {
+	return slog.Group("score", "primary", score.Primary, "secondary", score.Secondary)
+}
+
+// freeSpaceAttr describes the free space of spaces that received box, which is the first
+// one containing it; the attribute is empty for backends without free rectangles.
+func freeSpaceAttr(spaces []FreeSpaceBox, box *Box) slog.Attr {
+	for _, space := range spaces {
+		if box.X >= space.X && box.Y >= space.Y &&
+			box.X+box.Width <= space.X+space.Width && box.Y+box.Height <= space.Y+space.Height {
+			return slog.Group("free", "x", space.X, "y", space.Y, "width", space.Width, "height", space.Height)
+		}
+	}
+	return slog.Attr{}
+}
+
+// freeSpaceValues copies the free spaces of bin, which the backend may modify in place.
+func freeSpaceValues(bin *Bin) []FreeSpaceBox {
+	spaces := make([]FreeSpaceBox, len(bin.FreeSpaces))
+	for i, space := range bin.FreeSpaces {
+		spaces[i] = *space
+	}
+	return spaces
+}
+
+// binAttr describes a bin by its index in the bins of the packer Pack was called on.
+func (p *Packer) binAttr(bin *Bin) slog.Attr {
+	for i, b := range p.Bins {
+		if b == bin {
+			return slog.Int("bin", p.binOffset+i)
+		}
+	}
+	return slog.Int("bin", -1)
+}
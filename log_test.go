@@ -0,0 +1,95 @@
+package binpacking
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	records := func(t *testing.T, buf *bytes.Buffer) []map[string]any {
+		t.Helper()
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record map[string]any
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("got invalid record %q: %v", line, err)
+			}
+			out = append(out, record)
+		}
+		return out
+	}
+	boxes := func() []*Box {
+		return []*Box{{ID: "a", Width: 6, Height: 10}, {ID: "b", Width: 4, Height: 10}, {ID: "c", Width: 20, Height: 20}}
+	}
+
+	t.Run("debug records explain each placement", func(t *testing.T) {
+		var buf bytes.Buffer
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		packer.Pack(boxes(), PackerOptions{})
+
+		counts := make(map[string]int)
+		for _, record := range records(t, &buf) {
+			msg := record["msg"].(string)
+			counts[msg]++
+			if msg != "placed" {
+				continue
+			}
+			if _, ok := record["free"].(map[string]any); !ok {
+				t.Errorf("got %v, want the free space the box went into", record)
+			}
+			if record["bin"] != 0.0 {
+				t.Errorf("got bin %v, want 0", record["bin"])
+			}
+		}
+		if counts["placed"] != 2 || counts["best fit"] != 2 || counts["no box fits"] != 1 {
+			t.Errorf("got %v, want 2 placed, 2 best fit and 1 no box fits", counts)
+		}
+	})
+
+	t.Run("template bins are numbered in the packer", func(t *testing.T) {
+		var buf bytes.Buffer
+		packer := NewPacker([]*Bin{NewBin(6, 10, nil)})
+		packer.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		packer.Pack(boxes()[:2], PackerOptions{BinTemplates: []BinTemplate{{Width: 10, Height: 10}}})
+		var bins []float64
+		for _, record := range records(t, &buf) {
+			if record["msg"] == "placed" {
+				bins = append(bins, record["bin"].(float64))
+			}
+		}
+		if len(bins) != 2 || bins[0] != 0 || bins[1] != 1 {
+			t.Errorf("got bins %v, want [0 1]", bins)
+		}
+	})
+
+	t.Run("restarts log trials but not their placements", func(t *testing.T) {
+		var buf bytes.Buffer
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		packer.Pack(boxes(), PackerOptions{Restarts: 3})
+		counts := make(map[string]int)
+		for _, record := range records(t, &buf) {
+			counts[record["msg"].(string)]++
+		}
+		if counts["restart"] != 3 || counts["placed"] != 2 {
+			t.Errorf("got %v, want 3 restarts and 2 placed", counts)
+		}
+	})
+
+	t.Run("nothing is logged above debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+		packer.Pack(boxes(), PackerOptions{})
+		if buf.Len() != 0 {
+			t.Errorf("got %q, want no output", buf.String())
+		}
+	})
+}
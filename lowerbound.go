@@ -0,0 +1,234 @@
+package binpacking
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// maxBoundParameters caps the number of values of each parameter of the L2 bound that
+// L2LowerBound tries, keeping it fast for large inputs. Trying fewer values can only
+// weaken the bound, never make it invalid.
+const maxBoundParameters = 32
+
+// ContinuousLowerBound returns the continuous lower bound on the number of bins of the
+// given size needed to pack the boxes: their total area divided by the bin area, rounded
+// up. It is 0 for an empty bin size.
+func ContinuousLowerBound(binWidth, binHeight float64, boxes []*Box) int {
+	binArea := binWidth * binHeight
+	if !(binArea > 0) {
+		return 0
+	}
+	area := 0.0
+	for _, box := range boxes {
+		if box != nil {
+			area += box.Area()
+		}
+	}
+	return ceilBins(area / binArea)
+}
+
+// L2LowerBound returns a lower bound on the number of bins of the given size needed to
+// pack the boxes, at least ContinuousLowerBound and usually tighter when many boxes are
+// more than half a bin wide or tall. It is the largest of the continuous bound, the
+// Martello–Toth L2 bound of the one-dimensional problems formed by the boxes that cannot
+// be stacked and by those that cannot stand side by side, and the L2 bound of Martello
+// and Vigo (1998), which counts the boxes that need a bin of their own and the bins the
+// boxes unable to join some of them need on top.
+//
+// Boxes that may rotate are counted in a class only if they belong to it in both
+// orientations, so the bound holds whichever way they are packed. Boxes that fit the bin
+// in neither orientation are ignored. Spacing, margins and defects are not taken into
+// account; they only make more bins necessary.
+func L2LowerBound(binWidth, binHeight float64, boxes []*Box) int {
+	if !(binWidth > 0 && binHeight > 0) {
+		return 0
+	}
+	items := make([]boundItem, 0, len(boxes))
+	for _, box := range boxes {
+		if box == nil {
+			continue
+		}
+		item := boundItem{box.Width, box.Height, !box.ConstrainRotation && box.Width != box.Height}
+		if item.any(func(w, h float64) bool { return w <= binWidth && h <= binHeight }) {
+			items = append(items, item)
+		}
+	}
+
+	area := 0.0
+	for _, item := range items {
+		area += item.width * item.height
+	}
+	bound := ceilBins(area / (binWidth * binHeight))
+	bound = max(bound, lineBound(items, binWidth, func(w, h float64) (float64, bool) { return w, h > binHeight/2 }))
+	bound = max(bound, lineBound(items, binHeight, func(w, h float64) (float64, bool) { return h, w > binWidth/2 }))
+	return max(bound, pairBound(items, binWidth, binHeight))
+}
+
+// boundItem is a box as seen by the lower bounds: its size and whether it may rotate.
+type boundItem struct {
+	width, height float64
+	rotatable     bool
+}
+
+// all reports whether the item satisfies cond in every orientation it may take.
+func (item boundItem) all(cond func(w, h float64) bool) bool {
+	return cond(item.width, item.height) && (!item.rotatable || cond(item.height, item.width))
+}
+
+// any reports whether the item satisfies cond in some orientation it may take.
+func (item boundItem) any(cond func(w, h float64) bool) bool {
+	return cond(item.width, item.height) || item.rotatable && cond(item.height, item.width)
+}
+
+// lineBound is the Martello–Toth L2 bound of a one-dimensional problem hidden in the two
+// dimensional one. Boxes more than half a bin tall cannot be stacked, so those in one bin
+// stand side by side and their widths add up to at most the bin width; likewise for the
+// heights of boxes more than half a bin wide. size returns the length of the item along
+// the bin side of the given capacity for an orientation, and whether the orientation
+// belongs to the problem; an item belongs if all its orientations do, with its shortest
+// length among them.
+func lineBound(items []boundItem, capacity float64, size func(w, h float64) (float64, bool)) int {
+	var sizes []float64
+	for _, item := range items {
+		length, ok := size(item.width, item.height)
+		if !ok {
+			continue
+		}
+		if item.rotatable {
+			rotated, ok := size(item.height, item.width)
+			if !ok {
+				continue
+			}
+			length = min(length, rotated)
+		}
+		sizes = append(sizes, length)
+	}
+	return oneDimensionalBound(sizes, capacity)
+}
+
+// oneDimensionalBound returns the Martello–Toth L2 bound for packing the sizes into bins
+// of the given capacity: for every threshold a up to half the capacity, the sizes above
+// capacity - a and those above half the capacity each need a bin of their own, and the
+// sizes between a and half the capacity that do not fit into the room left beside the
+// latter need further bins.
+func oneDimensionalBound(sizes []float64, capacity float64) int {
+	if len(sizes) == 0 {
+		return 0
+	}
+	slices.Sort(sizes)
+	prefix := make([]float64, len(sizes)+1)
+	for i, size := range sizes {
+		prefix[i+1] = prefix[i] + size
+	}
+	above := func(x float64) int { return sort.Search(len(sizes), func(i int) bool { return sizes[i] > x }) }
+	atLeast := func(x float64) int { return sort.Search(len(sizes), func(i int) bool { return sizes[i] >= x }) }
+
+	half := above(capacity / 2) // Sizes from half on are above half the capacity
+	count := len(sizes) - half
+	bound := count
+	for i, a := range append([]float64{0}, sizes[:half]...) {
+		if i > 1 && a == sizes[i-2] {
+			continue // Same threshold as the previous size
+		}
+		large := above(capacity - a) // Sizes from large on leave no room for one of [a, C/2]
+		small := atLeast(a)          // Sizes from small to half are those of [a, C/2]
+		room := float64(large-half)*capacity - (prefix[large] - prefix[half])
+		bound = max(bound, count+ceilBins((prefix[half]-prefix[small]-room)/capacity))
+	}
+	return bound
+}
+
+// pairBound is the L2 bound of Martello and Vigo (1998). Boxes more than half a bin wide
+// and half a bin tall need a bin each. For parameters p and q up to half the bin width and
+// height, a box at least p wide and q tall cannot share a bin with one more than W - p
+// wide and H - q tall, so the boxes between p × q and half the bin size that do not fit
+// into the room left in the bins of the other large boxes need further bins.
+func pairBound(items []boundItem, binWidth, binHeight float64) int {
+	binArea := binWidth * binHeight
+	var large []boundItem
+	for _, item := range items {
+		if item.all(func(w, h float64) bool { return w > binWidth/2 && h > binHeight/2 }) {
+			large = append(large, item)
+		}
+	}
+
+	bound := len(large)
+	heights := boundParameters(items, binHeight/2)
+	for _, p := range boundParameters(items, binWidth/2) {
+		for _, q := range heights {
+			room := 0.0 // Room left by the large boxes an in-between box may join
+			for _, item := range large {
+				if !item.all(func(w, h float64) bool { return w > binWidth-p && h > binHeight-q }) {
+					room += binArea - item.width*item.height
+				}
+			}
+			area := 0.0
+			for _, item := range items {
+				if item.all(func(w, h float64) bool { return w >= p && w <= binWidth/2 && h >= q && h <= binHeight/2 }) {
+					area += item.width * item.height
+				}
+			}
+			bound = max(bound, len(large)+ceilBins((area-room)/binArea))
+		}
+	}
+	return bound
+}
+
+// boundParameters returns the values worth trying for a parameter of pairBound: 0 and
+// the box sides up to limit, at most maxBoundParameters of them, spread evenly.
+func boundParameters(items []boundItem, limit float64) []float64 {
+	values := []float64{0}
+	for _, item := range items {
+		for _, side := range []float64{item.width, item.height} {
+			if side <= limit {
+				values = append(values, side)
+			}
+		}
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+	if len(values) <= maxBoundParameters {
+		return values
+	}
+	sampled := make([]float64, maxBoundParameters)
+	for i := range sampled {
+		sampled[i] = values[i*(len(values)-1)/(maxBoundParameters-1)]
+	}
+	return sampled
+}
+
+// ceilBins rounds a fractional number of bins up, forgiving rounding errors of the areas
+// it was computed from. Negative values count as 0.
+func ceilBins(bins float64) int {
+	if !(bins > 0) {
+		return 0
+	}
+	return int(math.Ceil(bins - 1e-9))
+}
+
+// lowerBound returns the lower bound reported in PackResult for the boxes packed into
+// the given bins: L2LowerBound if the bins share one size, otherwise the continuous bound
+// for the largest bin.
+func lowerBound(bins []*Bin, boxes []*Box) int {
+	var largest *Bin
+	uniform := true
+	for _, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		if largest != nil && (bin.Width != largest.Width || bin.Height != largest.Height) {
+			uniform = false
+		}
+		if largest == nil || bin.Area() > largest.Area() {
+			largest = bin
+		}
+	}
+	if largest == nil {
+		return 0
+	}
+	if uniform {
+		return L2LowerBound(largest.Width, largest.Height, boxes)
+	}
+	return ContinuousLowerBound(largest.Width, largest.Height, boxes)
+}
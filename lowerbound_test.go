@@ -0,0 +1,110 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// boxesOf returns n boxes of the given size.
+func boxesOf(n int, width, height float64, constrainRotation bool) []*Box {
+	boxes := make([]*Box, n)
+	for i := range boxes {
+		boxes[i] = NewBox(width, height, constrainRotation)
+	}
+	return boxes
+}
+
+func TestLowerBounds(t *testing.T) {
+	tests := []struct {
+		name           string
+		boxes          []*Box
+		continuous, l2 int
+	}{
+		{"empty", nil, 0, 0},
+		{"exact area", boxesOf(4, 5, 5, true), 1, 1},
+		{"area just over one bin", boxesOf(5, 5, 5, true), 2, 2},
+		{"boxes over half the bin need a bin each", boxesOf(6, 6, 6, false), 3, 6},
+		{"wide boxes cannot stand side by side", boxesOf(3, 6, 4, true), 1, 2},
+		{"rotated, the same boxes share a bin", boxesOf(3, 6, 4, false), 1, 1},
+		{"small boxes cannot join a box leaving thin strips", append(boxesOf(1, 9, 9, true), NewBox(2, 2, true)), 1, 2},
+		{"boxes larger than the bin are ignored by L2", append(boxesOf(1, 11, 5, true), NewBox(5, 5, true)), 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContinuousLowerBound(10, 10, tt.boxes); got != tt.continuous {
+				t.Errorf("ContinuousLowerBound: got %d, want %d", got, tt.continuous)
+			}
+			if got := L2LowerBound(10, 10, tt.boxes); got != tt.l2 {
+				t.Errorf("L2LowerBound: got %d, want %d", got, tt.l2)
+			}
+		})
+	}
+
+	t.Run("empty bin", func(t *testing.T) {
+		boxes := boxesOf(2, 1, 1, false)
+		if got := ContinuousLowerBound(0, 10, boxes); got != 0 {
+			t.Errorf("ContinuousLowerBound: got %d, want 0", got)
+		}
+		if got := L2LowerBound(10, 0, boxes); got != 0 {
+			t.Errorf("L2LowerBound: got %d, want 0", got)
+		}
+	})
+
+	t.Run("one-dimensional bound", func(t *testing.T) {
+		// With a = 3 the 8 needs a bin alone, and not all 3s fit beside the 6s.
+		if got := oneDimensionalBound([]float64{8, 6, 6, 3, 3, 3}, 10); got != 4 {
+			t.Errorf("got %d, want 4", got)
+		}
+	})
+
+	t.Run("never above a packing", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(3, 3))
+		for round := 0; round < 200; round++ {
+			var boxes []*Box
+			for n := 1 + rng.IntN(30); n > 0; n-- {
+				boxes = append(boxes, NewBox(float64(1+rng.IntN(10)), float64(1+rng.IntN(10)), rng.IntN(2) == 0))
+			}
+			bound := L2LowerBound(10, 10, boxes)
+			if bound < ContinuousLowerBound(10, 10, boxes) {
+				t.Fatalf("round %d: L2 bound %d below the continuous bound", round, bound)
+			}
+			packer := NewPacker(nil)
+			packer.Pack(boxes, PackerOptions{BinTemplates: []BinTemplate{{Width: 10, Height: 10}}})
+			if used := packer.Result().BinsUsed; bound > used {
+				t.Fatalf("round %d: got bound %d, want at most the %d bins used", round, bound, used)
+			}
+		}
+	})
+}
+
+func TestPackResultLowerBound(t *testing.T) {
+	t.Run("identical bins", func(t *testing.T) {
+		packer := NewPacker(nil)
+		packer.Pack(boxesOf(5, 6, 6, false), PackerOptions{BinTemplates: []BinTemplate{{Width: 10, Height: 10}}})
+		result := packer.Result()
+		if result.BinsUsed != 5 || result.LowerBound != 5 || result.Gap != 0 {
+			t.Errorf("got %d bins, bound %d, gap %v, want 5, 5, 0", result.BinsUsed, result.LowerBound, result.Gap)
+		}
+	})
+
+	t.Run("mixed bins", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(20, 20, nil)})
+		packer.Pack([]*Box{NewBox(10, 10, false), NewBox(20, 20, false)}, PackerOptions{})
+		result := packer.Result()
+		// The continuous bound for the 20 × 20 bin: 500 / 400, rounded up.
+		if result.BinsUsed != 2 || result.LowerBound != 2 || result.Gap != 0 {
+			t.Errorf("got %d bins, bound %d, gap %v, want 2, 2, 0", result.BinsUsed, result.LowerBound, result.Gap)
+		}
+	})
+
+	t.Run("gap", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.Bins[0].Insert(NewBox(5, 5, false))
+		packer.Bins[1].Insert(NewBox(5, 5, false))
+		packer.Pack(nil, PackerOptions{})
+		result := packer.Result()
+		if result.LowerBound != 1 || result.Gap != 1 {
+			t.Errorf("got bound %d, gap %v, want 1, 1", result.LowerBound, result.Gap)
+		}
+	})
+}
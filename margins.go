@@ -0,0 +1,61 @@
+package binpacking
+
+import (
+	"fmt"
+	"math"
+)
+
+// Margins is an unusable border along the edges of a bin, such as the edge trim of a panel.
+type Margins struct {
+	Top    float64
+	Right  float64
+	Bottom float64
+	Left   float64
+}
+
+// SetMargins sets the bin's border, so every placement lies inside the trim area and
+// positions stay relative to the bin's own top-left corner. The bin must not hold any
+// boxes yet; defects are kept. The returned error wraps ErrInvalidDimensions if a margin
+// is negative or not finite, or the margins leave no room, and ErrPlacement if the bin
+// is not empty or its backend does not implement FixedPlacer.
+func (b *Bin) SetMargins(margins Margins) error {
+	for _, side := range []float64{margins.Top, margins.Right, margins.Bottom, margins.Left} {
+		if side < 0 || math.IsNaN(side) || math.IsInf(side, 0) {
+			return fmt.Errorf("%w: margin %g", ErrInvalidDimensions, side)
+		}
+	}
+	if margins.Left+margins.Right > b.Width || margins.Top+margins.Bottom > b.Height {
+		return fmt.Errorf("%w: margins %+v exceed the %gx%g bin", ErrInvalidDimensions, margins, b.Width, b.Height)
+	}
+	if len(b.Boxes) > 0 {
+		return fmt.Errorf("%w: margins must be set before boxes are placed", ErrPlacement)
+	}
+	if _, ok := b.backend().(FixedPlacer); !ok {
+		return fmt.Errorf("%w: backend %T does not support margins", ErrPlacement, b.backend())
+	}
+	b.Margins = margins
+	b.reset() // Rebuilds the free area around the margins and defects
+	return nil
+}
+
+// marginStrips returns the border as up to four disjoint rectangles: full-height strips
+// on the left and right, and the top and bottom strips in between.
+func (b *Bin) marginStrips() []FreeSpaceBox {
+	m := b.Margins
+	if m == (Margins{}) {
+		return nil
+	}
+	innerWidth := b.Width - m.Left - m.Right
+	strips := make([]FreeSpaceBox, 0, 4)
+	for _, strip := range []FreeSpaceBox{
+		{X: 0, Y: 0, Width: m.Left, Height: b.Height},
+		{X: b.Width - m.Right, Y: 0, Width: m.Right, Height: b.Height},
+		{X: m.Left, Y: 0, Width: innerWidth, Height: m.Top},
+		{X: m.Left, Y: b.Height - m.Bottom, Width: innerWidth, Height: m.Bottom},
+	} {
+		if strip.Width > 0 && strip.Height > 0 {
+			strips = append(strips, strip)
+		}
+	}
+	return strips
+}
@@ -0,0 +1,68 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMargins(t *testing.T) {
+	margins := Margins{Top: 1, Right: 2, Bottom: 3, Left: 4}
+	inside := func(t *testing.T, bin *Bin) {
+		t.Helper()
+		for _, box := range bin.Boxes {
+			if box.X < margins.Left || box.Y < margins.Top || box.X+box.Width > bin.Width-margins.Right || box.Y+box.Height > bin.Height-margins.Bottom {
+				t.Errorf("Box %s is outside the trim area", box.Label())
+			}
+		}
+	}
+	newBoxes := func() []*Box {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 20; i++ {
+			boxes = append(boxes, NewBox(2, 2, true))
+		}
+		return boxes
+	}
+
+	bins := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(10, 10, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(10, 10, SkylineOptions{}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(10, 10) },
+		"contact":    func() *Bin { return NewContactPointBin(10, 10) },
+	}
+	for name, newBin := range bins {
+		t.Run("places inside the margins with "+name, func(t *testing.T) {
+			bin := newBin()
+			if err := bin.SetMargins(margins); err != nil {
+				t.Fatalf("SetMargins: %v", err)
+			}
+			NewPacker([]*Bin{bin}).Pack(newBoxes(), PackerOptions{})
+			inside(t, bin)
+			// The trim area is 4x6, exactly six boxes.
+			if len(bin.Boxes) != 6 {
+				t.Errorf("Packed: got %d, want %d", len(bin.Boxes), 6)
+			}
+		})
+	}
+
+	t.Run("places inside the margins with shelves", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.SetMargins(margins)
+		packed := NewPacker([]*Bin{bin}).Pack(newBoxes(), PackerOptions{Algorithm: AlgorithmShelfFirstFit})
+		inside(t, bin)
+		if len(packed) != 6 {
+			t.Errorf("Packed: got %d, want %d", len(packed), 6)
+		}
+	})
+
+	t.Run("rejects invalid margins", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		if err := bin.SetMargins(Margins{Left: 6, Right: 5}); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("Too wide: got %v, want ErrInvalidDimensions", err)
+		}
+		bin.Insert(NewBox(1, 1, true))
+		if err := bin.SetMargins(margins); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Non-empty bin: got %v, want ErrPlacement", err)
+		}
+	})
+}
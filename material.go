@@ -0,0 +1,31 @@
+package binpacking
+
+import "strings"
+
+// fitsMaterial reports whether the box may go into the bin by its material: a box
+// without a Material goes into any bin, any other only into bins whose Material is one
+// of the comma-separated materials it lists. Materials are compared exactly, apart from
+// spaces around the commas.
+func (b *Bin) fitsMaterial(box *Box) bool {
+	return box.Material == "" || b.isMaterial(box.Material) // Inlined, so boxes without a material cost no call
+}
+
+// isMaterial reports whether the bin's Material is one of a comma-separated list.
+func (b *Bin) isMaterial(materials string) bool {
+	for rest := materials; ; {
+		material, more, found := strings.Cut(rest, ",")
+		if strings.TrimSpace(material) == b.Material {
+			return true
+		}
+		if !found {
+			return false
+		}
+		rest = more
+	}
+}
+
+// accepts reports whether the bin may take box at all, whatever room it has left: its
+// material matches and it keeps the bin within MaxWeight.
+func (b *Bin) accepts(box *Box) bool {
+	return b.fitsMaterial(box) && b.fitsWeight(box)
+}
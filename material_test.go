@@ -0,0 +1,144 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaterial(t *testing.T) {
+	newBins := func() []*Bin {
+		oak, mdf := NewBin(10, 10, nil), NewBin(10, 10, nil)
+		oak.Material, mdf.Material = "oak", "MDF"
+		return []*Bin{oak, mdf}
+	}
+	newBox := func(material string) *Box {
+		box := NewBox(5, 5, false)
+		box.Material = material
+		return box
+	}
+
+	t.Run("fitsMaterial", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Material = "oak"
+		tests := []struct {
+			material string
+			want     bool
+		}{
+			{"", true},
+			{"oak", true},
+			{"MDF", false},
+			{"Oak", false},
+			{"walnut, oak", true},
+			{"walnut,MDF", false},
+		}
+		for _, tt := range tests {
+			if got := bin.fitsMaterial(newBox(tt.material)); got != tt.want {
+				t.Errorf("%q: got %v, want %v", tt.material, got, tt.want)
+			}
+		}
+		if NewBin(10, 10, nil).fitsMaterial(newBox("oak")) {
+			t.Error("got an oak box accepted by a bin without material")
+		}
+	})
+
+	t.Run("pack", func(t *testing.T) {
+		for _, algorithm := range []PackingAlgorithm{AlgorithmBestFit, AlgorithmShelfNextFit, AlgorithmShelfFirstFit} {
+			bins := newBins()
+			boxes := []*Box{newBox("MDF"), newBox("oak"), newBox(""), newBox("MDF,oak"), newBox("walnut")}
+			packer := NewPacker(bins)
+			packer.Pack(boxes, PackerOptions{Algorithm: algorithm})
+			for _, bin := range bins {
+				for _, box := range bin.Boxes {
+					if !bin.fitsMaterial(box) {
+						t.Errorf("algorithm %d: got a %q box in the %s bin", algorithm, box.Material, bin.Material)
+					}
+				}
+			}
+			// Next-fit never returns to the oak bin once the MDF box opened the other.
+			if algorithm != AlgorithmShelfNextFit && (len(packer.UnpackedBoxes) != 1 || packer.UnpackedBoxes[0] != boxes[4]) {
+				t.Errorf("algorithm %d: got %d unpacked boxes, want the walnut one", algorithm, len(packer.UnpackedBoxes))
+			}
+			if err := packer.Result().Validate(); err != nil {
+				t.Errorf("algorithm %d: %v", algorithm, err)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		bin := newBins()[0]
+		if err := bin.InsertChecked(newBox("MDF")); !errors.Is(err, ErrWrongMaterial) || !errors.Is(err, ErrNoFit) {
+			t.Errorf("got %v, want ErrWrongMaterial", err)
+		}
+		if err := bin.Place(newBox("MDF"), 0, 0); !errors.Is(err, ErrPlacement) {
+			t.Errorf("got %v, want ErrPlacement", err)
+		}
+		box := newBox("MDF")
+		box.Packed = true
+		bin.Boxes = append(bin.Boxes, box)
+		if err := ValidateLayout(bin); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("got %v, want ErrInvalidLayout", err)
+		}
+	})
+
+	t.Run("reject reason", func(t *testing.T) {
+		// The oak bin has room for the MDF box but the MDF bin does not.
+		bins := newBins()
+		if err := bins[1].Place(NewBox(10, 10, false), 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		box, tooBig := newBox("MDF"), NewBox(20, 20, false)
+		tooBig.Material = "oak"
+		observer := &recordingObserver{}
+		_, err := NewPacker(bins).PackChecked([]*Box{box, tooBig}, PackerOptions{Observer: observer})
+		if !errors.Is(err, ErrWrongMaterial) {
+			t.Errorf("got %v, want ErrWrongMaterial", err)
+		}
+		if reason := observer.rejected[box]; !errors.Is(reason, ErrWrongMaterial) {
+			t.Errorf("got rejection %v, want ErrWrongMaterial", reason)
+		}
+		if reason := observer.rejected[tooBig]; errors.Is(reason, ErrWrongMaterial) || !errors.Is(reason, ErrNoFit) {
+			t.Errorf("got rejection %v for a box that fits no bin, want ErrNoFit", reason)
+		}
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		bins := newBins()
+		boxes := []*Box{NewBox(10, 10, false), NewBox(10, 10, false)}
+		boxes[0].Material, boxes[1].Material = "MDF", "MDF"
+		packed := NewExactPacker(bins, time.Second).Pack(boxes)
+		if len(bins[1].Boxes) != 1 || len(packed) != 1 {
+			t.Errorf("got %d boxes in the MDF bin and %d packed, want 1 and 1", len(bins[1].Boxes), len(packed))
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var job Job
+		input := `{
+			"bins": [{"width": 10, "height": 10, "qty": 1, "material": "oak"}, {"width": 10, "height": 10, "qty": 1, "material": "MDF"}],
+			"boxes": [{"width": 10, "height": 10, "material": "MDF"}]
+		}`
+		if err := json.Unmarshal([]byte(input), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := NewJobResult(result).Placements[0].Bin; got != 2 {
+			t.Errorf("got bin %d, want the MDF bin 2", got)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		specs, err := ReadBoxesCSV(strings.NewReader("width,height,material\n5,5,\"oak,walnut\"\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := specs[0].Box.Material; got != "oak,walnut" {
+			t.Errorf("got material %q, want oak,walnut", got)
+		}
+	})
+}
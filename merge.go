@@ -0,0 +1,42 @@
+package binpacking
+
+// MergeFreeSpaces combines free spaces that share a full edge, up to the bin's Tolerance,
+// into larger rectangles, and returns the number of merges. A box that straddles two
+// such spaces then fits, where before neither space could hold it. The MaxRects backend
+// keeps every maximal free rectangle, so its free list only fragments where spaces were
+// dropped, by MaxFreeSpaces or MinFreeWidth, or set directly; the Guillotine backend
+// and the waste map of a Skyline backend keep disjoint pieces, which fragment with every
+// cut. A skyline itself is always merged already.
+func (b *Bin) MergeFreeSpaces() int {
+	merges := b.mergeAdjacent()
+	if merges > 0 {
+		b.indexFreeSpaces()
+		b.touch()
+	}
+	return merges
+}
+
+// settleFreeSpaces tidies the free list after it changed: it merges adjacent spaces if
+// MergeAdjacent is set, then discards slivers, so that pieces merged into a usable space
+// are kept.
+func (b *Bin) settleFreeSpaces() {
+	if b.MergeAdjacent {
+		b.mergeAdjacent()
+	}
+	b.discardSlivers()
+}
+
+// mergeAdjacent is MergeFreeSpaces without updating the index and generation.
+func (b *Bin) mergeAdjacent() int {
+	skyline, ok := b.Backend.(*SkylineBackend)
+	if !ok {
+		return b.mergeFreeList()
+	}
+	if len(skyline.waste) < 2 {
+		return 0
+	}
+	var merges int
+	skyline.waste, merges = mergeRects(skyline.waste, b.tolerance())
+	skyline.syncFreeSpaces(b)
+	return merges
+}
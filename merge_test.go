@@ -0,0 +1,101 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMergeFreeSpaces(t *testing.T) {
+	quadrants := func() []*FreeSpaceBox {
+		return []*FreeSpaceBox{
+			{X: 0, Y: 0, Width: 5, Height: 5}, {X: 5, Y: 0, Width: 5, Height: 5},
+			{X: 0, Y: 5, Width: 5, Height: 5}, {X: 5, Y: 5, Width: 5, Height: 5},
+		}
+	}
+
+	t.Run("MergeFreeSpaces", func(t *testing.T) {
+		bin := NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis)
+		bin.FreeSpaces = quadrants()
+		if bin.Insert(NewBox(8, 8, false)) {
+			t.Fatal("got an 8x8 box placed across fragments")
+		}
+		generation := bin.Generation()
+		if got := bin.MergeFreeSpaces(); got != 3 {
+			t.Errorf("got %d merges, want 3", got)
+		}
+		if got := freeSpacesOf(bin); len(got) != 1 || got[0] != (FreeSpaceBox{Width: 10, Height: 10}) {
+			t.Errorf("got free spaces %v, want the whole bin", got)
+		}
+		if bin.Generation() == generation {
+			t.Error("got the generation unchanged by merging")
+		}
+		if !bin.Insert(NewBox(8, 8, false)) {
+			t.Error("got no fit after merging")
+		}
+		if got := bin.MergeFreeSpaces(); got != 0 {
+			t.Errorf("got %d merges of an unfragmented list, want 0", got)
+		}
+	})
+
+	t.Run("tolerance", func(t *testing.T) {
+		bin := NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis)
+		bin.Tolerance = 1e-6
+		bin.FreeSpaces = []*FreeSpaceBox{{Width: 5, Height: 10}, {X: 5.0000001, Width: 4.9999999, Height: 10.0000002}}
+		if got := bin.MergeFreeSpaces(); got != 1 {
+			t.Fatalf("got %d merges, want 1", got)
+		}
+		want := FreeSpaceBox{Width: 10, Height: 10}
+		if got := *bin.FreeSpaces[0]; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("MergeAdjacent", func(t *testing.T) {
+		for _, merge := range []bool{false, true} {
+			bin := NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis)
+			bin.MergeAdjacent = merge
+			bin.FreeSpaces = quadrants()
+			bin.Insert(NewBox(5, 5, true))
+			if got := bin.Insert(NewBox(5, 10, true)); got != merge {
+				t.Errorf("MergeAdjacent %v: got fit %v, want %v", merge, got, merge)
+			}
+		}
+	})
+
+	t.Run("skyline waste", func(t *testing.T) {
+		skyline := &SkylineBackend{WasteMap: true}
+		bin := NewBin(10, 10, nil)
+		bin.Backend = skyline
+		skyline.ensureNodes(bin)
+		skyline.nodes = []skylineNode{{X: 0, Y: 6, Width: 10}}
+		skyline.waste = []*FreeSpaceBox{{X: 0, Y: 0, Width: 4, Height: 6}, {X: 4, Y: 0, Width: 3, Height: 6}}
+		skyline.syncFreeSpaces(bin)
+		if got := bin.MergeFreeSpaces(); got != 1 {
+			t.Fatalf("got %d merges, want 1", got)
+		}
+		if got, want := *skyline.waste[0], (FreeSpaceBox{Width: 7, Height: 6}); len(skyline.waste) != 1 || got != want {
+			t.Errorf("got waste %v, want %v", skyline.waste, want)
+		}
+		if len(bin.FreeSpaces) != 2 {
+			t.Errorf("got %d free spaces, want the merged gap and the skyline", len(bin.FreeSpaces))
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin, err := NewBinWith(10, 10, WithMergeAdjacent())
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		if !restored.MergeAdjacent {
+			t.Error("got MergeAdjacent lost in the round trip")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMirror(t *testing.T) {
+	t.Run("orientations", func(t *testing.T) {
+		tests := []struct {
+			name                    string
+			constrain, mirror       bool
+			options                 *TagOptions
+			fits, rotated, mirrored bool
+		}{
+			{"fixed", true, false, nil, false, false, false},
+			{"mirrored", true, true, nil, true, false, true},
+			{"rotated", false, true, nil, true, true, false},
+			{"override", true, true, &TagOptions{ConstrainRotation: true}, false, false, false},
+		}
+		for _, tt := range tests {
+			bin := NewBin(2, 6, nil)
+			box := NewBox(6, 2, tt.constrain)
+			box.AllowMirror = tt.mirror
+			if got := bin.InsertWith(box, tt.options); got != tt.fits {
+				t.Fatalf("%s: got fit %t, want %t", tt.name, got, tt.fits)
+			}
+			if box.Rotated != tt.rotated || box.Mirrored != tt.mirrored {
+				t.Errorf("%s: got rotated %t, mirrored %t, want %t, %t", tt.name, box.Rotated, box.Mirrored, tt.rotated, tt.mirrored)
+			}
+			if tt.fits && (box.Width != 2 || box.Height != 6) {
+				t.Errorf("%s: got %gx%g, want 2x6", tt.name, box.Width, box.Height)
+			}
+		}
+	})
+
+	t.Run("Mirror", func(t *testing.T) {
+		box := NewBox(3, 1, true)
+		box.Mirror()
+		if box.Width != 1 || box.Height != 3 || !box.Mirrored || box.Rotated {
+			t.Errorf("got %+v, want 1x3 mirrored", *box)
+		}
+		box.Mirror()
+		if box.Width != 3 || box.Mirrored {
+			t.Errorf("got %+v after mirroring back, want 3x1", *box)
+		}
+	})
+
+	t.Run("result", func(t *testing.T) {
+		box := NewBox(6, 2, true)
+		box.AllowMirror = true
+		packer := NewPacker([]*Bin{NewBin(2, 6, nil)})
+		packer.Pack([]*Box{box}, PackerOptions{})
+		result := packer.Result()
+		if got := result.Placements; len(got) != 1 || !got[0].Mirrored || got[0].Rotated {
+			t.Errorf("got placements %+v, want one mirrored", got)
+		}
+		if err := result.Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var job Job
+		input := `{"bins":[{"width":2,"height":6}],"boxes":[{"width":6,"height":2,"rotatable":false,"mirror":true}]}`
+		if err := json.Unmarshal([]byte(input), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.Placements; len(got) != 1 || !got[0].Packed || !got[0].Mirrored {
+			t.Errorf("got placements %+v, want one mirrored", got)
+		}
+	})
+}
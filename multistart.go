@@ -0,0 +1,153 @@
+package binpacking
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+)
+
+// PackMetric selects how results of different runs are compared, e.g. by PackerOptions.Restarts.
+type PackMetric int
+
+const (
+	// MetricPackedArea prefers the run packing the most box area, then the one using fewest bins.
+	MetricPackedArea PackMetric = iota
+	// MetricPackedCount prefers the run packing the most boxes, then the one using fewest bins.
+	MetricPackedCount
+	// MetricUsedBinArea prefers the run packing the most boxes, then the one whose bins in use
+	// have the smallest total area, i.e. the least waste.
+	MetricUsedBinArea
+	// MetricPackedValue prefers the run packing the most total Box.Value, then the one
+	// using fewest bins.
+	MetricPackedValue
+	// MetricTotalCost prefers the run packing the most boxes, then the one whose bins in use
+	// have the lowest total Bin.Cost.
+	MetricTotalCost
+)
+
+// runStats summarizes one packing run for comparison by a PackMetric.
+type runStats struct {
+	packedArea  float64
+	packedCount int
+	packedValue float64
+	binsUsed    int
+	usedBinArea float64
+	totalCost   float64
+}
+
+// statsFor computes the statistics of the given bins after a run that packed packed.
+func statsFor(bins []*Bin, packed []*Box) runStats {
+	var stats runStats
+	for _, box := range packed {
+		stats.packedArea += box.Area()
+		stats.packedValue += box.Value
+	}
+	stats.packedCount = len(packed)
+	for _, bin := range bins {
+		if bin != nil && len(bin.Boxes) > 0 {
+			stats.binsUsed++
+			stats.usedBinArea += bin.Area()
+			stats.totalCost += bin.Cost
+		}
+	}
+	return stats
+}
+
+// better reports whether a is a strictly better run than b according to the metric.
+func (m PackMetric) better(a, b runStats) bool {
+	switch m {
+	case MetricPackedCount:
+		if a.packedCount != b.packedCount {
+			return a.packedCount > b.packedCount
+		}
+		return a.binsUsed < b.binsUsed
+	case MetricPackedValue:
+		if a.packedValue != b.packedValue {
+			return a.packedValue > b.packedValue
+		}
+		return a.binsUsed < b.binsUsed
+	case MetricTotalCost:
+		if a.packedCount != b.packedCount {
+			return a.packedCount > b.packedCount
+		}
+		return a.totalCost < b.totalCost
+	case MetricUsedBinArea:
+		if a.packedCount != b.packedCount {
+			return a.packedCount > b.packedCount
+		}
+		return a.usedBinArea < b.usedBinArea
+	default:
+		if a.packedArea != b.packedArea {
+			return a.packedArea > b.packedArea
+		}
+		return a.binsUsed < b.binsUsed
+	}
+}
+
+// packMultiStart runs the scoreboard once and the sequential best-fit once per restart,
+// each on scratch copies of the bins, then repeats the best run on the real bins.
+func (p *Packer) packMultiStart(boxesToPack []*Box, options PackerOptions) []*Box {
+	rng := rand.New(rand.NewPCG(options.Seed, 0))
+
+	// trial packs copies of the boxes, in the order given by the indices, into copies of
+	// the bins. A nil order runs the plain scoreboard, or the boxes in their SortBy order.
+	trial := func(order []int) runStats {
+		bins, copies := trialCopies(p.Bins, boxesToPack)
+		scratch := &Packer{Bins: bins}
+		if order == nil {
+			return statsFor(scratch.Bins, scratch.packBestFit(copies, options, options.SortBy != SortNone))
+		}
+		return statsFor(scratch.Bins, scratch.packBestFit(permute(copies, order), options, true))
+	}
+
+	bestStats := trial(nil)
+	var bestOrder []int
+	for restart := 0; restart < options.Restarts && !options.expired(); restart++ {
+		order := preOrdering(boxesToPack, restart, rng)
+		stats := trial(order)
+		better := options.Metric.better(stats, bestStats)
+		if better {
+			bestStats, bestOrder = stats, order
+		}
+		if p.debugging() {
+			p.Logger.Debug("restart", slog.Int("restart", restart), slog.Int("packed", stats.packedCount), slog.Bool("best", better))
+		}
+	}
+
+	// With a time limit the best run may have been cut short. Replaying it without the
+	// deadline but limited to as many boxes reproduces it exactly, however late it is.
+	if options.clock != nil {
+		if bestStats.packedCount == 0 {
+			return make([]*Box, 0)
+		}
+		options.clock, options.Limit = nil, int64(bestStats.packedCount)
+	}
+	if bestOrder == nil {
+		return p.packBestFit(boxesToPack, options, options.SortBy != SortNone)
+	}
+	return p.packBestFit(permute(boxesToPack, bestOrder), options, true)
+}
+
+// preOrdering returns the box indices for the given restart: area, longest side and
+// perimeter descending for the first three, random permutations afterwards.
+func preOrdering(boxes []*Box, restart int, rng *rand.Rand) []int {
+	if restart >= 3 {
+		return rng.Perm(len(boxes))
+	}
+	key := []BoxOrder{SortAreaDesc, SortLongestSideDesc, SortPerimeterDesc}[restart].key
+	order := make([]int, len(boxes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return key(boxes[order[i]]) > key(boxes[order[j]]) })
+	return order
+}
+
+// permute returns the boxes in the order given by the indices.
+func permute(boxes []*Box, order []int) []*Box {
+	permuted := make([]*Box, 0, len(order))
+	for _, i := range order {
+		permuted = append(permuted, boxes[i])
+	}
+	return permuted
+}
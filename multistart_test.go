@@ -0,0 +1,57 @@
+package binpacking
+
+import "testing"
+
+func TestPackerRestarts(t *testing.T) {
+	newBoxes := func() []*Box {
+		return []*Box{
+			NewBox(2, 5, true), NewBox(2, 6, true), NewBox(4, 2, true), NewBox(5, 2, true), NewBox(2, 1, true),
+		}
+	}
+
+	t.Run("keeps a better pre-ordering", func(t *testing.T) {
+		single := NewPacker([]*Bin{NewBin(8, 8, nil)})
+		if packed := single.Pack(newBoxes(), PackerOptions{}); len(packed) == 5 {
+			t.Fatalf("Plain run packed every box; the instance no longer exercises restarts")
+		}
+
+		bin := NewBin(8, 8, nil)
+		packer := NewPacker([]*Bin{bin})
+		packed := packer.Pack(newBoxes(), PackerOptions{Restarts: 3})
+		if len(packed) != 5 || len(packer.UnpackedBoxes) != 0 {
+			t.Errorf("Packed boxes: got %d, want %d", len(packed), 5)
+		}
+		if len(bin.Boxes) != len(packed) {
+			t.Errorf("Boxes in bin: got %d, want %d", len(bin.Boxes), len(packed))
+		}
+	})
+
+	t.Run("never loses to the plain run", func(t *testing.T) {
+		boxes := func() []*Box {
+			result := make([]*Box, 0)
+			for i := 0; i < 25; i++ {
+				result = append(result, NewBox(float64(3+i*7%17), float64(2+i*5%13), false))
+			}
+			return result
+		}
+		area := func(packed []*Box) float64 {
+			total := 0.0
+			for _, box := range packed {
+				total += box.Area()
+			}
+			return total
+		}
+		plain := NewPacker([]*Bin{NewBin(40, 40, nil)}).Pack(boxes(), PackerOptions{})
+
+		packed := NewPacker([]*Bin{NewBin(40, 40, nil)}).Pack(boxes(), PackerOptions{Restarts: 6, Seed: 9})
+		if area(packed) < area(plain) {
+			t.Errorf("MetricPackedArea: packed area %g, want at least %g", area(packed), area(plain))
+		}
+		for _, metric := range []PackMetric{MetricPackedCount, MetricUsedBinArea} {
+			packed := NewPacker([]*Bin{NewBin(40, 40, nil)}).Pack(boxes(), PackerOptions{Restarts: 6, Seed: 9, Metric: metric})
+			if len(packed) < len(plain) {
+				t.Errorf("Metric %d: packed %d boxes, want at least %d", metric, len(packed), len(plain))
+			}
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package binpacking
+
+import "math"
+
+// Number is the set of types sizes can be given in with NewBoxOf and NewBinOf and read
+// back in with PlacedRect.
+//
+// The package itself computes in float64 only: bin, box and free space dimensions,
+// positions, areas and placement scores all share that one type, so values flow
+// between them without conversions. Integer sizes, such as pixels or millimetres, are
+// represented exactly up to 2^53, and so are the sums and differences packing derives
+// from them, which keeps layouts of integer inputs on integer coordinates.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// NewBoxOf is NewBox for sizes of any Number type.
+func NewBoxOf[T Number](width, height T, constrainRotation bool) *Box {
+	return NewBox(float64(width), float64(height), constrainRotation)
+}
+
+// NewBinOf is NewBin for sizes of any Number type.
+func NewBinOf[T Number](width, height T, placement PlacementStrategyFunc) *Bin {
+	return NewBin(float64(width), float64(height), placement)
+}
+
+// PlacedRect returns the position and size of the box, as placed, in the Number type
+// the sizes were given in. Integer types are rounded to the nearest value, so that
+// rounding errors of fractional spacings or scaled sizes never shift a box by one.
+func PlacedRect[T Number](box *Box) (x, y, width, height T) {
+	return toNumber[T](box.X), toNumber[T](box.Y), toNumber[T](box.Width), toNumber[T](box.Height)
+}
+
+// toNumber converts v to T, rounding to the nearest value for integer types.
+func toNumber[T Number](v float64) T {
+	half := 0.5
+	if T(half) == 0 {
+		return T(math.Round(v))
+	}
+	return T(v)
+}
@@ -0,0 +1,31 @@
+package binpacking
+
+import "testing"
+
+func TestNumber(t *testing.T) {
+	t.Run("integer sizes", func(t *testing.T) {
+		bin := NewBinOf(uint16(30), uint16(20), nil)
+		box := NewBoxOf(int32(12), int32(7), true)
+		if box.Width != 12 || box.Height != 7 || bin.Width != 30 || bin.Height != 20 {
+			t.Fatalf("got box %gx%g in bin %gx%g, want 12x7 in 30x20", box.Width, box.Height, bin.Width, bin.Height)
+		}
+		bin.Insert(NewBoxOf(int32(30), int32(5), true))
+		if !bin.Insert(box) {
+			t.Fatal("box does not fit")
+		}
+		x, y, w, h := PlacedRect[int32](box)
+		if x != int32(box.X) || y != 5 || w != 12 || h != 7 {
+			t.Errorf("got [%d,%d] %dx%d, want [%g,5] 12x7", x, y, w, h, box.X)
+		}
+	})
+
+	t.Run("rounding", func(t *testing.T) {
+		box := &Box{X: 2.9999999999, Y: 0.1 + 0.2, Width: 1.5, Height: 4}
+		if x, y, w, h := PlacedRect[int](box); x != 3 || y != 0 || w != 2 || h != 4 {
+			t.Errorf("got [%d,%d] %dx%d, want [3,0] 2x4", x, y, w, h)
+		}
+		if x, y, _, _ := PlacedRect[float32](box); x != float32(2.9999999999) || y != float32(0.1+0.2) {
+			t.Errorf("got [%g,%g], want the float32 positions unrounded", x, y)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package binpacking
+
+import "sort"
+
+// Objective selects what Packer.Pack optimizes when choosing the next box to place.
+type Objective int
+
+const (
+	// ObjectiveBestFit places the box/bin pair with the best placement score first,
+	// which tends to maximize the packed area.
+	ObjectiveBestFit Objective = iota
+	// ObjectiveMaxValue is a knapsack mode that maximizes the total Box.Value packed into
+	// the available bins. Boxes are placed in order of value density (value per unit of
+	// area), highest first, each at its best position; the placement score breaks ties
+	// between boxes of equal density. Boxes without a value are placed last.
+	ObjectiveMaxValue
+	// ObjectivePreferValue behaves like ObjectiveBestFit when every box fits. Otherwise
+	// boxes are placed in order of Box.Value, highest first, so the boxes that matter most
+	// are the ones packed; the placement score breaks ties between boxes of equal value.
+	ObjectivePreferValue
+	// ObjectiveMinCost minimizes the total Bin.Cost of the bins that end up holding boxes;
+	// empty bins are free. Bins already in use are filled first, and a new bin is opened
+	// in order of cost per unit of area, cheapest first. After packing, the contents of
+	// each bin are moved to a cheaper unused bin when they all fit there.
+	ObjectiveMinCost
+)
+
+// rank returns the ScoreBoard.Rank function implementing the objective,
+// or nil if entries are ranked by placement score alone.
+func (o Objective) rank() func(entry *ScoreBoardEntry, score Score) Score {
+	switch o {
+	case ObjectiveMaxValue:
+		return func(entry *ScoreBoardEntry, score Score) Score {
+			return NewScoreWithTieBreak(-valueDensity(entry.Box), score.Primary)
+		}
+	case ObjectivePreferValue:
+		return func(entry *ScoreBoardEntry, score Score) Score {
+			return NewScoreWithTieBreak(-entry.Box.Value, score.Primary)
+		}
+	case ObjectiveMinCost:
+		return func(entry *ScoreBoardEntry, score Score) Score {
+			opening := 0.0
+			if len(entry.Bin.Boxes) == 0 {
+				opening = entry.Bin.Cost / entry.Bin.Area()
+			}
+			return NewScoreWithTieBreak(opening, score.Primary)
+		}
+	}
+	return nil
+}
+
+// fitsAll reports whether a plain best-fit run, on scratch copies of the bins and boxes,
+// packs every box.
+func (p *Packer) fitsAll(boxesToPack []*Box, options PackerOptions) bool {
+	bins, copies := trialCopies(p.Bins, boxesToPack)
+	scratch := &Packer{Bins: bins}
+	options.Objective = ObjectiveBestFit
+	options.Limit = 0
+	return len(scratch.packBestFit(copies, options, false)) == len(copies)
+}
+
+// valueDensity returns the value of the box per unit of area. Zero-area boxes with a
+// value are infinitely dense, which Score clamps so that they go first.
+func valueDensity(box *Box) float64 {
+	return box.Value / box.Area()
+}
+
+// downgradeBins moves the contents of bins filled entirely by this run into cheaper
+// unused bins that can hold all of them, most expensive bins first.
+func (p *Packer) downgradeBins(packedBoxes []*Box, tagOptions map[string]TagOptions) {
+	packedNow := make(map[*Box]struct{}, len(packedBoxes))
+	for _, box := range packedBoxes {
+		packedNow[box] = struct{}{}
+	}
+	used := make([]*Bin, 0, len(p.Bins))
+	for _, bin := range p.Bins {
+		if bin == nil || len(bin.Boxes) == 0 {
+			continue
+		}
+		movable := true
+		for _, box := range bin.Boxes {
+			if _, ok := packedNow[box]; !ok {
+				movable = false // Holds boxes from an earlier run, which stay where they are
+				break
+			}
+		}
+		if movable {
+			used = append(used, bin)
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool { return used[i].Cost > used[j].Cost })
+
+	for _, bin := range used {
+		for _, candidate := range p.cheaperEmptyBins(bin) {
+			if !refillable(candidate, bin.Boxes, tagOptions) {
+				continue
+			}
+			boxes := bin.Boxes
+			for _, box := range boxes {
+				box.Packed = false
+			}
+			bin.reset()
+			for _, box := range boxes {
+				candidate.InsertWith(box, tagOptionsFor(tagOptions, box)) // Succeeds like in refillable
+				p.events.move(candidate, box)
+			}
+			break
+		}
+	}
+}
+
+// cheaperEmptyBins returns the empty bins cheaper than bin, cheapest first.
+func (p *Packer) cheaperEmptyBins(bin *Bin) []*Bin {
+	candidates := make([]*Bin, 0)
+	for _, other := range p.Bins {
+		if other != nil && other != bin && len(other.Boxes) == 0 && other.Cost < bin.Cost {
+			candidates = append(candidates, other)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Cost < candidates[j].Cost })
+	return candidates
+}
+
+// refillable reports whether copies of boxes, inserted in order, all fit into bin.
+func refillable(bin *Bin, boxes []*Box, tagOptions map[string]TagOptions) bool {
+	scratch := bin.scratch()
+	for _, box := range boxes {
+		copied := *box
+		copied.cluster = nil
+		copied.Packed = false
+		if !scratch.InsertWith(&copied, tagOptionsFor(tagOptions, box)) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagOptionsFor returns the overrides for the box's tag, or nil if there are none.
+func tagOptionsFor(tagOptions map[string]TagOptions, box *Box) *TagOptions {
+	if options, ok := tagOptions[box.Tag]; ok {
+		return &options
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package binpacking
+
+import "testing"
+
+func TestObjective(t *testing.T) {
+	t.Run("max value packs the most valuable boxes", func(t *testing.T) {
+		// Only one of the big boxes fits; the small one fits in either case.
+		cheap := &Box{Width: 10, Height: 6, Value: 1}
+		precious := &Box{Width: 10, Height: 5, Value: 100}
+		small := &Box{Width: 2, Height: 2, Value: 10}
+		boxes := []*Box{cheap, precious, small}
+
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack(boxes, PackerOptions{Objective: ObjectiveMaxValue})
+		if !precious.Packed || !small.Packed || cheap.Packed {
+			t.Errorf("Packed: got cheap=%v precious=%v small=%v, want false true true", cheap.Packed, precious.Packed, small.Packed)
+		}
+	})
+
+	t.Run("best fit ignores value", func(t *testing.T) {
+		cheap := &Box{Width: 10, Height: 6, Value: 1}
+		precious := &Box{Width: 10, Height: 5, Value: 100}
+		packer := NewPacker([]*Bin{NewBin(10, 6, nil)})
+		packer.Pack([]*Box{precious, cheap}, PackerOptions{})
+		if !cheap.Packed {
+			t.Errorf("Exact fit packed: got %v, want %v", cheap.Packed, true)
+		}
+	})
+
+	t.Run("prefer value only matters when boxes are left out", func(t *testing.T) {
+		cheap := &Box{Width: 10, Height: 6, Value: 1}
+		precious := &Box{Width: 4, Height: 4, Value: 100}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack([]*Box{cheap, precious}, PackerOptions{Objective: ObjectivePreferValue})
+		if !cheap.Packed || !precious.Packed {
+			t.Fatalf("Both boxes fit and must be packed")
+		}
+		// Same layout as the plain best fit run.
+		plain := []*Box{{Width: 10, Height: 6, Value: 1}, {Width: 4, Height: 4, Value: 100}}
+		NewPacker([]*Bin{NewBin(10, 10, nil)}).Pack(plain, PackerOptions{})
+		if cheap.Label() != plain[0].Label() || precious.Label() != plain[1].Label() {
+			t.Errorf("Layout: got %s and %s, want %s and %s", cheap.Label(), precious.Label(), plain[0].Label(), plain[1].Label())
+		}
+
+		// When not everything fits, the most valuable box wins over two exact fits.
+		big := &Box{Width: 8, Height: 8, Value: 50}
+		smalls := []*Box{{Width: 10, Height: 5, Value: 10}, {Width: 10, Height: 5, Value: 10}}
+		packer = NewPacker([]*Bin{NewBin(10, 10, nil)})
+		result := packer.Pack(append([]*Box{big}, smalls...), PackerOptions{Objective: ObjectivePreferValue})
+		if !big.Packed {
+			t.Errorf("Most valuable box packed: got %v, want %v", big.Packed, true)
+		}
+		if value := packer.Result().PackedValue(); value != 50 {
+			t.Errorf("PackedValue: got %g, want %g (packed %d boxes)", value, 50.0, len(result))
+		}
+		if value := packer.Result().UnpackedValue(); value != 20 {
+			t.Errorf("UnpackedValue: got %g, want %g", value, 20.0)
+		}
+	})
+
+	t.Run("min cost picks the cheapest material mix", func(t *testing.T) {
+		expensive := NewBin(100, 100, nil)
+		expensive.Cost = 50
+		cheap := NewBin(60, 60, nil)
+		cheap.Cost = 10
+		unused := NewBin(40, 40, nil)
+		unused.Cost = 30
+
+		packer := NewPacker([]*Bin{expensive, cheap, unused})
+		packer.Pack([]*Box{NewBox(50, 50, false), NewBox(10, 10, false)}, PackerOptions{Objective: ObjectiveMinCost})
+		if len(cheap.Boxes) != 2 || len(expensive.Boxes) != 0 {
+			t.Errorf("Boxes per bin: got %d expensive, %d cheap, want 0 and 2", len(expensive.Boxes), len(cheap.Boxes))
+		}
+		if cost := packer.Result().TotalCost(); cost != 10 {
+			t.Errorf("TotalCost: got %g, want %g", cost, 10.0)
+		}
+	})
+
+	t.Run("min cost moves lightly used bins to cheaper stock", func(t *testing.T) {
+		// Cost per area favors the big sheet, but a small part alone belongs on the small one.
+		big := NewBin(100, 100, nil)
+		big.Cost = 20
+		small := NewBin(20, 20, nil)
+		small.Cost = 5
+		packer := NewPacker([]*Bin{big, small})
+		packer.Pack([]*Box{NewBox(15, 15, false)}, PackerOptions{Objective: ObjectiveMinCost})
+		if len(small.Boxes) != 1 || len(big.Boxes) != 0 {
+			t.Errorf("Boxes per bin: got %d big, %d small, want 0 and 1", len(big.Boxes), len(small.Boxes))
+		}
+		if len(big.FreeSpaces) != 1 || big.FreeSpaces[0].Width != 100 {
+			t.Errorf("Emptied bin must be reset, got free spaces %d", len(big.FreeSpaces))
+		}
+	})
+}
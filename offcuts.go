@@ -0,0 +1,87 @@
+package binpacking
+
+import "sort"
+
+// Offcuts returns the remaining stock of the bin: the maximal free rectangles around its
+// boxes, defects and margins, recomputed from the layout rather than taken from the
+// backend's free list. Rectangles contained in others are removed, ones below
+// MinFreeWidth or MinFreeHeight are dropped, and the rest are ordered by area, largest
+// first, then top to bottom and left to right. Maximal rectangles may overlap; each
+// one is a piece that can be cut from the remaining material. Spacing is not deducted.
+func (b *Bin) Offcuts() []FreeSpaceBox {
+	layout := &Bin{Width: b.Width, Height: b.Height, FreeSpaces: []*FreeSpaceBox{{Width: b.Width, Height: b.Height}}}
+	for _, box := range b.Boxes {
+		layout.splitFreeSpaces(box)
+	}
+	for _, region := range b.blocked() {
+		layout.splitFreeSpaces(defectBox(region))
+	}
+
+	return b.usableOffcuts(layout.FreeSpaces)
+}
+
+// offcutPieces returns the remaining stock of the bin cut into disjoint rectangles, so
+// that their areas add up to the free area of the bin, minus pieces below MinFreeWidth
+// or MinFreeHeight. They are ordered like Offcuts.
+func (b *Bin) offcutPieces() []FreeSpaceBox {
+	pieces := []*FreeSpaceBox{{Width: b.Width, Height: b.Height}}
+	for _, box := range b.Boxes {
+		pieces = subtractDisjoint(pieces, box, b.tolerance())
+	}
+	for _, region := range b.blocked() {
+		pieces = subtractDisjoint(pieces, defectBox(region), b.tolerance())
+	}
+	return b.usableOffcuts(pieces)
+}
+
+// usableOffcuts copies the spaces that are at least MinFreeWidth by MinFreeHeight,
+// largest first, then top to bottom and left to right.
+func (b *Bin) usableOffcuts(spaces []*FreeSpaceBox) []FreeSpaceBox {
+	offcuts := make([]FreeSpaceBox, 0, len(spaces))
+	for _, space := range spaces {
+		if space.Width > 0 && space.Height > 0 && space.Width >= b.MinFreeWidth && space.Height >= b.MinFreeHeight {
+			offcuts = append(offcuts, *space)
+		}
+	}
+	sort.SliceStable(offcuts, func(i, j int) bool {
+		a, c := offcuts[i], offcuts[j]
+		if areaA, areaC := a.Width*a.Height, c.Width*c.Height; areaA != areaC {
+			return areaA > areaC
+		}
+		if a.Y != c.Y {
+			return a.Y < c.Y
+		}
+		return a.X < c.X
+	})
+	return offcuts
+}
+
+// discardSlivers drops free spaces below MinFreeWidth or MinFreeHeight, including the
+// gaps of a skyline's waste map. The skyline itself is kept, as it is the frontier of
+// the free area rather than an offcut.
+func (b *Bin) discardSlivers() {
+	if b.MinFreeWidth <= 0 && b.MinFreeHeight <= 0 {
+		return
+	}
+	usable := func(space *FreeSpaceBox) bool {
+		return space.Width >= b.MinFreeWidth && space.Height >= b.MinFreeHeight
+	}
+	if skyline, ok := b.Backend.(*SkylineBackend); ok {
+		kept := skyline.waste[:0]
+		for _, space := range skyline.waste {
+			if usable(space) {
+				kept = append(kept, space)
+			}
+		}
+		skyline.waste = kept
+		skyline.syncFreeSpaces(b)
+		return
+	}
+	kept := b.FreeSpaces[:0]
+	for _, space := range b.FreeSpaces {
+		if usable(space) {
+			kept = append(kept, space)
+		}
+	}
+	b.FreeSpaces = kept
+}
@@ -0,0 +1,55 @@
+package binpacking
+
+import "testing"
+
+func TestMinFreeSize(t *testing.T) {
+	bins := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(10, 10, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis) },
+	}
+	for name, newBin := range bins {
+		t.Run("discards slivers with "+name, func(t *testing.T) {
+			bin := newBin()
+			bin.MinFreeWidth, bin.MinFreeHeight = 2, 2
+			bin.Insert(NewBox(9, 6, true))
+			for _, space := range bin.FreeSpaces {
+				if space.Width < 2 || space.Height < 2 {
+					t.Errorf("Free space %+v is below the threshold", *space)
+				}
+			}
+			if bin.Insert(NewBox(1, 1, true)) && bin.Boxes[1].Y < 6 {
+				t.Errorf("Box placed into a discarded sliver: %s", bin.Boxes[1].Label())
+			}
+			if !bin.Insert(NewBox(9, 3, true)) {
+				t.Errorf("Insert into the usable offcut: got false, want true")
+			}
+		})
+	}
+}
+
+func TestOffcuts(t *testing.T) {
+	t.Run("returns maximal rectangles largest first", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Place(NewBox(6, 4, true), 0, 0)
+		got := bin.Offcuts()
+		want := []FreeSpaceBox{{X: 0, Y: 4, Width: 10, Height: 6}, {X: 6, Y: 0, Width: 4, Height: 10}}
+		if len(got) != len(want) {
+			t.Fatalf("Offcuts: got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Offcut %d: got %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("excludes margins and defects", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.SetMargins(Margins{Top: 1, Right: 1, Bottom: 1, Left: 1})
+		bin.AddDefect(1, 1, 8, 2)
+		got := bin.Offcuts()
+		if len(got) != 1 || got[0] != (FreeSpaceBox{X: 1, Y: 3, Width: 8, Height: 6}) {
+			t.Errorf("Offcuts: got %v, want the 8x6 piece at [1,3]", got)
+		}
+	})
+}
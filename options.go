@@ -0,0 +1,236 @@
+package binpacking
+
+import "time"
+
+// BinOption configures a bin created by NewBinWith.
+type BinOption func(*binConfig)
+
+// binConfig collects the options of NewBinWith, so that they are applied in a fixed
+// order whatever order they are given in: the backend before the regions it blocks.
+type binConfig struct {
+	placement     PlacementStrategyFunc
+	backend       Backend
+	margins       Margins
+	spacing       float64
+	defects       []FreeSpaceBox
+	maxWeight     float64
+	cost          float64
+	tolerance     float64
+	minFreeWidth  float64
+	minFreeHeight float64
+	tieBreak      SpaceTieBreak
+	mergeAdjacent bool
+	name          string
+	material      string
+	thickness     float64
+}
+
+// NewBinWith creates an empty bin of the given size configured by the options, e.g.
+//
+//	bin, err := NewBinWith(100, 80, WithStrategy(BestAreaFit), WithSpacing(1), WithWeightLimit(40))
+//
+// Without options it is NewBin with the default strategy. The returned error wraps
+// ErrInvalidDimensions for an invalid size, or is the error of SetMargins, SetSpacing
+// or AddDefect for an invalid option.
+func NewBinWith(width, height float64, options ...BinOption) (*Bin, error) {
+	var config binConfig
+	for _, option := range options {
+		option(&config)
+	}
+	bin, err := NewBinChecked(width, height, config.placement)
+	if err != nil {
+		return nil, err
+	}
+	bin.Backend = config.backend
+	bin.MaxWeight = config.maxWeight
+	bin.Cost = config.cost
+	bin.Tolerance = config.tolerance
+	bin.TieBreak = config.tieBreak
+	bin.Name, bin.Material, bin.Thickness = config.name, config.material, config.thickness
+	bin.MergeAdjacent = config.mergeAdjacent
+	bin.MinFreeWidth, bin.MinFreeHeight = config.minFreeWidth, config.minFreeHeight
+	if config.margins != (Margins{}) {
+		if err := bin.SetMargins(config.margins); err != nil {
+			return nil, err
+		}
+	}
+	if config.spacing != 0 {
+		if err := bin.SetSpacing(config.spacing); err != nil {
+			return nil, err
+		}
+	}
+	for _, defect := range config.defects {
+		if err := bin.AddDefect(defect.X, defect.Y, defect.Width, defect.Height); err != nil {
+			return nil, err
+		}
+	}
+	bin.settleFreeSpaces()
+	return bin, nil
+}
+
+// WithStrategy sets the bin's placement strategy; nil selects the default.
+func WithStrategy(placement PlacementStrategyFunc) BinOption {
+	return func(c *binConfig) { c.placement = placement }
+}
+
+// WithBackend sets the bin's free-space backend, e.g. a GuillotineBackend.
+func WithBackend(backend Backend) BinOption {
+	return func(c *binConfig) { c.backend = backend }
+}
+
+// WithSpacing sets the gap kept between boxes and around them; see Bin.SetSpacing.
+func WithSpacing(spacing float64) BinOption {
+	return func(c *binConfig) { c.spacing = spacing }
+}
+
+// WithMargins sets the unusable border of the bin; see Bin.SetMargins.
+func WithMargins(margins Margins) BinOption {
+	return func(c *binConfig) { c.margins = margins }
+}
+
+// WithDefect adds a region no box may overlap; see Bin.AddDefect. It may be given
+// several times.
+func WithDefect(x, y, width, height float64) BinOption {
+	return func(c *binConfig) {
+		c.defects = append(c.defects, FreeSpaceBox{X: x, Y: y, Width: width, Height: height})
+	}
+}
+
+// WithWeightLimit sets Bin.MaxWeight, the total Box.Weight the bin holds.
+func WithWeightLimit(maxWeight float64) BinOption {
+	return func(c *binConfig) { c.maxWeight = maxWeight }
+}
+
+// WithCost sets Bin.Cost.
+func WithCost(cost float64) BinOption {
+	return func(c *binConfig) { c.cost = cost }
+}
+
+// WithName sets Bin.Name, the stock item the bin stands for.
+func WithName(name string) BinOption {
+	return func(c *binConfig) { c.name = name }
+}
+
+// WithMaterial sets Bin.Material and Bin.Thickness.
+func WithMaterial(material string, thickness float64) BinOption {
+	return func(c *binConfig) { c.material, c.thickness = material, thickness }
+}
+
+// WithTolerance sets Bin.Tolerance for geometric comparisons.
+func WithTolerance(tolerance float64) BinOption {
+	return func(c *binConfig) { c.tolerance = tolerance }
+}
+
+// WithMinFreeSize sets Bin.MinFreeWidth and Bin.MinFreeHeight, below which free
+// spaces are discarded.
+func WithMinFreeSize(width, height float64) BinOption {
+	return func(c *binConfig) { c.minFreeWidth, c.minFreeHeight = width, height }
+}
+
+// WithTieBreak sets Bin.TieBreak, the free space used when several score the same.
+func WithTieBreak(rule SpaceTieBreak) BinOption {
+	return func(c *binConfig) { c.tieBreak = rule }
+}
+
+// WithMergeAdjacent sets Bin.MergeAdjacent, merging free spaces that share an edge.
+func WithMergeAdjacent() BinOption {
+	return func(c *binConfig) { c.mergeAdjacent = true }
+}
+
+// PackerOption sets a field of PackerOptions, for NewPackerOptions and Packer.PackWith.
+type PackerOption func(*PackerOptions)
+
+// NewPackerOptions returns PackerOptions with the options applied in order to the zero
+// value, e.g. NewPackerOptions(WithLimit(10), WithRestarts(8), WithSeed(1)).
+func NewPackerOptions(options ...PackerOption) PackerOptions {
+	var packerOptions PackerOptions
+	for _, option := range options {
+		option(&packerOptions)
+	}
+	return packerOptions
+}
+
+// PackWith is Pack with the options given as PackerOption values.
+func (p *Packer) PackWith(boxes []*Box, options ...PackerOption) []*Box {
+	return p.Pack(boxes, NewPackerOptions(options...))
+}
+
+// WithLimit sets PackerOptions.Limit, the maximum number of boxes to pack.
+func WithLimit(limit int64) PackerOption {
+	return func(o *PackerOptions) { o.Limit = limit }
+}
+
+// WithSeed sets PackerOptions.Seed for the pre-orderings of Restarts.
+func WithSeed(seed uint64) PackerOption {
+	return func(o *PackerOptions) { o.Seed = seed }
+}
+
+// WithRestarts sets PackerOptions.Restarts.
+func WithRestarts(restarts int) PackerOption {
+	return func(o *PackerOptions) { o.Restarts = restarts }
+}
+
+// WithSortBy sets PackerOptions.SortBy, the order in which the boxes are inserted.
+func WithSortBy(order BoxOrder) PackerOption {
+	return func(o *PackerOptions) { o.SortBy = order }
+}
+
+// WithBinOrder sets PackerOptions.BinOrder, the order in which the bins are filled.
+func WithBinOrder(order BinOrder) PackerOption {
+	return func(o *PackerOptions) { o.BinOrder = order }
+}
+
+// WithAlgorithm sets PackerOptions.Algorithm.
+func WithAlgorithm(algorithm PackingAlgorithm) PackerOption {
+	return func(o *PackerOptions) { o.Algorithm = algorithm }
+}
+
+// WithObjective sets PackerOptions.Objective.
+func WithObjective(objective Objective) PackerOption {
+	return func(o *PackerOptions) { o.Objective = objective }
+}
+
+// WithBinTemplates appends to PackerOptions.BinTemplates and sets MaxBins, the total
+// number of bins the packer may have; zero means no limit.
+func WithBinTemplates(maxBins int, templates ...BinTemplate) PackerOption {
+	return func(o *PackerOptions) {
+		o.BinTemplates = append(o.BinTemplates, templates...)
+		o.MaxBins = maxBins
+	}
+}
+
+// WithTimeLimit sets PackerOptions.TimeLimit.
+func WithTimeLimit(limit time.Duration) PackerOption {
+	return func(o *PackerOptions) { o.TimeLimit = limit }
+}
+
+// WithDeterministic sets PackerOptions.Deterministic.
+func WithDeterministic() PackerOption {
+	return func(o *PackerOptions) { o.Deterministic = true }
+}
+
+// WithParallelism sets PackerOptions.Parallelism.
+func WithParallelism(parallelism int) PackerOption {
+	return func(o *PackerOptions) { o.Parallelism = parallelism }
+}
+
+// WithObserver sets PackerOptions.Observer.
+func WithObserver(observer PackObserver) PackerOption {
+	return func(o *PackerOptions) { o.Observer = observer }
+}
+
+// WithTieBreaks sets PackerOptions.SpaceTieBreak and PackerOptions.BinTieBreak.
+func WithTieBreaks(space SpaceTieBreak, bin BinTieBreak) PackerOption {
+	return func(o *PackerOptions) { o.SpaceTieBreak, o.BinTieBreak = space, bin }
+}
+
+// WithTagOptions sets the overrides for boxes with the given Box.Tag in
+// PackerOptions.TagOptions. It may be given once per tag.
+func WithTagOptions(tag string, options TagOptions) PackerOption {
+	return func(o *PackerOptions) {
+		if o.TagOptions == nil {
+			o.TagOptions = make(map[string]TagOptions)
+		}
+		o.TagOptions[tag] = options
+	}
+}
@@ -0,0 +1,87 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewBinWith(t *testing.T) {
+	bin, err := NewBinWith(100, 80,
+		WithDefect(40, 30, 10, 10), // Given before the backend, applied after it
+		WithBackend(&GuillotineBackend{SplitRule: SplitShorterLeftoverAxis}),
+		WithStrategy(BestAreaFit),
+		WithSpacing(1),
+		WithMargins(Margins{Left: 5}),
+		WithWeightLimit(40),
+		WithCost(3),
+		WithTolerance(1e-9),
+		WithMinFreeSize(2, 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := NewBinChecked(100, 80, BestAreaFit)
+	want.Backend = &GuillotineBackend{SplitRule: SplitShorterLeftoverAxis}
+	want.MaxWeight, want.Cost, want.Tolerance = 40, 3, 1e-9
+	want.MinFreeWidth, want.MinFreeHeight = 2, 2
+	for _, err := range []error{want.SetMargins(Margins{Left: 5}), want.SetSpacing(1), want.AddDefect(40, 30, 10, 10)} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bin.Margins != want.Margins || bin.Spacing != want.Spacing || len(bin.Defects) != 1 ||
+		bin.MaxWeight != 40 || bin.Cost != 3 || bin.Tolerance != 1e-9 || bin.MinFreeWidth != 2 || bin.MinFreeHeight != 2 {
+		t.Errorf("got %+v, want %+v", bin, want)
+	}
+	if _, ok := bin.Backend.(*GuillotineBackend); !ok {
+		t.Errorf("got backend %T, want *GuillotineBackend", bin.Backend)
+	}
+	if got, want := freeSpacesOf(bin), freeSpacesOf(want); len(got) != len(want) {
+		t.Errorf("got free spaces %v, want %v", got, want)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		bin, err := NewBinWith(10, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bin.Placement == nil || bin.Backend != nil || len(bin.FreeSpaces) != 1 {
+			t.Errorf("got %+v, want a plain bin", bin)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := NewBinWith(-1, 10); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("size: got %v, want ErrInvalidDimensions", err)
+		}
+		if _, err := NewBinWith(10, 10, WithSpacing(-1)); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("spacing: got %v, want ErrInvalidDimensions", err)
+		}
+		if _, err := NewBinWith(10, 10, WithMargins(Margins{Left: 20})); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("margins: got %v, want ErrInvalidDimensions", err)
+		}
+	})
+}
+
+func TestPackerOptionFuncs(t *testing.T) {
+	observer := &recordingObserver{}
+	templates := []BinTemplate{{Width: 10, Height: 10}}
+	got := NewPackerOptions(
+		WithLimit(5), WithSeed(7), WithRestarts(3), WithAlgorithm(AlgorithmShelfFirstFit),
+		WithObjective(ObjectiveMinCost), WithBinTemplates(4, templates...), WithTimeLimit(time.Second),
+		WithDeterministic(), WithParallelism(2), WithObserver(observer),
+		WithTagOptions("grain", TagOptions{ConstrainRotation: true}),
+	)
+	if got.Limit != 5 || got.Seed != 7 || got.Restarts != 3 || got.Algorithm != AlgorithmShelfFirstFit ||
+		got.Objective != ObjectiveMinCost || len(got.BinTemplates) != 1 || got.MaxBins != 4 || got.TimeLimit != time.Second ||
+		!got.Deterministic || got.Parallelism != 2 || got.Observer != observer || !got.TagOptions["grain"].ConstrainRotation {
+		t.Errorf("got %+v", got)
+	}
+
+	packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+	packed := packer.PackWith([]*Box{NewBox(5, 5, false), NewBox(5, 5, false)}, WithLimit(1))
+	if len(packed) != 1 {
+		t.Errorf("got %d boxes packed, want the limit of 1", len(packed))
+	}
+}
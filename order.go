@@ -0,0 +1,136 @@
+package binpacking
+
+import (
+	"slices"
+	"time"
+)
+
+// Order is a set of boxes that belong together, e.g. the cartons of one customer order
+// or the parts of one assembly, which should end up in as few bins as possible.
+// An order with an empty ID holds boxes that belong to no order, which are packed normally.
+type Order struct {
+	ID    string
+	Boxes []*Box
+	// NoSplit forbids spreading the order over bins: its boxes all go into one bin, or
+	// none of them is packed.
+	NoSplit bool
+}
+
+// orderGroupPrefix starts the Box.Group of the boxes of orders that may not be split.
+const orderGroupPrefix = "order:"
+
+// PackOrders packs the boxes of the orders like Pack, keeping each order in as few bins
+// as it can. It sets the boxes' OrderID to their order's ID and first packs every order
+// whole into a single bin, like a Box.Group, largest order first. The boxes of orders that
+// fit no bin whole are then packed by themselves, unless the order has NoSplit, with an
+// OrderSplitPenalty larger than any score of the built-in strategies unless the options
+// set one: a box opens another bin for its order only if it fits none the order is in.
+// The boxes of an order with NoSplit keep a Group named "order:" and the ID, which
+// replaces their own; those of other orders keep their own Group, which only applies
+// when the order is split. Like Pack, it returns the boxes packed, over both rounds;
+// PackResult.Orders reports the bins of every order.
+func (p *Packer) PackOrders(orders []*Order, options PackerOptions) []*Box {
+	start := time.Now()
+	boxes := make([]*Box, 0)
+	groups := make(map[*Box]string) // Own groups of the boxes of orders that may be split
+	ordered := false
+	for _, order := range orders {
+		for _, box := range order.Boxes {
+			if box == nil {
+				continue
+			}
+			boxes = append(boxes, box)
+			if order.ID == "" {
+				continue
+			}
+			box.OrderID, ordered = order.ID, true
+			if !order.NoSplit {
+				groups[box] = box.Group
+			}
+			box.Group = orderGroupPrefix + order.ID
+		}
+	}
+	if options.OrderSplitPenalty == 0 && ordered {
+		options.OrderSplitPenalty = orderPenalty(p.Bins, options.BinTemplates)
+	}
+	packed := p.Pack(boxes, options)
+	split := false
+	for box, group := range groups {
+		box.Group = group
+		split = split || !box.Packed
+	}
+	if !split || options.Limit > 0 && int64(len(packed)) >= options.Limit || p.lastTimedOut {
+		p.lastElapsed = time.Since(start)
+		return packed
+	}
+	rest := options
+	if options.Limit > 0 {
+		rest.Limit = options.Limit - int64(len(packed))
+	}
+	if options.TimeLimit > 0 {
+		rest.TimeLimit = max(options.TimeLimit-time.Since(start), time.Nanosecond)
+	}
+	packed = append(packed, p.Pack(p.UnpackedBoxes, rest)...)
+	p.lastPacked = packed
+	p.lastElapsed = time.Since(start)
+	return packed
+}
+
+// orderPenalty returns a split penalty larger than any score a built-in strategy gives a
+// box in one of the bins or the bins made from the templates: the scores are lengths,
+// areas or positions within the bin.
+func orderPenalty(bins []*Bin, templates []BinTemplate) float64 {
+	penalty := 1.0
+	grow := func(width, height float64) {
+		penalty = max(penalty, 2*(width*height+width+height)+1)
+	}
+	for _, bin := range bins {
+		grow(bin.Width, bin.Height)
+	}
+	for _, template := range templates {
+		grow(template.Width, template.Height)
+	}
+	return penalty
+}
+
+// OrderResult reports where the boxes of one order went.
+type OrderResult struct {
+	ID       string
+	Bins     []int // Indices into PackResult.Bins of the bins holding the order's boxes, ascending
+	Packed   int   // Boxes of the order that were packed
+	Unpacked int   // Boxes of the order left unpacked
+}
+
+// Split reports whether the order spans more than one bin.
+func (o OrderResult) Split() bool {
+	return len(o.Bins) > 1
+}
+
+// Orders reports the bins of every order of the last call to Pack, by the boxes'
+// OrderID, in the order the orders first appear in Placements. Boxes without an order
+// are left out.
+func (r *PackResult) Orders() []OrderResult {
+	orders := make([]OrderResult, 0)
+	index := make(map[string]int)
+	for _, placement := range r.Placements {
+		if placement.OrderID == "" {
+			continue
+		}
+		i, ok := index[placement.OrderID]
+		if !ok {
+			i = len(orders)
+			index[placement.OrderID] = i
+			orders = append(orders, OrderResult{ID: placement.OrderID})
+		}
+		order := &orders[i]
+		if !placement.Packed {
+			order.Unpacked++
+			continue
+		}
+		order.Packed++
+		if at, found := slices.BinarySearch(order.Bins, placement.BinIndex); !found {
+			order.Bins = slices.Insert(order.Bins, at, placement.BinIndex)
+		}
+	}
+	return orders
+}
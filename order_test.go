@@ -0,0 +1,142 @@
+package binpacking
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestPackOrders(t *testing.T) {
+	squares := func(n int, side float64) []*Box {
+		boxes := make([]*Box, n)
+		for i := range boxes {
+			boxes[i] = NewBox(side, side, false)
+		}
+		return boxes
+	}
+
+	t.Run("minimize splits", func(t *testing.T) {
+		// Box by box, the best fit puts the small box of b next to the boxes of a, which
+		// leaves no room for its large box.
+		newOrders := func() []*Order {
+			return []*Order{
+				{ID: "a", Boxes: []*Box{NewBox(6, 6, false), NewBox(4, 4, false), NewBox(4, 4, false)}},
+				{ID: "b", Boxes: []*Box{NewBox(6, 6, false), NewBox(4, 4, false)}},
+			}
+		}
+		var boxes []*Box
+		for _, order := range newOrders() {
+			for _, box := range order.Boxes {
+				box.OrderID = order.ID
+				boxes = append(boxes, box)
+			}
+		}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.Pack(boxes, PackerOptions{SortBy: SortAreaDesc})
+		if orders := packer.Result().Orders(); !orders[0].Split() && !orders[1].Split() {
+			t.Fatalf("got %+v from Pack, want a split order", orders)
+		}
+
+		packer = NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		if packed := packer.PackOrders(newOrders(), PackerOptions{}); len(packed) != 5 {
+			t.Fatalf("got %d packed boxes, want 5", len(packed))
+		}
+		for _, order := range packer.Result().Orders() {
+			if order.Split() || order.Unpacked != 0 {
+				t.Errorf("order %s: got %+v, want all boxes in one bin", order.ID, order)
+			}
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("split when needed", func(t *testing.T) {
+		orders := []*Order{{ID: "big", Boxes: squares(6, 5)}, {Boxes: squares(1, 5)}}
+		orders[0].Boxes[0].Group = "own"
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		if packed := packer.PackOrders(orders, PackerOptions{}); len(packed) != 7 {
+			t.Errorf("got %d packed boxes, want 7", len(packed))
+		}
+		got := packer.Result().Orders()
+		if len(got) != 1 || !slices.Equal(got[0].Bins, []int{0, 1}) || got[0].Packed != 6 {
+			t.Errorf("got %+v, want 6 boxes in bins [0 1]", got)
+		}
+		if group := orders[0].Boxes[0].Group; group != "own" {
+			t.Errorf("got group %q, want the box's own", group)
+		}
+		if len(packer.Result().Placements) != 7 {
+			t.Errorf("got %d placements, want both rounds' 7", len(packer.Result().Placements))
+		}
+	})
+
+	t.Run("NoSplit", func(t *testing.T) {
+		orders := []*Order{
+			{ID: "big", Boxes: squares(6, 5), NoSplit: true},
+			{ID: "small", Boxes: squares(2, 5), NoSplit: true},
+		}
+		orders[0].Boxes[0].Group = "own"
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.PackOrders(orders, PackerOptions{})
+		if len(packer.UnpackedBoxes) != 6 {
+			t.Errorf("got %d unpacked boxes, want the 6 of the order too big for a bin", len(packer.UnpackedBoxes))
+		}
+		want := []OrderResult{{ID: "big", Unpacked: 6}, {ID: "small", Bins: []int{0}, Packed: 2}}
+		got := packer.Result().Orders()
+		slices.SortFunc(got, func(a, b OrderResult) int { return cmp.Compare(a.ID, b.ID) })
+		if len(got) != len(want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID || !slices.Equal(got[i].Bins, want[i].Bins) || got[i].Packed != want[i].Packed || got[i].Unpacked != want[i].Unpacked {
+				t.Errorf("got %+v, want %+v", got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("boxes without order", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack(squares(2, 5), PackerOptions{})
+		if got := packer.Result().Orders(); len(got) != 0 {
+			t.Errorf("got orders %+v, want none", got)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var job Job
+		input := `{
+			"bins": [{"width": 10, "height": 10, "qty": 2}],
+			"boxes": [{"width": 5, "height": 5, "qty": 3, "order": "a"}, {"width": 5, "height": 5, "qty": 3, "order": "b"}],
+			"options": {"orders": "nosplit"}
+		}`
+		if err := json.Unmarshal([]byte(input), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := NewJobResult(result)
+		if len(r.Orders) != 2 {
+			t.Fatalf("got orders %+v, want 2", r.Orders)
+		}
+		for _, order := range r.Orders {
+			if len(order.Bins) != 1 || order.Packed != 3 {
+				t.Errorf("order %s: got %+v, want 3 boxes in one bin", order.ID, order)
+			}
+		}
+		if r.Orders[0].Bins[0] == r.Orders[1].Bins[0] {
+			t.Errorf("got both orders in bin %d, want one each", r.Orders[0].Bins[0])
+		}
+		for _, placement := range r.Placements {
+			if placement.Order == "" {
+				t.Errorf("placement %+v: got no order", placement)
+			}
+		}
+		job.Options.Orders = "sometimes"
+		if _, err := job.Pack(); err == nil {
+			t.Error("got no error for an unknown order mode")
+		}
+	})
+}
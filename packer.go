@@ -1,18 +1,168 @@
 package binpacking
 
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
 // PackerOptions defines optional parameters for the packing process.
 type PackerOptions struct {
 	// Limit specifies the maximum number of boxes to pack.
 	// If zero or negative, packing continues until no more boxes fit
 	// or all boxes are packed.
 	Limit int64
+
+	// OrderSplitPenalty is added to the score of placing a box into a bin that holds
+	// none of its order's boxes while other bins already do (see Box.OrderID).
+	// It is a soft objective: a large enough penalty keeps orders together whenever
+	// possible, but a box is still placed in another bin rather than left unpacked.
+	// The value is in the same units as the bins' placement scores. Zero disables it.
+	OrderSplitPenalty float64
+
+	// SortBy, if not SortNone, sorts the boxes before packing and makes the best-fit
+	// algorithm insert them one at a time in that order, each at its best position over
+	// all bins, instead of picking the best scoring box/bin pair at each step. Placing
+	// large boxes first, as SortAreaDesc does, is the classic decreasing heuristic and
+	// often packs denser than the given order. The shelf algorithms sort by height
+	// themselves, so for them SortBy breaks ties between boxes of equal height.
+	SortBy BoxOrder
+
+	// BinOrder selects the order in which the best-fit algorithm fills the bins. The
+	// zero value, BestGlobalFit, places each box wherever it fits best; FillFirstBinFully
+	// closes each bin before starting the next, and RoundRobin takes turns. Bins opened
+	// from BinTemplates are always filled one at a time.
+	BinOrder BinOrder
+
+	// Algorithm selects the packing algorithm. The zero value, AlgorithmBestFit,
+	// uses the scoreboard; the shelf algorithms trade density for speed on very large inputs.
+	Algorithm PackingAlgorithm
+
+	// TagOptions maps Box.Tag values to per-class overrides of the bins' settings,
+	// e.g. a different placement strategy or no rotation for structural parts.
+	// Boxes whose tag is not present use each bin's own settings.
+	TagOptions map[string]TagOptions
+
+	// Objective selects what the best-fit algorithm optimizes when ranking candidate
+	// placements. The zero value, ObjectiveBestFit, ranks by placement score only.
+	Objective Objective
+
+	// Restarts runs the best-fit pack this many extra times, inserting the boxes one at a
+	// time in different pre-orderings (area, longest side and perimeter descending, then
+	// random orderings drawn from Seed), and keeps the best result according to Metric.
+	// The plain scoreboard run, or the SortBy run if set, always takes part, so restarts
+	// never make the result worse. Ignored by the shelf algorithms.
+	Restarts int
+	Seed     uint64     // Seed for the random pre-orderings used by Restarts
+	Metric   PackMetric // How Restarts ranks results; the zero value is MetricPackedArea
+
+	// BinTemplates gives the packer an unlimited supply of bins. Once the packer's own bins
+	// are full, new bins are created from the templates and appended to Packer.Bins until
+	// every box is packed or no template can hold any remaining box.
+	BinTemplates []BinTemplate
+
+	// MaxBins caps the total number of bins, including the packer's own ones, that may
+	// exist once bins are opened from BinTemplates. Boxes that would need more bins are
+	// reported as unpacked. Zero or negative means unlimited.
+	MaxBins int
+
+	// KeepApart lists pairs of boxes, groups or classes that must never share a bin.
+	// Boxes that can only go into a bin holding a conflicting box are left unpacked.
+	KeepApart []KeepApart
+
+	// Guillotine rejects placements after which a bin could no longer be cut apart with
+	// straight edge-to-edge cuts (see ValidateGuillotine), whatever the bins' backends.
+	// Boxes are then tried in other bins, or left unpacked. Shelf layouts always qualify.
+	Guillotine bool
+
+	// TimeLimit bounds the time Pack spends. Once it has passed, no further restarts are
+	// tried, no more bins are opened and no more boxes are placed: Pack returns the best
+	// result found so far, which may leave boxes unpacked that would otherwise fit.
+	// PackResult.Elapsed and PackResult.TimedOut report how the budget was used.
+	// Zero or negative means no limit.
+	TimeLimit time.Duration
+
+	// Deterministic makes the layout depend on nothing but the bins and the set of boxes,
+	// for golden tests and reproducible cut plans: the boxes are put into a canonical order
+	// first (see CanonicalOrder), so the order of the slice passed to Pack does not matter,
+	// and TimeLimit is ignored, since how far packing gets within it depends on the
+	// machine. Ties between equally good placements always go to the earliest candidate in
+	// bin order, then box order. Without it the layout is still the same for the same
+	// slice, as long as TimeLimit does not cut packing short.
+	Deterministic bool
+
+	// AutoPlacement picks the placement strategy for the data: before packing, Pack runs
+	// ChooseStrategy on a sample of the boxes and sets the winner as the Placement of
+	// every bin and template, replacing their own. The bins keep it afterwards.
+	AutoPlacement bool
+
+	// SpaceTieBreak, if not TieFirstSpace, is set as the TieBreak of every bin and
+	// template before packing, replacing their own, and decides which free space a box
+	// goes into when several score the same. The bins keep it afterwards.
+	SpaceTieBreak SpaceTieBreak
+
+	// BinTieBreak decides which bin a box goes into when it fits several equally well,
+	// by default the first. Rules other than TieFirstBin compare bins by their fill,
+	// which makes selecting each placement take time linear in the number of candidates.
+	BinTieBreak BinTieBreak
+
+	// Parallelism is the number of goroutines scoring the scoreboard's box/bin pairs,
+	// which dominates the start of large jobs; see ScoreBoard.Parallelism. Zero or one
+	// scores serially; a negative value uses runtime.GOMAXPROCS. The layout is the same
+	// whatever the value.
+	Parallelism int
+
+	// OnProgress, if set, is called by Pack after each box it places, with the number of
+	// boxes placed so far, the number of boxes the call packs and the efficiency of the bins
+	// in use as in PackResult.Efficiency, e.g. to drive a progress bar. Trial layouts of
+	// Restarts, bin templates and groups are not reported, so placed only ever grows; with
+	// Restarts it starts once the best trial is replayed.
+	OnProgress func(placed, total int, efficiency float64)
+
+	// Observer, if set, is told of every bin Pack opens, box it places and box it leaves
+	// unpacked; see PackObserver.
+	Observer PackObserver
+
+	clock *packClock // Deadline of the current Pack call derived from TimeLimit
+}
+
+// packClock tracks the deadline of a Pack call and whether it cut packing short.
+type packClock struct {
+	deadline time.Time
+	hit      bool
+}
+
+// expired reports whether the time limit of the current Pack call has passed, and
+// records that packing was cut short if so.
+func (o *PackerOptions) expired() bool {
+	if o.clock == nil || time.Now().Before(o.clock.deadline) {
+		return false
+	}
+	o.clock.hit = true
+	return true
 }
 
 // Packer orchestrates the bin packing process by coordinating
 // bins, boxes, and the scoreboard evaluating potential fits.
+// A Packer is not safe for concurrent use; SyncPacker serializes calls to one.
 type Packer struct {
 	Bins          []*Bin // Bins available for packing. Owned/managed by the Packer instance.
 	UnpackedBoxes []*Box // Boxes that could not be packed in the last call to Pack.
+
+	lastPacked   []*Box        // Boxes packed in the last call to Pack, reported by Result
+	lastElapsed  time.Duration // Duration of the last call to Pack
+	lastTimedOut bool          // Whether the last call to Pack ran out of PackerOptions.TimeLimit
+	events       *packEvents   // Reports of the running call to Pack, if any are asked for
+	board        *ScoreBoard   // Scoreboard of the running best-fit loop, kept in step by AddBin and RemoveBin
+
+	// Logger, if set, receives debug records explaining the packer's decisions: the
+	// scoreboard's best entry at each step, the free space each box went into, how many
+	// redundant free spaces were pruned, vetoed and failed insertions, restarts and opened
+	// template bins. Trial layouts are not logged. Nil disables logging.
+	Logger *slog.Logger
+
+	binOffset int // Index of Bins[0] among the bins of the packer this one fills, for logs
 }
 
 // NewPacker creates a new Packer instance with a given set of initial bins.
@@ -29,11 +179,28 @@ func NewPacker(bins []*Bin) *Packer {
 	}
 }
 
-// Pack attempts to pack the given boxes into the packer's bins using a best-fit strategy.
+// Reset clears every bin of the packer with Bin.Clear and forgets the boxes of the last
+// call to Pack, so the packer can be pooled and reused for another set of boxes. Bins
+// opened from templates stay in Bins, empty, and are filled before any new template
+// bin is opened; truncate Bins to drop them. Results taken before the reset describe
+// the cleared bins and should not be used afterwards.
+func (p *Packer) Reset() {
+	for _, bin := range p.Bins {
+		bin.Clear()
+	}
+	p.UnpackedBoxes = make([]*Box, 0)
+	p.lastPacked = nil
+	p.lastElapsed = 0
+	p.lastTimedOut = false
+}
+
+// Pack attempts to pack the given boxes into the packer's bins using a best-fit strategy
+// (or the algorithm selected in options).
 //
 // Args:
 //
 //	boxes: A slice of Box pointers to attempt packing. Boxes marked as Packed=true are skipped.
+//	  Boxes with invalid dimensions (see ValidateDimensions) are reported as unpacked.
 //	options: PackerOptions allowing specification of limits, etc.
 //
 // Returns:
@@ -42,37 +209,271 @@ func NewPacker(bins []*Bin) *Packer {
 //
 // Note: This method updates the Packer's UnpackedBoxes field with boxes that could not be placed.
 func (p *Packer) Pack(boxes []*Box, options PackerOptions) []*Box {
+	start := time.Now()
+	if options.Deterministic {
+		options.TimeLimit = 0
+	}
+	if options.TimeLimit > 0 {
+		options.clock = &packClock{deadline: start.Add(options.TimeLimit)}
+	}
+	defer func() {
+		p.lastElapsed = time.Since(start)
+		p.lastTimedOut = options.clock != nil && options.clock.hit
+	}()
 	packedBoxes := make([]*Box, 0)
 	// We will calculate unpacked boxes at the end.
 
 	// 1. Filter out nil boxes and those already marked as packed.
+	//    Boxes with invalid dimensions can never be placed and are reported as unpacked.
 	boxesToPack := make([]*Box, 0, len(boxes))
+	invalidBoxes := make([]*Box, 0)
 	for _, box := range boxes {
-		if box != nil && !box.Packed {
-			boxesToPack = append(boxesToPack, box)
+		if box == nil || box.Packed {
+			continue
 		}
+		if box.Validate() != nil {
+			invalidBoxes = append(invalidBoxes, box)
+			continue
+		}
+		boxesToPack = append(boxesToPack, box)
+	}
+	if options.Deterministic {
+		CanonicalOrder(boxesToPack)
+	}
+	options.SortBy.Sort(boxesToPack)
+	if options.AutoPlacement && len(boxesToPack) > 0 {
+		options.BinTemplates = p.applyStrategy(ChooseStrategy(p.Bins, boxesToPack, options), options.BinTemplates)
+	}
+	if options.SpaceTieBreak != TieFirstSpace {
+		options.BinTemplates = p.applyTieBreak(options.SpaceTieBreak, options.BinTemplates)
 	}
 
+	p.events = newPackEvents(options, p.Bins, len(boxesToPack))
+	defer func() { p.events = nil }()
+
 	// Return early if no boxes need packing.
 	if len(boxesToPack) == 0 {
-		p.UnpackedBoxes = make([]*Box, 0) // Ensure it's empty
+		p.UnpackedBoxes = invalidBoxes
+		p.lastPacked = packedBoxes
+		p.rejectUnpacked(options)
 		return packedBoxes
 	}
 
-	// 2. Determine packing limit
+	// 2. Place grouped boxes (Box.Group) first, a whole group per bin, then run the selected
+	//    packing algorithm on the rest, opening bins from templates while boxes remain.
+	packedBoxes, ungrouped := p.packGroups(boxesToPack, options)
+	rest := options
+	if options.Limit > 0 {
+		rest.Limit = options.Limit - int64(len(packedBoxes))
+	}
+	if len(ungrouped) > 0 && (options.Limit <= 0 || rest.Limit > 0) {
+		restPacked := p.run(ungrouped, rest)
+		if len(options.BinTemplates) > 0 {
+			restPacked = append(restPacked, p.packFromTemplates(ungrouped, restPacked, rest)...)
+		}
+		packedBoxes = append(packedBoxes, restPacked...)
+	}
+
+	// 3. Determine which boxes remain unpacked by comparing the initial
+	//    list of boxes considered for packing with the list of successfully packed boxes.
+	packedBoxSet := make(map[*Box]struct{}, len(packedBoxes))
+	for _, packedBox := range packedBoxes {
+		packedBoxSet[packedBox] = struct{}{}
+	}
+
+	// Initialize/clear UnpackedBoxes for this run
+	p.UnpackedBoxes = make([]*Box, 0, len(boxesToPack)-len(packedBoxes)+len(invalidBoxes))
+	for _, initialBox := range boxesToPack { // Iterate over boxes *considered* for packing
+		if _, wasPacked := packedBoxSet[initialBox]; !wasPacked {
+			// If a box from the initial 'toPack' list is NOT in the 'packed' set, it's unpacked.
+			p.UnpackedBoxes = append(p.UnpackedBoxes, initialBox)
+		}
+	}
+	p.UnpackedBoxes = append(p.UnpackedBoxes, invalidBoxes...)
+	p.lastPacked = packedBoxes
+	p.rejectUnpacked(options)
+
+	return packedBoxes
+}
+
+// rejectUnpacked reports the boxes left unpacked by Pack to the observer, with the
+// reason each was not packed.
+func (p *Packer) rejectUnpacked(options PackerOptions) {
+	if p.events == nil || p.events.observer == nil {
+		return
+	}
+	for _, box := range p.UnpackedBoxes {
+		p.events.reject(box, p.rejectReason(box, options))
+	}
+}
+
+// rejectReason returns why Pack left the box unpacked: its Box.Validate error,
+// ErrLimitReached, ErrTimeLimit, ErrWrongMaterial or ErrExceedsWeight if a bin has room
+// for it but is of another material or lacks the weight capacity, or ErrNoFit.
+func (p *Packer) rejectReason(box *Box, options PackerOptions) error {
+	switch err := box.Validate(); {
+	case err != nil:
+		return err
+	case options.Limit > 0 && int64(len(p.lastPacked)) >= options.Limit:
+		return ErrLimitReached
+	case options.clock != nil && options.clock.hit:
+		return ErrTimeLimit
+	}
+	for _, bin := range p.Bins {
+		if bin == nil || bin.accepts(box) {
+			continue
+		}
+		unlimited, anyMaterial := *bin, *box
+		unlimited.MaxWeight, anyMaterial.Material = 0, ""
+		if unlimited.placementFor(&anyMaterial, tagOptionsFor(options.TagOptions, box)).Fits {
+			if !bin.fitsMaterial(box) {
+				return ErrWrongMaterial
+			}
+			return ErrExceedsWeight
+		}
+	}
+	return ErrNoFit
+}
+
+// PackChecked is Pack reporting the boxes it leaves unpacked as an error: nil if every
+// box was packed, otherwise one error per unpacked box, joined with errors.Join, naming
+// the box and wrapping the reason it was not packed, as passed to
+// PackObserver.OnBoxRejected. errors.Is matches the joined error against any of the
+// reasons, such as ErrNoFit or ErrInvalidDimensions.
+func (p *Packer) PackChecked(boxes []*Box, options PackerOptions) ([]*Box, error) {
+	packed := p.Pack(boxes, options)
+	options.clock = &packClock{hit: p.lastTimedOut} // As seen by the call just made
+	var errs []error
+	for _, box := range p.UnpackedBoxes {
+		errs = append(errs, fmt.Errorf("box %s: %w", box.Label(), p.rejectReason(box, options)))
+	}
+	return packed, errors.Join(errs...)
+}
+
+// run packs boxes into the packer's bins with the algorithm selected in options.
+func (p *Packer) run(boxesToPack []*Box, options PackerOptions) []*Box {
+	switch options.Algorithm {
+	case AlgorithmShelfNextFit, AlgorithmShelfFirstFit:
+		return p.packShelves(boxesToPack, options)
+	}
+
+	if options.Objective == ObjectivePreferValue && p.fitsAll(boxesToPack, options) {
+		options.Objective = ObjectiveBestFit // Everything fits; value does not matter
+	}
+	var packedBoxes []*Box
+	if options.Restarts > 0 {
+		packedBoxes = p.packMultiStart(boxesToPack, options)
+	} else {
+		packedBoxes = p.packBestFit(boxesToPack, options, options.SortBy != SortNone)
+	}
+	if options.Objective == ObjectiveMinCost {
+		p.downgradeBins(packedBoxes, options.TagOptions)
+	}
+	return packedBoxes
+}
+
+// packBestFit packs boxes with the scoreboard: at each step the globally best
+// box/bin pairing is inserted, then the scores of the modified bin are refreshed.
+// With sequential set, the boxes are instead inserted one at a time in the given
+// order, each at its best position over all bins. The bins are filled in the order
+// selected by options.BinOrder, and boxes of a higher Box.Priority are all tried before
+// any box of a lower one, required boxes before the others of their priority.
+func (p *Packer) packBestFit(boxesToPack []*Box, options PackerOptions, sequential bool) []*Box {
+	if options.BinOrder == FillFirstBinFully && len(p.Bins) > 1 {
+		return p.packBinByBin(boxesToPack, options, sequential)
+	}
+	if tiers := priorityTiers(boxesToPack); tiers != nil {
+		return p.packTiers(tiers, options, sequential)
+	}
+	packedBoxes := make([]*Box, 0)
+
+	// Determine packing limit
 	limit := options.Limit
 	useLimit := limit > 0 // Only use the limit if it's positive
 
-	// 3. Set up the ScoreBoard.
+	// Set up the ScoreBoard.
 	// Use the packer's current set of bins and the filtered list of boxes.
-	board := NewScoreBoard(p.Bins, boxesToPack)
+	// Sequential runs score only the next box of the sequence.
+	var pending []*Box
+	if sequential {
+		boxesToPack, pending = nil, boxesToPack
+	}
+	board := NewParallelScoreBoard(p.Bins, boxesToPack, options.TagOptions, options.Parallelism)
+	p.board = board
+	defer func() { p.board = nil }()
+
+	// Track which bins each order already occupies, including boxes packed in earlier runs.
+	orderBins := make(map[string]map[*Bin]struct{})
+	trackOrder := func(bin *Bin, box *Box) {
+		if box.OrderID == "" {
+			return
+		}
+		if orderBins[box.OrderID] == nil {
+			orderBins[box.OrderID] = make(map[*Bin]struct{})
+		}
+		orderBins[box.OrderID][bin] = struct{}{}
+	}
+	if options.OrderSplitPenalty != 0 {
+		for _, bin := range p.Bins {
+			for _, box := range bin.Boxes {
+				trackOrder(bin, box)
+			}
+		}
+		board.Penalty = func(entry *ScoreBoardEntry) float64 {
+			bins := orderBins[entry.Box.OrderID]
+			if entry.Box.OrderID == "" || len(bins) == 0 {
+				return 0 // First box of an order may go anywhere
+			}
+			if _, ok := bins[entry.Bin]; ok {
+				return 0 // Joining the order's existing bin
+			}
+			return options.OrderSplitPenalty
+		}
+	}
+
+	board.Rank = options.Objective.rank()
+	board.Prefer = options.BinTieBreak.prefer()
+
+	// Entries that would put a box next to one it must be kept apart from are skipped,
+	// as are those for other bins than the one whose turn it is.
+	apart := newSeparation(options.KeepApart, p.Bins)
+	focus := newBinFocus(options.BinOrder, p.Bins)
+	if apart != nil || focus != nil {
+		board.Exclude = func(entry *ScoreBoardEntry) bool {
+			return !focus.allows(entry.Bin) || !apart.allows(entry.Bin, entry.Box)
+		}
+	}
+
+	logging := p.debugging()
 
-	// 4. Main packing loop: Continues as long as a best fit can be found.
+	// Main packing loop: Continues as long as a best fit can be found.
 	for {
+		if options.expired() {
+			if logging {
+				p.Logger.Debug("time limit reached", slog.Int("packed", len(packedBoxes)))
+			}
+			break
+		}
+		if sequential && len(board.Entries) == 0 && len(pending) > 0 {
+			board.AddBox(pending[0]) // Score the next box of the sequence
+			pending = pending[1:]
+		}
 		bestEntry := board.BestFit()
 
 		// If BestFit returns nil, no more *fitting* boxes can be placed in any bin.
+		// In a sequential run, the current box is skipped and the next one is scored.
+		// In a RoundRobin run, the turn first passes to the bins the box may still fit.
 		if bestEntry == nil {
+			if focus.skip() {
+				continue
+			}
+			if sequential && len(pending) > 0 {
+				board.clear()
+				continue
+			}
+			if logging {
+				p.Logger.Debug("no box fits", slog.Int("packed", len(packedBoxes)), slog.Int("pending", len(board.Entries)+len(pending)))
+			}
 			break // Exit the packing loop
 		}
 
@@ -88,17 +489,46 @@ func (p *Packer) Pack(boxes []*Box, options PackerOptions) []*Box {
 			continue // Try finding the next best fit
 		}
 
+		// A placement that would make the layout uncuttable is discarded until the bin changes.
+		if options.Guillotine && !keepsGuillotine(bestEntry.Bin, bestEntry.Box, bestEntry.Options) {
+			if logging {
+				p.Logger.Debug("guillotine veto", boxAttr(bestEntry.Box), p.binAttr(bestEntry.Bin), scoreAttr(bestEntry.Score))
+			}
+			bestEntry.Score = NoFit
+			continue
+		}
+
 		// Attempt to insert the chosen box into the chosen bin.
-		inserted := bestEntry.Bin.Insert(bestEntry.Box)
+		var free []FreeSpaceBox
+		var pruned int
+		if logging {
+			p.Logger.Debug("best fit", boxAttr(bestEntry.Box), p.binAttr(bestEntry.Bin), scoreAttr(bestEntry.Score), slog.Int("candidates", len(board.Entries)))
+			free, pruned = freeSpaceValues(bestEntry.Bin), bestEntry.Bin.pruned
+		}
+		inserted := bestEntry.Bin.InsertWith(bestEntry.Box, bestEntry.Options)
 
 		// If insertion failed, remove the box from consideration.
 		if !inserted {
+			if logging {
+				p.Logger.Debug("insert failed", boxAttr(bestEntry.Box), p.binAttr(bestEntry.Bin))
+			}
 			board.RemoveBox(bestEntry.Box)
 			continue // Try the next best fit
 		}
+		if logging {
+			box := bestEntry.Box
+			p.Logger.Debug("placed", boxAttr(box), p.binAttr(bestEntry.Bin),
+				slog.Float64("x", box.X), slog.Float64("y", box.Y), slog.Bool("rotated", box.Rotated),
+				freeSpaceAttr(free, box), slog.Int("freeSpaces", len(bestEntry.Bin.FreeSpaces)),
+				slog.Int("pruned", bestEntry.Bin.pruned-pruned))
+		}
 
 		// Add the successfully placed box to the list of packed boxes for this run.
 		packedBoxes = append(packedBoxes, bestEntry.Box)
+		p.events.place(bestEntry.Bin, bestEntry.Box)
+		trackOrder(bestEntry.Bin, bestEntry.Box)
+		apart.place(bestEntry.Bin, bestEntry.Box)
+		focus.place()
 
 		// Remove the now-packed box from the ScoreBoard so it's not considered again.
 		board.RemoveBox(bestEntry.Box)
@@ -112,21 +542,5 @@ func (p *Packer) Pack(boxes []*Box, options PackerOptions) []*Box {
 		}
 	} // End packing loop
 
-	// 5. Determine which boxes remain unpacked by comparing the initial
-	//    list of boxes considered for packing with the list of successfully packed boxes.
-	packedBoxSet := make(map[*Box]struct{}, len(packedBoxes))
-	for _, packedBox := range packedBoxes {
-		packedBoxSet[packedBox] = struct{}{}
-	}
-
-	// Initialize/clear UnpackedBoxes for this run
-	p.UnpackedBoxes = make([]*Box, 0, len(boxesToPack)-len(packedBoxes))
-	for _, initialBox := range boxesToPack { // Iterate over boxes *considered* for packing
-		if _, wasPacked := packedBoxSet[initialBox]; !wasPacked {
-			// If a box from the initial 'toPack' list is NOT in the 'packed' set, it's unpacked.
-			p.UnpackedBoxes = append(p.UnpackedBoxes, initialBox)
-		}
-	}
-
 	return packedBoxes
 }
@@ -0,0 +1,141 @@
+package binpacking
+
+// ScoreBoardEntry3D pairs a Box3D with a Bin3D and the score of its best placement.
+type ScoreBoardEntry3D struct {
+	Bin   *Bin3D // Bin being considered
+	Box   *Box3D // Box being placed
+	Score Score  // Calculated Score (NoFit initially, then set by Calculate)
+}
+
+// Calculate recomputes the entry's score and returns it.
+func (e *ScoreBoardEntry3D) Calculate() Score {
+	if e.Bin == nil || e.Box == nil {
+		e.Score = NoFit
+		return NoFit
+	}
+	e.Score = e.Bin.ScoreFor(e.Box)
+	return e.Score
+}
+
+// Fit reports whether the entry represents a valid placement.
+func (e *ScoreBoardEntry3D) Fit() bool {
+	return !e.Score.IsNoFit()
+}
+
+// ScoreBoard3D is the three dimensional counterpart of ScoreBoard.
+type ScoreBoard3D struct {
+	Entries []*ScoreBoardEntry3D // All calculated bin/box placement evaluations
+	Bins    []*Bin3D             // The list of available bins
+}
+
+// NewScoreBoard3D creates a scoreboard scoring every box against every bin.
+func NewScoreBoard3D(bins []*Bin3D, boxes []*Box3D) *ScoreBoard3D {
+	sb := &ScoreBoard3D{
+		Entries: make([]*ScoreBoardEntry3D, 0, len(bins)*len(boxes)),
+		Bins:    bins,
+	}
+	for _, bin := range bins {
+		for _, box := range boxes {
+			if bin == nil || box == nil {
+				continue
+			}
+			entry := &ScoreBoardEntry3D{Bin: bin, Box: box, Score: NoFit}
+			entry.Calculate()
+			sb.Entries = append(sb.Entries, entry)
+		}
+	}
+	return sb
+}
+
+// BestFit returns the fitting entry with the lowest score, or nil if none fits.
+func (sb *ScoreBoard3D) BestFit() *ScoreBoardEntry3D {
+	var best *ScoreBoardEntry3D
+	for _, entry := range sb.Entries {
+		if !entry.Fit() {
+			continue
+		}
+		if best == nil || entry.Score.Less(best.Score) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// RemoveBox removes all entries for the box.
+func (sb *ScoreBoard3D) RemoveBox(box *Box3D) {
+	filtered := make([]*ScoreBoardEntry3D, 0, len(sb.Entries))
+	for _, entry := range sb.Entries {
+		if entry.Box != box {
+			filtered = append(filtered, entry)
+		}
+	}
+	sb.Entries = filtered
+}
+
+// RecalculateBin updates the scores of all entries for the bin.
+func (sb *ScoreBoard3D) RecalculateBin(bin *Bin3D) {
+	for _, entry := range sb.Entries {
+		if entry.Bin == bin {
+			entry.Calculate()
+		}
+	}
+}
+
+// Packer3D packs Box3D items into Bin3D containers using the same best-fit scoreboard
+// flow as Packer.
+type Packer3D struct {
+	Bins          []*Bin3D // Bins available for packing
+	UnpackedBoxes []*Box3D // Boxes that could not be packed in the last call to Pack
+}
+
+// NewPacker3D creates a new Packer3D with a copy of the given bin slice.
+func NewPacker3D(bins []*Bin3D) *Packer3D {
+	packerBins := make([]*Bin3D, len(bins))
+	copy(packerBins, bins)
+	return &Packer3D{Bins: packerBins, UnpackedBoxes: make([]*Box3D, 0)}
+}
+
+// Pack attempts to pack the given boxes into the packer's bins and returns the boxes
+// packed by this call. Only PackerOptions.Limit applies to 3D packing.
+// Boxes that are nil, already packed, or have invalid dimensions are not packed;
+// invalid boxes are reported in UnpackedBoxes.
+func (p *Packer3D) Pack(boxes []*Box3D, options PackerOptions) []*Box3D {
+	packedBoxes := make([]*Box3D, 0)
+	p.UnpackedBoxes = make([]*Box3D, 0)
+
+	boxesToPack := make([]*Box3D, 0, len(boxes))
+	for _, box := range boxes {
+		if box == nil || box.Packed {
+			continue
+		}
+		if box.Validate() != nil {
+			p.UnpackedBoxes = append(p.UnpackedBoxes, box)
+			continue
+		}
+		boxesToPack = append(boxesToPack, box)
+	}
+
+	board := NewScoreBoard3D(p.Bins, boxesToPack)
+	for {
+		best := board.BestFit()
+		if best == nil {
+			break
+		}
+		board.RemoveBox(best.Box)
+		if !best.Bin.Insert(best.Box) {
+			continue
+		}
+		packedBoxes = append(packedBoxes, best.Box)
+		board.RecalculateBin(best.Bin)
+		if options.Limit > 0 && int64(len(packedBoxes)) >= options.Limit {
+			break
+		}
+	}
+
+	for _, box := range boxesToPack {
+		if !box.Packed {
+			p.UnpackedBoxes = append(p.UnpackedBoxes, box)
+		}
+	}
+	return packedBoxes
+}
@@ -0,0 +1,73 @@
+package binpacking
+
+import "testing"
+
+func TestPacker3D(t *testing.T) {
+	t.Run("fills a container exactly", func(t *testing.T) {
+		bin := NewBin3D(20, 10, 10, nil)
+		boxes := []*Box3D{NewBox3D(10, 10, 10, false), NewBox3D(10, 10, 10, false), NewBox3D(10, 10, 10, false)}
+		packer := NewPacker3D([]*Bin3D{bin})
+		packed := packer.Pack(boxes, PackerOptions{})
+
+		if len(packed) != 2 {
+			t.Errorf("Packed box count: got %d, want %d", len(packed), 2)
+		}
+		if len(packer.UnpackedBoxes) != 1 {
+			t.Errorf("Unpacked box count: got %d, want %d", len(packer.UnpackedBoxes), 1)
+		}
+		if bin.Efficiency() != 100 {
+			t.Errorf("Bin efficiency: got %.2f, want %.2f", bin.Efficiency(), 100.0)
+		}
+		if len(bin.FreeSpaces) != 0 {
+			t.Errorf("Free space count: got %d, want %d", len(bin.FreeSpaces), 0)
+		}
+	})
+
+	t.Run("rotates across axes to fit", func(t *testing.T) {
+		bin := NewBin3D(30, 10, 20, nil)
+		box := NewBox3D(10, 20, 30, false)
+		if !bin.Insert(box) {
+			t.Fatalf("Insert: got false, want true")
+		}
+		if box.Width != 30 || box.Height != 10 || box.Depth != 20 {
+			t.Errorf("Oriented dimensions: got %s", box.Label())
+		}
+		if box.Orientation != OrientationDWH {
+			t.Errorf("Orientation: got %d, want %d", box.Orientation, OrientationDWH)
+		}
+	})
+
+	t.Run("respects rotation constraints", func(t *testing.T) {
+		constrained := NewBox3D(10, 20, 30, true)
+		upright := NewBox3D(10, 20, 30, false)
+		upright.KeepUpright = true
+		if NewBin3D(30, 10, 20, nil).Insert(constrained) {
+			t.Errorf("Constrained box must not be rotated to fit")
+		}
+		if NewBin3D(30, 10, 20, nil).Insert(upright) {
+			t.Errorf("Upright box must not be tipped over to fit")
+		}
+		if !NewBin3D(30, 20, 10, nil).Insert(upright) || upright.Height != 20 {
+			t.Errorf("Upright box must be rotatable around the vertical axis")
+		}
+	})
+
+	t.Run("places boxes without overlap", func(t *testing.T) {
+		bin := NewBin3D(50, 40, 30, BottomBackLeft)
+		boxes := make([]*Box3D, 0)
+		for i := 0; i < 30; i++ {
+			boxes = append(boxes, NewBox3D(float64(5+i%7), float64(4+i%5), float64(6+i%3), false))
+		}
+		NewPacker3D([]*Bin3D{bin}).Pack(boxes, PackerOptions{})
+		for i, a := range bin.Boxes {
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height && a.Z < c.Z+c.Depth && c.Z < a.Z+a.Depth {
+					t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+				}
+			}
+			if a.X+a.Width > bin.Width || a.Y+a.Height > bin.Height || a.Z+a.Depth > bin.Depth {
+				t.Errorf("Box exceeds bin: %s", a.Label())
+			}
+		}
+	})
+}
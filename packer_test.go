@@ -100,6 +100,46 @@ func TestBin(t *testing.T) {
 			t.Errorf("Box dimensions after insertion: got %fx%f, want %dx%d", bin.Boxes[0].Width, bin.Boxes[0].Height, 100, 50)
 		}
 	})
+
+	t.Run("caps free spaces when limit exceeded", func(t *testing.T) {
+		bin := NewBin(100, 100, nil)
+		bin.MaxFreeSpaces = 2
+		sizes := [][2]float64{{7, 3}, {13, 5}, {4, 11}, {9, 9}, {3, 17}, {21, 2}}
+		for i := 0; i < 24; i++ {
+			bin.Insert(NewBox(sizes[i%len(sizes)][0], sizes[i%len(sizes)][1], true))
+		}
+
+		if !bin.Compacted() {
+			t.Errorf("Compacted: got %v, want %v", bin.Compacted(), true)
+		}
+		if len(bin.FreeSpaces) > 2 {
+			t.Errorf("Free space count: got %d, want <= %d", len(bin.FreeSpaces), 2)
+		}
+		for i, a := range bin.Boxes {
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("Boxes overlap: %s and %s", a.Label(), c.Label())
+				}
+			}
+		}
+	})
+
+	t.Run("scores placements by contact with ContactPointFit", func(t *testing.T) {
+		bin := NewContactPointBin(100, 100)
+		first := NewBox(40, 30, true)
+		second := NewBox(20, 70, true)
+		bin.Insert(first)
+		bin.Insert(second)
+
+		// Below the first box the second one touches the left and bottom edges and the
+		// first box (70+20+20); beside it only the top edge and the first box (20+30).
+		if second.X != 0 || second.Y != 30 {
+			t.Errorf("Second box position: got [%g,%g], want [0,30]", second.X, second.Y)
+		}
+		if got := contactLength(bin, 40, 0, 10, 30); got != 10+30 {
+			t.Errorf("Contact length: got %g, want %g", got, 40.0)
+		}
+	})
 }
 
 func TestPacker(t *testing.T) {
@@ -507,6 +547,204 @@ func TestPacker(t *testing.T) {
 			t.Errorf("Unpacked box count/content mismatch")
 		}
 	})
+
+	t.Run("keeps order together with split penalty", func(t *testing.T) {
+		for _, tt := range []struct {
+			penalty     float64
+			wantBig     int
+			wantSmall   int
+			description string
+		}{
+			{0, 1, 1, "without penalty the 40x40 box takes the exact fit in the small bin"},
+			{1000, 2, 0, "with penalty the 40x40 box joins its order in the big bin"},
+		} {
+			big := NewBin(100, 100, nil)
+			small := NewBin(40, 40, nil)
+			first, second := NewBox(60, 60, false), NewBox(40, 40, false)
+			first.OrderID, second.OrderID = "A", "A"
+			packer := NewPacker([]*Bin{big, small})
+			packer.Pack([]*Box{first}, PackerOptions{})
+			packer.Pack([]*Box{second}, PackerOptions{OrderSplitPenalty: tt.penalty})
+
+			if len(big.Boxes) != tt.wantBig || len(small.Boxes) != tt.wantSmall {
+				t.Errorf("%s: got %d/%d boxes, want %d/%d", tt.description, len(big.Boxes), len(small.Boxes), tt.wantBig, tt.wantSmall)
+			}
+		}
+	})
+
+	t.Run("applies per-tag options", func(t *testing.T) {
+		calls := 0
+		counting := func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+			calls++
+			return BottomLeft(freeSpace, rectWidth, rectHeight)
+		}
+		bin := NewBin(100, 50, nil)
+		structural := NewBox(50, 100, false)
+		structural.Tag = "structural"
+		filler := NewBox(50, 100, false)
+		filler.Tag = "filler"
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack([]*Box{structural, filler}, PackerOptions{TagOptions: map[string]TagOptions{
+			"structural": {ConstrainRotation: true},
+			"filler":     {Placement: counting},
+		}})
+
+		if structural.Packed {
+			t.Errorf("Structural box Packed: got %v, want %v", structural.Packed, false)
+		}
+		if !filler.Packed {
+			t.Errorf("Filler box Packed: got %v, want %v", filler.Packed, true)
+		}
+		if calls == 0 {
+			t.Errorf("Filler strategy was never called")
+		}
+	})
+
+	t.Run("groups results by bin", func(t *testing.T) {
+		bin1 := NewBin(100, 50, nil)
+		bin2 := NewBin(50, 50, nil)
+		bin3 := NewBin(10, 10, nil)
+		bin1.Cost, bin2.Cost, bin3.Cost = 3, 2, 1
+		packer := NewPacker([]*Bin{bin1, bin2, bin3})
+		packer.Pack([]*Box{NewBox(40, 40, false), NewBox(15, 10, false), NewBox(50, 45, false)}, PackerOptions{})
+
+		result := packer.Result()
+		if len(result.Packed) != 3 || len(result.Unpacked) != 0 {
+			t.Fatalf("Result: got %d packed/%d unpacked, want 3/0", len(result.Packed), len(result.Unpacked))
+		}
+		groups := result.ByBin()
+		if len(groups) != 3 {
+			t.Fatalf("Group count: got %d, want %d", len(groups), 3)
+		}
+		if len(groups[0].Boxes) != 2 || len(groups[1].Boxes) != 1 || len(groups[2].Boxes) != 0 {
+			t.Errorf("Group box counts: got %d/%d/%d, want 2/1/0", len(groups[0].Boxes), len(groups[1].Boxes), len(groups[2].Boxes))
+		}
+		if groups[0].UsedArea != 1750 || groups[0].WasteArea != 3250 {
+			t.Errorf("Bin 1 areas: got used %g waste %g, want 1750/3250", groups[0].UsedArea, groups[0].WasteArea)
+		}
+		if groups[0].Cost != 3 || groups[2].Cost != 0 {
+			t.Errorf("Group costs: got %g/%g, want 3/0", groups[0].Cost, groups[2].Cost)
+		}
+		for _, group := range groups {
+			offcutArea := 0.0
+			for _, offcut := range group.Offcuts {
+				offcutArea += offcut.Width * offcut.Height
+			}
+			if got, want := offcutArea+group.UsedArea, group.Bin.Area(); got != want {
+				t.Errorf("Bin %d offcut and used area: got %g, want %g", group.Index+1, got, want)
+			}
+		}
+	})
+
+	t.Run("records placements as values", func(t *testing.T) {
+		bin1 := NewBin(10, 10, nil)
+		bin2 := NewBin(20, 5, nil)
+		tall := NewBox(5, 20, false)
+		fitting := NewBox(10, 10, true)
+		huge := NewBox(50, 50, true)
+		packer := NewPacker([]*Bin{bin1, bin2})
+		packer.Pack([]*Box{fitting, tall, huge}, PackerOptions{})
+
+		result := packer.Result()
+		if len(result.Placements) != 3 {
+			t.Fatalf("Placements: got %d, want %d", len(result.Placements), 3)
+		}
+		want := map[*Box]BoxPlacement{
+			fitting: {Box: fitting, Packed: true, BinIndex: 0, Width: 10, Height: 10, UV: UVRect{U1: 1, V1: 1}},
+			tall:    {Box: tall, Packed: true, BinIndex: 1, Width: 20, Height: 5, Rotated: true, UV: UVRect{U1: 1, V1: 1}},
+			huge:    {Box: huge, BinIndex: -1, Width: 50, Height: 50},
+		}
+		for _, placement := range result.Placements {
+			if placement != want[placement.Box] {
+				t.Errorf("Placement: got %+v, want %+v", placement, want[placement.Box])
+			}
+		}
+		if result.BinsUsed != 2 || result.PackedArea != 200 || result.UnpackedArea != 2500 || result.Efficiency != 100 {
+			t.Errorf("Summary: got %d bins, %g packed, %g unpacked, %g%%", result.BinsUsed, result.PackedArea, result.UnpackedArea, result.Efficiency)
+		}
+
+		// Mutating a box afterwards does not change the snapshot.
+		fitting.X = 99
+		if result.Placements[0].X == 99 || result.Placements[1].X == 99 {
+			t.Errorf("Placements must not follow later mutation")
+		}
+	})
+
+	t.Run("plans without modifying anything", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Insert(NewBox(10, 5, true))
+		boxes := []*Box{NewBox(5, 5, true), NewBox(5, 5, true), NewBox(5, 5, true)}
+		packer := NewPacker([]*Bin{bin})
+
+		plan := packer.Plan(boxes, PackerOptions{})
+		if len(plan.Packed) != 2 || len(plan.Unpacked) != 1 {
+			t.Errorf("Plan: got %d packed/%d unpacked, want 2/1", len(plan.Packed), len(plan.Unpacked))
+		}
+		if plan.Packed[0] != boxes[0] || plan.Placements[0].Box != boxes[0] {
+			t.Errorf("Plan must refer to the original boxes")
+		}
+		if !plan.Placements[0].Packed || plan.Placements[0].Y != 5 {
+			t.Errorf("First placement: got %+v, want packed at y=5", plan.Placements[0])
+		}
+		for _, box := range boxes {
+			if box.Packed || box.X != 0 || box.Y != 0 {
+				t.Errorf("Box %s must be untouched", box.Label())
+			}
+		}
+		if len(bin.Boxes) != 1 || len(packer.UnpackedBoxes) != 0 {
+			t.Errorf("Packer state must be untouched: %d boxes in bin, %d unpacked", len(bin.Boxes), len(packer.UnpackedBoxes))
+		}
+	})
+
+	t.Run("plans what Pack packs with bin-bound strategies", func(t *testing.T) {
+		newBoxes := func() []*Box {
+			return []*Box{
+				NewBox(5, 5, false), NewBox(4, 4, false), NewBox(3, 3, false), NewBox(1, 6, false), NewBox(2, 5, false),
+				NewBox(5, 2, false), NewBox(2, 3, false), NewBox(6, 1, false), NewBox(3, 4, false), NewBox(2, 2, false),
+			}
+		}
+		plan := NewPacker([]*Bin{NewContactPointBin(9, 9)}).Plan(newBoxes(), PackerOptions{})
+		packer := NewPacker([]*Bin{NewContactPointBin(9, 9)})
+		packer.Pack(newBoxes(), PackerOptions{})
+		packed := packer.Result()
+
+		if len(plan.Placements) != len(packed.Placements) {
+			t.Fatalf("Placements: got %d, want %d", len(plan.Placements), len(packed.Placements))
+		}
+		for i, got := range plan.Placements {
+			want := packed.Placements[i]
+			got.Box, want.Box = nil, nil
+			if got != want {
+				t.Errorf("Placement %d: got %+v, want %+v", i, got, want)
+			}
+		}
+	})
+
+	t.Run("respects bin weight capacity", func(t *testing.T) {
+		light := NewBin(100, 100, nil)
+		light.MaxWeight = 10
+		heavy := NewBin(100, 100, nil)
+		boxes := []*Box{
+			{Width: 10, Height: 10, Weight: 6},
+			{Width: 10, Height: 10, Weight: 6},
+			{Width: 10, Height: 10, Weight: 3},
+		}
+		if !light.Insert(boxes[0]) {
+			t.Fatalf("Insert within capacity: got false, want true")
+		}
+		if light.Insert(boxes[1]) || !light.ScoreFor(boxes[1]).IsNoFit() {
+			t.Errorf("Overweight placement must be rejected")
+		}
+
+		packer := NewPacker([]*Bin{light, heavy})
+		packer.Pack(boxes, PackerOptions{})
+		if light.Weight() > light.MaxWeight {
+			t.Errorf("Light bin weight: got %g, want at most %g", light.Weight(), light.MaxWeight)
+		}
+		if len(packer.UnpackedBoxes) != 0 {
+			t.Errorf("Unpacked boxes: got %d, want %d", len(packer.UnpackedBoxes), 0)
+		}
+	})
 }
 
 // newBins creates standard bins used across multiple tests.
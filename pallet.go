@@ -0,0 +1,242 @@
+package binpacking
+
+import "fmt"
+
+// LayerPattern is the arrangement of the cartons in a pallet layer.
+type LayerPattern int
+
+const (
+	// PatternColumn places every carton in the same orientation, in rows and columns,
+	// so the cartons of the layers stack in columns, which bear the most load.
+	PatternColumn LayerPattern = iota
+	// PatternInterlocked splits the layer into two blocks, one of cartons turned by 90
+	// degrees. Successive layers are turned by 180 degrees, so the blocks swap sides and
+	// every layer ties the one below it together.
+	PatternInterlocked
+	// PatternPinwheel arranges four blocks around the center of the layer, alternately
+	// turned, like the blades of a pinwheel. Successive layers are mirrored, so the
+	// seams between the blocks cross.
+	PatternPinwheel
+)
+
+// String returns "column", "interlocked" or "pinwheel".
+func (p LayerPattern) String() string {
+	switch p {
+	case PatternInterlocked:
+		return "interlocked"
+	case PatternPinwheel:
+		return "pinwheel"
+	}
+	return "column"
+}
+
+// PalletLayer builds pallet loads of identical cartons from repeating layers: it tries
+// each pattern on the pallet's footprint and keeps the one holding the most cartons, the
+// earlier pattern on ties, then stacks layers up to MaxHeight.
+type PalletLayer struct {
+	Width, Height float64 // Footprint of the pallet
+	MaxHeight     float64 // Height the load may reach; zero builds a single layer
+	// Patterns are the patterns to try, in order of preference; nil tries them all.
+	Patterns []LayerPattern
+}
+
+// NewPalletLayer creates a PalletLayer for a pallet with the given footprint and height
+// limit, trying every pattern.
+func NewPalletLayer(width, height, maxHeight float64) *PalletLayer {
+	return &PalletLayer{Width: width, Height: height, MaxHeight: maxHeight}
+}
+
+// PalletPlan is a pallet load built by PalletLayer.
+type PalletPlan struct {
+	Pattern  LayerPattern // The pattern of every layer
+	PerLayer int          // Cartons in each layer
+	Layers   []LayerPlan  // Bottom layer first
+}
+
+// Total returns the number of cartons on the pallet.
+func (p *PalletPlan) Total() int {
+	return p.PerLayer * len(p.Layers)
+}
+
+// LayerPlan is one layer of a PalletPlan.
+type LayerPlan struct {
+	Index   int          // Zero-based, from the bottom
+	Z       float64      // Height of the layer's underside above the pallet
+	Pattern LayerPattern // The layer's pattern
+	Flipped bool         // Whether the layer is turned or mirrored against the one below
+	// Cartons are the cartons of the layer as packed boxes on the footprint; Rotated
+	// marks those turned by 90 degrees.
+	Cartons []*Box
+}
+
+// Build lays out cartons of the given footprint and height. A carton of width w and
+// length l may stand either way round on the pallet. The returned error wraps
+// ErrInvalidDimensions if a size is invalid or the carton is empty, and ErrNoFit if no carton fits the footprint
+// or the height limit.
+func (p *PalletLayer) Build(width, length, height float64) (*PalletPlan, error) {
+	for _, size := range [][2]float64{{p.Width, p.Height}, {p.MaxHeight, 0}, {width, length}, {height, 0}} {
+		if err := ValidateDimensions(size[0], size[1]); err != nil {
+			return nil, err
+		}
+	}
+	if width == 0 || length == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: empty %gx%gx%g carton", ErrInvalidDimensions, width, length, height)
+	}
+	patterns := p.Patterns
+	if patterns == nil {
+		patterns = []LayerPattern{PatternColumn, PatternInterlocked, PatternPinwheel}
+	}
+	var best []*Box
+	pattern := PatternColumn
+	for _, candidate := range patterns {
+		if layer := p.layer(candidate, width, length); len(layer) > len(best) {
+			best, pattern = layer, candidate
+		}
+	}
+	layers := 1
+	if p.MaxHeight > 0 {
+		layers = int(p.MaxHeight/height + 1e-9)
+	}
+	if len(best) == 0 || layers == 0 {
+		return nil, fmt.Errorf("%w: no %gx%gx%g carton fits the %gx%g pallet up to %g",
+			ErrNoFit, width, length, height, p.Width, p.Height, p.MaxHeight)
+	}
+
+	plan := &PalletPlan{Pattern: pattern, PerLayer: len(best)}
+	for i := range layers {
+		flipped := pattern != PatternColumn && i%2 == 1
+		layer := LayerPlan{Index: i, Z: float64(i) * height, Pattern: pattern, Flipped: flipped, Cartons: make([]*Box, len(best))}
+		for j, carton := range best {
+			placed := *carton
+			if flipped {
+				placed.X = p.Width - carton.X - carton.Width
+				if pattern == PatternInterlocked {
+					placed.Y = p.Height - carton.Y - carton.Height
+				}
+			}
+			layer.Cartons[j] = &placed
+		}
+		plan.Layers = append(plan.Layers, layer)
+	}
+	return plan, nil
+}
+
+// layer returns the cartons of the best layer of the pattern, or none if the pattern
+// does not apply.
+func (p *PalletLayer) layer(pattern LayerPattern, w, l float64) []*Box {
+	switch pattern {
+	case PatternColumn:
+		upright := p.block(0, 0, p.Width, p.Height, w, l, false)
+		if turned := p.block(0, 0, p.Width, p.Height, l, w, true); len(turned) > len(upright) {
+			return turned
+		}
+		return upright
+	case PatternInterlocked:
+		return p.interlocked(w, l)
+	case PatternPinwheel:
+		return p.pinwheel(w, l)
+	}
+	return nil
+}
+
+// block fills the region at (x, y) of the given size with cartons w wide and l long,
+// in rows and columns from its top-left corner.
+func (p *PalletLayer) block(x, y, width, height, w, l float64, rotated bool) []*Box {
+	cols, rows := patternCount(width, w), patternCount(height, l)
+	cartons := make([]*Box, 0, cols*rows)
+	for r := range rows {
+		for c := range cols {
+			carton := &Box{X: x + float64(c)*w, Y: y + float64(r)*l, Width: w, Height: l, Packed: true, Rotated: rotated}
+			cartons = append(cartons, carton)
+		}
+	}
+	return cartons
+}
+
+// interlocked tries every split of the footprint into two blocks of differently turned
+// cartons, across its width and across its height.
+func (p *PalletLayer) interlocked(w, l float64) []*Box {
+	var best []*Box
+	for _, turn := range [2]bool{false, true} {
+		// The first block holds cartons w wide and l long, the second turned ones.
+		fw, fl := w, l
+		if turn {
+			fw, fl = l, w
+		}
+		for k := 1; float64(k)*fw < p.Width; k++ {
+			split := float64(k) * fw
+			first := p.block(0, 0, split, p.Height, fw, fl, turn)
+			second := p.block(split, 0, p.Width-split, p.Height, fl, fw, !turn)
+			if len(first) > 0 && len(second) > 0 && len(first)+len(second) > len(best) {
+				best = append(first, second...)
+			}
+		}
+		for k := 1; float64(k)*fl < p.Height; k++ {
+			split := float64(k) * fl
+			first := p.block(0, 0, p.Width, split, fw, fl, turn)
+			second := p.block(0, split, p.Width, p.Height-split, fl, fw, !turn)
+			if len(first) > 0 && len(second) > 0 && len(first)+len(second) > len(best) {
+				best = append(first, second...)
+			}
+		}
+	}
+	return best
+}
+
+// pinwheel tries the pinwheels of four blocks: a block of cols by rows cartons w wide in
+// the top-left corner and the same block in the bottom-right one, and a block of cartons
+// turned in the top-right corner and the bottom-left one, so the blocks go round the
+// center of the footprint.
+func (p *PalletLayer) pinwheel(w, l float64) []*Box {
+	var best []*Box
+	for _, turn := range [2]bool{false, true} {
+		fw, fl := w, l
+		if turn {
+			fw, fl = l, w
+		}
+		for cols := 1; float64(cols)*fw < p.Width; cols++ {
+			for rows := 1; float64(rows)*fl < p.Height; rows++ {
+				aw, ah := float64(cols)*fw, float64(rows)*fl // Size of the first block
+				// The turned blocks take the rest of the width beside the first block
+				// and of the height beside the other one.
+				bw := float64(patternCount(p.Width-aw, fl)) * fl
+				bh := float64(patternCount(p.Height-ah, fw)) * fw
+				if bw == 0 || bh == 0 {
+					continue
+				}
+				blocks := [4][]*Box{
+					p.block(0, 0, aw, ah, fw, fl, turn),
+					p.block(p.Width-bw, 0, bw, bh, fl, fw, !turn),
+					p.block(p.Width-aw, p.Height-ah, aw, ah, fw, fl, turn),
+					p.block(0, p.Height-bh, bw, bh, fl, fw, !turn),
+				}
+				cartons := make([]*Box, 0)
+				for _, block := range blocks {
+					cartons = append(cartons, block...)
+				}
+				if len(cartons) > len(best) && !cartonsOverlap(cartons) {
+					best = cartons
+				}
+			}
+		}
+	}
+	return best
+}
+
+// patternCount returns how many lengths of size fit into length.
+func patternCount(length, size float64) int {
+	return max(int(length/size+1e-9), 0)
+}
+
+// cartonsOverlap reports whether any two of the cartons overlap.
+func cartonsOverlap(cartons []*Box) bool {
+	const tol = 1e-9
+	for i, a := range cartons {
+		for _, b := range cartons[i+1:] {
+			if a.X < b.X+b.Width-tol && b.X < a.X+a.Width-tol && a.Y < b.Y+b.Height-tol && b.Y < a.Y+a.Height-tol {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPalletLayer(t *testing.T) {
+	tests := []struct {
+		width, height float64
+		pattern       LayerPattern
+		perLayer      int
+	}{
+		{1200, 800, PatternColumn, 8},
+		{1200, 1000, PatternInterlocked, 10},
+		{1000, 1000, PatternPinwheel, 8},
+	}
+	for _, tt := range tests {
+		pallet := NewPalletLayer(tt.width, tt.height, 1000)
+		plan, err := pallet.Build(400, 300, 250)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Pattern != tt.pattern || plan.PerLayer != tt.perLayer {
+			t.Errorf("%gx%g: got %v with %d per layer, want %v with %d", tt.width, tt.height, plan.Pattern, plan.PerLayer, tt.pattern, tt.perLayer)
+		}
+		if len(plan.Layers) != 4 || plan.Total() != 4*tt.perLayer {
+			t.Errorf("%gx%g: got %d layers and %d cartons, want 4 layers", tt.width, tt.height, len(plan.Layers), plan.Total())
+		}
+		for i, layer := range plan.Layers {
+			if layer.Index != i || layer.Z != float64(i)*250 || layer.Pattern != plan.Pattern {
+				t.Errorf("%gx%g: got layer %d %+v", tt.width, tt.height, i, layer)
+			}
+			if want := plan.Pattern != PatternColumn && i%2 == 1; layer.Flipped != want {
+				t.Errorf("%gx%g: layer %d: got flipped %t, want %t", tt.width, tt.height, i, layer.Flipped, want)
+			}
+			if cartonsOverlap(layer.Cartons) {
+				t.Errorf("%gx%g: layer %d: got overlapping cartons", tt.width, tt.height, i)
+			}
+			for _, carton := range layer.Cartons {
+				if carton.X < 0 || carton.Y < 0 || carton.X+carton.Width > tt.width || carton.Y+carton.Height > tt.height {
+					t.Errorf("%gx%g: layer %d: got carton %s off the pallet", tt.width, tt.height, i, carton.Label())
+				}
+				if turned := carton.Width == 300; carton.Rotated != turned {
+					t.Errorf("%gx%g: layer %d: got carton %s rotated %t", tt.width, tt.height, i, carton.Label(), carton.Rotated)
+				}
+			}
+		}
+	}
+
+	t.Run("Patterns", func(t *testing.T) {
+		pallet := &PalletLayer{Width: 1000, Height: 1000, Patterns: []LayerPattern{PatternColumn}}
+		plan, err := pallet.Build(400, 300, 250)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Pattern != PatternColumn || plan.PerLayer != 6 || len(plan.Layers) != 1 {
+			t.Errorf("got %v with %d per layer in %d layers, want one column layer of 6", plan.Pattern, plan.PerLayer, len(plan.Layers))
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		pallet := NewPalletLayer(1200, 800, 1000)
+		if _, err := pallet.Build(900, 900, 250); !errors.Is(err, ErrNoFit) {
+			t.Errorf("got %v for a carton larger than the pallet, want ErrNoFit", err)
+		}
+		if _, err := pallet.Build(400, 300, 1200); !errors.Is(err, ErrNoFit) {
+			t.Errorf("got %v for a carton taller than the limit, want ErrNoFit", err)
+		}
+		for _, size := range [][3]float64{{-400, 300, 250}, {0, 300, 250}} {
+			if _, err := pallet.Build(size[0], size[1], size[2]); !errors.Is(err, ErrInvalidDimensions) {
+				t.Errorf("%v: got %v, want ErrInvalidDimensions", size, err)
+			}
+		}
+	})
+
+	if got := PatternPinwheel.String(); got != "pinwheel" {
+		t.Errorf("got %q, want pinwheel", got)
+	}
+}
@@ -0,0 +1,137 @@
+package binpacking
+
+import "slices"
+
+// PanelOptions configures Panelize.
+type PanelOptions struct {
+	// Rails are the strips along the edges of the panel reserved for handling, tooling
+	// holes and conveyor clearance; no board is placed on them.
+	Rails Margins
+	// Spacing is the gap between boards, and between the boards and the rails, left for
+	// the router; zero butts the boards against each other for v-scoring.
+	Spacing float64
+	// KeepOuts are regions no board may cover, e.g. around fiducials and tooling holes.
+	KeepOuts []FreeSpaceBox
+	// Options configures the packing of the panel; see Packer.Pack.
+	Options PackerOptions
+}
+
+// BreakoutMethod is how a board is separated from the rest of a panel.
+type BreakoutMethod int
+
+const (
+	// VScore is a groove scored straight across the whole panel, along which it snaps.
+	VScore BreakoutMethod = iota
+	// MouseBite is a row of drilled holes along one board edge, for edges a straight
+	// line across the panel would cut through another board.
+	MouseBite
+)
+
+// String returns "v-score" or "mouse-bite".
+func (m BreakoutMethod) String() string {
+	if m == MouseBite {
+		return "mouse-bite"
+	}
+	return "v-score"
+}
+
+// BreakoutLine is a suggested separation line of a panel, from (X1, Y1) to (X2, Y2).
+type BreakoutLine struct {
+	Method         BreakoutMethod
+	X1, Y1, X2, Y2 float64
+}
+
+// Panel is the result of Panelize.
+type Panel struct {
+	// Bin holds the boards, with the rails as its Margins, the keep-outs as its Defects
+	// and the spacing as its Spacing.
+	Bin       *Bin
+	Breakouts []BreakoutLine // V-scores first, then mouse bites
+	Unplaced  []*Box         // Boards that did not fit
+}
+
+// Panelize packs PCBs onto a panel of the given size, for manufacturing them together:
+// the boards stay off the rails and the keep-outs, the spacing apart, and the panel
+// suggests how to break them out. Every board edge not on the panel's outline gets a
+// v-score where a straight line across the whole panel along it cuts through no board,
+// and a mouse bite along the edge elsewhere. The boards are packed as by Packer.Pack.
+//
+// The returned error wraps ErrInvalidDimensions if the panel size, the rails, the
+// spacing or the size of a keep-out are invalid.
+func Panelize(panelWidth, panelHeight float64, boards []*Box, options PanelOptions) (*Panel, error) {
+	if err := ValidateDimensions(panelWidth, panelHeight); err != nil {
+		return nil, err
+	}
+	bin := NewBin(panelWidth, panelHeight, nil)
+	if err := bin.SetMargins(options.Rails); err != nil {
+		return nil, err
+	}
+	if err := bin.SetSpacing(options.Spacing); err != nil {
+		return nil, err
+	}
+	for _, keepOut := range options.KeepOuts {
+		if err := bin.AddDefect(keepOut.X, keepOut.Y, keepOut.Width, keepOut.Height); err != nil {
+			return nil, err
+		}
+	}
+	packer := NewPacker([]*Bin{bin})
+	packer.Pack(boards, options.Options)
+	return &Panel{Bin: bin, Breakouts: breakoutLines(bin), Unplaced: slices.Clone(packer.UnpackedBoxes)}, nil
+}
+
+// breakoutLines suggests the separation lines of the boxes in bin.
+func breakoutLines(bin *Bin) []BreakoutLine {
+	tol := bin.layoutTolerance()
+	// clear reports whether a straight line across the bin at pos, vertical or not, runs
+	// through no box.
+	clear := func(pos float64, vertical bool) bool {
+		for _, box := range bin.Boxes {
+			start, size := box.Y, box.Height
+			if vertical {
+				start, size = box.X, box.Width
+			}
+			if pos > start+tol && pos < start+size-tol {
+				return false
+			}
+		}
+		return true
+	}
+	scores := make([]BreakoutLine, 0)
+	bites := make([]BreakoutLine, 0)
+	seen := make(map[BreakoutLine]bool)
+	add := func(line BreakoutLine) {
+		if seen[line] {
+			return
+		}
+		seen[line] = true
+		if line.Method == VScore {
+			scores = append(scores, line)
+		} else {
+			bites = append(bites, line)
+		}
+	}
+	for _, box := range bin.Boxes {
+		right, bottom := box.X+box.Width, box.Y+box.Height
+		for _, x := range []float64{box.X, right} {
+			if x <= tol || x >= bin.Width-tol {
+				continue // On the outline of the panel
+			}
+			if clear(x, true) {
+				add(BreakoutLine{VScore, x, 0, x, bin.Height})
+			} else {
+				add(BreakoutLine{MouseBite, x, box.Y, x, bottom})
+			}
+		}
+		for _, y := range []float64{box.Y, bottom} {
+			if y <= tol || y >= bin.Height-tol {
+				continue
+			}
+			if clear(y, false) {
+				add(BreakoutLine{VScore, 0, y, bin.Width, y})
+			} else {
+				add(BreakoutLine{MouseBite, box.X, y, right, y})
+			}
+		}
+	}
+	return append(scores, bites...)
+}
@@ -0,0 +1,84 @@
+package binpacking
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPanelize(t *testing.T) {
+	t.Run("layout", func(t *testing.T) {
+		boards := make([]*Box, 6)
+		for i := range boards {
+			boards[i] = NewBox(30, 20, true)
+		}
+		options := PanelOptions{
+			Rails: Margins{Top: 5, Bottom: 5}, Spacing: 2,
+			KeepOuts: []FreeSpaceBox{{X: 0, Y: 0, Width: 6, Height: 6}, {X: 94, Y: 74, Width: 6, Height: 6}},
+		}
+		panel, err := Panelize(100, 80, boards, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(panel.Bin.Boxes) != 6 || len(panel.Unplaced) != 0 {
+			t.Errorf("got %d boards placed and %d unplaced, want all 6 placed", len(panel.Bin.Boxes), len(panel.Unplaced))
+		}
+		if err := ValidateLayout(panel.Bin); err != nil {
+			t.Error(err)
+		}
+		for _, board := range panel.Bin.Boxes {
+			if board.Y < 7 || board.Y+board.Height > 73 {
+				t.Errorf("got board %s within the spacing of the rails", board.Label())
+			}
+		}
+	})
+
+	t.Run("breakouts", func(t *testing.T) {
+		// A pinwheel: no straight line across the panel separates the boards along
+		// y=40 or y=60, so those edges get mouse bites.
+		bin := NewBin(100, 80, nil)
+		for _, r := range [][4]float64{{0, 0, 60, 40}, {60, 0, 40, 60}, {0, 40, 60, 40}, {60, 60, 40, 20}} {
+			if err := bin.Place(NewBox(r[2], r[3], true), r[0], r[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := []BreakoutLine{
+			{VScore, 60, 0, 60, 80},
+			{MouseBite, 0, 40, 60, 40},
+			{MouseBite, 60, 60, 100, 60},
+		}
+		if got := breakoutLines(bin); !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("v-scores", func(t *testing.T) {
+		boards := []*Box{NewBox(50, 35, true), NewBox(50, 35, true), NewBox(50, 35, true), NewBox(50, 35, true)}
+		panel, err := Panelize(100, 80, boards, PanelOptions{Rails: Margins{Top: 5, Bottom: 5}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range panel.Breakouts {
+			if line.Method != VScore {
+				t.Errorf("got %v %+v, want only v-scores in a grid", line.Method, line)
+			}
+		}
+		// The rails and the middle of the grid are scored off.
+		if got := len(panel.Breakouts); got != 4 {
+			t.Errorf("got %d breakouts %v, want 4", got, panel.Breakouts)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := Panelize(100, 80, nil, PanelOptions{Spacing: -1}); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("got %v, want ErrInvalidDimensions", err)
+		}
+		if _, err := Panelize(100, 80, nil, PanelOptions{KeepOuts: []FreeSpaceBox{{Width: -1, Height: 10}}}); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("got %v for a keep-out, want ErrInvalidDimensions", err)
+		}
+	})
+
+	if got := MouseBite.String(); got != "mouse-bite" {
+		t.Errorf("got %q, want mouse-bite", got)
+	}
+}
@@ -0,0 +1,66 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestParallelism(t *testing.T) {
+	boxes := func() []*Box {
+		rng := rand.New(rand.NewPCG(7, 0))
+		out := make([]*Box, 100)
+		for i := range out {
+			out[i] = &Box{Width: float64(1 + rng.IntN(12)), Height: float64(1 + rng.IntN(12))}
+		}
+		return out
+	}
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(60, 60, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(60, 60, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(60, 60, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(60, 60) },
+	}
+	layout := func(bins []*Bin) []FreeSpaceBox {
+		var out []FreeSpaceBox
+		for _, bin := range bins {
+			for _, box := range bin.Boxes {
+				out = append(out, FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height})
+			}
+		}
+		return out
+	}
+
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			serialBins := []*Bin{newBin(), newBin(), newBin()}
+			NewPacker(serialBins).Pack(boxes(), PackerOptions{})
+			for _, parallelism := range []int{3, -1} {
+				bins := []*Bin{newBin(), newBin(), newBin()}
+				NewPacker(bins).Pack(boxes(), PackerOptions{Parallelism: parallelism})
+				got, want := layout(bins), layout(serialBins)
+				if len(got) != len(want) {
+					t.Fatalf("parallelism %d: got %d boxes, want %d", parallelism, len(got), len(want))
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("parallelism %d: box %d: got %v, want %v", parallelism, i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("scoreboard scores like the serial one", func(t *testing.T) {
+		bins := []*Bin{NewBin(30, 30, nil), NewBin(20, 40, nil), NewBin(5, 5, nil)}
+		serial := NewScoreBoardWithTags(bins, boxes(), nil)
+		parallel := NewParallelScoreBoard(bins, boxes(), nil, 4)
+		if len(parallel.Entries) != len(serial.Entries) {
+			t.Fatalf("got %d entries, want %d", len(parallel.Entries), len(serial.Entries))
+		}
+		for i, entry := range serial.Entries {
+			if parallel.Entries[i].Score != entry.Score {
+				t.Fatalf("entry %d: got %v, want %v", i, parallel.Entries[i].Score, entry.Score)
+			}
+		}
+	})
+}
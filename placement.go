@@ -8,8 +8,8 @@ import (
 // within a set of free spaces, according to a specific placement strategy.
 type PlacementInfo struct {
 	// Score represents the quality of the placement, calculated by a PlacementStrategyFunc.
-	// Lower scores generally indicate better fits. A score of math.MaxInt64 indicates no fit.
-	Score float64
+	// Lower scores generally indicate better fits. NoFit indicates no placement was found.
+	Score Score
 	// ChosenSpace is a pointer to the specific FreeSpaceBox where the placement should occur.
 	// Will be nil if Fits is false.
 	ChosenSpace *FreeSpaceBox
@@ -25,8 +25,65 @@ type PlacementInfo struct {
 
 // PlacementStrategyFunc defines the signature for functions that calculate a score
 // indicating how well a rectangle of given dimensions fits into a specific FreeSpaceBox.
-// Lower scores are considered better fits. The returned score is a single int64 value.
-type PlacementStrategyFunc func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) float64
+// Lower scores are considered better fits; build them with NewScore, or with
+// NewScoreWithTieBreak for a primary criterion plus a tie-breaker. Returning NoFit
+// rejects the placement.
+type PlacementStrategyFunc func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score
+
+// TagOptions overrides a bin's packing settings for boxes carrying a given Box.Tag,
+// so heterogeneous jobs can use a different strategy per class of part within one run.
+type TagOptions struct {
+	// Placement is used instead of the bin's own strategy. Nil keeps the bin's strategy.
+	Placement PlacementStrategyFunc
+	// ConstrainRotation forbids rotation, and mirroring, regardless of the box's own setting.
+	ConstrainRotation bool
+}
+
+// placement returns the strategy to use in bin, honoring the override if any.
+// Safe to call on a nil receiver.
+func (o *TagOptions) placement(bin *Bin) PlacementStrategyFunc {
+	if o != nil && o.Placement != nil {
+		return o.Placement
+	}
+	return bin.Placement
+}
+
+// scoresPerSpace reports whether strategy scores a free space from the space and the box
+// alone, as all built-in strategies but ContactPointFit do. A box's best score over a set
+// of free spaces can then be updated from the spaces that are new.
+func scoresPerSpace(strategy PlacementStrategyFunc) bool {
+	switch placementName(strategy) {
+	case "best-short-side-fit", "best-long-side-fit", "best-area-fit", "bottom-left", "center-out":
+		return true
+	}
+	return false
+}
+
+// candidate returns a copy of the box's size, shape and anchor to evaluate, with the rotation
+// override applied.
+// Safe to call on a nil receiver.
+func (o *TagOptions) candidate(box *Box) *Box {
+	candidate := NewBox(box.Width, box.Height, o.fixedOrientation(box))
+	candidate.Anchor, candidate.Round = box.Anchor, box.Round
+	return candidate
+}
+
+// fixedOrientation reports whether the box must keep its sides as given: the override
+// constrains rotation, or the box does and may not be mirrored instead.
+// Safe to call on a nil receiver.
+func (o *TagOptions) fixedOrientation(box *Box) bool {
+	return o != nil && o.ConstrainRotation || box.ConstrainRotation && !box.AllowMirror
+}
+
+// turn swaps the sides of a box for a placement that needs it: a rotation, or a mirror if
+// the box's rotation is constrained.
+func turn(box *Box) {
+	if box.ConstrainRotation {
+		box.Mirror()
+	} else {
+		box.Rotate()
+	}
+}
 
 // FindBestPlacement iterates through available free spaces to find the best possible
 // position for a given Box, according to the provided PlacementStrategyFunc.
@@ -41,22 +98,73 @@ type PlacementStrategyFunc func(freeSpace *FreeSpaceBox, rectWidth, rectHeight f
 // Returns:
 //
 //	A PlacementInfo struct containing details of the best fit found.
-//	If no fit is possible, PlacementInfo.Fits will be false and Score will be math.MaxInt64.
+//	If no fit is possible, PlacementInfo.Fits will be false and Score will be NoFit.
+//
+// Sizes are compared exactly; bins compare them up to their Tolerance.
 func FindBestPlacement(box *Box, freeSpaces []*FreeSpaceBox, placement PlacementStrategyFunc) PlacementInfo {
-	// Initialize with worst possible score (using float64 max) and Fits=false
-	bestInfo := PlacementInfo{Score: math.MaxFloat64, Fits: false} // <--- FIX HERE
+	return findBestFit(fitSize{box.Width, box.Height, box.ConstrainRotation, 0, TieFirstSpace, nil, nil}, freeSpaces, placement)
+}
+
+// fitSize is the size of a box as it is scored against free spaces. Scoring works on it
+// rather than on a Box so that it needs no heap allocation.
+type fitSize struct {
+	width, height     float64
+	constrainRotation bool
+	tolerance         float64 // Bin.Tolerance, by which a free space may fall short of the size
+	tieBreak          SpaceTieBreak
+	anchor            *anchorRule // Box.Anchor in the bin, or nil
+	// position picks where in a free space the box goes, for strategies that choose it, or
+	// the top-left corner if nil.
+	position func(space *FreeSpaceBox, width, height float64) (float64, float64)
+}
+
+// sizeFor returns the size of box as scored in the bin, with the rotation override of
+// options and the bin's spacing applied, like padded(options.candidate(box)), and the
+// bin's tie-breaking rule and the box's anchor.
+func (b *Bin) sizeFor(box *Box, options *TagOptions) fitSize {
+	size := fitSize{
+		box.Width, box.Height, options.fixedOrientation(box),
+		b.tolerance(), b.TieBreak, b.anchoring(box.Anchor, b.usesMaxRects()), nil,
+	}
+	if b.Spacing > 0 {
+		size.width += b.Spacing
+		size.height += b.Spacing
+	}
+	return size
+}
+
+// corner returns where in space a box of the given size, as oriented, goes: the top-left
+// corner, the point its strategy picks, or for an anchored box the corner its anchor
+// picks, if any.
+func (box fitSize) corner(space *FreeSpaceBox, width, height float64) (float64, float64, bool) {
+	if box.anchor != nil {
+		return box.anchor.position(space, width, height)
+	}
+	if box.position != nil {
+		x, y := box.position(space, width, height)
+		return x, y, true
+	}
+	return space.X, space.Y, true
+}
+
+// findBestFit is FindBestPlacement for a box of the given size, breaking ties between
+// equally scored spaces by its tie-breaking rule and skipping spaces its anchor rules out.
+func findBestFit(box fitSize, freeSpaces []*FreeSpaceBox, placement PlacementStrategyFunc) PlacementInfo {
+	// Initialize with NoFit, which every real placement beats
+	bestInfo := PlacementInfo{Score: NoFit, Fits: false}
 
 	for _, freeSpace := range freeSpaces {
 		// Try placing the box in its original orientation
-		if freeSpace.Width >= box.Width && freeSpace.Height >= box.Height {
-			score := placement(freeSpace, box.Width, box.Height)
+		if freeSpace.Width+box.tolerance >= box.width && freeSpace.Height+box.tolerance >= box.height {
+			x, y, ok := box.corner(freeSpace, box.width, box.height)
+			score := placement(freeSpace, box.width, box.height)
 			// If this placement is better than the best found so far
-			if score < bestInfo.Score {
+			if ok && box.tieBreak.wins(score, freeSpace, &bestInfo) {
 				bestInfo = PlacementInfo{
 					Score:         score,
 					ChosenSpace:   freeSpace,
-					X:             freeSpace.X, // Place at the top-left corner of the chosen free space
-					Y:             freeSpace.Y,
+					X:             x, // Place at the top-left corner of the chosen free space, unless anchored
+					Y:             y,
 					NeedsRotation: false,
 					Fits:          true,
 				}
@@ -64,16 +172,17 @@ func FindBestPlacement(box *Box, freeSpaces []*FreeSpaceBox, placement Placement
 		}
 
 		// Try placing the box in its rotated orientation, if allowed and different dimensions
-		if !box.ConstrainRotation && box.Width != box.Height && freeSpace.Width >= box.Height && freeSpace.Height >= box.Width {
+		if !box.constrainRotation && box.width != box.height && freeSpace.Width+box.tolerance >= box.height && freeSpace.Height+box.tolerance >= box.width {
 			// Calculate score using rotated dimensions
-			score := placement(freeSpace, box.Height, box.Width)
+			x, y, ok := box.corner(freeSpace, box.height, box.width)
+			score := placement(freeSpace, box.height, box.width)
 			// If this placement is better than the best found so far
-			if score < bestInfo.Score {
+			if ok && box.tieBreak.wins(score, freeSpace, &bestInfo) {
 				bestInfo = PlacementInfo{
 					Score:         score,
 					ChosenSpace:   freeSpace,
-					X:             freeSpace.X, // Place at the top-left corner of the chosen free space
-					Y:             freeSpace.Y,
+					X:             x, // Place at the top-left corner of the chosen free space, unless anchored
+					Y:             y,
 					NeedsRotation: true, // Mark that rotation is needed
 					Fits:          true,
 				}
@@ -86,47 +195,101 @@ func FindBestPlacement(box *Box, freeSpaces []*FreeSpaceBox, placement Placement
 
 // BestAreaFit implements the PlacementStrategyFunc interface.
 // It scores placements by minimizing the leftover area in the free space after placing
-// the rectangle. As a tie-breaker, it adds the 'short side fit' (the smaller
-// of the horizontal or vertical leftover dimensions). Lower scores are better.
-func BestAreaFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) float64 {
-	areaFit := freeSpace.Width*freeSpace.Height - rectWidth*rectHeight
+// the rectangle. Ties are broken by the 'short side fit' (the smaller of the horizontal
+// or vertical leftover dimensions). Lower scores are better.
+func BestAreaFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+	// Leftover area expressed as the sum of the two leftover strips. This is equal to
+	// freeW*freeH - rectW*rectH but never subtracts two huge products, so it cannot
+	// produce Inf-Inf for very large dimensions.
+	areaFit := (freeSpace.Width-rectWidth)*freeSpace.Height + rectWidth*(freeSpace.Height-rectHeight)
 	leftOverHoriz := math.Abs(freeSpace.Width - rectWidth)
 	leftOverVert := math.Abs(freeSpace.Height - rectHeight)
 	shortSideFit := math.Min(leftOverHoriz, leftOverVert)
-	// Combine area fit and short side fit into a single score
-	return areaFit + shortSideFit
+	return NewScoreWithTieBreak(areaFit, shortSideFit)
 }
 
 // BestShortSideFit implements the PlacementStrategyFunc interface.
-// It scores placements by minimizing the sum of the leftover dimensions
-// (horizontal gap + vertical gap) in the free space. Lower scores are better.
-// Note: This differs from some BSSF implementations that prioritize minimizing the
-// smaller gap first, then the larger gap as a tie-breaker (lexicographical score).
-func BestShortSideFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) float64 {
+// It scores placements by minimizing the smaller of the leftover dimensions
+// (the "short side fit") in the free space, breaking ties with the larger one.
+// Lower scores are better.
+func BestShortSideFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
 	leftOverHoriz := math.Abs(freeSpace.Width - rectWidth)
 	leftOverVert := math.Abs(freeSpace.Height - rectHeight)
-	// Return the sum of the horizontal and vertical gaps
-	return leftOverHoriz + leftOverVert
+	return NewScoreWithTieBreak(math.Min(leftOverHoriz, leftOverVert), math.Max(leftOverHoriz, leftOverVert))
 }
 
 // BestLongSideFit implements the PlacementStrategyFunc interface.
-// It scores placements primarily by minimizing the larger of the leftover dimensions
-// (the "long side fit") in the free space. Lower scores are better.
-// Note: Due to the single int64 return type limitation, the secondary tie-breaker
-// (minimizing the short side fit) cannot be directly incorporated into the score
-// for lexicographical comparison. This implementation returns only the long side fit value.
-func BestLongSideFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) float64 {
+// It scores placements by minimizing the larger of the leftover dimensions
+// (the "long side fit") in the free space, breaking ties with the smaller one.
+// Lower scores are better.
+func BestLongSideFit(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
 	leftOverHoriz := math.Abs(freeSpace.Width - rectWidth)
 	leftOverVert := math.Abs(freeSpace.Height - rectHeight)
-	// Return the larger gap (long side fit) as the score.
-	return math.Max(leftOverHoriz, leftOverVert)
+	return NewScoreWithTieBreak(math.Max(leftOverHoriz, leftOverVert), math.Min(leftOverHoriz, leftOverVert))
 }
 
 // BottomLeft implements the PlacementStrategyFunc interface.
-// It scores placements based on a combination of the free space's top-left corner (X, Y)
-// and the height of the rectangle being placed. It aims to minimize Y + X + rectHeight.
-// Lower scores indicate preferred placements (lower, then left-er, considering height).
-func BottomLeft(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) float64 {
-	// Score prioritizes lower Y, then lower X, then lower rectangle height?
-	return freeSpace.Y + freeSpace.X + rectHeight
+// It scores placements by the position of the rectangle's far edge (Y + rectHeight),
+// breaking ties with the X coordinate, so boxes settle against the Y=0 edge first
+// and then towards X=0. Lower scores are better.
+func BottomLeft(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+	return NewScoreWithTieBreak(freeSpace.Y+rectHeight, freeSpace.X)
+}
+
+// ContactPointFit returns a PlacementStrategyFunc for bin that scores placements by the
+// length of the box's perimeter touching the bin edges or boxes already in bin. More contact
+// is better, so the score is the negated contact length. This heuristic often produces
+// denser layouts than BestShortSideFit.
+//
+// Unlike the other strategies it needs the bin's state, so it is bound to a bin:
+//
+//	bin := NewBin(w, h, nil)
+//	bin.Placement = ContactPointFit(bin)
+//
+// NewContactPointBin does the same in one call.
+//
+// ContactPointFit is not inlined so that all its strategies share one function, which
+// lets bin serialization recognize and rebind them.
+//
+//go:noinline
+func ContactPointFit(bin *Bin) PlacementStrategyFunc {
+	return func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+		return NewScore(-contactLength(bin, freeSpace.X, freeSpace.Y, rectWidth, rectHeight))
+	}
+}
+
+// NewContactPointBin creates a bin using ContactPointFit as its placement strategy.
+func NewContactPointBin(width, height float64) *Bin {
+	bin := NewBin(width, height, nil)
+	bin.Placement = ContactPointFit(bin)
+	return bin
+}
+
+// contactLength returns how much of the perimeter of the rectangle at (x, y) touches the
+// edges of the bin or the boxes and defects already in it.
+func contactLength(bin *Bin, x, y, width, height float64) float64 {
+	contact := 0.0
+	if x == 0 || x+width == bin.Width {
+		contact += height
+	}
+	if y == 0 || y+height == bin.Height {
+		contact += width
+	}
+	for _, box := range bin.occupied() {
+		if box.X == x+width || box.X+box.Width == x {
+			contact += overlapLength(box.Y, box.Y+box.Height, y, y+height)
+		}
+		if box.Y == y+height || box.Y+box.Height == y {
+			contact += overlapLength(box.X, box.X+box.Width, x, x+width)
+		}
+	}
+	return contact
+}
+
+// overlapLength returns the length of the intersection of intervals [a1, a2] and [b1, b2].
+func overlapLength(a1, a2, b1, b2 float64) float64 {
+	if a2 < b1 || b2 < a1 {
+		return 0
+	}
+	return math.Min(a2, b2) - math.Max(a1, b1)
 }
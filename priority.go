@@ -0,0 +1,59 @@
+package binpacking
+
+import (
+	"cmp"
+	"slices"
+)
+
+// compareTiers orders boxes by Box.Priority, highest first, and required boxes before
+// the others of their priority.
+func compareTiers(a, b *Box) int {
+	return cmp.Or(cmp.Compare(b.Priority, a.Priority), compareBool(b.Required, a.Required))
+}
+
+// priorityTiers splits boxes by Box.Priority and Box.Required in the order of
+// compareTiers, keeping their order within each tier. It returns nil if all the boxes
+// belong to one tier.
+func priorityTiers(boxes []*Box) [][]*Box {
+	mixed := false
+	for _, box := range boxes {
+		if compareTiers(box, boxes[0]) != 0 {
+			mixed = true
+			break
+		}
+	}
+	if !mixed {
+		return nil
+	}
+	sorted := slices.Clone(boxes)
+	slices.SortStableFunc(sorted, compareTiers)
+	var tiers [][]*Box
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && compareTiers(sorted[end], sorted[start]) == 0 {
+			end++
+		}
+		tiers = append(tiers, sorted[start:end])
+		start = end
+	}
+	return tiers
+}
+
+// packTiers is packBestFit for boxes of several priorities: each tier is packed in turn,
+// highest priority first, into the space the tiers before it left, so a box is never
+// passed over for a lower priority one however much better that one fits. Within a tier
+// the boxes compete on score as usual.
+func (p *Packer) packTiers(tiers [][]*Box, options PackerOptions, sequential bool) []*Box {
+	packedBoxes := make([]*Box, 0)
+	for _, tier := range tiers {
+		if options.Limit > 0 && int64(len(packedBoxes)) >= options.Limit || options.expired() {
+			break
+		}
+		round := options
+		if options.Limit > 0 {
+			round.Limit = options.Limit - int64(len(packedBoxes))
+		}
+		packedBoxes = append(packedBoxes, p.packBestFit(tier, round, sequential)...)
+	}
+	return packedBoxes
+}
@@ -0,0 +1,55 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriority(t *testing.T) {
+	t.Run("before better fits", func(t *testing.T) {
+		for _, rush := range []bool{false, true} {
+			exact, rushed := NewBox(10, 10, false), NewBox(6, 6, false)
+			if rush {
+				rushed.Priority = 1
+			}
+			packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+			packer.Pack([]*Box{exact, rushed}, PackerOptions{})
+			if rushed.Packed != rush || exact.Packed == rush {
+				t.Errorf("priority %d: got the rush box packed %v and the exact fit %v", rushed.Priority, rushed.Packed, exact.Packed)
+			}
+		}
+	})
+
+	t.Run("ties broken by score", func(t *testing.T) {
+		boxes := []*Box{{Width: 2, Height: 2, ID: "low"}, {Width: 3, Height: 3, ID: "loose", Priority: 2}, {Width: 10, Height: 4, ID: "tight", Priority: 2}, {Width: 1, Height: 1, ID: "mid", Priority: 1}}
+		observer := &recordingObserver{}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack(boxes, PackerOptions{Observer: observer})
+		var got []string
+		for _, placement := range observer.placements {
+			got = append(got, placement.ID)
+		}
+		if want := []string{"tight", "loose", "mid", "low"}; !slices.Equal(got, want) {
+			t.Errorf("got placements %v, want %v", got, want)
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{
+			Bins:  []JobBin{{Width: 10, Height: 10, Qty: 1}},
+			Boxes: []JobBox{{Width: 10, Height: 10, ID: "exact"}, {Width: 6, Height: 6, ID: "rush", Priority: 1}},
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, placement := range result.Placements {
+			if placement.Packed != (placement.ID == "rush") {
+				t.Errorf("box %s: got packed %v", placement.ID, placement.Packed)
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package binpacking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOnProgress(t *testing.T) {
+	type call struct {
+		placed, total int
+		efficiency    float64
+	}
+	record := func(calls *[]call) func(placed, total int, efficiency float64) {
+		return func(placed, total int, efficiency float64) {
+			*calls = append(*calls, call{placed, total, efficiency})
+		}
+	}
+	boxes := func() []*Box {
+		return []*Box{
+			{Width: 5, Height: 5}, {Width: 5, Height: 5}, {Width: 5, Height: 5},
+			{Width: 5, Height: 5}, {Width: 20, Height: 20}, {Width: -1, Height: 3},
+		}
+	}
+	check := func(t *testing.T, calls []call, packer *Packer, wantTotal int) {
+		t.Helper()
+		packed := len(packer.Result().Packed)
+		if len(calls) != packed {
+			t.Fatalf("got %d calls, want one per packed box (%d)", len(calls), packed)
+		}
+		for i, c := range calls {
+			if c.placed != i+1 || c.total != wantTotal {
+				t.Errorf("call %d: got %d of %d, want %d of %d", i, c.placed, c.total, i+1, wantTotal)
+			}
+		}
+		if got, want := calls[len(calls)-1].efficiency, packer.Result().Efficiency; math.Abs(got-want) > 1e-9 {
+			t.Errorf("got final efficiency %v, want %v", got, want)
+		}
+	}
+
+	t.Run("best fit reports every placed box", func(t *testing.T) {
+		var calls []call
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack(boxes(), PackerOptions{OnProgress: record(&calls)})
+		check(t, calls, packer, 5) // The invalid box is not counted
+		if calls[3].efficiency != 100 {
+			t.Errorf("got efficiency %v, want 100", calls[3].efficiency)
+		}
+	})
+
+	t.Run("restarts report only the replayed layout", func(t *testing.T) {
+		var calls []call
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.Pack(boxes(), PackerOptions{Restarts: 5, OnProgress: record(&calls)})
+		check(t, calls, packer, 5)
+	})
+
+	t.Run("shelves", func(t *testing.T) {
+		var calls []call
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packer.Pack(boxes(), PackerOptions{Algorithm: AlgorithmShelfFirstFit, OnProgress: record(&calls)})
+		check(t, calls, packer, 5)
+	})
+
+	t.Run("template bins and groups", func(t *testing.T) {
+		var calls []call
+		in := boxes()
+		in[0].Group, in[1].Group = "pair", "pair"
+		packer := NewPacker(nil)
+		packer.Pack(in, PackerOptions{
+			BinTemplates: []BinTemplate{{Width: 10, Height: 10}, {Width: 20, Height: 20}},
+			OnProgress:   record(&calls),
+		})
+		check(t, calls, packer, 5)
+		if len(calls) != 5 {
+			t.Errorf("got %d calls, want 5", len(calls))
+		}
+	})
+
+	t.Run("boxes packed earlier count towards the efficiency", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack([]*Box{{Width: 5, Height: 10}}, PackerOptions{})
+		var calls []call
+		packer.Pack([]*Box{{Width: 5, Height: 5}}, PackerOptions{OnProgress: record(&calls)})
+		if len(calls) != 1 || calls[0].efficiency != 75 {
+			t.Errorf("got %v, want one call at 75%%", calls)
+		}
+	})
+}
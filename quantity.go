@@ -0,0 +1,47 @@
+package binpacking
+
+// BoxSpec describes Count identical boxes, so callers do not have to allocate every
+// instance themselves. Box is the template copied for each instance; its position and
+// packing status are reset.
+type BoxSpec struct {
+	Box   Box // Template of every instance, including ID, Tag, Value and Data
+	Count int // Number of instances; zero or negative means none
+}
+
+// Instances returns Count fresh copies of the template, each a distinct *Box that
+// Pack can place independently.
+func (s BoxSpec) Instances() []*Box {
+	if s.Count <= 0 {
+		return nil
+	}
+	instances := make([]*Box, s.Count)
+	template := s.Box
+	template.X, template.Y, template.Packed, template.cluster = 0, 0, false, nil
+	// Allocate the instances in one block; they are still independent boxes.
+	block := make([]Box, s.Count)
+	for i := range block {
+		block[i] = template
+		instances[i] = &block[i]
+	}
+	return instances
+}
+
+// ExpandSpecs returns the instances of all specs, spec by spec.
+func ExpandSpecs(specs []BoxSpec) []*Box {
+	total := 0
+	for _, spec := range specs {
+		total += max(spec.Count, 0)
+	}
+	boxes := make([]*Box, 0, total)
+	for _, spec := range specs {
+		boxes = append(boxes, spec.Instances()...)
+	}
+	return boxes
+}
+
+// PackSpecs expands the specs and packs the instances like Pack. The returned boxes,
+// Packer.Result and Packer.UnpackedBoxes report each instance separately; instances of
+// the same spec share its ID and can be told apart by their positions.
+func (p *Packer) PackSpecs(specs []BoxSpec, options PackerOptions) []*Box {
+	return p.Pack(ExpandSpecs(specs), options)
+}
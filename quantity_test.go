@@ -0,0 +1,40 @@
+package binpacking
+
+import "testing"
+
+func TestBoxSpec(t *testing.T) {
+	t.Run("expands into independent instances", func(t *testing.T) {
+		spec := BoxSpec{Box: Box{Width: 2, Height: 3, ID: "SKU-1", X: 5, Packed: true}, Count: 3}
+		instances := spec.Instances()
+		if len(instances) != 3 {
+			t.Fatalf("Instances: got %d, want %d", len(instances), 3)
+		}
+		instances[0].Rotate()
+		for _, box := range instances[1:] {
+			if box == instances[0] || box.Width != 2 || box.ID != "SKU-1" || box.Packed || box.X != 0 {
+				t.Errorf("Instance: got %+v, want a fresh copy of the template", *box)
+			}
+		}
+		if got := (BoxSpec{Box: spec.Box}).Instances(); len(got) != 0 {
+			t.Errorf("Zero count: got %d instances, want none", len(got))
+		}
+	})
+
+	t.Run("packs every instance", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 15, nil)})
+		packed := packer.PackSpecs([]BoxSpec{
+			{Box: Box{Width: 5, Height: 5, ID: "square"}, Count: 3},
+			{Box: Box{Width: 10, Height: 5, ID: "strip"}, Count: 2},
+		}, PackerOptions{})
+		if len(packed) != 4 || len(packer.UnpackedBoxes) != 1 {
+			t.Fatalf("Packed: got %d packed/%d unpacked, want 4/1", len(packed), len(packer.UnpackedBoxes))
+		}
+		result := packer.Result()
+		if len(result.Placements) != 5 {
+			t.Errorf("Placements: got %d, want %d", len(result.Placements), 5)
+		}
+		if result.Efficiency != 100 {
+			t.Errorf("Efficiency: got %v, want %v", result.Efficiency, 100)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package binpacking
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// layoutPalette is the set of fill colors cycled through when rendering boxes.
+var layoutPalette = []color.RGBA{
+	{0x4e, 0x79, 0xa7, 0xff},
+	{0xf2, 0x8e, 0x2b, 0xff},
+	{0xe1, 0x57, 0x59, 0xff},
+	{0x76, 0xb7, 0xb2, 0xff},
+	{0x59, 0xa1, 0x4f, 0xff},
+	{0xed, 0xc9, 0x48, 0xff},
+	{0xb0, 0x7a, 0xa1, 0xff},
+	{0xff, 0x9d, 0xa7, 0xff},
+}
+
+// RenderImage draws the bin layout as a raster image whose longest side is maxSize pixels.
+// Free area is white, each box is filled with a color from a fixed palette (in placement
+// order) and outlined in black, so the result is deterministic for a given layout.
+func (b *Bin) RenderImage(maxSize int) *image.RGBA {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+	scale := 0.0
+	if longest := max(b.Width, b.Height); longest > 0 {
+		scale = float64(maxSize) / longest
+	}
+	width := max(1, int(b.Width*scale+0.5))
+	height := max(1, int(b.Height*scale+0.5))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	outline := image.NewUniform(color.Black)
+	for i, box := range b.Boxes {
+		rect := image.Rect(
+			int(box.X*scale+0.5), int(box.Y*scale+0.5),
+			int((box.X+box.Width)*scale+0.5), int((box.Y+box.Height)*scale+0.5),
+		).Intersect(img.Bounds())
+		if rect.Empty() {
+			continue // Too small to show at this scale
+		}
+		draw.Draw(img, rect, image.NewUniform(layoutPalette[i%len(layoutPalette)]), image.Point{}, draw.Src)
+
+		// One pixel outline so adjacent boxes stay distinguishable
+		draw.Draw(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+1), outline, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(rect.Min.X, rect.Max.Y-1, rect.Max.X, rect.Max.Y), outline, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+1, rect.Max.Y), outline, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(rect.Max.X-1, rect.Min.Y, rect.Max.X, rect.Max.Y), outline, image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// asciiSymbols are the characters cycled through, in placement order, by DrawASCII.
+const asciiSymbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// DrawASCII renders the bin layout as a character grid inside a frame, for quick
+// inspection in tests and terminal output. Scale is the number of characters per unit
+// of length; zero or negative uses 1. Each cell shows the box covering its center,
+// lettered A-Z, a-z, 0-9 in placement order and then cycling, '#' for defects and
+// margins, and '.' for free area. Boxes smaller than a cell may not show at all.
+//
+// A 4x2 bin holding a 2x2 box at the origin draws as:
+//
+//	+----+
+//	|AA..|
+//	|AA..|
+//	+----+
+func (b *Bin) DrawASCII(scale float64) string {
+	if scale <= 0 {
+		scale = 1
+	}
+	columns := max(0, int(b.Width*scale+0.5))
+	rows := max(0, int(b.Height*scale+0.5))
+	grid := make([][]byte, rows)
+	for r := range grid {
+		grid[r] = make([]byte, columns)
+		for c := range grid[r] {
+			grid[r][c] = '.'
+		}
+	}
+	// fill marks the cells whose centers lie inside the rectangle.
+	fill := func(x, y, width, height float64, symbol byte) {
+		for r := max(0, int(y*scale)); r < rows && (float64(r)+0.5)/scale < y+height; r++ {
+			if (float64(r)+0.5)/scale < y {
+				continue
+			}
+			for c := max(0, int(x*scale)); c < columns && (float64(c)+0.5)/scale < x+width; c++ {
+				if (float64(c)+0.5)/scale >= x {
+					grid[r][c] = symbol
+				}
+			}
+		}
+	}
+	for _, region := range b.Defects {
+		fill(region.X, region.Y, region.Width, region.Height, '#')
+	}
+	for _, region := range b.marginStrips() {
+		fill(region.X, region.Y, region.Width, region.Height, '#')
+	}
+	for i, box := range b.Boxes {
+		fill(box.X, box.Y, box.Width, box.Height, asciiSymbols[i%len(asciiSymbols)])
+	}
+
+	var sb strings.Builder
+	frame := "+" + strings.Repeat("-", columns) + "+\n"
+	sb.WriteString(frame)
+	for _, row := range grid {
+		sb.WriteByte('|')
+		sb.Write(row)
+		sb.WriteString("|\n")
+	}
+	sb.WriteString(frame)
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package binpacking
+
+import "testing"
+
+func TestDrawASCII(t *testing.T) {
+	t.Run("draws boxes, defects and free area", func(t *testing.T) {
+		bin := NewBin(6, 3, nil)
+		if err := bin.Place(NewBox(2, 2, true), 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := bin.Place(NewBox(3, 1, true), 2, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := bin.AddDefect(5, 2, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+		want := "+------+\n" +
+			"|AABBB.|\n" +
+			"|AA....|\n" +
+			"|.....#|\n" +
+			"+------+\n"
+		if got := bin.DrawASCII(1); got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("scales the grid", func(t *testing.T) {
+		bin := NewBin(40, 20, nil)
+		if err := bin.Place(NewBox(20, 10, true), 20, 10); err != nil {
+			t.Fatal(err)
+		}
+		want := "+----+\n" +
+			"|....|\n" +
+			"|..AA|\n" +
+			"+----+\n"
+		if got := bin.DrawASCII(0.1); got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package binpacking
+
+import "slices"
+
+// Repack defragments the bin in place: it takes the boxes out and inserts them again,
+// largest first in CanonicalOrder, so free space scattered between boxes placed over
+// time, as in an incrementally built sprite atlas, is gathered into larger rectangles.
+// Locked boxes stay where they are and the others are packed around them.
+//
+// Repacking the same boxes never changes Efficiency, so the layout is judged by the
+// largest of the bin's Offcuts instead. Repack reports whether that rectangle grew; if
+// not, or if any box no longer fits, the bin and its boxes are restored exactly as they
+// were. Boxes may move and rotate, so positions read from them before are stale once
+// Repack returns true.
+func (b *Bin) Repack() bool {
+	if len(b.Boxes) == 0 {
+		return false
+	}
+	saved := b.scratch()
+	states := make([]Box, len(b.Boxes))
+	for i, box := range b.Boxes {
+		states[i] = *box
+	}
+	before := largestOffcut(b)
+
+	boxes := slices.Clone(b.Boxes)
+	for _, box := range boxes {
+		box.Packed = false
+	}
+	b.reset()
+	ok := true
+	var movable []*Box
+	for _, box := range boxes {
+		if !box.Locked {
+			movable = append(movable, box)
+		} else if b.Place(box, box.X, box.Y) != nil {
+			ok = false
+		}
+	}
+	CanonicalOrder(movable)
+	for _, box := range movable {
+		if !ok {
+			break
+		}
+		ok = b.Insert(box)
+	}
+
+	if ok && largestOffcut(b) > before && !closeTo(largestOffcut(b), before) {
+		return true
+	}
+	// Restore the layout: the bin's state from the copy, then every box and, through
+	// the cluster's bounding box, its members.
+	*b = *saved
+	for i, box := range b.Boxes {
+		*box = states[i]
+		if box.cluster != nil {
+			box.cluster.place()
+		}
+	}
+	b.indexFreeSpaces()
+	b.touch() // The free spaces are the copy's, not those scored before
+	return false
+}
+
+// largestOffcut returns the area of the bin's largest offcut, zero if it has none.
+func largestOffcut(b *Bin) float64 {
+	offcuts := b.Offcuts()
+	if len(offcuts) == 0 {
+		return 0
+	}
+	return offcuts[0].Width * offcuts[0].Height
+}
@@ -0,0 +1,104 @@
+package binpacking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRepack(t *testing.T) {
+	// Boxes placed over time at scattered positions leave only small free rectangles.
+	fragmented := func() (*Bin, []*Box) {
+		bin := NewBin(10, 10, nil)
+		var boxes []*Box
+		for _, pos := range [][2]float64{{1, 1}, {6, 2}, {2, 6}, {7, 7}} {
+			box := NewBox(2, 2, false)
+			if err := bin.Place(box, pos[0], pos[1]); err != nil {
+				t.Fatal(err)
+			}
+			box.Locked = false // Placed by hand here, but free to move
+			boxes = append(boxes, box)
+		}
+		return bin, boxes
+	}
+
+	t.Run("consolidates free space", func(t *testing.T) {
+		bin, boxes := fragmented()
+		before := largestOffcut(bin)
+		if !bin.Repack() {
+			t.Fatal("got no improvement, want the free space consolidated")
+		}
+		if after := largestOffcut(bin); after <= before {
+			t.Errorf("got largest offcut %g, want more than %g", after, before)
+		}
+		if len(bin.Boxes) != len(boxes) {
+			t.Errorf("got %d boxes, want %d", len(bin.Boxes), len(boxes))
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+		if !bin.Insert(NewBox(6, 10, false)) {
+			t.Error("got no room for a 6x10 box after repacking")
+		}
+	})
+
+	t.Run("no improvement restores the layout", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		for i := 0; i < 3; i++ {
+			bin.Insert(NewBox(3, 4, false))
+		}
+		want := layoutOf([]*Bin{bin})
+		spaces := freeSpacesOf(bin)
+		if bin.Repack() {
+			t.Fatal("got an improvement for a compact layout")
+		}
+		if got := layoutOf([]*Bin{bin}); !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if got := freeSpacesOf(bin); len(got) != len(spaces) {
+			t.Errorf("got %d free spaces, want %d", len(got), len(spaces))
+		}
+		if !bin.Insert(NewBox(3, 4, false)) {
+			t.Error("got no room for another box after restoring")
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("locked boxes stay", func(t *testing.T) {
+		bin, boxes := fragmented()
+		boxes[3].Locked = true
+		if !bin.Repack() {
+			t.Fatal("got no improvement")
+		}
+		if boxes[3].X != 7 || boxes[3].Y != 7 || !boxes[3].Locked {
+			t.Errorf("got the locked box at [%g,%g], want [7,7]", boxes[3].X, boxes[3].Y)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("clusters move with their members", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		a, b := NewBox(2, 2, true), NewBox(2, 2, true)
+		b.X = 2
+		cluster := NewCluster([]*Box{a, b}, true)
+		if err := bin.Place(cluster.Box, 5, 5); err != nil {
+			t.Fatal(err)
+		}
+		cluster.Box.Locked = false
+		if !bin.Repack() {
+			t.Fatal("got no improvement")
+		}
+		if a.X != cluster.Box.X || b.X != cluster.Box.X+2 || a.Y != cluster.Box.Y {
+			t.Errorf("got members at [%g,%g] and [%g,%g], cluster at [%g,%g]", a.X, a.Y, b.X, b.Y, cluster.Box.X, cluster.Box.Y)
+		}
+	})
+
+	t.Run("empty bin", func(t *testing.T) {
+		if NewBin(10, 10, nil).Repack() {
+			t.Error("got an improvement for an empty bin")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package binpacking
+
+// BinReport describes the utilization of a bin, for dashboards and monitoring; it
+// encodes to JSON with the same field style as JobResult.
+type BinReport struct {
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Cost       float64 `json:"cost,omitempty"`
+	Name       string  `json:"name,omitempty"` // Bin.Name, Material and Thickness of the stock
+	Material   string  `json:"material,omitempty"`
+	Thickness  float64 `json:"thickness,omitempty"`
+	Boxes      int     `json:"boxes"`
+	Efficiency float64 `json:"efficiency"` // Percentage of the bin's area occupied by boxes
+	UsedArea   float64 `json:"usedArea"`
+	FreeArea   float64 `json:"freeArea"` // Area not occupied by boxes, including margins and defects
+	Weight     float64 `json:"weight,omitempty"`
+	MaxWeight  float64 `json:"maxWeight,omitempty"`
+
+	// LargestFree is the largest of the bin's Offcuts, the biggest rectangle that could
+	// still be cut from it; zero if there is none.
+	LargestFree ReportRect `json:"largestFree"`
+
+	Placements []BoxReport `json:"placements"` // The boxes in placement order
+}
+
+// ReportRect is a rectangle of a BinReport.
+type ReportRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// BoxReport is a box of a BinReport, as placed.
+type BoxReport struct {
+	ID string `json:"id,omitempty"`
+	ReportRect
+	Rotated  bool `json:"rotated"`
+	Mirrored bool `json:"mirrored,omitempty"`
+}
+
+// Report describes the bin's utilization as it is now.
+func (b *Bin) Report() BinReport {
+	report := BinReport{
+		Width: b.Width, Height: b.Height, Cost: b.Cost, Boxes: len(b.Boxes),
+		Name: b.Name, Material: b.Material, Thickness: b.Thickness,
+		Efficiency: b.Efficiency(), MaxWeight: b.MaxWeight,
+		Placements: make([]BoxReport, 0, len(b.Boxes)),
+	}
+	for _, box := range b.Boxes {
+		report.UsedArea += box.Area()
+		report.Weight += box.Weight
+		report.Placements = append(report.Placements, BoxReport{
+			ID: box.ID, ReportRect: ReportRect{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}, Rotated: box.Rotated, Mirrored: box.Mirrored,
+		})
+	}
+	report.FreeArea = b.Area() - report.UsedArea
+	if offcuts := b.Offcuts(); len(offcuts) > 0 {
+		largest := offcuts[0]
+		report.LargestFree = ReportRect{X: largest.X, Y: largest.Y, Width: largest.Width, Height: largest.Height}
+	}
+	return report
+}
@@ -0,0 +1,46 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBinReport(t *testing.T) {
+	bin := NewBin(10, 10, nil)
+	bin.Cost, bin.MaxWeight = 4, 50
+	box := NewBox(10, 4, true)
+	box.ID, box.Weight = "a", 3
+	if err := bin.Place(box, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	report := bin.Report()
+	if report.Boxes != 1 || report.Efficiency != 40 || report.UsedArea != 40 || report.FreeArea != 60 || report.Weight != 3 {
+		t.Errorf("got %+v, want one box using 40%% of the bin", report)
+	}
+	if want := (ReportRect{X: 0, Y: 4, Width: 10, Height: 6}); report.LargestFree != want {
+		t.Errorf("got largest free %+v, want %+v", report.LargestFree, want)
+	}
+	if len(report.Placements) != 1 || report.Placements[0].ID != "a" || report.Placements[0].Width != 10 {
+		t.Errorf("got placements %+v", report.Placements)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"width":10,"height":10,"cost":4,"boxes":1,"efficiency":40,"usedArea":40,"freeArea":60,"weight":3,"maxWeight":50,` +
+		`"largestFree":{"x":0,"y":4,"width":10,"height":6},"placements":[{"id":"a","x":0,"y":0,"width":10,"height":4,"rotated":false}]}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := json.Marshal(NewBin(0, 0, nil).Report())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"width":0,"height":0,"boxes":0,"efficiency":0,"usedArea":0,"freeArea":0,"largestFree":{"x":0,"y":0,"width":0,"height":0},"placements":[]}`; string(data) != want {
+			t.Errorf("got %s, want %s", data, want)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRequired means a box with Box.Required set was left unpacked; see PackRequired.
+var ErrRequired = errors.New("binpacking: required box not packed")
+
+// PackRequired is Pack failing hard when a box that must be packed is not: if any box with
+// Box.Required set is left unpacked, the returned error holds one error per such box,
+// joined with errors.Join, naming the box by ID and size and wrapping both ErrRequired and the reason it
+// was not packed, as passed to PackObserver.OnBoxRejected. Other boxes are left unpacked
+// silently, as with Pack. The layout is kept either way; Reset discards it.
+func (p *Packer) PackRequired(boxes []*Box, options PackerOptions) ([]*Box, error) {
+	packed := p.Pack(boxes, options)
+	return packed, p.requiredError(options)
+}
+
+// requiredError returns the error of PackRequired for the last call to Pack.
+func (p *Packer) requiredError(options PackerOptions) error {
+	options.clock = &packClock{hit: p.lastTimedOut} // As seen by the last call
+	var errs []error
+	for _, box := range p.UnpackedBoxes {
+		if box.Required {
+			name := box.Label()
+			if box.ID != "" {
+				name = fmt.Sprintf("%q (%s)", box.ID, name)
+			}
+			errs = append(errs, fmt.Errorf("%w: box %s: %w", ErrRequired, name, p.rejectReason(box, options)))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPackRequired(t *testing.T) {
+	t.Run("offenders", func(t *testing.T) {
+		must := &Box{Width: 6, Height: 6, ID: "must", Required: true}
+		huge := &Box{Width: 20, Height: 20, ID: "huge", Required: true}
+		optional := &Box{Width: 11, Height: 11, ID: "optional"}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		packed, err := packer.PackRequired([]*Box{optional, huge, must}, PackerOptions{})
+		if len(packed) != 1 || packed[0] != must {
+			t.Errorf("got %d boxes packed, want only the required one that fits", len(packed))
+		}
+		if !errors.Is(err, ErrRequired) || !errors.Is(err, ErrNoFit) {
+			t.Fatalf("got %v, want ErrRequired and ErrNoFit", err)
+		}
+		if msg := err.Error(); !strings.Contains(msg, "huge") || strings.Contains(msg, "optional") {
+			t.Errorf("got %q, want only the required box named", msg)
+		}
+	})
+
+	t.Run("placed before the others", func(t *testing.T) {
+		exact, must := NewBox(10, 10, false), &Box{Width: 6, Height: 6, Required: true}
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil)})
+		if _, err := packer.PackRequired([]*Box{exact, must}, PackerOptions{}); err != nil {
+			t.Errorf("got %v, want the required box packed ahead of the better fit", err)
+		}
+		if _, err := packer.PackRequired([]*Box{NewBox(3, 3, false)}, PackerOptions{}); err != nil {
+			t.Errorf("got %v for an optional box that does not fit", err)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		body := `{"bins": [{"width": 10, "height": 10, "qty": 1}], "boxes": [{"width": 5, "height": 5, "qty": 5, "id": "a", "required": true}]}`
+		var job Job
+		if err := json.Unmarshal([]byte(body), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if !errors.Is(err, ErrRequired) || result == nil || result.BinsUsed != 1 {
+			t.Errorf("got %v and result %v, want ErrRequired with the layout", err, result)
+		}
+		rec := httptest.NewRecorder()
+		(&JobHandler{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pack", strings.NewReader(body)))
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("got status %d, want %d: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body.String())
+		}
+	})
+}
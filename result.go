@@ -0,0 +1,226 @@
+package binpacking
+
+import "time"
+
+// PackResult summarizes the outcome of the last call to Packer.Pack.
+type PackResult struct {
+	Bins     []*Bin // All bins of the packer, in packer order
+	Packed   []*Box // Boxes packed by the last call to Pack, in placement order
+	Unpacked []*Box // Boxes that could not be packed by the last call to Pack
+
+	// Placements holds a snapshot of where each box of the last call to Pack ended up:
+	// the packed boxes in placement order, followed by the unpacked ones. The records
+	// are values, so they stay valid when the boxes are modified or packed again.
+	Placements []BoxPlacement
+
+	BinsUsed     int     // Number of bins holding at least one box
+	PackedArea   float64 // Total area of the packed boxes
+	UnpackedArea float64 // Total area of the unpacked boxes
+	Efficiency   float64 // Percentage of the area of the bins in use occupied by boxes
+
+	// LowerBound is a lower bound on the number of bins needed for the boxes in the bins:
+	// L2LowerBound if all bins share one size, otherwise ContinuousLowerBound for the
+	// largest bin. Gap is how far BinsUsed is above it, relative to it, so 0 proves the
+	// packing optimal in bins and 0.25 means at most 25% more bins than needed are used.
+	LowerBound int
+	Gap        float64
+
+	Elapsed  time.Duration // Time the last call to Pack took
+	TimedOut bool          // Whether PackerOptions.TimeLimit cut the last call to Pack short
+}
+
+// BoxPlacement records the outcome of packing one box.
+type BoxPlacement struct {
+	Box      *Box    // The box the record describes, for correlation only
+	ID       string  // Box.ID at the time of the snapshot, which survives serialization
+	OrderID  string  // Box.OrderID at the time of the snapshot
+	Packed   bool    // Whether the box was packed
+	BinIndex int     // Index of the bin in PackResult.Bins; -1 if the box is unpacked
+	X, Y     float64 // Position of the top-left corner in the bin
+	Width    float64 // Width as placed, i.e. after rotation
+	Height   float64 // Height as placed, i.e. after rotation
+	Rotated  bool    // Whether the box was rotated from its original orientation
+	Mirrored bool    // Whether the box was turned over; see Box.Mirror
+	Radius   float64 // Radius of a round box, centered at X+Radius, Y+Radius; zero for rectangles
+	// UV is the placed rectangle normalized by the bin size, for texture atlases; zero if
+	// the box is unpacked. With Rotated set, the box's content is turned by 90 degrees; with
+	// Mirrored set, it is transposed.
+	UV UVRect
+}
+
+// PackedValue returns the total Box.Value of the packed boxes.
+func (r *PackResult) PackedValue() float64 {
+	total := 0.0
+	for _, box := range r.Packed {
+		total += box.Value
+	}
+	return total
+}
+
+// UnpackedValue returns the total Box.Value of the boxes left unpacked.
+func (r *PackResult) UnpackedValue() float64 {
+	total := 0.0
+	for _, box := range r.Unpacked {
+		total += box.Value
+	}
+	return total
+}
+
+// TotalCost returns the total Bin.Cost of the bins holding at least one box.
+func (r *PackResult) TotalCost() float64 {
+	total := 0.0
+	for _, bin := range r.Bins {
+		if bin != nil && len(bin.Boxes) > 0 {
+			total += bin.Cost
+		}
+	}
+	return total
+}
+
+// BinGroup is the per-bin view of a PackResult.
+type BinGroup struct {
+	Index      int            // Index of the bin in PackResult.Bins
+	Bin        *Bin           // The bin itself
+	Boxes      []*Box         // Boxes in the bin, in placement order
+	Efficiency float64        // Percentage of the bin's area occupied by boxes
+	UsedArea   float64        // Total area of the boxes in the bin
+	WasteArea  float64        // Area of the bin not occupied by boxes
+	Cost       float64        // Cost of the bin, zero if the bin holds no boxes
+	Offcuts    []FreeSpaceBox // Disjoint free rectangles remaining in the bin, largest first
+}
+
+// Result returns a PackResult for the last call to Pack.
+// The bins and boxes are shared with the packer, not copied; Placements and the
+// summary statistics are a snapshot taken when Result is called.
+func (p *Packer) Result() *PackResult {
+	result := &PackResult{
+		Bins:       p.Bins,
+		Packed:     p.lastPacked,
+		Unpacked:   p.UnpackedBoxes,
+		Placements: make([]BoxPlacement, 0, len(p.lastPacked)+len(p.UnpackedBoxes)),
+		Elapsed:    p.lastElapsed,
+		TimedOut:   p.lastTimedOut,
+	}
+
+	binIndex := make(map[*Box]int)
+	binArea, boxArea := 0.0, 0.0
+	var boxes []*Box // All boxes in the bins, whichever call to Pack placed them
+	for i, bin := range p.Bins {
+		if bin == nil || len(bin.Boxes) == 0 {
+			continue
+		}
+		result.BinsUsed++
+		binArea += bin.Area()
+		for _, box := range bin.Boxes {
+			binIndex[box] = i
+			boxArea += box.Area()
+			boxes = append(boxes, box)
+		}
+	}
+	for _, box := range p.lastPacked {
+		result.Placements = append(result.Placements, p.packedPlacement(box, binIndex))
+		result.PackedArea += box.Area()
+	}
+	for _, box := range p.UnpackedBoxes {
+		if box == nil {
+			continue
+		}
+		result.Placements = append(result.Placements, unpackedPlacement(box))
+		result.UnpackedArea += box.Area()
+	}
+	if binArea > 0 {
+		result.Efficiency = boxArea / binArea * 100
+	}
+	result.LowerBound = lowerBound(p.Bins, boxes)
+	if result.LowerBound > 0 {
+		result.Gap = float64(result.BinsUsed-result.LowerBound) / float64(result.LowerBound)
+	}
+	return result
+}
+
+// packedPlacement records where a box packed by the last call to Pack is now, given the
+// index of the bin holding each box.
+func (p *Packer) packedPlacement(box *Box, binIndex map[*Box]int) BoxPlacement {
+	index, ok := binIndex[box]
+	if !ok {
+		index = -1 // Moved out of the packer's bins since
+	}
+	placement := BoxPlacement{
+		Box: box, ID: box.ID, OrderID: box.OrderID, Packed: ok, BinIndex: index,
+		X: box.X, Y: box.Y, Width: box.Width, Height: box.Height, Rotated: box.Rotated, Mirrored: box.Mirrored, Radius: box.Radius(),
+	}
+	if ok {
+		bin := p.Bins[index]
+		placement.UV = NewUVRect(box.X, box.Y, box.Width, box.Height, bin.Width, bin.Height)
+	}
+	return placement
+}
+
+// unpackedPlacement records a box left unpacked.
+func unpackedPlacement(box *Box) BoxPlacement {
+	return BoxPlacement{Box: box, ID: box.ID, OrderID: box.OrderID, BinIndex: -1, Width: box.Width, Height: box.Height, Radius: box.Radius()}
+}
+
+// ByBin groups the result per bin, in the order of PackResult.Bins, so report
+// generators and exporters don't have to re-derive the bin to boxes mapping.
+// Empty bins are included with no boxes and zero cost.
+func (r *PackResult) ByBin() []BinGroup {
+	groups := make([]BinGroup, 0, len(r.Bins))
+	for i, bin := range r.Bins {
+		if bin == nil {
+			continue
+		}
+		group := BinGroup{
+			Index:      i,
+			Bin:        bin,
+			Boxes:      append([]*Box(nil), bin.Boxes...), // Copy so callers can't reorder the bin
+			Efficiency: bin.Efficiency(),
+			Offcuts:    bin.offcutPieces(),
+		}
+		for _, box := range bin.Boxes {
+			group.UsedArea += box.Area()
+		}
+		group.WasteArea = bin.Area() - group.UsedArea
+		if len(bin.Boxes) > 0 {
+			group.Cost = bin.Cost // Unused stock costs nothing
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// Plan computes the packing Pack would produce without modifying the packer, its bins or
+// the boxes, e.g. to answer "would this fit?" in a request handler.
+//
+// The returned result describes scratch copies: Bins are copies of the packer's bins
+// holding copies of the boxes, while Packed, Unpacked and each BoxPlacement.Box refer
+// to the original boxes, which stay untouched. Cluster members are not positioned.
+// The built-in strategies bound to a bin, such as ContactPointFit, score the copies; a
+// custom Placement closure bound to a bin still scores the original, so the plan may
+// differ from the packing for those.
+func (p *Packer) Plan(boxes []*Box, options PackerOptions) *PackResult {
+	bins, copies := trialCopies(p.Bins, boxes)
+	scratch := &Packer{Bins: bins}
+	original := make(map[*Box]*Box, len(copies))
+	for i, copied := range copies {
+		if copied != nil {
+			original[copied] = boxes[i]
+		}
+	}
+	scratch.Pack(copies, options)
+
+	result := scratch.Result()
+	restore := func(boxes []*Box) []*Box {
+		restored := make([]*Box, len(boxes))
+		for i, box := range boxes {
+			restored[i] = original[box]
+		}
+		return restored
+	}
+	result.Packed = restore(result.Packed)
+	result.Unpacked = restore(result.Unpacked)
+	for i := range result.Placements {
+		result.Placements[i].Box = original[result.Placements[i].Box]
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package binpacking
+
+import "math"
+
+// Score is the quality of a candidate placement as computed by a PlacementStrategyFunc.
+// Scores compare lexicographically: the lower Primary wins, and Secondary breaks ties.
+// The zero value is NoFit, which is distinct from every real score, so a box that cannot
+// be placed never has to be encoded as a magic number.
+type Score struct {
+	Primary   float64 // Main criterion; only meaningful when the score is not NoFit
+	Secondary float64 // Tie-breaker used when Primary values are equal
+	fits      bool    // False for NoFit
+}
+
+// NoFit is the Score of a placement that is not possible.
+var NoFit = Score{}
+
+// NewScore returns a fitting Score with a single criterion.
+// Values that overflowed to infinity are clamped to the largest finite float64 and
+// NaN (e.g. the result of Inf-Inf) is treated as the worst possible fit, so a
+// placement that does fit is never confused with NoFit or poisons comparisons.
+func NewScore(primary float64) Score {
+	return NewScoreWithTieBreak(primary, 0)
+}
+
+// NewScoreWithTieBreak returns a fitting Score compared by primary first and by secondary
+// when the primary values are equal. Both values are sanitized like in NewScore.
+func NewScoreWithTieBreak(primary, secondary float64) Score {
+	return Score{Primary: sanitizeScore(primary), Secondary: sanitizeScore(secondary), fits: true}
+}
+
+// sanitizeScore clamps infinities and maps NaN to the worst finite value.
+func sanitizeScore(value float64) float64 {
+	switch {
+	case math.IsNaN(value), math.IsInf(value, 1):
+		return math.MaxFloat64
+	case math.IsInf(value, -1):
+		return -math.MaxFloat64
+	}
+	return value
+}
+
+// IsNoFit reports whether the score represents an impossible placement.
+func (s Score) IsNoFit() bool {
+	return !s.fits
+}
+
+// Less reports whether s is a strictly better score than other.
+// Any fitting score is better than NoFit; NoFit is never better than anything.
+func (s Score) Less(other Score) bool {
+	if !s.fits {
+		return false
+	}
+	if !other.fits {
+		return true
+	}
+	if s.Primary != other.Primary {
+		return s.Primary < other.Primary
+	}
+	return s.Secondary < other.Secondary
+}
+
+// Add returns the score with delta added to its primary criterion, saturating instead
+// of overflowing. Adding to NoFit yields NoFit.
+func (s Score) Add(delta float64) Score {
+	if !s.fits {
+		return NoFit
+	}
+	return NewScoreWithTieBreak(s.Primary+delta, s.Secondary)
+}
@@ -1,5 +1,11 @@
 package binpacking
 
+import (
+	"runtime"
+	"slices"
+	"sync"
+)
+
 // ScoreBoard manages the evaluation of potential placements (ScoreBoardEntry)
 // for a set of boxes into a set of bins.
 type ScoreBoard struct {
@@ -8,41 +14,119 @@ type ScoreBoard struct {
 	// Note: Storing the original boxes list might be redundant if CurrentBoxes() is sufficient.
 	// Consider if this field is truly needed or if it should be InitialBoxes.
 	Boxes []*Box // The initial list of boxes provided
+
+	// Penalty, if set, returns an amount added to an entry's score when ranking
+	// entries in BestFit. It lets the packer express soft objectives (such as
+	// keeping an order together) without changing the placement scores themselves.
+	Penalty func(entry *ScoreBoardEntry) float64
+
+	// TagOptions maps Box.Tag values to overrides applied to entries for those boxes.
+	TagOptions map[string]TagOptions
+
+	// Rank, if set, maps an entry's score (after Penalty) to the score BestFit compares.
+	// It lets an objective reorder the entries, e.g. by box value, while the placement
+	// score still breaks ties. Entries that do not fit are never passed to Rank.
+	Rank func(entry *ScoreBoardEntry, score Score) Score
+
+	// Exclude, if set, reports entries that BestFit must skip although they fit, e.g.
+	// because a constraint forbids that box in that bin at the moment.
+	Exclude func(entry *ScoreBoardEntry) bool
+
+	// Prefer, if set, breaks ties between entries whose ranked scores are equal: it
+	// reports whether entry should win over best, the earliest such entry so far. Without
+	// it, or when it returns false, the earliest entry wins. Like a Penalty, it may depend
+	// on the state of the bins, so BestFit then scans the entries.
+	Prefer func(entry, best *ScoreBoardEntry) bool
+
+	// Parallelism is the number of goroutines scoring entries when boxes or bins are
+	// added and bins are recalculated; zero or one scores serially and a negative value
+	// uses runtime.GOMAXPROCS. Scoring only reads the bins, so the built-in backends and
+	// strategies are safe to run concurrently; custom ones must be as well.
+	Parallelism int
+
+	// Priority queue of the entries used by BestFit without a Penalty; see score_heap.go.
+	queue     scoreQueue
+	queueing  bool   // Whether the queue is maintained, i.e. BestFit has used it
+	rebuild   bool   // Whether the queue is out of date and must be rebuilt before use
+	dense     bool   // Whether the last rescoring changed too many entries to queue them
+	queued    int    // len(Entries) as maintained through the scoreboard's methods
+	queueRank bool   // Whether the queued keys were ranked
+	nextSeq   uint64 // Creation number of the next entry
+
+	// Entries of each bin, so that RecalculateBin need not scan all entries. Entries
+	// removed since are dropped when the bin's list is next used.
+	binEntries map[*Bin][]*ScoreBoardEntry
+	indexed    int // len(Entries) as maintained through the scoreboard's methods
+
+	full  []*ScoreBoardEntry // Buffer of RecalculateBin
+	fresh []*FreeSpaceBox    // Buffer of RecalculateBin
+
+	// Free spaces of each MaxRects bin when its entries were last recalculated, so that
+	// RecalculateBin can tell which spaces are new.
+	spaces map[*Bin]map[*FreeSpaceBox]struct{}
+
+	// Identical boxes, scored once per bin and bin generation; see dedupe.go.
+	specs    map[boxSpec]int32         // Number of each spec of the boxes
+	specList []boxSpec                 // Spec of each number
+	cache    map[scoreKey]*cachedScore // Scores of identical boxes
+	scoring  []*cachedScore            // Buffer of scoreAll
+	twins    []scoreTwin               // Buffer of scoreAll
+	unique   []*ScoreBoardEntry        // Buffer of scoreAll
 }
 
+// minParallelEntries is the number of entries below which scoring stays serial, since
+// starting goroutines would cost more than it saves.
+const minParallelEntries = 256
+
 // NewScoreBoard creates a new ScoreBoard, initializing entries by calculating
 // the score for each initial box against each initial bin.
 func NewScoreBoard(bins []*Bin, boxes []*Box) *ScoreBoard {
+	return NewScoreBoardWithTags(bins, boxes, nil)
+}
+
+// NewScoreBoardWithTags is like NewScoreBoard but applies the per-tag overrides
+// to entries of boxes whose Tag appears in tagOptions.
+func NewScoreBoardWithTags(bins []*Bin, boxes []*Box, tagOptions map[string]TagOptions) *ScoreBoard {
+	return NewParallelScoreBoard(bins, boxes, tagOptions, 0)
+}
+
+// NewParallelScoreBoard is like NewScoreBoardWithTags but scores the initial entries, and
+// later ones, with the given ScoreBoard.Parallelism.
+func NewParallelScoreBoard(bins []*Bin, boxes []*Box, tagOptions map[string]TagOptions, parallelism int) *ScoreBoard {
 	sb := &ScoreBoard{
-		Entries: make([]*ScoreBoardEntry, 0, len(bins)*len(boxes)), // Pre-allocate slice capacity
-		Bins:    bins,
-		Boxes:   boxes,
+		Entries:     make([]*ScoreBoardEntry, 0, len(bins)*len(boxes)), // Pre-allocate slice capacity
+		Bins:        bins,
+		Boxes:       boxes,
+		TagOptions:  tagOptions,
+		Parallelism: parallelism,
+		binEntries:  make(map[*Bin][]*ScoreBoardEntry, len(bins)),
 	}
 
-	// Populate initial entries
+	// Populate initial entries, scoring them all at once
 	for _, bin := range bins {
-		sb.addBinEntries(bin, boxes)
+		sb.newBinEntries(bin, boxes)
 	}
+	sb.calculate(sb.Entries)
 
 	return sb
 }
 
 // CurrentBoxes returns a slice containing unique pointers to all boxes
-// currently represented in the ScoreBoard entries.
+// currently represented in the ScoreBoard entries, in the order they first appear
+// in Entries, so that bins added later list their entries in a reproducible order.
 func (sb *ScoreBoard) CurrentBoxes() []*Box {
 	// Use a map to efficiently track unique box pointers
 	boxSet := make(map[*Box]struct{})
+	uniqueBoxes := make([]*Box, 0)
 	for _, entry := range sb.Entries {
-		if entry != nil && entry.Box != nil {
+		if entry == nil || entry.Box == nil {
+			continue
+		}
+		if _, seen := boxSet[entry.Box]; !seen {
 			boxSet[entry.Box] = struct{}{}
+			uniqueBoxes = append(uniqueBoxes, entry.Box)
 		}
 	}
-
-	// Convert the map keys (unique box pointers) back into a slice
-	uniqueBoxes := make([]*Box, 0, len(boxSet))
-	for boxPtr := range boxSet {
-		uniqueBoxes = append(uniqueBoxes, boxPtr)
-	}
 	return uniqueBoxes
 }
 
@@ -52,27 +136,57 @@ func (sb *ScoreBoard) AnyBoxesLeft() bool {
 }
 
 // BestFit finds the ScoreBoardEntry representing the best possible placement
-// (lowest score) among all entries that indicate a valid fit.
-// Returns nil if no fitting placement exists in the current entries.
+// (lowest score) among all entries that indicate a valid fit, the earliest one on ties
+// unless Prefer picks another. Returns nil if no fitting placement exists in the current
+// entries. If a Penalty or Rank is set, entries are compared by their penalized and
+// ranked score; entries reported by Exclude are skipped.
+//
+// Without a Penalty or Prefer, which may change with every placement, the entries are
+// kept in a min-heap with lazy invalidation, so BestFit costs O(log n) amortized instead
+// of a scan of all entries. The scan is still used right after rescoring that changed a
+// large share of the entries, e.g. with only a few bins, since rebuilding the heap would
+// cost more. Rank must then be a function of the entry's box, bin and score
+// only. Entries rescored with Calculate are requeued; a Score assigned directly is only
+// noticed when the entry surfaces, which is right for making it worse, e.g. NoFit.
 func (sb *ScoreBoard) BestFit() *ScoreBoardEntry {
+	if sb.Penalty == nil && sb.Prefer == nil && !sb.dense {
+		return sb.bestQueued()
+	}
+	return sb.scanBestFit()
+}
+
+// scanBestFit is BestFit by a linear scan of the entries.
+func (sb *ScoreBoard) scanBestFit() *ScoreBoardEntry {
 	var bestEntry *ScoreBoardEntry = nil // Initialize best to nil
+	bestScore := NoFit
 
 	for _, entry := range sb.Entries {
 		// Check if the entry represents a valid fit.
-		// Fit() internally checks entry.Score != nil && !entry.Score.IsBlank()
+		// Fit() internally checks that entry.Score is not NoFit
 		if entry == nil || !entry.Fit() {
 			continue // Skip invalid entries or those that don't fit
 		}
+		if sb.Exclude != nil && sb.Exclude(entry) {
+			continue
+		}
+
+		score := entry.Score
+		if sb.Penalty != nil {
+			score = score.Add(sb.Penalty(entry))
+		}
+		if sb.Rank != nil {
+			score = sb.Rank(entry, score)
+		}
 
 		// If this is the first valid entry found, it's the best so far.
 		if bestEntry == nil {
-			bestEntry = entry
+			bestEntry, bestScore = entry, score
 			continue
 		}
 
 		// Compare current entry's score value with the best score value found so far.
-		if entry.Score < bestEntry.Score {
-			bestEntry = entry
+		if score.Less(bestScore) || sb.Prefer != nil && !bestScore.Less(score) && sb.Prefer(entry, bestEntry) {
+			bestEntry, bestScore = entry, score
 		}
 	}
 	return bestEntry
@@ -84,16 +198,38 @@ func (sb *ScoreBoard) RemoveBox(boxToRemove *Box) {
 	if boxToRemove == nil {
 		return // Nothing to remove
 	}
-	// Create a new slice to hold entries that don't match the box to remove.
-	// Pre-allocate capacity for efficiency.
-	filteredEntries := make([]*ScoreBoardEntry, 0, len(sb.Entries))
+	// Filter the entries in place: RemoveBox runs after every placement, and allocating
+	// a copy of all entries each time would dominate packing large jobs.
+	filteredEntries := sb.Entries[:0]
 	for _, entry := range sb.Entries {
 		// Keep the entry if its Box pointer is not the one to remove.
 		if entry != nil && entry.Box != boxToRemove {
 			filteredEntries = append(filteredEntries, entry)
+		} else if entry != nil {
+			entry.removed = true // Its queued items are dropped as they surface
+		}
+	}
+	if sb.queued == len(sb.Entries) {
+		sb.queued = len(filteredEntries)
+	}
+	if sb.indexed == len(sb.Entries) {
+		sb.indexed = len(filteredEntries)
+	}
+	clear(sb.Entries[len(filteredEntries):]) // Release the removed entries
+	sb.Entries = filteredEntries
+}
+
+// clear removes all entries.
+func (sb *ScoreBoard) clear() {
+	for _, entry := range sb.Entries {
+		if entry != nil {
+			entry.removed = true
 		}
 	}
-	sb.Entries = filteredEntries // Replace the old slice with the filtered one
+	sb.Entries = sb.Entries[:0]
+	sb.queued = 0
+	sb.indexed = 0
+	clear(sb.binEntries)
 }
 
 // AddBin incorporates a new bin into the scoreboard.
@@ -107,29 +243,227 @@ func (sb *ScoreBoard) AddBin(bin *Bin) {
 	sb.addBinEntries(bin, sb.CurrentBoxes())
 }
 
+// RemoveBin removes a bin and all entries associated with it from the scoreboard.
+func (sb *ScoreBoard) RemoveBin(binToRemove *Bin) {
+	index := slices.Index(sb.Bins, binToRemove)
+	if binToRemove == nil || index < 0 {
+		return
+	}
+	// Bins may be shared with the caller's slice, so the remaining bins are copied.
+	sb.Bins = slices.Delete(slices.Clone(sb.Bins), index, index+1)
+	filteredEntries := sb.Entries[:0]
+	for _, entry := range sb.Entries {
+		if entry != nil && entry.Bin != binToRemove {
+			filteredEntries = append(filteredEntries, entry)
+		} else if entry != nil {
+			entry.removed = true
+		}
+	}
+	if sb.queued == len(sb.Entries) {
+		sb.queued = len(filteredEntries)
+	}
+	if sb.indexed == len(sb.Entries) {
+		sb.indexed = len(filteredEntries)
+	}
+	clear(sb.Entries[len(filteredEntries):])
+	sb.Entries = filteredEntries
+	delete(sb.binEntries, binToRemove)
+	delete(sb.spaces, binToRemove)
+	for key := range sb.cache {
+		if key.bin == binToRemove {
+			delete(sb.cache, key)
+		}
+	}
+}
+
+// AddBox incorporates a new box into the scoreboard.
+// It calculates and adds entries for this box against all bins.
+func (sb *ScoreBoard) AddBox(box *Box) {
+	if box == nil {
+		return // Cannot add a nil box
+	}
+	sb.Boxes = append(sb.Boxes, box)
+	first := len(sb.Entries)
+	for _, bin := range sb.Bins {
+		sb.newBinEntries(bin, []*Box{box})
+	}
+	sb.calculate(sb.Entries[first:])
+}
+
 // RecalculateBin updates the scores for all entries associated with a specific bin.
 // Useful if the bin's state (e.g., free spaces) has changed.
+//
+// For a MaxRects bin with a built-in strategy other than ContactPointFit, an entry whose
+// best free space is still there is only scored against the free spaces added since the
+// last recalculation, as placing a box never enlarges a space. Entries whose space was
+// split or pruned, and all entries of other bins, are scored in full.
 func (sb *ScoreBoard) RecalculateBin(bin *Bin) {
 	if bin == nil {
 		return
 	}
-	for _, entry := range sb.Entries {
-		// If the entry belongs to the specified bin, recalculate its score.
-		if entry != nil && entry.Bin == bin {
-			entry.Calculate()
+	stale := sb.entriesOf(bin)
+	full := stale
+	current, fresh, incremental := sb.diffSpaces(bin)
+	if incremental {
+		perSpace := scoresPerSpace(bin.Placement)
+		full = sb.full[:0]
+		for _, entry := range stale {
+			ok := perSpace
+			if entry.Options != nil && entry.Options.Placement != nil {
+				ok = scoresPerSpace(entry.Options.Placement)
+			}
+			if !ok || !entry.rescore(current, fresh) {
+				full = append(full, entry)
+			}
+		}
+	}
+	sb.scoreAll(full)
+	sb.enqueueAll(stale)
+
+	if incremental {
+		clear(full)
+		sb.full = full[:0]
+	}
+	clear(fresh)
+	sb.fresh = fresh[:0]
+}
+
+// entriesOf returns the entries of the bin, indexing the entries by bin first if
+// Entries was changed without the scoreboard's methods.
+func (sb *ScoreBoard) entriesOf(bin *Bin) []*ScoreBoardEntry {
+	if sb.binEntries == nil || sb.indexed != len(sb.Entries) {
+		sb.binEntries = make(map[*Bin][]*ScoreBoardEntry, len(sb.Bins))
+		for _, entry := range sb.Entries {
+			if entry != nil {
+				entry.removed = false // As in rebuildQueue, which the changed length also forces
+				sb.binEntries[entry.Bin] = append(sb.binEntries[entry.Bin], entry)
+			}
+		}
+		sb.indexed = len(sb.Entries)
+	}
+	entries := sb.binEntries[bin][:0]
+	for _, entry := range sb.binEntries[bin] {
+		if !entry.removed {
+			entries = append(entries, entry)
+		}
+	}
+	clear(sb.binEntries[bin][len(entries):]) // Release the removed entries
+	sb.binEntries[bin] = entries
+	return entries
+}
+
+// diffSpaces records the free spaces of the bin and returns them as a set, together with
+// those added since the previous call. It reports false, after recording them, if the
+// bin's spaces cannot be compared: on the first call for the bin, and for backends other
+// than MaxRects or a compacted bin, whose spaces may grow.
+func (sb *ScoreBoard) diffSpaces(bin *Bin) (map[*FreeSpaceBox]struct{}, []*FreeSpaceBox, bool) {
+	if !bin.usesMaxRects() || bin.compacted {
+		if sb.spaces != nil {
+			delete(sb.spaces, bin)
+		}
+		return nil, nil, false
+	}
+	if sb.spaces == nil {
+		sb.spaces = make(map[*Bin]map[*FreeSpaceBox]struct{})
+	}
+	known, ok := sb.spaces[bin]
+	if !ok {
+		known = make(map[*FreeSpaceBox]struct{}, len(bin.FreeSpaces))
+		sb.spaces[bin] = known
+	}
+	fresh := sb.fresh[:0]
+	for _, space := range bin.FreeSpaces {
+		if _, seen := known[space]; !seen {
+			fresh = append(fresh, space)
 		}
 	}
+	// The set is updated in place rather than rebuilt, since it is needed on every call.
+	clear(known)
+	for _, space := range bin.FreeSpaces {
+		known[space] = struct{}{}
+	}
+	return known, fresh, ok
 }
 
 // addBinEntries creates ScoreBoardEntry objects for a given bin and list of boxes,
 // calculates their scores, and adds them to the scoreboard's entries.
 func (sb *ScoreBoard) addBinEntries(bin *Bin, boxes []*Box) {
+	first := len(sb.Entries)
+	sb.newBinEntries(bin, boxes)
+	sb.calculate(sb.Entries[first:])
+}
+
+// newBinEntries adds unscored entries for a given bin and list of boxes.
+func (sb *ScoreBoard) newBinEntries(bin *Bin, boxes []*Box) {
 	for _, box := range boxes {
 		if bin == nil || box == nil {
 			continue // Skip nil inputs
 		}
 		entry := NewScoreBoardEntry(bin, box)
-		entry.Calculate() // Calculate the score for this bin/box pair
+		entry.Options = tagOptionsFor(sb.TagOptions, box)
+		entry.spec = sb.specID(box)
+		entry.board, entry.seq = sb, sb.nextSeq
+		sb.nextSeq++
+		if sb.queued == len(sb.Entries) {
+			sb.queued++
+		}
+		if sb.binEntries != nil && sb.indexed == len(sb.Entries) {
+			sb.indexed++
+			sb.binEntries[bin] = append(sb.binEntries[bin], entry)
+		}
 		sb.Entries = append(sb.Entries, entry)
 	}
 }
+
+// workers returns the number of goroutines scoring entries.
+func (sb *ScoreBoard) workers() int {
+	if sb.Parallelism < 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return sb.Parallelism
+}
+
+// calculate scores the entries, spread over the scoreboard's workers when there are
+// enough of them. Each entry only writes its own score, so the workers share nothing.
+func (sb *ScoreBoard) calculate(entries []*ScoreBoardEntry) {
+	sb.scoreAll(entries)
+	sb.enqueueAll(entries)
+}
+
+// scoreAll scores the entries like calculate without queueing them.
+func (sb *ScoreBoard) scoreAll(entries []*ScoreBoardEntry) {
+	entries = sb.dedupe(entries)
+	defer sb.copyTwins()
+	workers := sb.workers()
+	if workers <= 1 || len(entries) < minParallelEntries {
+		for _, entry := range entries {
+			entry.score()
+		}
+		return
+	}
+
+	// The first entry of each bin is scored up front, so backends initializing their
+	// state lazily on first use (such as a zero SkylineBackend) do so only once.
+	ready := make(map[*Bin]struct{})
+	for _, entry := range entries {
+		if _, ok := ready[entry.Bin]; !ok {
+			ready[entry.Bin] = struct{}{}
+			entry.score()
+		}
+	}
+
+	workers = min(workers, len(entries)/(minParallelEntries/4))
+	chunk := (len(entries) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(entries); start += chunk {
+		part := entries[start:min(start+chunk, len(entries))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, entry := range part {
+				entry.score()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,13 +1,29 @@
 package binpacking
 
-import "math"
-
 // ScoreBoardEntry holds a potential pairing of a Box with a Bin
 // and the calculated Score for that placement.
 type ScoreBoardEntry struct {
-	Bin   *Bin    // Pointer to the Bin being considered (allows nil)
-	Box   *Box    // Pointer to the Box being placed (allows nil)
-	Score float64 // Pointer to the calculated Score (allows nil initially, then set by Calculate)
+	Bin   *Bin  // Pointer to the Bin being considered (allows nil)
+	Box   *Box  // Pointer to the Box being placed (allows nil)
+	Score Score // Calculated Score (NoFit initially, then set by Calculate)
+
+	Options *TagOptions // Optional overrides for the Box within this Bin (allows nil)
+
+	// Bookkeeping of the ScoreBoard's priority queue.
+	board   *ScoreBoard // Scoreboard holding the entry, told of new scores
+	seq     uint64      // Creation order, which breaks ties between equal scores
+	version uint32      // Incremented whenever the entry is queued again
+	removed bool        // Set once the entry has been removed from the scoreboard
+
+	queuedFit   bool  // Whether the entry's current item is in the queue
+	queuedScore Score // Score of the entry's current item
+
+	// Free space of the entry's best placement and the score computed for it, so that
+	// RecalculateBin can skip entries whose space survived; see rescore.
+	space      *FreeSpaceBox
+	spaceScore Score
+
+	spec int32 // Number of the box's spec in the scoreboard, zero if none; see dedupe.go
 }
 
 // NewScoreBoardEntry creates a new entry linking a Bin and a Box,
@@ -16,30 +32,70 @@ func NewScoreBoardEntry(bin *Bin, box *Box) *ScoreBoardEntry {
 	return &ScoreBoardEntry{
 		Bin:   bin,
 		Box:   box,
-		Score: math.MaxFloat64, // Initialize score to indicate no calculation/fit yet
+		Score: NoFit, // Initialize score to indicate no calculation/fit yet
 	}
 }
 
 // Calculate determines the placement score for the entry's Box within its Bin.
 // It calls the associated Bin's ScoreFor method and stores the result internally.
-// It returns the calculated Score. If Bin or Box is nil, it returns math.MaxFloat64 and
+// It returns the calculated Score. If Bin or Box is nil, it returns NoFit and
 // sets the internal Score appropriately.
-func (sbe *ScoreBoardEntry) Calculate() float64 {
+func (sbe *ScoreBoardEntry) Calculate() Score {
+	score := sbe.score()
+	if sbe.board != nil {
+		sbe.board.enqueue(sbe)
+	}
+	return score
+}
+
+// score is Calculate without telling the scoreboard, so entries can be scored
+// concurrently.
+func (sbe *ScoreBoardEntry) score() Score {
 	// Handle cases where Bin or Box might not be set
 	if sbe.Bin == nil || sbe.Box == nil {
-		sbe.Score = math.MaxFloat64 // Ensure score is set to max value
-		return math.MaxFloat64
+		sbe.Score = NoFit
+		return NoFit
 	}
 
-	// Call the ScoreFor method assumed to exist on the Bin type.
-	// This will return math.MaxFloat64 if the box doesn't fit in the bin.
-	sbe.Score = sbe.Bin.ScoreFor(sbe.Box)
+	// Find the best placement in the bin, remembering the free space it uses.
+	// The score is NoFit if the box doesn't fit in the bin.
+	placement := sbe.Bin.placementFor(sbe.Box, sbe.Options)
+	sbe.Score, sbe.space, sbe.spaceScore = placement.Score, placement.ChosenSpace, placement.Score
 	return sbe.Score
 }
 
 // Fit determines if the calculated score represents a valid placement.
-// Returns true if the score is NOT the maximum float value (indicating a fit was found).
+// Returns true if the score is not NoFit (indicating a fit was found).
 func (sbe *ScoreBoardEntry) Fit() bool {
-	// A valid fit has a score less than the maximum possible float value.
-	return sbe.Score < math.MaxFloat64
+	return !sbe.Score.IsNoFit()
+}
+
+// rescore updates the score of an entry of a MaxRects bin whose free spaces changed,
+// given the set of spaces the bin has now and the ones that are new, and reports whether
+// it could. Free spaces only shrink or disappear when a box is placed, so if the space of
+// the entry's best placement survived, the best placement now is either still that one
+// or in a new space, and only those need to be scored. This requires a strategy that
+// scores each space on its own; see scoresPerSpace. It cannot when the space is gone, or
+// when the score was assigned directly, e.g. set to NoFit by a constraint.
+func (sbe *ScoreBoardEntry) rescore(current map[*FreeSpaceBox]struct{}, fresh []*FreeSpaceBox) bool {
+	if sbe.Score != sbe.spaceScore {
+		return false
+	}
+	if !sbe.Bin.accepts(sbe.Box) {
+		sbe.Score, sbe.space, sbe.spaceScore = NoFit, nil, NoFit
+		return true
+	}
+	if sbe.Score.IsNoFit() {
+		return true // The new spaces were cut from old ones, none of which could hold the box
+	}
+	if _, ok := current[sbe.space]; !ok {
+		return false
+	}
+	if len(fresh) > 0 {
+		placement := findBestFit(sbe.Bin.sizeFor(sbe.Box, sbe.Options), fresh, sbe.Options.placement(sbe.Bin))
+		if sbe.Bin.TieBreak.wins(placement.Score, placement.ChosenSpace, &PlacementInfo{Score: sbe.Score, ChosenSpace: sbe.space, Fits: true}) {
+			sbe.Score, sbe.space, sbe.spaceScore = placement.Score, placement.ChosenSpace, placement.Score
+		}
+	}
+	return true
 }
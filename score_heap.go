@@ -0,0 +1,190 @@
+package binpacking
+
+import "math/bits"
+
+// scoreItem is a queued entry with the ranked score it had when it was queued. The item
+// is stale once the entry has been removed or queued again, and is then dropped when it
+// surfaces.
+type scoreItem struct {
+	key     Score // entry.Score after Rank, the score the queue is ordered by
+	seq     uint64
+	entry   *ScoreBoardEntry
+	version uint32 // entry.version when queued
+}
+
+// before reports whether item a is served before item b: by ranked score, breaking ties
+// by entry creation order so that the queue picks the same entry as a linear scan of
+// Entries.
+func (a *scoreItem) before(b *scoreItem) bool {
+	if a.key.Less(b.key) {
+		return true
+	}
+	if b.key.Less(a.key) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+// scoreQueue is a binary min-heap of scoreboard entries. It is typed rather than built
+// on container/heap so that pushing does not allocate.
+type scoreQueue []scoreItem
+
+// push adds an item.
+func (q *scoreQueue) push(item scoreItem) {
+	*q = append(*q, item)
+	h := *q
+	i := len(h) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h[i].before(&h[parent]) {
+			break
+		}
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+// pop removes the first item.
+func (q *scoreQueue) pop() scoreItem {
+	h := *q
+	top := h[0]
+	last := len(h) - 1
+	h[0] = h[last]
+	h[last] = scoreItem{} // Release the entry
+	*q = h[:last]
+	q.down(0)
+	return top
+}
+
+// down restores the heap order below i.
+func (q scoreQueue) down(i int) {
+	for {
+		first := 2*i + 1
+		if first >= len(q) {
+			return
+		}
+		if second := first + 1; second < len(q) && q[second].before(&q[first]) {
+			first = second
+		}
+		if !q[first].before(&q[i]) {
+			return
+		}
+		q[i], q[first] = q[first], q[i]
+		i = first
+	}
+}
+
+// init establishes the heap order of all items in linear time.
+func (q scoreQueue) init() {
+	for i := len(q)/2 - 1; i >= 0; i-- {
+		q.down(i)
+	}
+}
+
+// item returns the queue item of a fitting entry, marking it as the entry's current one.
+func (sb *ScoreBoard) item(entry *ScoreBoardEntry) scoreItem {
+	entry.version++
+	entry.queuedFit, entry.queuedScore = entry.Fit(), entry.Score
+	item := scoreItem{key: entry.Score, seq: entry.seq, entry: entry, version: entry.version}
+	if sb.Rank != nil {
+		item.key = sb.Rank(entry, entry.Score)
+	}
+	return item
+}
+
+// enqueue queues the entry with its current score, superseding earlier items of it. It
+// does nothing until BestFit first uses the queue, or while the queue awaits a rebuild.
+func (sb *ScoreBoard) enqueue(entry *ScoreBoardEntry) {
+	if !sb.queueing || sb.rebuild || (entry.queuedFit && entry.queuedScore == entry.Score) {
+		return // Most recalculated entries keep their score and their item
+	}
+	if entry.Fit() {
+		sb.queue.push(sb.item(entry))
+	} else {
+		entry.version++ // Supersedes the item of its earlier, fitting score
+		entry.queuedFit = false
+	}
+}
+
+// enqueueAll queues the rescored entries. When many of them changed, as when there are
+// few bins, the queue is left to be rebuilt and BestFit scans the entries instead, which
+// is cheaper than maintaining a heap that changes almost entirely at every step.
+func (sb *ScoreBoard) enqueueAll(entries []*ScoreBoardEntry) {
+	if !sb.queueing {
+		return
+	}
+	changed := 0
+	for _, entry := range entries {
+		if entry.queuedFit != entry.Fit() || (entry.Fit() && entry.queuedScore != entry.Score) {
+			changed++
+		}
+	}
+	// Each change costs a push now and, as the entries of the bin just filled tend to be
+	// the best ones, a pop of its stale item soon after: about 4·log₂(n) comparisons in
+	// all, against n for a scan.
+	sb.dense = 4*changed*bits.Len(uint(len(sb.Entries))) > len(sb.Entries)
+	if sb.dense || sb.rebuild {
+		sb.rebuild = true
+		return
+	}
+	for _, entry := range entries {
+		sb.enqueue(entry)
+	}
+}
+
+// rebuildQueue queues every fitting entry afresh, numbering the entries in their order.
+// It is needed when Entries was changed without the scoreboard's methods, when Rank was
+// set or cleared, after dense rescoring, and when stale items outnumber the entries.
+func (sb *ScoreBoard) rebuildQueue() {
+	sb.queue = sb.queue[:0]
+	for i, entry := range sb.Entries {
+		if entry == nil {
+			continue
+		}
+		entry.seq, entry.removed = uint64(i), false
+		if entry.Fit() {
+			sb.queue = append(sb.queue, sb.item(entry))
+		} else {
+			entry.version++
+			entry.queuedFit = false
+		}
+	}
+	sb.queue.init()
+	sb.nextSeq = uint64(len(sb.Entries))
+	sb.queued, sb.queueRank, sb.queueing, sb.rebuild = len(sb.Entries), sb.Rank != nil, true, false
+}
+
+// bestQueued returns the best fitting entry from the queue in O(log n) amortized,
+// dropping stale items on the way. Excluded entries are set aside while searching and
+// queued again afterwards, as they may be allowed later.
+func (sb *ScoreBoard) bestQueued() *ScoreBoardEntry {
+	if !sb.queueing || sb.rebuild || sb.queued != len(sb.Entries) || sb.queueRank != (sb.Rank != nil) || len(sb.queue) > 2*len(sb.Entries)+64 {
+		sb.rebuildQueue()
+	}
+	var excluded []scoreItem
+	var best *ScoreBoardEntry
+	for len(sb.queue) > 0 {
+		top := &sb.queue[0]
+		entry := top.entry
+		if entry.removed || top.version != entry.version {
+			sb.queue.pop() // Superseded by a later item, or gone
+			continue
+		}
+		if entry.Score != entry.queuedScore {
+			sb.queue.pop() // Score assigned directly rather than by Calculate
+			entry.queuedFit = false
+			sb.enqueue(entry)
+			continue
+		}
+		if sb.Exclude != nil && sb.Exclude(entry) {
+			excluded = append(excluded, sb.queue.pop())
+			continue
+		}
+		best = entry
+		break
+	}
+	for _, item := range excluded {
+		sb.queue.push(item)
+	}
+	return best
+}
@@ -0,0 +1,90 @@
+package binpacking
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestScoreBoardHeap(t *testing.T) {
+	setup := func(seed uint64) ([]*Bin, []*Box) {
+		rng := rand.New(rand.NewPCG(seed, 1))
+		bins := []*Bin{NewBin(40, 40, nil), NewBin(30, 50, BestAreaFit), NewBin(25, 25, BottomLeft)}
+		boxes := make([]*Box, 60)
+		for i := range boxes {
+			// Few distinct sizes, so that many scores tie.
+			boxes[i] = &Box{Width: float64(2 + 2*rng.IntN(5)), Height: float64(2 + 2*rng.IntN(5)), Value: float64(rng.IntN(3))}
+		}
+		return bins, boxes
+	}
+	// run packs like the packer does, checking at every step that the heap picks the
+	// entry a linear scan picks.
+	run := func(t *testing.T, board *ScoreBoard) {
+		t.Helper()
+		for step := 0; ; step++ {
+			want := board.scanBestFit()
+			got := board.BestFit()
+			if got != want {
+				t.Fatalf("step %d: got %+v, want %+v", step, got, want)
+			}
+			if got == nil {
+				return
+			}
+			if !got.Bin.InsertWith(got.Box, got.Options) {
+				t.Fatalf("step %d: best entry does not fit", step)
+			}
+			board.RemoveBox(got.Box)
+			board.RecalculateBin(got.Bin)
+		}
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		for seed := uint64(0); seed < 5; seed++ {
+			bins, boxes := setup(seed)
+			run(t, NewScoreBoard(bins, boxes))
+		}
+	})
+
+	t.Run("rank", func(t *testing.T) {
+		bins, boxes := setup(9)
+		board := NewScoreBoard(bins, boxes)
+		board.Rank = ObjectiveMaxValue.rank()
+		run(t, board)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		bins, boxes := setup(11)
+		board := NewScoreBoard(bins, boxes)
+		// Small boxes may not go into the first bin while it is less than half full.
+		board.Exclude = func(entry *ScoreBoardEntry) bool {
+			return entry.Bin == bins[0] && entry.Box.Area() < 20 && entry.Bin.Efficiency() < 50
+		}
+		run(t, board)
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		bins, boxes := setup(13)
+		board := NewParallelScoreBoard(bins, boxes, nil, 4)
+		run(t, board)
+	})
+
+	t.Run("entries rescored or changed directly", func(t *testing.T) {
+		bins, boxes := setup(17)
+		board := NewScoreBoard(bins, boxes)
+		best := board.BestFit()
+		best.Score = NoFit // Vetoed, as the packer does for uncuttable layouts
+		if got, want := board.BestFit(), board.scanBestFit(); got != want || got == best {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+		best.Calculate() // Restored
+		if got := board.BestFit(); got != best {
+			t.Fatalf("got %+v, want the restored entry %+v", got, best)
+		}
+
+		board.Entries = board.Entries[:10]
+		if got, want := board.BestFit(), board.scanBestFit(); got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+		board.AddBox(&Box{Width: 1, Height: 1})
+		run(t, board)
+	})
+}
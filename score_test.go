@@ -0,0 +1,67 @@
+package binpacking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	t.Run("orders NoFit after every fitting score", func(t *testing.T) {
+		worst := NewScore(math.Inf(1))
+		if worst.IsNoFit() {
+			t.Errorf("NewScore(+Inf).IsNoFit: got %v, want %v", worst.IsNoFit(), false)
+		}
+		if !worst.Less(NoFit) {
+			t.Errorf("NewScore(+Inf).Less(NoFit): got %v, want %v", worst.Less(NoFit), true)
+		}
+		if NoFit.Less(worst) || NoFit.Less(NoFit) {
+			t.Errorf("NoFit must never be less than another score")
+		}
+		if NewScore(math.NaN()).IsNoFit() {
+			t.Errorf("NewScore(NaN) must still be a fitting score")
+		}
+		if !NoFit.Add(1).IsNoFit() {
+			t.Errorf("NoFit.Add(1) must stay NoFit")
+		}
+	})
+
+	t.Run("compares primary then secondary", func(t *testing.T) {
+		a := NewScoreWithTieBreak(1, 5)
+		b := NewScoreWithTieBreak(1, 3)
+		c := NewScoreWithTieBreak(2, 0)
+		if !b.Less(a) || a.Less(b) {
+			t.Errorf("Secondary must break ties: got %v, want %v", a.Less(b), false)
+		}
+		if !a.Less(c) {
+			t.Errorf("Primary must dominate: got %v, want %v", a.Less(c), true)
+		}
+		if a.Less(a) {
+			t.Errorf("Equal scores must not be less than each other")
+		}
+	})
+
+	t.Run("breaks short side ties with the long side", func(t *testing.T) {
+		// Both spaces leave a short side of 0; the narrower one also wastes less along the long side.
+		wide := &FreeSpaceBox{Width: 100, Height: 10}
+		narrow := &FreeSpaceBox{Y: 20, Width: 40, Height: 10}
+		info := FindBestPlacement(NewBox(30, 10, true), []*FreeSpaceBox{wide, narrow}, BestShortSideFit)
+		if info.ChosenSpace != narrow {
+			t.Errorf("Chosen space: got %+v, want %+v", *info.ChosenSpace, *narrow)
+		}
+	})
+
+	t.Run("packs boxes with very large dimensions", func(t *testing.T) {
+		bin := NewBin(1e200, 1e200, BestAreaFit)
+		box := NewBox(1e199, 1e199, false)
+		score := bin.ScoreFor(box)
+		if score.IsNoFit() {
+			t.Fatalf("ScoreFor: got NoFit, want a fitting score")
+		}
+		if math.IsNaN(score.Primary) || math.IsInf(score.Primary, 0) {
+			t.Errorf("Score value: got %v, want a finite number", score.Primary)
+		}
+		if !bin.Insert(box) {
+			t.Errorf("Insert result: got %v, want %v", false, true)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package binpacking
+
+import "sync/atomic"
+
+// generations numbers the states of all bins, so that a number is never reused, not
+// even by a bin restored to an earlier state or by a copy of a bin.
+var generations atomic.Uint64
+
+// Generation returns the number of the bin's current state. It changes with every
+// placement and with anything else that changes the bin's free area, such as Clear,
+// AddDefect or a Repack that is undone, so a score computed for the bin stays valid for
+// as long as Generation returns the same number. Changing the bin's fields directly
+// does not change it.
+func (b *Bin) Generation() uint64 {
+	return b.generation
+}
+
+// touch gives the bin a new generation.
+func (b *Bin) touch() {
+	b.generation = generations.Add(1)
+}
+
+// scoreKey identifies the scores the scoreboard caches: those of boxes of a spec, with
+// or without TagOptions, in a bin.
+type scoreKey struct {
+	bin    *Bin
+	spec   int32
+	tagged bool
+}
+
+// cachedScore is the cached score of a scoreKey. It is valid while the bin is in the
+// generation it was computed in and its free list has not been replaced since.
+type cachedScore struct {
+	generation uint64
+	spaces     int           // len(bin.FreeSpaces) when scored
+	first      *FreeSpaceBox // bin.FreeSpaces[0] when scored, nil if there was none
+	boxes      int           // len(bin.Boxes) when scored
+
+	score, spaceScore Score
+	space             *FreeSpaceBox
+
+	from *ScoreBoardEntry // Entry being scored for the key by the current scoreAll, if any
+}
+
+// validFor reports whether the cached score still describes the bin.
+func (c *cachedScore) validFor(bin *Bin) bool {
+	return c.generation == bin.generation && c.spaces == len(bin.FreeSpaces) &&
+		c.boxes == len(bin.Boxes) && c.first == firstSpace(bin)
+}
+
+// firstSpace returns the first free space of the bin, nil if it has none.
+func firstSpace(bin *Bin) *FreeSpaceBox {
+	if len(bin.FreeSpaces) == 0 {
+		return nil
+	}
+	return bin.FreeSpaces[0]
+}
@@ -0,0 +1,98 @@
+package binpacking
+
+import "testing"
+
+func TestGeneration(t *testing.T) {
+	bin := NewBin(10, 10, nil)
+	changes := []struct {
+		name   string
+		change func()
+	}{
+		{"Insert", func() { bin.Insert(NewBox(2, 2, false)) }},
+		{"Place", func() { bin.Place(NewBox(2, 2, false), 5, 5) }},
+		{"AddDefect", func() { bin.AddDefect(0, 8, 1, 1) }},
+		{"Repack", func() { bin.Repack() }},
+		{"Clear", func() { bin.Clear() }},
+	}
+	seen := map[uint64]bool{bin.Generation(): true}
+	for _, tt := range changes {
+		tt.change()
+		if seen[bin.Generation()] {
+			t.Errorf("%s: got generation %d again", tt.name, bin.Generation())
+		}
+		seen[bin.Generation()] = true
+	}
+
+	before := bin.Generation()
+	bin.ScoreFor(NewBox(3, 3, false))
+	bin.CanFit(NewBox(3, 3, false))
+	bin.Insert(NewBox(20, 20, false))
+	if bin.Generation() != before {
+		t.Errorf("got generation %d after scoring and a failed insert, want %d", bin.Generation(), before)
+	}
+}
+
+func TestScoreCache(t *testing.T) {
+	var calls int
+	strategy := func(space *FreeSpaceBox, width, height float64) Score {
+		calls++
+		return BestShortSideFit(space, width, height)
+	}
+	bins := []*Bin{NewBin(10, 10, strategy), NewBin(20, 5, strategy)}
+	board := NewScoreBoard(bins, nil)
+
+	board.AddBox(NewBox(3, 4, false))
+	first := calls
+	board.AddBox(NewBox(3, 4, false))
+	if calls != first {
+		t.Errorf("got %d strategy calls for an identical box in unchanged bins, want none", calls-first)
+	}
+
+	// After a placement only the bin that changed is scored again.
+	bins[0].Insert(NewBox(5, 5, false))
+	calls = 0
+	NewScoreBoard([]*Bin{bins[0]}, []*Box{NewBox(3, 4, false)})
+	inChanged := calls
+	calls = 0
+	board.AddBox(NewBox(3, 4, false))
+	if calls != inChanged {
+		t.Errorf("got %d strategy calls, want %d for the changed bin only", calls, inChanged)
+	}
+
+	// A free list replaced without a placement is not served from the cache.
+	bins[1].FreeSpaces = []*FreeSpaceBox{{Width: 2, Height: 2}}
+	board.AddBox(NewBox(3, 4, false))
+	if last := board.Entries[len(board.Entries)-1]; last.Bin != bins[1] || !last.Score.IsNoFit() {
+		t.Errorf("got score %v in the replaced free list, want NoFit", last.Score)
+	}
+
+	// Sequential runs, which add the boxes one at a time, pack as they did uncached.
+	layout := func(distinct bool) []FreeSpaceBox {
+		sizes := [][2]float64{{3, 4}, {5, 2}, {2, 2}}
+		boxes := make([]*Box, 200)
+		for i := range boxes {
+			boxes[i] = NewBox(sizes[i/70][0], sizes[i/70][1], false)
+			if distinct {
+				boxes[i].Weight = float64(i) * 1e-9 // Unlimited bins ignore it
+			}
+		}
+		bins := []*Bin{NewBin(30, 30, nil), NewBin(25, 20, nil)}
+		NewPacker(bins).Pack(boxes, PackerOptions{SortBy: SortAreaDesc})
+		var placed []FreeSpaceBox
+		for _, bin := range bins {
+			for _, box := range bin.Boxes {
+				placed = append(placed, FreeSpaceBox{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height})
+			}
+		}
+		return placed
+	}
+	got, want := layout(false), layout(true)
+	if len(got) != len(want) {
+		t.Fatalf("got %d boxes placed, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("box %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package binpacking
+
+// KeepApart forbids boxes matching A from sharing a bin with boxes matching B, e.g. to
+// separate hazardous materials. A key matches a box whose ID, Group or Tag equals it, so
+// a rule can name a single box, a group or a class of boxes. A equal to B keeps every
+// matching box in a bin of its own.
+type KeepApart struct {
+	A string
+	B string
+}
+
+// separation tracks which keys are present in each bin and answers whether a box may
+// join a bin under a set of KeepApart rules. A nil *separation allows everything.
+type separation struct {
+	conflicts map[string][]string     // Key to the keys it must be kept apart from
+	present   map[*Bin]map[string]int // Number of boxes per key in each bin
+}
+
+// newSeparation returns a separation for the rules that already accounts for the boxes
+// in bins, or nil if there are no rules.
+func newSeparation(rules []KeepApart, bins []*Bin) *separation {
+	if len(rules) == 0 {
+		return nil
+	}
+	s := &separation{conflicts: make(map[string][]string), present: make(map[*Bin]map[string]int)}
+	for _, rule := range rules {
+		if rule.A == "" || rule.B == "" {
+			continue
+		}
+		s.conflicts[rule.A] = append(s.conflicts[rule.A], rule.B)
+		if rule.A != rule.B {
+			s.conflicts[rule.B] = append(s.conflicts[rule.B], rule.A)
+		}
+	}
+	for _, bin := range bins {
+		if bin == nil {
+			continue
+		}
+		for _, box := range bin.Boxes {
+			s.place(bin, box)
+		}
+	}
+	return s
+}
+
+// keys returns the keys a box can be matched by.
+func (s *separation) keys(box *Box) []string {
+	keys := make([]string, 0, 3)
+	for _, key := range []string{box.ID, box.Group, box.Tag} {
+		if _, ok := s.conflicts[key]; ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// allows reports whether box may be placed into bin.
+func (s *separation) allows(bin *Bin, box *Box) bool {
+	if s == nil {
+		return true
+	}
+	present := s.present[bin]
+	for _, key := range s.keys(box) {
+		for _, other := range s.conflicts[key] {
+			if present[other] > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// allowsTogether reports whether the boxes may share bin with each other and with the
+// bin's current contents.
+func (s *separation) allowsTogether(bin *Bin, boxes []*Box) bool {
+	if s == nil {
+		return true
+	}
+	for i, box := range boxes {
+		if !s.allows(bin, box) {
+			return false
+		}
+		for _, key := range s.keys(box) {
+			for _, other := range s.conflicts[key] {
+				for _, earlier := range boxes[:i] {
+					if earlier.ID == other || earlier.Group == other || earlier.Tag == other {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// place records that box was placed into bin.
+func (s *separation) place(bin *Bin, box *Box) {
+	if s == nil {
+		return
+	}
+	keys := s.keys(box)
+	if len(keys) == 0 {
+		return
+	}
+	if s.present[bin] == nil {
+		s.present[bin] = make(map[string]int)
+	}
+	for _, key := range keys {
+		s.present[bin][key]++
+	}
+}
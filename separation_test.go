@@ -0,0 +1,65 @@
+package binpacking
+
+import "testing"
+
+func TestKeepApart(t *testing.T) {
+	tagged := func(tag string) *Box {
+		box := NewBox(5, 5, true)
+		box.Tag = tag
+		return box
+	}
+	binOf := func(bins []*Bin, box *Box) int {
+		for i, bin := range bins {
+			for _, placed := range bin.Boxes {
+				if placed == box {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	rules := []KeepApart{{A: "oxidizer", B: "flammable"}}
+
+	for _, algorithm := range []PackingAlgorithm{AlgorithmBestFit, AlgorithmShelfFirstFit} {
+		t.Run("separates classes", func(t *testing.T) {
+			bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+			oxidizer, flammable, other := tagged("oxidizer"), tagged("flammable"), tagged("")
+			packed := NewPacker(bins).Pack([]*Box{oxidizer, flammable, other}, PackerOptions{Algorithm: algorithm, KeepApart: rules})
+			if len(packed) != 3 {
+				t.Fatalf("Algorithm %d: got %d packed, want %d", algorithm, len(packed), 3)
+			}
+			if binOf(bins, oxidizer) == binOf(bins, flammable) {
+				t.Errorf("Algorithm %d: oxidizer and flammable share bin %d", algorithm, binOf(bins, oxidizer))
+			}
+		})
+	}
+
+	t.Run("leaves conflicting boxes unpacked", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		packer := NewPacker([]*Bin{bin})
+		packed := packer.Pack([]*Box{tagged("oxidizer"), tagged("flammable")}, PackerOptions{KeepApart: rules})
+		if len(packed) != 1 || len(packer.UnpackedBoxes) != 1 {
+			t.Errorf("Packed: got %d packed/%d unpacked, want 1/1", len(packed), len(packer.UnpackedBoxes))
+		}
+	})
+
+	t.Run("respects boxes already in the bins", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		bins[0].Insert(tagged("oxidizer"))
+		flammable := tagged("flammable")
+		NewPacker(bins).Pack([]*Box{flammable}, PackerOptions{KeepApart: rules})
+		if got := binOf(bins, flammable); got != 1 {
+			t.Errorf("Bin: got %d, want %d", got, 1)
+		}
+	})
+
+	t.Run("separates groups", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		a, b := NewBox(5, 5, true), NewBox(5, 5, true)
+		a.Group, b.Group = "order-1", "order-2"
+		NewPacker(bins).Pack([]*Box{a, b}, PackerOptions{KeepApart: []KeepApart{{A: "order-1", B: "order-2"}}})
+		if !a.Packed || !b.Packed || binOf(bins, a) == binOf(bins, b) {
+			t.Errorf("Groups: got bins %d and %d, want different bins", binOf(bins, a), binOf(bins, b))
+		}
+	})
+}
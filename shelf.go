@@ -0,0 +1,145 @@
+package binpacking
+
+import "sort"
+
+// PackingAlgorithm selects how Packer.Pack assigns boxes to bins.
+type PackingAlgorithm int
+
+const (
+	// AlgorithmBestFit repeatedly inserts the best scoring box/bin pair from the scoreboard.
+	// It gives the densest layouts but costs O(n²·m) for n boxes and m bins.
+	AlgorithmBestFit PackingAlgorithm = iota
+	// AlgorithmShelfNextFit is Next-Fit Decreasing Height (NFDH): boxes sorted by height are
+	// laid out left to right on horizontal shelves, opening a new shelf (or moving to the next
+	// bin) when the current one is full. Earlier shelves are never revisited.
+	AlgorithmShelfNextFit
+	// AlgorithmShelfFirstFit is First-Fit Decreasing Height (FFDH): like NFDH, but each box goes
+	// on the first shelf, in any bin, with enough room left.
+	AlgorithmShelfFirstFit
+)
+
+// shelf is a horizontal strip of a bin holding boxes side by side.
+type shelf struct {
+	bin    *Bin
+	y      float64 // Top of the shelf
+	height float64 // Height of the tallest (first) box on the shelf
+	used   float64 // Right edge of the boxes on the shelf, starting at the left margin
+}
+
+// shelfBox is a box with the orientation chosen for shelf packing.
+type shelfBox struct {
+	box           *Box
+	width, height float64
+	rotate        bool
+}
+
+// packShelves packs boxes with a shelf algorithm in O(n log n) for the sort plus one pass
+// over the boxes (and, for first-fit, over the open shelves).
+//
+// Shelf algorithms compute positions themselves, so only bins using the default MaxRects
+// backend without Spacing take part; other bins are left untouched. Boxes already in a bin are respected by
+// starting the first shelf below the lowest of them. Tag options and order penalties do not
+// apply; KeepApart rules and anchors do, so an anchored box that no shelf position puts
+// against its edges is left unpacked.
+func (p *Packer) packShelves(boxesToPack []*Box, options PackerOptions) []*Box {
+	packedBoxes := make([]*Box, 0)
+
+	bins := make([]*Bin, 0, len(p.Bins))
+	maxWidth := 0.0
+	for _, bin := range p.Bins {
+		if bin != nil && bin.usesMaxRects() && bin.Spacing <= 0 {
+			bins = append(bins, bin)
+			maxWidth = max(maxWidth, bin.Width-bin.Margins.Left-bin.Margins.Right)
+		}
+	}
+	if len(bins) == 0 {
+		return packedBoxes
+	}
+
+	// Lay boxes down on their long side when they still fit the widest bin, which keeps shelves low.
+	items := make([]shelfBox, 0, len(boxesToPack))
+	for _, box := range boxesToPack {
+		item := shelfBox{box: box, width: box.Width, height: box.Height}
+		if !box.ConstrainRotation && box.Height > box.Width && box.Height <= maxWidth {
+			item = shelfBox{box: box, width: box.Height, height: box.Width, rotate: true}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].height > items[j].height })
+
+	// Each bin's next shelf starts below its open shelves, or below any boxes and defects
+	// already placed, and shelves span the area inside the margins.
+	tops := make(map[*Bin]float64, len(bins))
+	for _, bin := range bins {
+		tops[bin] = bin.Margins.Top
+		for _, box := range bin.Boxes {
+			tops[bin] = max(tops[bin], box.Y+box.Height)
+		}
+		for _, defect := range bin.Defects {
+			tops[bin] = max(tops[bin], defect.Y+defect.Height)
+		}
+	}
+	apart := newSeparation(options.KeepApart, bins)
+	// An anchored box only goes where the shelf puts it against the edges it needs.
+	admits := func(bin *Bin, item shelfBox, x, y float64) bool {
+		rule := bin.anchoring(item.box.Anchor, false)
+		return rule == nil || rule.admits(x, y, item.width, item.height)
+	}
+	openShelf := func(bin *Bin, item shelfBox) *shelf {
+		m, tol := bin.Margins, bin.tolerance()
+		if item.width > bin.Width-m.Left-m.Right+tol || tops[bin]+item.height > bin.Height-m.Bottom+tol ||
+			!bin.accepts(item.box) || !apart.allows(bin, item.box) || !admits(bin, item, m.Left, tops[bin]) {
+			return nil
+		}
+		s := &shelf{bin: bin, y: tops[bin], height: item.height, used: m.Left}
+		tops[bin] += item.height
+		return s
+	}
+	fits := func(s *shelf, item shelfBox) bool {
+		return s != nil && s.used+item.width <= s.bin.Width-s.bin.Margins.Right+s.bin.tolerance() && item.height <= s.height+s.bin.tolerance() &&
+			s.bin.accepts(item.box) && apart.allows(s.bin, item.box) && admits(s.bin, item, s.used, s.y)
+	}
+
+	shelves := make([]*shelf, 0)
+	current := 0 // Next-fit: index of the bin currently being filled
+	for _, item := range items {
+		var target *shelf
+		switch options.Algorithm {
+		case AlgorithmShelfFirstFit:
+			for _, s := range shelves {
+				if fits(s, item) {
+					target = s
+					break
+				}
+			}
+			for i := 0; target == nil && i < len(bins); i++ {
+				if target = openShelf(bins[i], item); target != nil {
+					shelves = append(shelves, target)
+				}
+			}
+		default: // Next fit
+			if n := len(shelves); n > 0 && fits(shelves[n-1], item) {
+				target = shelves[n-1]
+			}
+			for i := current; target == nil && i < len(bins); i++ {
+				if target = openShelf(bins[i], item); target != nil {
+					shelves = append(shelves, target)
+					current = i
+				}
+			}
+		}
+		if target == nil {
+			continue // Fits in no remaining bin; reported as unpacked
+		}
+
+		target.bin.commit(item.box, target.used, target.y, item.rotate, false)
+		target.used += item.width
+		apart.place(target.bin, item.box)
+		packedBoxes = append(packedBoxes, item.box)
+		p.events.place(target.bin, item.box)
+		if options.Limit > 0 && int64(len(packedBoxes)) >= options.Limit {
+			break
+		}
+	}
+	return packedBoxes
+}
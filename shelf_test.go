@@ -0,0 +1,67 @@
+package binpacking
+
+import "testing"
+
+func TestShelfPacking(t *testing.T) {
+	newBoxes := func() []*Box {
+		boxes := make([]*Box, 0)
+		for i := 0; i < 200; i++ {
+			boxes = append(boxes, NewBox(float64(2+i*7%13), float64(1+i*3%11), i%5 == 0))
+		}
+		return boxes
+	}
+
+	for _, algorithm := range []PackingAlgorithm{AlgorithmShelfNextFit, AlgorithmShelfFirstFit} {
+		bins := []*Bin{NewBin(60, 40, nil), NewBin(50, 50, nil)}
+		packer := NewPacker(bins)
+		packed := packer.Pack(newBoxes(), PackerOptions{Algorithm: algorithm})
+
+		if len(packed) == 0 {
+			t.Fatalf("Algorithm %d: no boxes packed", algorithm)
+		}
+		if len(packed)+len(packer.UnpackedBoxes) != 200 {
+			t.Errorf("Algorithm %d: packed+unpacked: got %d, want %d", algorithm, len(packed)+len(packer.UnpackedBoxes), 200)
+		}
+		for _, bin := range bins {
+			for i, a := range bin.Boxes {
+				if a.X+a.Width > bin.Width || a.Y+a.Height > bin.Height {
+					t.Errorf("Algorithm %d: box exceeds bin: %s", algorithm, a.Label())
+				}
+				for _, c := range bin.Boxes[i+1:] {
+					if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+						t.Errorf("Algorithm %d: boxes overlap: %s and %s", algorithm, a.Label(), c.Label())
+					}
+				}
+			}
+		}
+	}
+
+	t.Run("first fit revisits earlier shelves", func(t *testing.T) {
+		boxes := func() []*Box {
+			return []*Box{NewBox(6, 10, true), NewBox(6, 8, true), NewBox(4, 2, true)}
+		}
+		next := NewBin(10, 18, nil)
+		NewPacker([]*Bin{next}).Pack(boxes(), PackerOptions{Algorithm: AlgorithmShelfNextFit})
+		first := NewBin(10, 18, nil)
+		NewPacker([]*Bin{first}).Pack(boxes(), PackerOptions{Algorithm: AlgorithmShelfFirstFit})
+
+		// The 4x2 box fits next to the first 6x10 box: FFDH finds that spot, NFDH does not.
+		var nextSmall, firstSmall *Box
+		for _, b := range next.Boxes {
+			if b.Width == 4 {
+				nextSmall = b
+			}
+		}
+		for _, b := range first.Boxes {
+			if b.Width == 4 {
+				firstSmall = b
+			}
+		}
+		if nextSmall == nil || nextSmall.Y != 10 {
+			t.Errorf("NFDH small box: got %v, want it on the second shelf", nextSmall)
+		}
+		if firstSmall == nil || firstSmall.Y != 0 {
+			t.Errorf("FFDH small box: got %v, want it on the first shelf", firstSmall)
+		}
+	})
+}
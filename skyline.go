@@ -0,0 +1,216 @@
+package binpacking
+
+import "slices"
+
+// SkylineHeuristic selects how a SkylineBackend scores candidate positions on the skyline.
+type SkylineHeuristic int
+
+const (
+	// SkylineBottomLeft places each box where its top edge ends up lowest,
+	// preferring the left-most position on ties.
+	SkylineBottomLeft SkylineHeuristic = iota
+	// SkylineMinWaste places each box where it leaves the least unusable area beneath it,
+	// using the top edge as a tie-breaker.
+	SkylineMinWaste
+)
+
+// SkylineOptions configures a bin created by NewSkylineBin.
+type SkylineOptions struct {
+	Heuristic SkylineHeuristic // How positions on the skyline are scored
+	// WasteMap keeps the gaps left beneath boxes as free rectangles and tries them
+	// before the skyline, recovering most of the density lost by the skyline model.
+	WasteMap bool
+	// Placement scores candidate rectangles in the waste map. Nil uses BestShortSideFit.
+	Placement PlacementStrategyFunc
+}
+
+// skylineNode is a horizontal segment of the skyline: the area at and beyond Y
+// is free between X and X+Width.
+type skylineNode struct {
+	X, Y, Width float64
+}
+
+// SkylineBackend tracks the free area of a bin as a skyline: the boundary of the placed
+// boxes seen from the open side of the bin. Finding and committing placements is linear in
+// the number of skyline segments, which makes it much faster than MaxRects for large
+// sprite-atlas style workloads, at some cost in density.
+//
+// The bin's FreeSpaces are kept up to date for reporting: they contain the waste map
+// rectangles followed by one rectangle above each skyline segment.
+type SkylineBackend struct {
+	Heuristic SkylineHeuristic // How positions on the skyline are scored
+	WasteMap  bool             // Whether gaps beneath boxes are reused
+
+	nodes []skylineNode     // Skyline segments ordered by X, covering the full bin width
+	waste []*FreeSpaceBox   // Disjoint gaps beneath placed boxes
+	split GuillotineBackend // Splits waste rectangles when a box is placed in one
+}
+
+// NewSkylineBin creates a bin that uses a SkylineBackend.
+func NewSkylineBin(width, height float64, options SkylineOptions) *Bin {
+	bin := NewBin(width, height, options.Placement)
+	bin.Backend = &SkylineBackend{
+		Heuristic: options.Heuristic,
+		WasteMap:  options.WasteMap,
+		nodes:     []skylineNode{{X: 0, Y: 0, Width: width}},
+		split:     GuillotineBackend{SplitRule: SplitMaximizeArea},
+	}
+	return bin
+}
+
+// FindPlacement implements Backend. Boxes are tried in the waste map first, using strategy,
+// and then on the skyline using the backend's heuristic.
+func (s *SkylineBackend) FindPlacement(bin *Bin, box *Box, strategy PlacementStrategyFunc) PlacementInfo {
+	s.ensureNodes(bin)
+	if len(s.waste) > 0 { // Without WasteMap, only gaps beneath fixed boxes are kept
+		if info := FindBestPlacement(box, s.waste, strategy); info.Fits {
+			return info
+		}
+	}
+
+	best := PlacementInfo{Score: NoFit}
+	try := func(i int, width, height float64, rotated bool) {
+		y, ok := s.fitAt(i, width, height, bin)
+		if !ok {
+			return
+		}
+		var score Score
+		switch s.Heuristic {
+		case SkylineMinWaste:
+			score = NewScoreWithTieBreak(s.wasteBelow(i, width, y), y+height)
+		default:
+			score = NewScoreWithTieBreak(y+height, s.nodes[i].X)
+		}
+		if score.Less(best.Score) {
+			best = PlacementInfo{Score: score, X: s.nodes[i].X, Y: y, NeedsRotation: rotated, Fits: true}
+		}
+	}
+	for i := range s.nodes {
+		try(i, box.Width, box.Height, false)
+		if !box.ConstrainRotation && box.Width != box.Height {
+			try(i, box.Height, box.Width, true)
+		}
+	}
+	return best
+}
+
+// Place implements Backend.
+func (s *SkylineBackend) Place(bin *Bin, box *Box, placement PlacementInfo) {
+	s.ensureNodes(bin)
+	if placement.ChosenSpace != nil {
+		// Placed in a waste rectangle; the skyline is unaffected.
+		newWaste := make([]*FreeSpaceBox, 0, len(s.waste)+1)
+		for _, space := range s.waste {
+			if space == placement.ChosenSpace {
+				newWaste = append(newWaste, s.split.split(space, box, bin.tolerance())...)
+			} else {
+				newWaste = append(newWaste, space)
+			}
+		}
+		s.waste = newWaste
+		s.syncFreeSpaces(bin)
+		return
+	}
+
+	left, right := box.X, box.X+box.Width
+	top := box.Y + box.Height
+	newNodes := make([]skylineNode, 0, len(s.nodes)+2)
+	inserted := false
+	for _, n := range s.nodes {
+		nodeRight := n.X + n.Width
+		if nodeRight <= left || n.X >= right {
+			newNodes = append(newNodes, n) // Untouched segment
+			continue
+		}
+		if s.WasteMap && n.Y < box.Y {
+			// The gap between this segment and the bottom of the box becomes waste.
+			x := max(n.X, left)
+			s.waste = append(s.waste, &FreeSpaceBox{X: x, Y: n.Y, Width: min(nodeRight, right) - x, Height: box.Y - n.Y})
+		}
+		if n.X < left {
+			newNodes = append(newNodes, skylineNode{X: n.X, Y: n.Y, Width: left - n.X})
+		}
+		if !inserted {
+			newNodes = append(newNodes, skylineNode{X: left, Y: top, Width: box.Width})
+			inserted = true
+		}
+		if nodeRight > right {
+			newNodes = append(newNodes, skylineNode{X: right, Y: n.Y, Width: nodeRight - right})
+		}
+	}
+
+	// Merge neighbouring segments at the same height.
+	merged := newNodes[:0]
+	for _, n := range newNodes {
+		if last := len(merged) - 1; last >= 0 && merged[last].Y == n.Y {
+			merged[last].Width += n.Width
+			continue
+		}
+		merged = append(merged, n)
+	}
+	s.nodes = merged
+	s.syncFreeSpaces(bin)
+}
+
+// clone returns a copy of the backend with its own skyline and waste map.
+func (s *SkylineBackend) clone() *SkylineBackend {
+	clone := *s
+	clone.nodes = slices.Clone(s.nodes)
+	clone.waste = make([]*FreeSpaceBox, len(s.waste))
+	for i, space := range s.waste {
+		copied := *space
+		clone.waste[i] = &copied
+	}
+	return &clone
+}
+
+// ensureNodes initializes the skyline for backends not created by NewSkylineBin.
+func (s *SkylineBackend) ensureNodes(bin *Bin) {
+	if s.nodes == nil {
+		s.nodes = []skylineNode{{X: 0, Y: 0, Width: bin.Width}}
+	}
+}
+
+// fitAt returns the Y position of a box of the given size whose left edge is at segment i,
+// resting on the highest segment it spans.
+func (s *SkylineBackend) fitAt(i int, width, height float64, bin *Bin) (float64, bool) {
+	left := s.nodes[i].X
+	right := left + width
+	tol := bin.tolerance()
+	if right > bin.Width+tol {
+		return 0, false
+	}
+	y := 0.0
+	for j := i; j < len(s.nodes) && s.nodes[j].X < right-tol; j++ {
+		y = max(y, s.nodes[j].Y)
+		if y+height > bin.Height+tol {
+			return 0, false
+		}
+	}
+	return y, true
+}
+
+// wasteBelow returns the area trapped beneath a box of the given width resting at height y
+// with its left edge at segment i.
+func (s *SkylineBackend) wasteBelow(i int, width, y float64) float64 {
+	left := s.nodes[i].X
+	right := left + width
+	waste := 0.0
+	for j := i; j < len(s.nodes) && s.nodes[j].X < right; j++ {
+		n := s.nodes[j]
+		waste += (min(n.X+n.Width, right) - max(n.X, left)) * (y - n.Y)
+	}
+	return waste
+}
+
+// syncFreeSpaces refreshes the bin's FreeSpaces from the waste map and the skyline.
+func (s *SkylineBackend) syncFreeSpaces(bin *Bin) {
+	spaces := make([]*FreeSpaceBox, 0, len(s.waste)+len(s.nodes))
+	spaces = append(spaces, s.waste...)
+	for _, n := range s.nodes {
+		if n.Y < bin.Height {
+			spaces = append(spaces, &FreeSpaceBox{X: n.X, Y: n.Y, Width: n.Width, Height: bin.Height - n.Y})
+		}
+	}
+	bin.FreeSpaces = spaces
+}
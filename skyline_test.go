@@ -0,0 +1,53 @@
+package binpacking
+
+import "testing"
+
+func TestSkylineBin(t *testing.T) {
+	for _, opts := range []SkylineOptions{
+		{Heuristic: SkylineBottomLeft},
+		{Heuristic: SkylineMinWaste},
+		{Heuristic: SkylineBottomLeft, WasteMap: true},
+		{Heuristic: SkylineMinWaste, WasteMap: true},
+	} {
+		bin := NewSkylineBin(128, 128, opts)
+		boxes := make([]*Box, 0)
+		for i := 0; i < 60; i++ {
+			boxes = append(boxes, NewBox(float64(4+i*7%29), float64(3+i*11%19), false))
+		}
+		NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+
+		if len(bin.Boxes) < 20 {
+			t.Errorf("%+v: packed only %d boxes", opts, len(bin.Boxes))
+		}
+		for i, a := range bin.Boxes {
+			if a.X < 0 || a.Y < 0 || a.X+a.Width > bin.Width || a.Y+a.Height > bin.Height {
+				t.Errorf("%+v: box exceeds bin: %s", opts, a.Label())
+			}
+			for _, c := range bin.Boxes[i+1:] {
+				if a.X < c.X+c.Width && c.X < a.X+a.Width && a.Y < c.Y+c.Height && c.Y < a.Y+a.Height {
+					t.Errorf("%+v: boxes overlap: %s and %s", opts, a.Label(), c.Label())
+				}
+			}
+		}
+		for _, space := range bin.FreeSpaces {
+			for _, box := range bin.Boxes {
+				if intersects(space, box, 0) {
+					t.Errorf("%+v: free space %+v overlaps box %s", opts, *space, box.Label())
+				}
+			}
+		}
+	}
+
+	t.Run("reuses waste map gaps", func(t *testing.T) {
+		bin := NewSkylineBin(20, 20, SkylineOptions{WasteMap: true})
+		bin.Insert(NewBox(10, 5, true)) // Skyline: 0..10 at 5, 10..20 at 0
+		bin.Insert(NewBox(20, 5, true)) // Rests at y=5, leaving a 10x5 gap at [10,0]
+		gap := NewBox(10, 5, true)
+		if !bin.Insert(gap) {
+			t.Fatalf("Insert into gap: got false, want true")
+		}
+		if gap.X != 10 || gap.Y != 0 {
+			t.Errorf("Gap box position: got [%g,%g], want [10,0]", gap.X, gap.Y)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package binpacking
+
+import (
+	"cmp"
+	"slices"
+)
+
+// BoxOrder selects the order in which Pack inserts the boxes; see PackerOptions.SortBy.
+type BoxOrder int
+
+const (
+	// SortNone keeps the boxes in the order given, and the best-fit algorithm picks the
+	// best scoring box/bin pair at each step whatever that order is.
+	SortNone BoxOrder = iota
+	// SortAreaDesc inserts the boxes largest area first.
+	SortAreaDesc
+	// SortLongestSideDesc inserts the boxes by their longer side, longest first.
+	SortLongestSideDesc
+	// SortPerimeterDesc inserts the boxes largest perimeter first.
+	SortPerimeterDesc
+	// SortWidthDesc inserts the boxes widest first, as given, before any rotation.
+	SortWidthDesc
+)
+
+// key returns the value the order sorts boxes by, largest first.
+func (o BoxOrder) key(box *Box) float64 {
+	switch o {
+	case SortAreaDesc:
+		return box.Area()
+	case SortLongestSideDesc:
+		return max(box.Width, box.Height)
+	case SortPerimeterDesc:
+		return box.Width + box.Height
+	case SortWidthDesc:
+		return box.Width
+	}
+	return 0
+}
+
+// Sort sorts the boxes into the order, keeping boxes with equal keys in their relative
+// order. SortNone leaves them as they are.
+func (o BoxOrder) Sort(boxes []*Box) {
+	if o == SortNone {
+		return
+	}
+	slices.SortStableFunc(boxes, func(a, b *Box) int { return cmp.Compare(o.key(b), o.key(a)) })
+}
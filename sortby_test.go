@@ -0,0 +1,75 @@
+package binpacking
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	newBoxes := func() []*Box {
+		boxes := []*Box{NewBox(2, 9, false), NewBox(5, 5, false), NewBox(8, 1, false), NewBox(4, 4, false), NewBox(3, 6, false)}
+		for i, box := range boxes {
+			box.ID = string(rune('a' + i))
+		}
+		return boxes
+	}
+	ids := func(boxes []*Box) string {
+		s := ""
+		for _, box := range boxes {
+			s += box.ID
+		}
+		return s
+	}
+
+	t.Run("Sort", func(t *testing.T) {
+		tests := []struct {
+			order BoxOrder
+			want  string
+		}{
+			{SortNone, "abcde"},
+			{SortAreaDesc, "baedc"},
+			{SortLongestSideDesc, "acebd"},
+			{SortPerimeterDesc, "abced"},
+			{SortWidthDesc, "cbdea"},
+		}
+		for _, tt := range tests {
+			boxes := newBoxes()
+			tt.order.Sort(boxes)
+			if got := ids(boxes); got != tt.want {
+				t.Errorf("order %d: got %s, want %s", tt.order, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("Pack", func(t *testing.T) {
+		boxes := newBoxes()
+		observer := &recordingObserver{}
+		packer := NewPacker([]*Bin{NewBin(20, 20, nil)})
+		packer.Pack(boxes, NewPackerOptions(WithSortBy(SortWidthDesc), WithObserver(observer)))
+		var got []string
+		for _, placement := range observer.placements {
+			got = append(got, placement.Box.ID)
+		}
+		if want := []string{"c", "b", "d", "e", "a"}; !slices.Equal(got, want) {
+			t.Errorf("got placements %v, want %v", got, want)
+		}
+		if ids(boxes) != "abcde" {
+			t.Errorf("got the caller's slice reordered to %s", ids(boxes))
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{Bins: []JobBin{{Width: 10, Height: 10, Qty: 1}}, Boxes: []JobBox{{Width: 5, Height: 5}}, Options: JobOptions{Sort: "area"}}
+		if _, err := job.Pack(); err != nil {
+			t.Fatal(err)
+		}
+		job.Options.Sort = "height"
+		if _, err := job.Pack(); !errors.Is(err, ErrInvalidJob) {
+			t.Errorf("got %v, want ErrInvalidJob", err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package binpacking
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetSpacing sets the minimum gap kept between boxes and between boxes and the edges,
+// margins and defects of the bin, modelling saw-blade kerf or texture-atlas padding.
+// The bin must not hold any boxes yet. Positions stay those of the boxes themselves.
+// The returned error wraps ErrInvalidDimensions if spacing is negative or not finite,
+// and ErrPlacement if the bin is not empty or its backend does not implement FixedPlacer.
+//
+// The shelf algorithms and ExactPacker compute positions themselves and skip bins with spacing.
+func (b *Bin) SetSpacing(spacing float64) error {
+	if spacing < 0 || math.IsNaN(spacing) || math.IsInf(spacing, 0) {
+		return fmt.Errorf("%w: spacing %g", ErrInvalidDimensions, spacing)
+	}
+	if len(b.Boxes) > 0 {
+		return fmt.Errorf("%w: spacing must be set before boxes are placed", ErrPlacement)
+	}
+	if _, ok := b.backend().(FixedPlacer); !ok {
+		return fmt.Errorf("%w: backend %T does not support spacing", ErrPlacement, b.backend())
+	}
+	b.Spacing = spacing
+	b.reset() // Rebuilds the free area with the gap along the edges
+	return nil
+}
+
+// padded returns box grown by the bin's spacing on its right and bottom sides, which is
+// the area it takes up in the free space bookkeeping, or box itself without spacing.
+// Two padded rectangles that do not overlap are at least the spacing apart.
+func (b *Bin) padded(box *Box) *Box {
+	if b.Spacing <= 0 {
+		return box
+	}
+	padded := *box
+	padded.cluster = nil
+	padded.Width += b.Spacing
+	padded.Height += b.Spacing
+	return &padded
+}
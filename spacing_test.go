@@ -0,0 +1,75 @@
+package binpacking
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpacing(t *testing.T) {
+	const spacing = 1.0
+	// gapsKept checks the gap between every pair of boxes and between boxes and the edges.
+	gapsKept := func(t *testing.T, bin *Bin) {
+		t.Helper()
+		for i, a := range bin.Boxes {
+			if a.X < spacing || a.Y < spacing || a.X+a.Width > bin.Width-spacing || a.Y+a.Height > bin.Height-spacing {
+				t.Errorf("Box %s is closer than %g to an edge", a.Label(), spacing)
+			}
+			for _, c := range bin.Boxes[i+1:] {
+				apartX := a.X+a.Width+spacing <= c.X || c.X+c.Width+spacing <= a.X
+				apartY := a.Y+a.Height+spacing <= c.Y || c.Y+c.Height+spacing <= a.Y
+				if !apartX && !apartY {
+					t.Errorf("Boxes %s and %s are closer than %g", a.Label(), c.Label(), spacing)
+				}
+			}
+		}
+	}
+
+	bins := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(10, 10, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(10, 10, nil, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(10, 10, SkylineOptions{}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(10, 10) },
+		"contact":    func() *Bin { return NewContactPointBin(10, 10) },
+	}
+	for name, newBin := range bins {
+		t.Run("keeps the gap with "+name, func(t *testing.T) {
+			bin := newBin()
+			if err := bin.SetSpacing(spacing); err != nil {
+				t.Fatalf("SetSpacing: %v", err)
+			}
+			boxes := make([]*Box, 0)
+			for i := 0; i < 20; i++ {
+				boxes = append(boxes, NewBox(2, 2, true))
+			}
+			packed := NewPacker([]*Bin{bin}).Pack(boxes, PackerOptions{})
+			gapsKept(t, bin)
+			// Each row fits three boxes: 1 + 3*(2+1) = 10.
+			if len(packed) != 9 {
+				t.Errorf("Packed: got %d, want %d", len(packed), 9)
+			}
+		})
+	}
+
+	t.Run("applies to fixed placements", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.SetSpacing(spacing)
+		if err := bin.Place(NewBox(2, 2, true), 0, 0); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Place at the edge: got %v, want ErrPlacement", err)
+		}
+		if err := bin.Place(NewBox(2, 2, true), 1, 1); err != nil {
+			t.Fatalf("Place: %v", err)
+		}
+		if err := bin.Place(NewBox(2, 2, true), 3.5, 1); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Place within the gap: got %v, want ErrPlacement", err)
+		}
+		bin.Insert(NewBox(3, 3, true))
+		gapsKept(t, bin)
+	})
+
+	t.Run("rejects invalid spacing", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		if err := bin.SetSpacing(-1); !errors.Is(err, ErrInvalidDimensions) {
+			t.Errorf("Negative: got %v, want ErrInvalidDimensions", err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package binpacking
+
+import (
+	"math"
+	"reflect"
+)
+
+// SpreadFit returns a PlacementStrategyFunc for bin that spreads boxes out instead of
+// packing them tightly, the inverse of the best-fit strategies: it prefers the position
+// farthest from the boxes already in the bin, e.g. for test cuts spread over a sheet or to
+// distribute weight. The score is the negated clearance, the distance from the box to the
+// nearest box in the bin, breaking ties with the negated area of the free space. An empty
+// bin offers the clearance of its diagonal, so with several bins the best-fit algorithm
+// starts every empty bin before it puts a second box into any, spreading the boxes across
+// the bins as well.
+//
+// In a MaxRects bin a box goes at whichever corner or the center of its free space has the
+// most clearance; the other backends place boxes at positions of their own, which are
+// scored where they fall. Like ContactPointFit it is bound to a bin:
+//
+//	bin := NewBin(w, h, nil)
+//	bin.Placement = SpreadFit(bin)
+//
+// SpreadFit is not inlined so that all its strategies share one function, which lets bin
+// serialization recognize and rebind them.
+//
+//go:noinline
+func SpreadFit(bin *Bin) PlacementStrategyFunc {
+	return func(freeSpace *FreeSpaceBox, rectWidth, rectHeight float64) Score {
+		if bin == nil {
+			return BestShortSideFit(freeSpace, rectWidth, rectHeight)
+		}
+		x, y := bin.spreadPosition(freeSpace, rectWidth, rectHeight)
+		return NewScoreWithTieBreak(-bin.clearance(x, y, rectWidth, rectHeight), -freeSpace.Width*freeSpace.Height)
+	}
+}
+
+// spreadFitCode is the code pointer shared by the strategies SpreadFit returns.
+var spreadFitCode = reflect.ValueOf(SpreadFit(nil)).Pointer()
+
+// spreadPosition returns where in space SpreadFit puts a box of the given size: the
+// position with the most clearance among the corners and the center of the space in a
+// MaxRects bin, the first on ties, else the top-left corner.
+func (b *Bin) spreadPosition(space *FreeSpaceBox, width, height float64) (float64, float64) {
+	if !b.usesMaxRects() || len(b.Boxes) == 0 {
+		return space.X, space.Y
+	}
+	far := [2]float64{max(space.X, space.X+space.Width-width), max(space.Y, space.Y+space.Height-height)}
+	candidates := [5][2]float64{
+		{space.X, space.Y}, {far[0], space.Y}, {space.X, far[1]}, far,
+		{(space.X + far[0]) / 2, (space.Y + far[1]) / 2},
+	}
+	bestX, bestY, best := space.X, space.Y, -1.0
+	for _, c := range candidates {
+		if clearance := b.clearance(c[0], c[1], width, height); clearance > best {
+			bestX, bestY, best = c[0], c[1], clearance
+		}
+	}
+	return bestX, bestY
+}
+
+// clearance returns the distance from the rectangle at (x, y) to the nearest box in the
+// bin, or the length of the bin's diagonal if it holds none.
+func (b *Bin) clearance(x, y, width, height float64) float64 {
+	nearest := math.Hypot(b.Width, b.Height)
+	for _, box := range b.Boxes {
+		dx := max(box.X-(x+width), x-(box.X+box.Width), 0)
+		dy := max(box.Y-(y+height), y-(box.Y+box.Height), 0)
+		nearest = min(nearest, math.Hypot(dx, dy))
+	}
+	return nearest
+}
@@ -0,0 +1,101 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSpreadFit(t *testing.T) {
+	t.Run("corners", func(t *testing.T) {
+		// The first boxes go into opposite corners, then into the remaining ones.
+		bin := NewBin(10, 10, nil)
+		bin.Placement = SpreadFit(bin)
+		wants := [][2]float64{{0, 0}, {8, 8}, {0, 8}, {8, 0}}
+		for i, want := range wants {
+			box := NewBox(2, 2, true)
+			if !bin.Insert(box) {
+				t.Fatalf("box %d: got no fit", i)
+			}
+			if box.X != want[0] || box.Y != want[1] {
+				t.Errorf("box %d: got [%g,%g], want %v", i, box.X, box.Y, want)
+			}
+		}
+		box := NewBox(2, 2, true)
+		bin.Insert(box)
+		if box.X != 4 || box.Y != 4 {
+			t.Errorf("got [%g,%g] for the fifth box, want the center [4,4]", box.X, box.Y)
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("across bins", func(t *testing.T) {
+		bins := []*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil), NewBin(10, 10, nil)}
+		for _, bin := range bins {
+			bin.Placement = SpreadFit(bin)
+		}
+		observer := &recordingObserver{}
+		packer := NewPacker(bins)
+		boxes := make([]*Box, 6)
+		for i := range boxes {
+			boxes[i] = NewBox(2, 2, true)
+		}
+		packer.Pack(boxes, PackerOptions{Observer: observer})
+		var got []int
+		for _, placement := range observer.placements {
+			got = append(got, placement.BinIndex)
+		}
+		if want := []int{0, 1, 2, 0, 1, 2}; !slices.Equal(got, want) {
+			t.Errorf("got bins %v, want %v", got, want)
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("dense", func(t *testing.T) {
+		// Spreading goes on once no box can keep its distance, until the bin is full.
+		bin := NewBin(10, 10, nil)
+		bin.Placement = SpreadFit(bin)
+		for bin.Insert(NewBox(2, 2, true)) {
+		}
+		if len(bin.Boxes) < 12 {
+			t.Errorf("got %d boxes, want at least 12", len(bin.Boxes))
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Placement = SpreadFit(bin)
+		bin.Insert(NewBox(2, 2, true))
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		box := NewBox(2, 2, true)
+		restored.Insert(box)
+		if box.X != 8 || box.Y != 8 {
+			t.Errorf("got [%g,%g] in the restored bin, want [8,8]", box.X, box.Y)
+		}
+	})
+
+	t.Run("job", func(t *testing.T) {
+		job := &Job{Bins: []JobBin{{Width: 10, Height: 10, Qty: 2}}, Boxes: []JobBox{{Width: 2, Height: 2}, {Width: 2, Height: 2}}, Options: JobOptions{Strategy: "spread"}}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.Placements; len(got) != 2 || got[0].BinIndex == got[1].BinIndex {
+			t.Errorf("got placements %+v, want one in each bin", got)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package binpacking
+
+import "time"
+
+// PackStats are the aggregate figures of a PackResult, for reports and monitoring.
+// Box counts and PackedArea cover the boxes of the last call to Pack; the bin figures
+// cover every box in the bins, whichever call placed it.
+type PackStats struct {
+	TotalBoxes    int // Boxes the last call to Pack was given, not counting nil or already packed ones
+	PackedBoxes   int // Boxes packed by the last call to Pack
+	UnpackedBoxes int // Boxes left unpacked by the last call to Pack
+
+	BinsProvided int // Bins of the packer, including those opened from templates
+	BinsUsed     int // Bins holding at least one box
+
+	PackedArea   float64 // Total area of the boxes packed by the last call to Pack
+	UnpackedArea float64 // Total area of the boxes left unpacked
+	BinArea      float64 // Total area of the bins in use
+	WasteArea    float64 // Area of the bins in use not occupied by boxes
+
+	// Efficiency is the percentage of the area of the bins in use occupied by boxes, and
+	// BinEfficiency that of each bin, in the order of PackResult.Bins, zero for unused
+	// and nil bins. MinEfficiency, MaxEfficiency and MeanEfficiency summarize the bins
+	// in use; they are zero if no bin is.
+	Efficiency     float64
+	BinEfficiency  []float64
+	MinEfficiency  float64
+	MaxEfficiency  float64
+	MeanEfficiency float64
+
+	Elapsed  time.Duration // Time the last call to Pack took
+	TimedOut bool          // Whether PackerOptions.TimeLimit cut the last call to Pack short
+}
+
+// Stats computes the aggregate figures of the result.
+func (r *PackResult) Stats() PackStats {
+	stats := PackStats{
+		TotalBoxes:    len(r.Packed) + len(r.Unpacked),
+		PackedBoxes:   len(r.Packed),
+		UnpackedBoxes: len(r.Unpacked),
+		BinsProvided:  len(r.Bins),
+		PackedArea:    r.PackedArea,
+		UnpackedArea:  r.UnpackedArea,
+		Efficiency:    r.Efficiency,
+		BinEfficiency: make([]float64, len(r.Bins)),
+		Elapsed:       r.Elapsed,
+		TimedOut:      r.TimedOut,
+	}
+	for i, bin := range r.Bins {
+		if bin == nil || len(bin.Boxes) == 0 {
+			continue
+		}
+		efficiency := bin.Efficiency()
+		stats.BinEfficiency[i] = efficiency
+		if stats.BinsUsed == 0 || efficiency < stats.MinEfficiency {
+			stats.MinEfficiency = efficiency
+		}
+		stats.MaxEfficiency = max(stats.MaxEfficiency, efficiency)
+		stats.MeanEfficiency += efficiency
+		stats.BinsUsed++
+
+		stats.BinArea += bin.Area()
+		stats.WasteArea += bin.Area()
+		for _, box := range bin.Boxes {
+			stats.WasteArea -= box.Area()
+		}
+	}
+	if stats.BinsUsed > 0 {
+		stats.MeanEfficiency /= float64(stats.BinsUsed)
+	}
+	return stats
+}
+
+// Stats computes the aggregate figures of the last call to Pack; see PackResult.Stats.
+func (p *Packer) Stats() PackStats {
+	return p.Result().Stats()
+}
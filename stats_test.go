@@ -0,0 +1,41 @@
+package binpacking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPackStats(t *testing.T) {
+	packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil), NewBin(20, 20, nil)})
+	boxes := []*Box{NewBox(20, 20, false), NewBox(10, 10, false), NewBox(5, 5, false), NewBox(30, 30, false), nil}
+	packer.Pack(boxes, PackerOptions{TimeLimit: time.Minute})
+	stats := packer.Stats()
+
+	if stats.TotalBoxes != 4 || stats.PackedBoxes != 3 || stats.UnpackedBoxes != 1 {
+		t.Errorf("got %d boxes, %d packed, %d unpacked, want 4, 3 and 1", stats.TotalBoxes, stats.PackedBoxes, stats.UnpackedBoxes)
+	}
+	if stats.BinsProvided != 3 || stats.BinsUsed != 3 {
+		t.Errorf("got %d bins used of %d, want 3 of 3", stats.BinsUsed, stats.BinsProvided)
+	}
+	if stats.PackedArea != 525 || stats.UnpackedArea != 900 || stats.BinArea != 600 || stats.WasteArea != 75 {
+		t.Errorf("got packed %g, unpacked %g, bin %g, waste %g, want 525, 900, 600 and 75",
+			stats.PackedArea, stats.UnpackedArea, stats.BinArea, stats.WasteArea)
+	}
+	if stats.Efficiency != 87.5 || stats.MinEfficiency != 25 || stats.MaxEfficiency != 100 || stats.MeanEfficiency != 75 {
+		t.Errorf("got efficiency %g, min %g, max %g, mean %g, want 87.5, 25, 100 and 75",
+			stats.Efficiency, stats.MinEfficiency, stats.MaxEfficiency, stats.MeanEfficiency)
+	}
+	if len(stats.BinEfficiency) != 3 || stats.BinEfficiency[2] != 100 {
+		t.Errorf("got bin efficiencies %v, want the large bin full", stats.BinEfficiency)
+	}
+	if stats.Elapsed <= 0 || stats.TimedOut {
+		t.Errorf("got elapsed %v, timed out %v", stats.Elapsed, stats.TimedOut)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		stats := NewPacker([]*Bin{NewBin(10, 10, nil)}).Stats()
+		if stats.BinsUsed != 0 || stats.MinEfficiency != 0 || stats.MeanEfficiency != 0 || stats.BinEfficiency[0] != 0 {
+			t.Errorf("got %+v, want no bins in use", stats)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBinStock(t *testing.T) {
+	want := func(t *testing.T, bin *Bin) {
+		t.Helper()
+		if bin.Name != "PLY-18" || bin.Material != "birch plywood" || bin.Thickness != 18 {
+			t.Errorf("got %q, %q, %g, want PLY-18, birch plywood, 18", bin.Name, bin.Material, bin.Thickness)
+		}
+	}
+
+	t.Run("options", func(t *testing.T) {
+		bin, err := NewBinWith(10, 10, WithName("PLY-18"), WithMaterial("birch plywood", 18))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want(t, bin)
+		want(t, bin.Clone())
+	})
+
+	t.Run("template", func(t *testing.T) {
+		want(t, BinTemplate{Width: 10, Height: 10, Name: "PLY-18", Material: "birch plywood", Thickness: 18}.NewBin())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.Name, bin.Material, bin.Thickness = "PLY-18", "birch plywood", 18
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		want(t, &restored)
+
+		report, err := json.Marshal(bin.Report())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(report), `"name":"PLY-18","material":"birch plywood","thickness":18`) {
+			t.Errorf("got report %s, want the stock fields", report)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		bins, templates, err := ReadBinsCSV(strings.NewReader("name,width,height,qty,material,thickness\nPLY-18,10,10,1,birch plywood,18\nPLY-18,10,10,,birch plywood,18\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bins) != 1 || len(templates) != 1 {
+			t.Fatalf("got %d bins and %d templates, want 1 and 1", len(bins), len(templates))
+		}
+		want(t, bins[0])
+		want(t, templates[0].NewBin())
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var job Job
+		input := `{
+			"bins": [{"width": 10, "height": 10, "qty": 1, "name": "PLY-18", "material": "birch plywood", "thickness": 18},
+				{"width": 10, "height": 10, "name": "MDF-12", "material": "MDF", "thickness": 12}],
+			"boxes": [{"width": 10, "height": 10, "qty": 2}]
+		}`
+		if err := json.Unmarshal([]byte(input), &job); err != nil {
+			t.Fatal(err)
+		}
+		result, err := job.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bins := NewJobResult(result).Bins
+		if len(bins) != 2 {
+			t.Fatalf("got %d bins, want 2", len(bins))
+		}
+		got := [][3]any{{bins[0].Name, bins[0].Material, bins[0].Thickness}, {bins[1].Name, bins[1].Material, bins[1].Thickness}}
+		if got[0] != [3]any{"PLY-18", "birch plywood", 18.0} || got[1] != [3]any{"MDF-12", "MDF", 12.0} {
+			t.Errorf("got stock %v, want PLY-18 and MDF-12", got)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package binpacking
+
+import "context"
+
+// StreamEvent reports the outcome of one box given to a StreamPacker.
+type StreamEvent struct {
+	Box *Box
+	// Placement is where the box ended up, as in PackResult.Placements. A box that
+	// ObjectiveMinCost moves to a cheaper bin is reported again with its new bin.
+	Placement BoxPlacement
+	// Err is why the box was left unpacked: its Box.Validate error, ErrLimitReached,
+	// ErrTimeLimit, ErrExceedsWeight or ErrNoFit. Nil if the box was placed.
+	Err error
+}
+
+// StreamPacker packs boxes that arrive one at a time, such as items reaching a warehouse
+// station, into the bins of a Packer. Boxes are buffered until BatchSize of them are
+// waiting and then packed together with Packer.Pack, so the packer can order a small
+// batch instead of taking each box as it comes; boxes already in the bins never move.
+// Bins opened from Options.BinTemplates stay open for later batches.
+//
+// Packer.Result describes the last batch only; Packer.BinUsage reports the bins as they
+// are. A StreamPacker is not safe for concurrent use.
+type StreamPacker struct {
+	Packer  *Packer       // The packer whose bins the boxes go into
+	Options PackerOptions // Options used to pack each batch; Options.Observer still receives every event
+
+	// BatchSize is the number of boxes Add buffers before packing them. Zero or one
+	// packs every box as soon as it is added.
+	BatchSize int
+
+	pending []*Box // Boxes added but not packed yet
+}
+
+// NewStreamPacker creates a StreamPacker packing batches of up to batchSize boxes into
+// the packer's bins with the given options.
+func NewStreamPacker(packer *Packer, options PackerOptions, batchSize int) *StreamPacker {
+	return &StreamPacker{Packer: packer, Options: options, BatchSize: batchSize}
+}
+
+// Add buffers the box and, once BatchSize boxes are waiting, packs them and returns an
+// event for each, in the order they happened: the placements, then the boxes left
+// unpacked. Until then it returns nil. Nil boxes are ignored, and so are boxes already
+// packed, like in Pack.
+func (s *StreamPacker) Add(box *Box) []StreamEvent {
+	if box == nil {
+		return nil
+	}
+	s.pending = append(s.pending, box)
+	if len(s.pending) < s.BatchSize {
+		return nil
+	}
+	return s.Flush()
+}
+
+// Pending returns the number of boxes added but not packed yet.
+func (s *StreamPacker) Pending() int {
+	return len(s.pending)
+}
+
+// Flush packs the buffered boxes, however few, and returns their events as Add does.
+func (s *StreamPacker) Flush() []StreamEvent {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	batch := s.pending
+	s.pending = nil
+	recorder := &streamRecorder{next: s.Options.Observer}
+	options := s.Options
+	options.Observer = recorder
+	s.Packer.Pack(batch, options)
+	return recorder.events
+}
+
+// Run packs the boxes received from the channel in the background and sends an event
+// for each on the returned channel. Boxes are packed as soon as no further box is
+// waiting on the channel, or BatchSize of them have been received, so a slow stream is
+// placed box by box and a burst in batches. When the box channel is closed the rest is
+// packed and the event channel closed. If ctx is cancelled, Run stops and closes the
+// event channel, dropping events not delivered yet; boxes not packed yet stay buffered
+// for Flush. The StreamPacker must not be used otherwise until the event channel is
+// closed.
+func (s *StreamPacker) Run(ctx context.Context, boxes <-chan *Box) <-chan StreamEvent {
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+		send := func(batch []StreamEvent) bool {
+			for _, event := range batch {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case box, ok := <-boxes:
+				if !ok {
+					send(s.Flush())
+					return
+				}
+				batch := s.Add(box)
+				if batch == nil && len(boxes) == 0 {
+					batch = s.Flush() // Nothing else is waiting
+				}
+				if !send(batch) {
+					return
+				}
+			}
+		}
+	}()
+	return events
+}
+
+// streamRecorder collects the events of a batch and passes them on to the caller's
+// observer, if any.
+type streamRecorder struct {
+	next   PackObserver
+	events []StreamEvent
+}
+
+func (r *streamRecorder) OnBinOpened(bin *Bin) {
+	if r.next != nil {
+		r.next.OnBinOpened(bin)
+	}
+}
+
+func (r *streamRecorder) OnBoxPlaced(bin *Bin, box *Box, placement BoxPlacement) {
+	r.events = append(r.events, StreamEvent{Box: box, Placement: placement})
+	if r.next != nil {
+		r.next.OnBoxPlaced(bin, box, placement)
+	}
+}
+
+func (r *streamRecorder) OnBoxRejected(box *Box, reason error) {
+	r.events = append(r.events, StreamEvent{
+		Box:       box,
+		Placement: unpackedPlacement(box),
+		Err:       reason,
+	})
+	if r.next != nil {
+		r.next.OnBoxRejected(box, reason)
+	}
+}
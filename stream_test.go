@@ -0,0 +1,106 @@
+package binpacking
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStreamPacker(t *testing.T) {
+	t.Run("batches", func(t *testing.T) {
+		observer := &recordingObserver{}
+		stream := NewStreamPacker(NewPacker([]*Bin{NewBin(10, 10, nil)}), PackerOptions{Observer: observer}, 3)
+		boxes := []*Box{NewBox(5, 10, false), NewBox(5, 5, false), NewBox(20, 20, false), NewBox(5, 5, false)}
+		for i, box := range boxes {
+			box.ID = string(rune('a' + i))
+		}
+		for _, box := range boxes[:2] {
+			if events := stream.Add(box); events != nil {
+				t.Fatalf("got %d events before the batch is full, want none", len(events))
+			}
+		}
+		if stream.Pending() != 2 {
+			t.Errorf("got %d boxes pending, want 2", stream.Pending())
+		}
+		events := stream.Add(boxes[2])
+		if len(events) != 3 || stream.Pending() != 0 {
+			t.Fatalf("got %d events with %d pending, want 3 and none", len(events), stream.Pending())
+		}
+		for _, event := range events[:2] {
+			if event.Err != nil || !event.Placement.Packed || event.Placement.BinIndex != 0 {
+				t.Errorf("box %s: got %+v, want it placed in bin 0", event.Box.ID, event)
+			}
+		}
+		if last := events[2]; last.Box != boxes[2] || !errors.Is(last.Err, ErrNoFit) || last.Placement.BinIndex != -1 {
+			t.Errorf("got %+v, want the large box rejected with ErrNoFit", last)
+		}
+
+		stream.Add(boxes[3])
+		events = stream.Flush()
+		if len(events) != 1 || events[0].Box != boxes[3] || events[0].Placement.X != 5 || events[0].Placement.Y != 5 {
+			t.Errorf("got %+v, want the last box placed at [5,5]", events)
+		}
+		if stream.Flush() != nil {
+			t.Error("got events from an empty buffer")
+		}
+		if len(observer.placements) != 3 || len(observer.rejected) != 1 {
+			t.Errorf("got %d placements and %d rejections observed, want 3 and 1", len(observer.placements), len(observer.rejected))
+		}
+	})
+
+	t.Run("unbatched", func(t *testing.T) {
+		stream := NewStreamPacker(NewPacker([]*Bin{NewBin(10, 10, nil)}), PackerOptions{}, 0)
+		if events := stream.Add(NewBox(2, 2, false)); len(events) != 1 {
+			t.Errorf("got %d events, want the box packed at once", len(events))
+		}
+		if stream.Add(nil) != nil || stream.Pending() != 0 {
+			t.Error("got a nil box buffered")
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		stream := NewStreamPacker(NewPacker(nil), PackerOptions{BinTemplates: []BinTemplate{{Width: 10, Height: 10}}}, 4)
+		boxes := make(chan *Box, 10)
+		for i := 0; i < 10; i++ {
+			boxes <- NewBox(5, 5, false)
+		}
+		close(boxes)
+		placed := 0
+		for event := range stream.Run(context.Background(), boxes) {
+			if event.Err != nil {
+				t.Errorf("got %v, want every box placed", event.Err)
+			}
+			placed++
+		}
+		if placed != 10 || len(stream.Packer.Bins) != 3 {
+			t.Errorf("got %d boxes placed in %d bins, want 10 in 3", placed, len(stream.Packer.Bins))
+		}
+	})
+
+	t.Run("Run places boxes as they arrive", func(t *testing.T) {
+		stream := NewStreamPacker(NewPacker([]*Bin{NewBin(10, 10, nil)}), PackerOptions{}, 100)
+		boxes := make(chan *Box)
+		events := stream.Run(context.Background(), boxes)
+		for i := 0; i < 3; i++ {
+			box := NewBox(5, 5, false)
+			boxes <- box
+			if event := <-events; event.Box != box || !event.Placement.Packed {
+				t.Fatalf("box %d: got %+v, want it placed before the next one arrives", i, event)
+			}
+		}
+		close(boxes)
+		if _, ok := <-events; ok {
+			t.Error("got an event after the last box")
+		}
+	})
+
+	t.Run("Run stops when cancelled", func(t *testing.T) {
+		stream := NewStreamPacker(NewPacker([]*Bin{NewBin(10, 10, nil)}), PackerOptions{}, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		events := stream.Run(ctx, make(chan *Box))
+		cancel()
+		if _, ok := <-events; ok {
+			t.Error("got an event, want the channel closed")
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrNoBinSize is returned by SuggestBinSize when no bin within the constraints holds every box.
+var ErrNoBinSize = errors.New("binpacking: no bin size fits all boxes")
+
+// SizeConstraints restricts the bin sizes considered by SuggestBinSize.
+type SizeConstraints struct {
+	PowerOfTwo  bool    // Both sides must be powers of two, as required by many GPU texture formats
+	Square      bool    // Width and height must be equal
+	AspectRatio float64 // Required width / height ratio; zero means any ratio
+	MaxWidth    float64 // Largest width to consider; zero means no limit
+	MaxHeight   float64 // Largest height to consider; zero means no limit
+	// Step is the granularity of the sides when PowerOfTwo is not set; zero means 1.
+	Step float64
+	// Placement is the strategy of the trial bins; nil uses BestShortSideFit.
+	Placement PlacementStrategyFunc
+}
+
+// BinSize is a candidate bin size returned by SuggestBinSize.
+type BinSize struct {
+	Width  float64
+	Height float64
+}
+
+// maxSuggestWidths caps the number of widths tried for free-form sizes; the stride
+// between candidates grows in multiples of Step beyond that.
+const maxSuggestWidths = 256
+
+// SuggestBinSize searches bin sizes satisfying the constraints and returns the ones with
+// the smallest area that hold all boxes, ordered by width. Whether the boxes fit is decided
+// by packing copies of them with the default best-fit packer, so the result is the smallest
+// size that packer manages, which may be slightly above the theoretical optimum.
+// The boxes themselves are not modified. ErrNoBinSize is returned if no size fits, and
+// an error wrapping ErrInvalidDimensions if a box has invalid dimensions.
+func SuggestBinSize(boxes []*Box, constraints SizeConstraints) ([]BinSize, error) {
+	valid := make([]*Box, 0, len(boxes))
+	totalArea, minWidth, minHeight, sumSides := 0.0, 0.0, 0.0, 0.0
+	for _, box := range boxes {
+		if box == nil {
+			continue
+		}
+		if err := box.Validate(); err != nil {
+			return nil, err
+		}
+		valid = append(valid, box)
+		totalArea += box.Area()
+		// Each box needs at least its short side in both directions if it may rotate.
+		w, h := box.Width, box.Height
+		if !box.ConstrainRotation {
+			w, h = min(box.Width, box.Height), min(box.Width, box.Height)
+		}
+		minWidth, minHeight = max(minWidth, w), max(minHeight, h)
+		sumSides += max(box.Width, box.Height)
+	}
+	if len(valid) == 0 {
+		return []BinSize{{}}, nil
+	}
+
+	step := constraints.Step
+	if step <= 0 {
+		step = 1
+	}
+	ratio := constraints.AspectRatio
+	if constraints.Square || ratio <= 0 {
+		ratio = 1
+	}
+	// A single row of all boxes always fits, so without limits the search stops at a
+	// bin whose sides are both at least the sum of the boxes' long sides.
+	maxWidth, maxHeight := constraints.MaxWidth, constraints.MaxHeight
+	if maxWidth <= 0 {
+		maxWidth = math.Ceil(sumSides*max(1, ratio)/step) * step
+	}
+	if maxHeight <= 0 {
+		maxHeight = math.Ceil(sumSides*max(1, 1/ratio)/step) * step
+	}
+	if constraints.PowerOfTwo && constraints.MaxWidth <= 0 {
+		maxWidth = nextPowerOfTwo(maxWidth)
+	}
+	if constraints.PowerOfTwo && constraints.MaxHeight <= 0 {
+		maxHeight = nextPowerOfTwo(maxHeight)
+	}
+
+	fits := func(width, height float64) bool {
+		if width*height < totalArea || width > maxWidth || height > maxHeight {
+			return false
+		}
+		copies := make([]*Box, len(valid))
+		for i, box := range valid {
+			copies[i] = NewBox(box.Width, box.Height, box.ConstrainRotation)
+		}
+		packed := NewPacker([]*Bin{NewBin(width, height, constraints.Placement)}).Pack(copies, PackerOptions{})
+		return len(packed) == len(copies)
+	}
+
+	var sizes []BinSize
+	switch {
+	case constraints.PowerOfTwo:
+		sizes = suggestPowerOfTwo(constraints, minWidth, minHeight, maxWidth, maxHeight, fits)
+	case constraints.Square || constraints.AspectRatio > 0:
+		// One degree of freedom: the smallest fitting width, found by binary search.
+		heightOf := func(width float64) float64 { return math.Ceil(width/ratio/step) * step }
+		lo := math.Ceil(max(minWidth, minHeight*ratio, math.Sqrt(totalArea*ratio))/step) * step
+		hi := math.Ceil(max(maxWidth, lo)/step) * step
+		if width, ok := smallestFitting(lo, hi, step, func(width float64) bool { return fits(width, heightOf(width)) }); ok {
+			sizes = []BinSize{{Width: width, Height: heightOf(width)}}
+		}
+	default:
+		sizes = suggestFree(minWidth, minHeight, maxWidth, maxHeight, totalArea, step, fits)
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("%w within %gx%g", ErrNoBinSize, maxWidth, maxHeight)
+	}
+	return sizes, nil
+}
+
+// suggestPowerOfTwo tries every pair of powers of two in order of area and returns all
+// fitting pairs of the smallest fitting area.
+func suggestPowerOfTwo(c SizeConstraints, minWidth, minHeight, maxWidth, maxHeight float64, fits func(w, h float64) bool) []BinSize {
+	candidates := make([]BinSize, 0)
+	for w := nextPowerOfTwo(minWidth); w <= maxWidth; w *= 2 {
+		for h := nextPowerOfTwo(minHeight); h <= maxHeight; h *= 2 {
+			if c.Square && w != h {
+				continue
+			}
+			if c.AspectRatio > 0 && w/h != c.AspectRatio {
+				continue
+			}
+			candidates = append(candidates, BinSize{Width: w, Height: h})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ai, aj := candidates[i].Width*candidates[i].Height, candidates[j].Width*candidates[j].Height
+		if ai != aj {
+			return ai < aj
+		}
+		return candidates[i].Width < candidates[j].Width
+	})
+
+	var result []BinSize
+	for _, size := range candidates {
+		if len(result) > 0 && size.Width*size.Height > result[0].Width*result[0].Height {
+			break
+		}
+		if fits(size.Width, size.Height) {
+			result = append(result, size)
+		}
+	}
+	return result
+}
+
+// suggestFree tries widths on the step grid and, for each, binary searches the smallest
+// fitting height, returning the sizes of smallest area.
+func suggestFree(minWidth, minHeight, maxWidth, maxHeight, totalArea, step float64, fits func(w, h float64) bool) []BinSize {
+	first := math.Ceil(minWidth/step) * step
+	stride := step
+	if count := (maxWidth - first) / step; count > maxSuggestWidths {
+		stride = math.Ceil(count/maxSuggestWidths) * step
+	}
+
+	var result []BinSize
+	bestArea := math.Inf(1)
+	for width := first; width <= maxWidth; width += stride {
+		lo := math.Ceil(max(minHeight, totalArea/width)/step) * step
+		if width*lo > bestArea {
+			continue // Cannot beat the best size even if the lower bound fits
+		}
+		height, ok := smallestFitting(lo, math.Floor(maxHeight/step)*step, step, func(h float64) bool { return fits(width, h) })
+		if !ok {
+			continue
+		}
+		switch area := width * height; {
+		case area < bestArea:
+			bestArea, result = area, []BinSize{{Width: width, Height: height}}
+		case area == bestArea:
+			result = append(result, BinSize{Width: width, Height: height})
+		}
+	}
+	return result
+}
+
+// smallestFitting binary searches the smallest value on the grid lo, lo+step, ..., hi
+// that fits, assuming larger values fit whenever smaller ones do.
+func smallestFitting(lo, hi, step float64, fits func(float64) bool) (float64, bool) {
+	if lo > hi || !fits(hi) {
+		return 0, false
+	}
+	low, high := 0, int(math.Round((hi-lo)/step))
+	for low < high {
+		mid := (low + high) / 2
+		if fits(lo + float64(mid)*step) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return lo + float64(low)*step, true
+}
+
+// nextPowerOfTwo returns the smallest power of two that is at least v (and at least 1).
+func nextPowerOfTwo(v float64) float64 {
+	p := 1.0
+	for p < v {
+		p *= 2
+	}
+	return p
+}
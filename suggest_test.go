@@ -0,0 +1,53 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSuggestBinSize(t *testing.T) {
+	sprites := func(n int) []*Box {
+		boxes := make([]*Box, n)
+		for i := range boxes {
+			boxes[i] = NewBox(32, 32, true)
+		}
+		return boxes
+	}
+
+	tests := []struct {
+		name        string
+		boxes       []*Box
+		constraints SizeConstraints
+		want        string
+	}{
+		{"power of two square", sprites(4), SizeConstraints{PowerOfTwo: true, Square: true}, "[{64 64}]"},
+		{"power of two any shape", sprites(3), SizeConstraints{PowerOfTwo: true}, "[{32 128} {64 64} {128 32}]"},
+		{"free size", []*Box{NewBox(10, 10, false), NewBox(10, 10, false)}, SizeConstraints{}, "[{10 20} {20 10}]"},
+		{"aspect ratio", []*Box{NewBox(10, 10, false), NewBox(10, 10, false)}, SizeConstraints{AspectRatio: 2}, "[{20 10}]"},
+		{"square with step", []*Box{NewBox(15, 7, false)}, SizeConstraints{Square: true, Step: 4}, "[{16 16}]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizes, err := SuggestBinSize(tt.boxes, tt.constraints)
+			if err != nil {
+				t.Fatalf("SuggestBinSize: unexpected error %v", err)
+			}
+			if got := fmt.Sprint(sizes); got != tt.want {
+				t.Errorf("Sizes: got %s, want %s", got, tt.want)
+			}
+			for _, box := range tt.boxes {
+				if box.Packed {
+					t.Errorf("Input box %s must not be packed", box.Label())
+				}
+			}
+		})
+	}
+
+	t.Run("reports when nothing fits", func(t *testing.T) {
+		_, err := SuggestBinSize(sprites(2), SizeConstraints{MaxWidth: 16})
+		if !errors.Is(err, ErrNoBinSize) {
+			t.Errorf("Error: got %v, want %v", err, ErrNoBinSize)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package binpacking
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+)
+
+// SVGOptions controls the drawing written by WriteSVG.
+type SVGOptions struct {
+	// Gap is the space, in bin units, left between bins stacked in the drawing.
+	// Zero uses 5% of the widest bin.
+	Gap float64
+	// Labels prints each box's ID, when set, at its center.
+	Labels bool
+	// Lines are drawn over each bin, by index, e.g. the crop marks of an Imposition.
+	Lines [][]CutMark
+}
+
+// WriteSVG writes the layouts of the bins to w as one SVG drawing in the bins' units,
+// the bins stacked top to bottom. Boxes use the palette of RenderImage, carry their ID
+// in a title element for tooltips, and defects and margins are hatched in grey, so the
+// drawing can be opened in any browser or embedded in reports.
+func WriteSVG(w io.Writer, bins []*Bin, options SVGOptions) error {
+	width, height := 0.0, 0.0
+	for _, bin := range bins {
+		width = max(width, bin.Width)
+	}
+	gap := options.Gap
+	if gap <= 0 {
+		gap = 0.05 * width
+	}
+	for i, bin := range bins {
+		if i > 0 {
+			height += gap
+		}
+		height += bin.Height
+	}
+
+	bw := bufio.NewWriter(w)
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	stroke := number(max(width, height) / 1000)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %s %s" width="%s" height="%s">`+"\n",
+		number(width), number(height), number(width), number(height))
+	fmt.Fprintf(bw, `<defs><pattern id="blocked" width="4" height="4" patternUnits="userSpaceOnUse"><path d="M0,4 L4,0" stroke="#888" stroke-width="1"/></pattern></defs>`+"\n")
+
+	top := 0.0
+	for i, bin := range bins {
+		fmt.Fprintf(bw, `<g id="bin-%d" transform="translate(0,%s)">`+"\n", i+1, number(top))
+		fmt.Fprintf(bw, `<rect width="%s" height="%s" fill="#fff" stroke="#000" stroke-width="%s"/>`+"\n",
+			number(bin.Width), number(bin.Height), stroke)
+		blocked := append(bin.marginStrips(), bin.Defects...)
+		for _, region := range blocked {
+			fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="url(#blocked)"/>`+"\n",
+				number(region.X), number(region.Y), number(region.Width), number(region.Height))
+		}
+		for j, box := range bin.Boxes {
+			c := layoutPalette[j%len(layoutPalette)]
+			element := "rect"
+			if box.Round {
+				element = "circle"
+				cx, cy := box.Center()
+				fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s" fill="#%02x%02x%02x" stroke="#000" stroke-width="%s">`,
+					number(cx), number(cy), number(box.Radius()), c.R, c.G, c.B, stroke)
+			} else {
+				fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="#%02x%02x%02x" stroke="#000" stroke-width="%s">`,
+					number(box.X), number(box.Y), number(box.Width), number(box.Height), c.R, c.G, c.B, stroke)
+			}
+			title := box.Label()
+			if box.ID != "" {
+				title = box.ID + ": " + title
+			}
+			fmt.Fprintf(bw, "<title>%s</title></%s>\n", html.EscapeString(title), element)
+			if options.Labels && box.ID != "" {
+				size := number(min(box.Width, box.Height) / 4)
+				fmt.Fprintf(bw, `<text x="%s" y="%s" font-size="%s" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+					number(box.X+box.Width/2), number(box.Y+box.Height/2), size, html.EscapeString(box.ID))
+			}
+		}
+		if i < len(options.Lines) {
+			for _, line := range options.Lines[i] {
+				fmt.Fprintf(bw, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#000" stroke-width="%s"/>`+"\n",
+					number(line.X1), number(line.Y1), number(line.X2), number(line.Y2), stroke)
+			}
+		}
+		bw.WriteString("</g>\n")
+		top += bin.Height + gap
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
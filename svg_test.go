@@ -0,0 +1,64 @@
+package binpacking
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVG(t *testing.T) {
+	first := NewBin(100, 50, nil)
+	first.Insert(&Box{Width: 40, Height: 20, ID: "a<b", ConstrainRotation: true})
+	first.Insert(&Box{Width: 10, Height: 10})
+	if err := first.AddDefect(90, 40, 10, 10); err != nil {
+		t.Fatal(err)
+	}
+	second := NewBin(60, 60, nil)
+	second.Insert(&Box{Width: 30, Height: 30, ID: "c"})
+
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, []*Bin{first, second}, SVGOptions{Gap: 10, Labels: true}); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+
+	// The output must be well-formed XML with one rectangle per bin, defect and box.
+	rects, texts := 0, 0
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Invalid SVG: %v\n%s", err, buf.String())
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "svg":
+				for _, attr := range start.Attr {
+					if attr.Name.Local == "viewBox" && attr.Value != "0 0 100 120" {
+						t.Errorf("viewBox: got %v, want %v", attr.Value, "0 0 100 120")
+					}
+				}
+			case "rect":
+				rects++
+			case "text":
+				texts++
+			}
+		}
+	}
+	if got, want := rects, 2+1+3; got != want {
+		t.Errorf("Rectangles: got %d, want %d", got, want)
+	}
+	if got, want := texts, 2; got != want {
+		t.Errorf("Labels: got %d, want %d", got, want)
+	}
+	if !strings.Contains(buf.String(), "a&lt;b: 40x20 at [0,0]") {
+		t.Errorf("Box title missing or not escaped:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `transform="translate(0,60)"`) {
+		t.Errorf("Second bin not offset by the first bin and the gap:\n%s", buf.String())
+	}
+}
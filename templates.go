@@ -0,0 +1,104 @@
+package binpacking
+
+import "log/slog"
+
+// BinTemplate describes a kind of stock from which the packer can create bins on demand.
+type BinTemplate struct {
+	Width     float64
+	Height    float64
+	Placement PlacementStrategyFunc // Strategy of the created bins; nil uses BestShortSideFit, ContactPointFit is bound to each bin
+	Cost      float64               // Cost of each created bin
+	MaxWeight float64               // Weight capacity of each created bin; zero means unlimited
+	Spacing   float64               // Spacing of each created bin; see Bin.SetSpacing
+	Tolerance float64               // Tolerance of each created bin; see Bin.Tolerance
+	TieBreak  SpaceTieBreak         // Tie-breaking rule of each created bin; see Bin.TieBreak
+	Name      string                // Stock name of each created bin; see Bin.Name
+	Material  string                // Material of each created bin; see Bin.Material
+	Thickness float64               // Thickness of each created bin; see Bin.Thickness
+}
+
+// NewBin creates an empty bin from the template.
+func (t BinTemplate) NewBin() *Bin {
+	bin := NewBin(t.Width, t.Height, t.Placement)
+	bin.Placement = strategyFor(bin.Placement, bin)
+	bin.Cost = t.Cost
+	bin.MaxWeight = t.MaxWeight
+	bin.Tolerance = t.Tolerance
+	bin.TieBreak = t.TieBreak
+	bin.Name, bin.Material, bin.Thickness = t.Name, t.Material, t.Thickness
+	if t.Spacing > 0 {
+		bin.SetSpacing(t.Spacing) // Cannot fail for an empty MaxRects bin and a positive spacing
+	}
+	return bin
+}
+
+// packFromTemplates opens bins from options.BinTemplates for the boxes not in packed,
+// one at a time and at most up to options.MaxBins bins in total. Each round tries every template on scratch copies and opens the one
+// packing the most area, preferring the lower cost on ties. It returns the boxes packed
+// into the new bins.
+func (p *Packer) packFromTemplates(boxesToPack, packed []*Box, options PackerOptions) []*Box {
+	packedSet := make(map[*Box]struct{}, len(packed))
+	for _, box := range packed {
+		packedSet[box] = struct{}{}
+	}
+	remaining := make([]*Box, 0, len(boxesToPack)-len(packed))
+	for _, box := range boxesToPack {
+		if _, ok := packedSet[box]; !ok {
+			remaining = append(remaining, box)
+		}
+	}
+
+	newlyPacked := make([]*Box, 0)
+	round := options
+	round.BinTemplates = nil
+	for len(remaining) > 0 && !options.expired() {
+		if options.MaxBins > 0 && len(p.Bins) >= options.MaxBins {
+			break // Stock exhausted; the rest is reported as unpacked
+		}
+		if options.Limit > 0 {
+			round.Limit = options.Limit - int64(len(packed)+len(newlyPacked))
+			if round.Limit <= 0 {
+				break
+			}
+		}
+
+		best, bestArea := -1, 0.0
+		for i, template := range options.BinTemplates {
+			scratch := &Packer{Bins: []*Bin{template.NewBin()}}
+			_, copies := trialCopies(nil, remaining)
+			area := 0.0
+			for _, box := range scratch.run(copies, round) {
+				area += box.Area()
+			}
+			if area > bestArea || (best >= 0 && area == bestArea && template.Cost < options.BinTemplates[best].Cost) {
+				best, bestArea = i, area
+			}
+		}
+		if best < 0 {
+			break // No template can hold any remaining box
+		}
+
+		bin := options.BinTemplates[best].NewBin()
+		p.Bins = append(p.Bins, bin)
+		p.events.open(bin, len(p.Bins)-1)
+		if p.debugging() {
+			p.Logger.Debug("opened template bin", slog.Int("bin", p.binOffset+len(p.Bins)-1), slog.Int("template", best), slog.Float64("area", bestArea))
+		}
+		sub := &Packer{Bins: []*Bin{bin}, events: p.events, Logger: p.Logger, binOffset: p.binOffset + len(p.Bins) - 1}
+		roundPacked := sub.run(remaining, round)
+		newlyPacked = append(newlyPacked, roundPacked...)
+
+		done := make(map[*Box]struct{}, len(roundPacked))
+		for _, box := range roundPacked {
+			done[box] = struct{}{}
+		}
+		next := remaining[:0]
+		for _, box := range remaining {
+			if _, ok := done[box]; !ok {
+				next = append(next, box)
+			}
+		}
+		remaining = next
+	}
+	return newlyPacked
+}
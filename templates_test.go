@@ -0,0 +1,69 @@
+package binpacking
+
+import "testing"
+
+func TestBinTemplates(t *testing.T) {
+	t.Run("opens bins until every box is packed", func(t *testing.T) {
+		packer := NewPacker(nil)
+		boxes := make([]*Box, 0)
+		for i := 0; i < 10; i++ {
+			boxes = append(boxes, NewBox(50, 50, false))
+		}
+		packed := packer.Pack(boxes, PackerOptions{BinTemplates: []BinTemplate{{Width: 100, Height: 100, Cost: 4}}})
+		if len(packed) != len(boxes) || len(packer.UnpackedBoxes) != 0 {
+			t.Errorf("Packed boxes: got %d, want %d", len(packed), len(boxes))
+		}
+		if len(packer.Bins) != 3 {
+			t.Errorf("Bins opened: got %d, want %d", len(packer.Bins), 3)
+		}
+		if cost := packer.Result().TotalCost(); cost != 12 {
+			t.Errorf("TotalCost: got %g, want %g", cost, 12.0)
+		}
+	})
+
+	t.Run("fills existing bins first and skips oversized boxes", func(t *testing.T) {
+		existing := NewBin(50, 50, nil)
+		packer := NewPacker([]*Bin{existing})
+		huge := NewBox(500, 500, false)
+		packer.Pack([]*Box{NewBox(50, 50, false), NewBox(40, 40, false), huge}, PackerOptions{
+			BinTemplates: []BinTemplate{{Width: 60, Height: 60, Cost: 2}, {Width: 40, Height: 40, Cost: 1}},
+		})
+		if len(existing.Boxes) != 1 {
+			t.Errorf("Existing bin boxes: got %d, want %d", len(existing.Boxes), 1)
+		}
+		if len(packer.Bins) != 2 || packer.Bins[1].Width != 40 {
+			t.Errorf("Expected one cheaper 40x40 bin to be opened, got %d bins", len(packer.Bins))
+		}
+		if len(packer.UnpackedBoxes) != 1 || packer.UnpackedBoxes[0] != huge {
+			t.Errorf("Unpacked boxes: got %d, want only the oversized box", len(packer.UnpackedBoxes))
+		}
+	})
+
+	t.Run("stops at MaxBins", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(100, 100, nil)})
+		boxes := make([]*Box, 0)
+		for i := 0; i < 10; i++ {
+			boxes = append(boxes, NewBox(50, 50, false))
+		}
+		packed := packer.Pack(boxes, PackerOptions{
+			BinTemplates: []BinTemplate{{Width: 100, Height: 100}},
+			MaxBins:      2,
+		})
+		if len(packer.Bins) != 2 {
+			t.Errorf("Bins: got %d, want %d", len(packer.Bins), 2)
+		}
+		if len(packed) != 8 || len(packer.UnpackedBoxes) != 2 {
+			t.Errorf("Packed/unpacked: got %d/%d, want 8/2", len(packed), len(packer.UnpackedBoxes))
+		}
+	})
+}
+
+func TestBinTemplateContactPoint(t *testing.T) {
+	bin := BinTemplate{Width: 10, Height: 10, Placement: ContactPointFit(nil)}.NewBin()
+	if !bin.Insert(NewBox(4, 4, false)) || !bin.Insert(NewBox(4, 4, false)) {
+		t.Fatal("got no fit")
+	}
+	if err := ValidateLayout(bin); err != nil {
+		t.Error(err)
+	}
+}
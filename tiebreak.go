@@ -0,0 +1,111 @@
+package binpacking
+
+// SpaceTieBreak selects which free space a bin uses when the placement strategy scores
+// several of them the same, e.g. BestShortSideFit for boxes that fill the width of more
+// than one space exactly.
+type SpaceTieBreak int
+
+const (
+	// TieFirstSpace uses the space that comes first in Bin.FreeSpaces, the default.
+	// Which one that is depends on the order in which earlier placements split the free
+	// area, so it is deterministic but arbitrary.
+	TieFirstSpace SpaceTieBreak = iota
+	// TieLowestPosition uses the space with the lowest Y, then the lowest X, which keeps
+	// equally good placements together along the Y=0 edge.
+	TieLowestPosition
+	// TieSmallestSpace uses the space with the smallest area (the narrowest one if
+	// these are equal), leaving the larger spaces for larger boxes.
+	TieSmallestSpace
+)
+
+// prefers reports whether the rule picks space over best, the space of the best
+// placement so far. Equal spaces are not preferred, so the earlier one stays.
+func (t SpaceTieBreak) prefers(space, best *FreeSpaceBox) bool {
+	switch t {
+	case TieLowestPosition:
+		if space.Y != best.Y {
+			return space.Y < best.Y
+		}
+		return space.X < best.X
+	case TieSmallestSpace:
+		if area, bestArea := space.Width*space.Height, best.Width*best.Height; area != bestArea {
+			return area < bestArea
+		}
+		return space.Width < best.Width
+	}
+	return false
+}
+
+// wins reports whether a placement with the given score in space beats best, breaking
+// ties between equal scores by the rule.
+func (t SpaceTieBreak) wins(score Score, space *FreeSpaceBox, best *PlacementInfo) bool {
+	if score.Less(best.Score) {
+		return true
+	}
+	return t != TieFirstSpace && best.Fits && !best.Score.Less(score) && t.prefers(space, best.ChosenSpace)
+}
+
+// BinTieBreak selects which bin Pack places the next box into when the box fits
+// several bins equally well.
+type BinTieBreak int
+
+const (
+	// TieFirstBin uses the bin that comes first in Packer.Bins, the default.
+	TieFirstBin BinTieBreak = iota
+	// TieFullestBin uses the bin with the highest Efficiency, consolidating boxes into
+	// few bins.
+	TieFullestBin
+	// TieEmptiestBin uses the bin with the lowest Efficiency, spreading boxes over the bins.
+	TieEmptiestBin
+)
+
+// prefer returns the ScoreBoard.Prefer function implementing the rule, or nil for
+// TieFirstBin.
+func (t BinTieBreak) prefer() func(entry, best *ScoreBoardEntry) bool {
+	if t != TieFullestBin && t != TieEmptiestBin {
+		return nil
+	}
+	fill := binFill{}
+	return func(entry, best *ScoreBoardEntry) bool {
+		if entry.Bin == best.Bin {
+			return false
+		}
+		if t == TieFullestBin {
+			return fill.of(entry.Bin) > fill.of(best.Bin)
+		}
+		return fill.of(entry.Bin) < fill.of(best.Bin)
+	}
+}
+
+// binFill caches the Efficiency of bins, which takes a pass over their boxes, for as
+// long as they hold the same number of boxes. Pack only ever adds boxes while it uses it.
+type binFill map[*Bin]struct {
+	boxes      int
+	efficiency float64
+}
+
+// of returns the Efficiency of the bin.
+func (f binFill) of(bin *Bin) float64 {
+	cached, ok := f[bin]
+	if !ok || cached.boxes != len(bin.Boxes) {
+		cached.boxes, cached.efficiency = len(bin.Boxes), bin.Efficiency()
+		f[bin] = cached
+	}
+	return cached.efficiency
+}
+
+// applyTieBreak sets the rule as the TieBreak of the packer's bins and returns copies of
+// the templates using it.
+func (p *Packer) applyTieBreak(rule SpaceTieBreak, templates []BinTemplate) []BinTemplate {
+	for _, bin := range p.Bins {
+		if bin != nil {
+			bin.TieBreak = rule
+		}
+	}
+	chosen := make([]BinTemplate, len(templates))
+	for i, template := range templates {
+		template.TieBreak = rule
+		chosen[i] = template
+	}
+	return chosen
+}
@@ -0,0 +1,102 @@
+package binpacking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// flatScore scores every placement the same, so that only the tie-breaking rules decide.
+func flatScore(*FreeSpaceBox, float64, float64) Score {
+	return NewScore(0)
+}
+
+func TestTieBreak(t *testing.T) {
+	t.Run("spaces", func(t *testing.T) {
+		tests := []struct {
+			rule  SpaceTieBreak
+			wantX float64
+			wantY float64
+		}{
+			{TieFirstSpace, 10, 10},
+			{TieLowestPosition, 5, 0},
+			{TieSmallestSpace, 0, 12},
+		}
+		for _, tt := range tests {
+			bin := NewBin(20, 20, flatScore)
+			bin.TieBreak = tt.rule
+			bin.FreeSpaces = []*FreeSpaceBox{
+				{X: 10, Y: 10, Width: 10, Height: 10},
+				{X: 0, Y: 12, Width: 4, Height: 8},
+				{X: 5, Y: 0, Width: 6, Height: 6},
+			}
+			box := NewBox(4, 2, true)
+			if !bin.Insert(box) {
+				t.Fatalf("rule %d: got no fit", tt.rule)
+			}
+			if box.X != tt.wantX || box.Y != tt.wantY {
+				t.Errorf("rule %d: got (%v, %v), want (%v, %v)", tt.rule, box.X, box.Y, tt.wantX, tt.wantY)
+			}
+		}
+	})
+
+	t.Run("bins", func(t *testing.T) {
+		tests := []struct {
+			rule BinTieBreak
+			want int
+		}{
+			{TieFirstBin, 0},
+			{TieFullestBin, 1},
+			{TieEmptiestBin, 2},
+		}
+		for _, tt := range tests {
+			bins := []*Bin{NewBin(10, 10, flatScore), NewBin(10, 10, flatScore), NewBin(10, 10, flatScore)}
+			bins[0].Insert(NewBox(2, 2, false))
+			bins[1].Insert(NewBox(5, 5, false))
+			packer := NewPacker(bins)
+			box := NewBox(1, 1, false)
+			packer.Pack([]*Box{box}, PackerOptions{BinTieBreak: tt.rule})
+			if got := packer.Result().Placements[0].BinIndex; got != tt.want {
+				t.Errorf("rule %d: got bin %d, want %d", tt.rule, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		packer := NewPacker([]*Bin{bin})
+		boxes := []*Box{NewBox(10, 10, false), NewBox(3, 3, false)}
+		packer.Pack(boxes, NewPackerOptions(
+			WithTieBreaks(TieLowestPosition, TieFullestBin),
+			WithBinTemplates(0, BinTemplate{Width: 10, Height: 10}),
+		))
+		if len(packer.Bins) != 2 {
+			t.Fatalf("got %d bins, want 2", len(packer.Bins))
+		}
+		for i, bin := range packer.Bins {
+			if bin.TieBreak != TieLowestPosition {
+				t.Errorf("bin %d: got rule %d, want %d", i, bin.TieBreak, TieLowestPosition)
+			}
+		}
+		if err := packer.Result().Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		bin, err := NewBinWith(10, 10, WithTieBreak(TieSmallestSpace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored Bin
+		if err := json.Unmarshal(data, &restored); err != nil {
+			t.Fatal(err)
+		}
+		if restored.TieBreak != TieSmallestSpace {
+			t.Errorf("got rule %d, want %d", restored.TieBreak, TieSmallestSpace)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package binpacking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeLimit(t *testing.T) {
+	manyBoxes := func() []*Box {
+		boxes := make([]*Box, 0, 400)
+		for i := 0; i < 400; i++ {
+			boxes = append(boxes, &Box{Width: float64(1 + i%7), Height: float64(1 + i%5)})
+		}
+		return boxes
+	}
+
+	t.Run("no limit reports elapsed time", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(100, 100, nil)})
+		packer.Pack(manyBoxes(), PackerOptions{})
+		result := packer.Result()
+		if result.Elapsed <= 0 {
+			t.Errorf("got elapsed %v, want > 0", result.Elapsed)
+		}
+		if result.TimedOut {
+			t.Errorf("got timed out, want not")
+		}
+	})
+
+	t.Run("generous limit does not change the result", func(t *testing.T) {
+		free := NewPacker([]*Bin{NewBin(60, 60, nil)})
+		wantPacked := len(free.Pack(manyBoxes(), PackerOptions{Restarts: 3}))
+		limited := NewPacker([]*Bin{NewBin(60, 60, nil)})
+		packed := limited.Pack(manyBoxes(), PackerOptions{Restarts: 3, TimeLimit: time.Minute})
+		if len(packed) != wantPacked {
+			t.Errorf("got %d packed, want %d", len(packed), wantPacked)
+		}
+		if limited.Result().TimedOut {
+			t.Errorf("got timed out, want not")
+		}
+	})
+
+	t.Run("expired limit returns a valid partial result", func(t *testing.T) {
+		bins := []*Bin{NewBin(60, 60, nil), NewBin(60, 60, nil)}
+		packer := NewPacker(bins)
+		boxes := manyBoxes()
+		packed := packer.Pack(boxes, PackerOptions{Restarts: 50, TimeLimit: time.Nanosecond})
+		result := packer.Result()
+		if !result.TimedOut {
+			t.Fatalf("got not timed out, want timed out")
+		}
+		if len(packed)+len(packer.UnpackedBoxes) != len(boxes) {
+			t.Errorf("got %d packed and %d unpacked, want %d boxes", len(packed), len(packer.UnpackedBoxes), len(boxes))
+		}
+		placed := 0
+		for _, bin := range bins {
+			placed += len(bin.Boxes)
+			for i, a := range bin.Boxes {
+				for _, b := range bin.Boxes[i+1:] {
+					if overlaps(a, b) {
+						t.Errorf("got overlapping boxes %v and %v", a, b)
+					}
+				}
+			}
+		}
+		if placed != len(packed) {
+			t.Errorf("got %d boxes in bins, want %d", placed, len(packed))
+		}
+	})
+
+	t.Run("expired limit stops opening template bins", func(t *testing.T) {
+		packer := NewPacker(nil)
+		packed := packer.Pack(manyBoxes(), PackerOptions{
+			TimeLimit:    time.Nanosecond,
+			BinTemplates: []BinTemplate{{Width: 20, Height: 20}},
+		})
+		if len(packed) != 0 || len(packer.Bins) != 0 {
+			t.Errorf("got %d packed in %d bins, want none", len(packed), len(packer.Bins))
+		}
+		if !packer.Result().TimedOut {
+			t.Errorf("got not timed out, want timed out")
+		}
+	})
+}
+
+// overlaps reports whether two packed boxes share any area.
+func overlaps(a, b *Box) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
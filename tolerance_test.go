@@ -0,0 +1,83 @@
+package binpacking
+
+import "testing"
+
+func TestTolerance(t *testing.T) {
+	a, b := 0.1, 0.2
+	width := a + b // 0.30000000000000004, a rounding error above 0.3
+
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(0.3, 1, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(0.3, 1, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(0.3, 1, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(0.3, 1) },
+	}
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			if newBin().Insert(NewBox(width, 0.5, true)) {
+				t.Fatal("got a fit without tolerance, want none")
+			}
+			bin := newBin()
+			bin.Tolerance = 1e-9
+			for i := 0; i < 2; i++ {
+				if !bin.Insert(NewBox(width, 0.5, true)) {
+					t.Fatalf("box %d: got no fit, want one within the tolerance", i)
+				}
+			}
+			if bin.Insert(NewBox(0.1, 0.1, true)) {
+				t.Error("got a box into a full bin")
+			}
+			for _, space := range bin.FreeSpaces {
+				if space.Width <= bin.Tolerance || space.Height <= bin.Tolerance {
+					t.Errorf("got a sliver free space %+v", *space)
+				}
+			}
+			if err := ValidateLayout(bin); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("shelf", func(t *testing.T) {
+		bin := NewBin(0.3, 1, nil)
+		bin.Tolerance = 1e-9
+		packer := NewPacker([]*Bin{bin})
+		packed := packer.Pack([]*Box{NewBox(width, 0.5, true), NewBox(width, 0.5, true)}, PackerOptions{Algorithm: AlgorithmShelfFirstFit})
+		if len(packed) != 2 {
+			t.Errorf("got %d boxes packed, want 2", len(packed))
+		}
+	})
+
+	t.Run("Place", func(t *testing.T) {
+		bin := NewBin(1, 1, nil)
+		bin.Tolerance = 1e-9
+		if err := bin.Place(NewBox(width, 1, true), 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		// Overlaps the first box by a rounding error and ends at 1.0000000000000000x.
+		if err := bin.Place(NewBox(0.7, 1, true), 0.3, 0); err != nil {
+			t.Errorf("got %v, want the box placed within the tolerance", err)
+		}
+		if len(bin.FreeSpaces) != 0 {
+			t.Errorf("got free spaces %v, want none", bin.FreeSpaces)
+		}
+	})
+
+	t.Run("pruning", func(t *testing.T) {
+		bin := NewBin(1, 1, nil)
+		bin.Tolerance = 1e-9
+		bin.FreeSpaces = []*FreeSpaceBox{{X: 0, Y: 0, Width: 0.5, Height: 1}, {X: 1e-12, Y: 0, Width: 0.5, Height: 1}, {X: 0.5, Y: 0, Width: 0.5, Height: 1}}
+		bin.pruneFreeList()
+		if len(bin.FreeSpaces) != 2 || bin.FreeSpaces[0].X != 0 || bin.FreeSpaces[1].X != 0.5 {
+			t.Errorf("got %d free spaces, want the first of the two within the tolerance and the third", len(bin.FreeSpaces))
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		bin := NewBin(0.3, 1, nil)
+		bin.Tolerance = -1
+		if bin.Insert(NewBox(width, 0.5, true)) {
+			t.Error("got a fit with a negative tolerance, want it treated as zero")
+		}
+	})
+}
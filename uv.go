@@ -0,0 +1,27 @@
+package binpacking
+
+// UVRect is a rectangle in normalized texture coordinates. U grows to the right and V
+// downwards from the top-left corner of the bin, both from 0 to 1, matching image and
+// bin coordinates; use FlipV for engines whose V axis points up.
+type UVRect struct {
+	U0, V0 float64 // Top-left corner
+	U1, V1 float64 // Bottom-right corner
+}
+
+// NewUVRect normalizes the rectangle at (x, y) of the given size by the bin size.
+// A bin with a zero side yields the zero UVRect.
+func NewUVRect(x, y, width, height, binWidth, binHeight float64) UVRect {
+	if binWidth <= 0 || binHeight <= 0 {
+		return UVRect{}
+	}
+	return UVRect{
+		U0: x / binWidth, V0: y / binHeight,
+		U1: (x + width) / binWidth, V1: (y + height) / binHeight,
+	}
+}
+
+// FlipV returns the rectangle with V measured upwards from the bottom edge, as used by
+// OpenGL-style texture coordinates. V0 stays the smaller coordinate.
+func (r UVRect) FlipV() UVRect {
+	return UVRect{U0: r.U0, V0: 1 - r.V1, U1: r.U1, V1: 1 - r.V0}
+}
@@ -0,0 +1,36 @@
+package binpacking
+
+import "testing"
+
+func TestUVRect(t *testing.T) {
+	t.Run("normalizes placements", func(t *testing.T) {
+		bin := NewBin(200, 100, nil)
+		box := NewBox(50, 25, true)
+		packer := NewPacker([]*Bin{bin})
+		packer.Pack([]*Box{NewBox(100, 100, true), box}, PackerOptions{})
+		var got UVRect
+		for _, placement := range packer.Result().Placements {
+			if placement.Box == box {
+				got = placement.UV
+			}
+		}
+		want := NewUVRect(box.X, box.Y, 50, 25, 200, 100)
+		if got != want || got.U1-got.U0 != 0.25 || got.V1-got.V0 != 0.25 {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("flips V", func(t *testing.T) {
+		got := UVRect{U0: 0.25, V0: 0, U1: 0.5, V1: 0.25}.FlipV()
+		want := UVRect{U0: 0.25, V0: 0.75, U1: 0.5, V1: 1}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("is zero for empty bins", func(t *testing.T) {
+		if got := NewUVRect(0, 0, 1, 1, 0, 10); got != (UVRect{}) {
+			t.Errorf("got %+v, want the zero UVRect", got)
+		}
+	})
+}
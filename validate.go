@@ -0,0 +1,257 @@
+package binpacking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidLayout is returned by ValidateLayout and PackResult.Validate when boxes
+// overlap, leave their bin or break one of its constraints, or a result does not match
+// its bins.
+var ErrInvalidLayout = errors.New("binpacking: invalid layout")
+
+// ValidateLayout checks the boxes in the bin: every box is packed with valid dimensions
+// inside the bin and its margins, overlaps no other box and no defect, keeps the bin's
+// Spacing from other boxes and from the edges, margins and defects, goes with the bin's
+// Material, and the boxes' total weight is within MaxWeight. It is meant for tests and debugging; set
+// Bin.CheckLayout to run the checks after every placement instead. The returned error
+// wraps ErrInvalidLayout, or ErrInvalidDimensions for a bin or box of invalid size,
+// and describes the first problem found.
+func ValidateLayout(bin *Bin) error {
+	if err := bin.Validate(); err != nil {
+		return err
+	}
+	weight := 0.0
+	for _, box := range bin.Boxes {
+		if err := bin.validatePlaced(box); err != nil {
+			return err
+		}
+		weight += box.Weight
+	}
+	if err := bin.validateWeight(weight); err != nil {
+		return err
+	}
+
+	// Sweep the boxes from left to right, comparing each with those starting before
+	// its padded right edge.
+	boxes := slices.Clone(bin.Boxes)
+	slices.SortFunc(boxes, func(a, b *Box) int {
+		if a.X < b.X {
+			return -1
+		} else if a.X > b.X {
+			return 1
+		}
+		return 0
+	})
+	tolerance := bin.layoutTolerance()
+	for i, box := range boxes {
+		right := box.X + box.Width + max(bin.Spacing, 0)
+		for _, other := range boxes[i+1:] {
+			if other.X >= right-tolerance {
+				break
+			}
+			if err := bin.validatePair(box, other); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// layoutTolerance is the distance by which boxes may overlap or leave the bin in
+// ValidateLayout, forgiving rounding errors of the positions: the bin's Tolerance, or
+// a billionth of its size if that is larger.
+func (b *Bin) layoutTolerance() float64 {
+	return max(b.tolerance(), 1e-9*max(b.Width, b.Height, 1))
+}
+
+// validatePlaced checks a box on its own: its state, its size and its position relative
+// to the edges, margins and defects of the bin.
+func (b *Bin) validatePlaced(box *Box) error {
+	if box == nil {
+		return fmt.Errorf("%w: nil box in the %gx%g bin", ErrInvalidLayout, b.Width, b.Height)
+	}
+	if err := box.Validate(); err != nil {
+		return fmt.Errorf("box %s: %w", box.Label(), err)
+	}
+	if !box.Packed {
+		return fmt.Errorf("%w: box %s is in the bin but not marked packed", ErrInvalidLayout, box.Label())
+	}
+	if !b.fitsMaterial(box) {
+		return fmt.Errorf("%w: box %s is in a bin of material %q", ErrInvalidLayout, box.Label(), b.Material)
+	}
+	if math.IsNaN(box.X) || math.IsNaN(box.Y) || math.IsInf(box.X, 0) || math.IsInf(box.Y, 0) {
+		return fmt.Errorf("%w: box %s is at [%g,%g]", ErrInvalidLayout, box.Label(), box.X, box.Y)
+	}
+
+	// The usable area is inside the margins, less the spacing along every side; boxes
+	// are padded on their right and bottom, so the left and top sides are checked here.
+	spacing, tolerance := max(b.Spacing, 0), b.layoutTolerance()
+	m := b.Margins
+	if box.X < m.Left+spacing-tolerance || box.Y < m.Top+spacing-tolerance ||
+		box.X+box.Width+spacing > b.Width-m.Right+tolerance || box.Y+box.Height+spacing > b.Height-m.Bottom+tolerance {
+		return fmt.Errorf("%w: box %s %gx%g at [%g,%g] is outside the usable area of the %gx%g bin",
+			ErrInvalidLayout, box.Label(), box.Width, box.Height, box.X, box.Y, b.Width, b.Height)
+	}
+	for _, defect := range b.Defects {
+		if b.overlaps(box, defectBox(defect)) {
+			return fmt.Errorf("%w: box %s %gx%g at [%g,%g] overlaps or comes too close to the defect %gx%g at [%g,%g]",
+				ErrInvalidLayout, box.Label(), box.Width, box.Height, box.X, box.Y, defect.Width, defect.Height, defect.X, defect.Y)
+		}
+	}
+	return nil
+}
+
+// validatePair checks that two boxes of the bin neither overlap nor come closer than
+// the spacing.
+func (b *Bin) validatePair(box, other *Box) error {
+	if box == other {
+		return fmt.Errorf("%w: box %s is in the bin twice", ErrInvalidLayout, box.Label())
+	}
+	if b.overlaps(box, other) {
+		return fmt.Errorf("%w: box %s %gx%g at [%g,%g] overlaps or comes too close to box %s %gx%g at [%g,%g]",
+			ErrInvalidLayout, box.Label(), box.Width, box.Height, box.X, box.Y,
+			other.Label(), other.Width, other.Height, other.X, other.Y)
+	}
+	return nil
+}
+
+// validateWeight checks the total weight of the bin's boxes.
+func (b *Bin) validateWeight(weight float64) error {
+	if b.MaxWeight > 0 && weight > b.MaxWeight*(1+1e-9) {
+		return fmt.Errorf("%w: boxes weigh %g, more than the bin's capacity of %g", ErrInvalidLayout, weight, b.MaxWeight)
+	}
+	return nil
+}
+
+// overlaps reports whether the two boxes, padded by the spacing, overlap by more than the
+// layout tolerance. Round boxes are compared as circles.
+func (b *Bin) overlaps(box, other *Box) bool {
+	spacing, tolerance := max(b.Spacing, 0), b.layoutTolerance()
+	if !(box.X < other.X+other.Width+spacing-tolerance && other.X < box.X+box.Width+spacing-tolerance &&
+		box.Y < other.Y+other.Height+spacing-tolerance && other.Y < box.Y+box.Height+spacing-tolerance) {
+		return false
+	}
+	return !box.Round && !other.Round || shapeGap(box, other) < spacing-tolerance
+}
+
+// checkPlaced validates the box just placed against the rest of the bin if CheckLayout
+// is set, and panics if the layout is broken.
+func (b *Bin) checkPlaced(box *Box) {
+	if !b.CheckLayout {
+		return
+	}
+	err := b.validatePlaced(box)
+	weight := 0.0
+	for _, other := range b.Boxes {
+		weight += other.Weight
+		if err == nil && other != box {
+			err = b.validatePair(box, other)
+		}
+	}
+	if err == nil {
+		err = b.validateWeight(weight)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Validate checks the result against its bins: every bin passes ValidateLayout, no box
+// is in two bins, the packed boxes are in the bins their placements name, at the recorded
+// positions, the unpacked boxes are in no bin, and BinsUsed, PackedArea and Efficiency
+// match the bins. A placement whose box is in none of the bins, as with the copies made
+// by Packer.Plan, must match the position and size of a box of the bin it names that no
+// other placement matches. Call it before modifying the bins or boxes. The returned error wraps
+// ErrInvalidLayout or ErrInvalidDimensions and describes the first problem found.
+func (r *PackResult) Validate() error {
+	binOf := make(map[*Box]int)
+	binsUsed := 0
+	binArea, boxArea := 0.0, 0.0
+	for i, bin := range r.Bins {
+		if bin == nil {
+			continue
+		}
+		if err := ValidateLayout(bin); err != nil {
+			return fmt.Errorf("bin %d: %w", i, err)
+		}
+		for _, box := range bin.Boxes {
+			if other, ok := binOf[box]; ok {
+				return fmt.Errorf("%w: box %s is in bins %d and %d", ErrInvalidLayout, box.Label(), other, i)
+			}
+			binOf[box] = i
+			boxArea += box.Area()
+		}
+		if len(bin.Boxes) > 0 {
+			binsUsed++
+			binArea += bin.Area()
+		}
+	}
+
+	matched := make(map[*Box]bool) // Boxes of the bins matched by a placement of a copy
+	for _, placement := range r.Placements {
+		box := placement.Box
+		if !placement.Packed || box == nil {
+			continue
+		}
+		if _, ok := binOf[box]; !ok {
+			if err := r.matchPlacement(placement, matched); err != nil {
+				return err
+			}
+			continue
+		}
+		if index := binOf[box]; index != placement.BinIndex {
+			return fmt.Errorf("%w: box %s is not in bin %d", ErrInvalidLayout, box.Label(), placement.BinIndex)
+		}
+		if placement.X != box.X || placement.Y != box.Y || placement.Width != box.Width || placement.Height != box.Height {
+			return fmt.Errorf("%w: box %s is %gx%g at [%g,%g], its placement %gx%g at [%g,%g]", ErrInvalidLayout, box.Label(),
+				box.Width, box.Height, box.X, box.Y, placement.Width, placement.Height, placement.X, placement.Y)
+		}
+	}
+	for _, box := range r.Unpacked {
+		if index, ok := binOf[box]; ok {
+			return fmt.Errorf("%w: unpacked box %s is in bin %d", ErrInvalidLayout, box.Label(), index)
+		}
+	}
+	packedArea := 0.0
+	for _, box := range r.Packed {
+		packedArea += box.Area()
+	}
+
+	if r.BinsUsed != binsUsed {
+		return fmt.Errorf("%w: BinsUsed is %d, %d bins hold boxes", ErrInvalidLayout, r.BinsUsed, binsUsed)
+	}
+	if !closeTo(r.PackedArea, packedArea) {
+		return fmt.Errorf("%w: PackedArea is %g, the packed boxes cover %g", ErrInvalidLayout, r.PackedArea, packedArea)
+	}
+	efficiency := 0.0
+	if binArea > 0 {
+		efficiency = boxArea / binArea * 100
+	}
+	if !closeTo(r.Efficiency, efficiency) {
+		return fmt.Errorf("%w: Efficiency is %g%%, the boxes cover %g%% of the bins in use", ErrInvalidLayout, r.Efficiency, efficiency)
+	}
+	return nil
+}
+
+// matchPlacement checks a placement whose box is in none of the bins against the boxes
+// of the bin it names, marking the box it matches in matched.
+func (r *PackResult) matchPlacement(placement BoxPlacement, matched map[*Box]bool) error {
+	if placement.BinIndex >= 0 && placement.BinIndex < len(r.Bins) && r.Bins[placement.BinIndex] != nil {
+		for _, box := range r.Bins[placement.BinIndex].Boxes {
+			if !matched[box] && box.X == placement.X && box.Y == placement.Y && box.Width == placement.Width && box.Height == placement.Height {
+				matched[box] = true
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: no box %gx%g at [%g,%g] in bin %d for placement %s", ErrInvalidLayout,
+		placement.Width, placement.Height, placement.X, placement.Y, placement.BinIndex, placement.Box.Label())
+}
+
+// closeTo reports whether two sums agree up to rounding errors.
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(math.Abs(a), math.Abs(b), 1)
+}
@@ -0,0 +1,169 @@
+package binpacking
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestValidateLayout(t *testing.T) {
+	backends := map[string]func() *Bin{
+		"maxrects":   func() *Bin { return NewBin(100, 80, nil) },
+		"guillotine": func() *Bin { return NewGuillotineBin(100, 80, BestAreaFit, SplitShorterLeftoverAxis) },
+		"skyline":    func() *Bin { return NewSkylineBin(100, 80, SkylineOptions{WasteMap: true}) },
+		"blf":        func() *Bin { return NewBottomLeftFillBin(100, 80) },
+		"contact":    func() *Bin { return NewContactPointBin(100, 80) },
+	}
+	for name, newBin := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(5, 5))
+			var bins []*Bin
+			for i := 0; i < 3; i++ {
+				bin := newBin()
+				bin.CheckLayout = true
+				bin.MaxWeight = 40
+				if err := bin.SetMargins(Margins{Top: 2, Left: 3}); err != nil {
+					t.Fatal(err)
+				}
+				if err := bin.SetSpacing(1); err != nil {
+					t.Fatal(err)
+				}
+				if err := bin.AddDefect(40, 30, 10, 10); err != nil {
+					t.Fatal(err)
+				}
+				bins = append(bins, bin)
+			}
+			var boxes []*Box
+			for i := 0; i < 60; i++ {
+				box := NewBox(float64(2+rng.IntN(20)), float64(2+rng.IntN(20)), false)
+				box.Weight = float64(rng.IntN(3))
+				boxes = append(boxes, box)
+			}
+			packer := NewPacker(bins)
+			packer.Pack(boxes, PackerOptions{})
+			for i, bin := range packer.Bins {
+				if err := ValidateLayout(bin); err != nil {
+					t.Errorf("bin %d: %v", i, err)
+				}
+			}
+			if err := packer.Result().Validate(); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+		})
+	}
+
+	tests := []struct {
+		name   string
+		breaks func(bin *Bin)
+	}{
+		{"overlap", func(bin *Bin) { bin.Boxes[1].X, bin.Boxes[1].Y = bin.Boxes[0].X+1, bin.Boxes[0].Y+1 }},
+		{"outside the bin", func(bin *Bin) { bin.Boxes[0].X = bin.Width - 1 }},
+		{"inside the margins", func(bin *Bin) { bin.Boxes[0].X = 0 }},
+		{"within the spacing", func(bin *Bin) { bin.Boxes[1].X, bin.Boxes[1].Y = bin.Boxes[0].X+bin.Boxes[0].Width+0.5, bin.Boxes[0].Y }},
+		{"over a defect", func(bin *Bin) { bin.Boxes[0].X, bin.Boxes[0].Y = 45, 35 }},
+		{"over the weight capacity", func(bin *Bin) { bin.Boxes[0].Weight = 100 }},
+		{"not packed", func(bin *Bin) { bin.Boxes[0].Packed = false }},
+		{"twice", func(bin *Bin) { bin.Boxes = append(bin.Boxes, bin.Boxes[0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := NewBin(100, 80, nil)
+			bin.MaxWeight = 50
+			if err := bin.SetMargins(Margins{Left: 5}); err != nil {
+				t.Fatal(err)
+			}
+			if err := bin.SetSpacing(1); err != nil {
+				t.Fatal(err)
+			}
+			if err := bin.AddDefect(40, 30, 10, 10); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 4; i++ {
+				if !bin.Insert(NewBox(10, 10, false)) {
+					t.Fatalf("box %d does not fit", i)
+				}
+			}
+			if err := ValidateLayout(bin); err != nil {
+				t.Fatalf("before breaking the layout: %v", err)
+			}
+			tt.breaks(bin)
+			if err := ValidateLayout(bin); !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("got %v, want ErrInvalidLayout", err)
+			}
+		})
+	}
+
+	t.Run("touching boxes", func(t *testing.T) {
+		a, b := 0.1, 0.2
+		bin := NewBin(10, 10, nil)
+		bin.Boxes = []*Box{
+			{Width: a + b, Height: 10, Packed: true}, // 0.30000000000000004 wide
+			{X: 0.3, Width: 9.7, Height: 10, Packed: true},
+		}
+		if err := ValidateLayout(bin); err != nil {
+			t.Errorf("got %v, want no error", err)
+		}
+	})
+
+	t.Run("CheckLayout panics", func(t *testing.T) {
+		bin := NewBin(10, 10, nil)
+		bin.CheckLayout = true
+		bin.Insert(NewBox(5, 5, false))
+		bin.Boxes[0].X, bin.Boxes[0].Y = 2, 2 // Moved over the free area the next box takes
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("got panic %v, want ErrInvalidLayout", err)
+			}
+		}()
+		bin.Insert(NewBox(5, 5, false))
+		t.Error("got no panic")
+	})
+}
+
+func TestPackResultValidate(t *testing.T) {
+	pack := func() *Packer {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		packer.Pack([]*Box{NewBox(6, 6, false), NewBox(6, 6, false), NewBox(20, 20, false)}, PackerOptions{})
+		return packer
+	}
+	if err := pack().Result().Validate(); err != nil {
+		t.Fatalf("got %v, want no error", err)
+	}
+
+	tests := []struct {
+		name   string
+		breaks func(p *Packer, r *PackResult)
+	}{
+		{"efficiency", func(p *Packer, r *PackResult) { r.Efficiency = 50 }},
+		{"bins used", func(p *Packer, r *PackResult) { r.BinsUsed = 1 }},
+		{"packed area", func(p *Packer, r *PackResult) { r.PackedArea = 1 }},
+		{"moved box", func(p *Packer, r *PackResult) { r.Placements[0].X = 3 }},
+		{"wrong bin", func(p *Packer, r *PackResult) { r.Placements[0].BinIndex = 1 - r.Placements[0].BinIndex }},
+		{"box in two bins", func(p *Packer, r *PackResult) { p.Bins[1].Boxes = append(p.Bins[1].Boxes, p.Bins[0].Boxes[0]) }},
+		{"unpacked box in a bin", func(p *Packer, r *PackResult) { r.Unpacked = append(r.Unpacked, p.Bins[0].Boxes[0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packer := pack()
+			result := packer.Result()
+			tt.breaks(packer, result)
+			if err := result.Validate(); !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("got %v, want ErrInvalidLayout", err)
+			}
+		})
+	}
+
+	t.Run("Plan", func(t *testing.T) {
+		packer := NewPacker([]*Bin{NewBin(10, 10, nil), NewBin(10, 10, nil)})
+		boxes := []*Box{NewBox(6, 6, false), NewBox(6, 6, false), NewBox(4, 8, false), NewBox(20, 20, false)}
+		plan := packer.Plan(boxes, PackerOptions{})
+		if err := plan.Validate(); err != nil {
+			t.Errorf("got %v, want no error", err)
+		}
+		plan.Placements[0].X += 1
+		if err := plan.Validate(); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("got %v for a moved placement, want ErrInvalidLayout", err)
+		}
+	})
+}
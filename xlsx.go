@@ -0,0 +1,238 @@
+package binpacking
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/png"
+	"io"
+	"strconv"
+)
+
+// XLSXOptions controls the content of the workbook written by WriteXLSX.
+type XLSXOptions struct {
+	// ImageSize is the longest side, in pixels, of the layout image embedded in
+	// each bin sheet. Zero uses 600; negative disables the images.
+	ImageSize int
+}
+
+// xlsxCell is a single worksheet cell value: a string, a number, or empty.
+type xlsxCell struct {
+	text     string
+	number   float64
+	isText   bool
+	isNumber bool
+}
+
+func textCell(s string) xlsxCell    { return xlsxCell{text: s, isText: true} }
+func numberCell(f float64) xlsxCell { return xlsxCell{number: f, isNumber: true} }
+func boolCell(v bool) xlsxCell      { return textCell(strconv.FormatBool(v)) }
+
+// xlsxSheetName returns the sheet name of the bin at index.
+func xlsxSheetName(index int) string {
+	return fmt.Sprintf("Bin %d", index+1)
+}
+
+// xlsxColumn returns the column letter for a zero-based column index.
+// Tables written by this package never exceed 26 columns.
+func xlsxColumn(index int) string {
+	return string(rune('A' + index))
+}
+
+// WriteXLSX writes a cutting-list workbook for the given bins to w.
+// The first sheet summarizes every bin (name, material, size, box count, efficiency,
+// cost), totalling the cost of the bins that hold boxes, and each bin then gets its own
+// sheet listing the placed boxes and their IDs with an embedded layout image.
+// The workbook is written with the standard library only and opens in Excel,
+// LibreOffice and Google Sheets.
+func WriteXLSX(w io.Writer, bins []*Bin, options XLSXOptions) error {
+	imageSize := options.ImageSize
+	if imageSize == 0 {
+		imageSize = 600
+	}
+	withImages := imageSize > 0
+
+	zw := zip.NewWriter(w)
+	write := func(name, content string) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	// Summary sheet first, followed by one sheet per bin.
+	summary := [][]xlsxCell{{
+		textCell("Bin"), textCell("Name"), textCell("Material"), textCell("Width"), textCell("Height"),
+		textCell("Boxes"), textCell("Efficiency %"), textCell("Cost"),
+	}}
+	totalCost := 0.0
+	for i, bin := range bins {
+		summary = append(summary, []xlsxCell{
+			textCell(xlsxSheetName(i)), textCell(bin.Name), textCell(bin.Material), numberCell(bin.Width), numberCell(bin.Height),
+			numberCell(float64(len(bin.Boxes))), numberCell(bin.Efficiency()), numberCell(bin.Cost),
+		})
+		if len(bin.Boxes) > 0 {
+			totalCost += bin.Cost // Unused stock costs nothing, as in PackResult.TotalCost
+		}
+	}
+	summary = append(summary, []xlsxCell{textCell("Total"), {}, {}, {}, {}, {}, {}, numberCell(totalCost)})
+	if err := write("xl/worksheets/sheet1.xml", xlsxSheet(summary, false)); err != nil {
+		return err
+	}
+
+	for i, bin := range bins {
+		rows := [][]xlsxCell{{textCell("#"), textCell("ID"), textCell("Width"), textCell("Height"), textCell("X"), textCell("Y"), textCell("Rotated")}}
+		for j, box := range bin.Boxes {
+			rows = append(rows, []xlsxCell{
+				numberCell(float64(j + 1)), textCell(box.ID), numberCell(box.Width), numberCell(box.Height),
+				numberCell(box.X), numberCell(box.Y), boolCell(box.Rotated),
+			})
+		}
+		if err := write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+2), xlsxSheet(rows, withImages)); err != nil {
+			return err
+		}
+		if !withImages {
+			continue
+		}
+
+		img := bin.RenderImage(imageSize)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		n := i + 1
+		if err := write(fmt.Sprintf("xl/media/image%d.png", n), buf.String()); err != nil {
+			return err
+		}
+		if err := write(fmt.Sprintf("xl/worksheets/_rels/sheet%d.xml.rels", n+1), xlsxRels(
+			"http://schemas.openxmlformats.org/officeDocument/2006/relationships/drawing",
+			fmt.Sprintf("../drawings/drawing%d.xml", n))); err != nil {
+			return err
+		}
+		if err := write(fmt.Sprintf("xl/drawings/_rels/drawing%d.xml.rels", n), xlsxRels(
+			"http://schemas.openxmlformats.org/officeDocument/2006/relationships/image",
+			fmt.Sprintf("../media/image%d.png", n))); err != nil {
+			return err
+		}
+		bounds := img.Bounds()
+		if err := write(fmt.Sprintf("xl/drawings/drawing%d.xml", n), xlsxDrawing(bounds.Dx(), bounds.Dy())); err != nil {
+			return err
+		}
+	}
+
+	if err := write("[Content_Types].xml", xlsxContentTypes(len(bins), withImages)); err != nil {
+		return err
+	}
+	if err := write("_rels/.rels", xlsxRels(
+		"http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument",
+		"xl/workbook.xml")); err != nil {
+		return err
+	}
+	if err := write("xl/workbook.xml", xlsxWorkbook(len(bins))); err != nil {
+		return err
+	}
+	if err := write("xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(bins))); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// xlsxEscape escapes text for inclusion in XML content or attributes.
+func xlsxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// xlsxSheet renders rows as worksheet XML, optionally referencing the sheet's drawing.
+func xlsxSheet(rows [][]xlsxCell, withDrawing bool) string {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&buf, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumn(c), r+1)
+			switch {
+			case cell.isText:
+				fmt.Fprintf(&buf, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xlsxEscape(cell.text))
+			case cell.isNumber:
+				fmt.Fprintf(&buf, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(cell.number, 'g', -1, 64))
+			}
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData>`)
+	if withDrawing {
+		buf.WriteString(`<drawing r:id="rId1"/>`)
+	}
+	buf.WriteString(`</worksheet>`)
+	return buf.String()
+}
+
+// xlsxDrawing anchors the sheet's layout image to the right of the parts table.
+func xlsxDrawing(widthPx, heightPx int) string {
+	const emuPerPixel = 9525
+	return xml.Header + fmt.Sprintf(`<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<xdr:oneCellAnchor><xdr:from><xdr:col>7</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>1</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from>`+
+		`<xdr:ext cx="%d" cy="%d"/>`+
+		`<xdr:pic><xdr:nvPicPr><xdr:cNvPr id="1" name="Layout"/><xdr:cNvPicPr/></xdr:nvPicPr>`+
+		`<xdr:blipFill><a:blip r:embed="rId1"/><a:stretch><a:fillRect/></a:stretch></xdr:blipFill>`+
+		`<xdr:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></xdr:spPr></xdr:pic>`+
+		`<xdr:clientData/></xdr:oneCellAnchor></xdr:wsDr>`,
+		widthPx*emuPerPixel, heightPx*emuPerPixel, widthPx*emuPerPixel, heightPx*emuPerPixel)
+}
+
+// xlsxRels renders a relationships part with a single relationship rId1.
+func xlsxRels(relType, target string) string {
+	return xml.Header + fmt.Sprintf(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="%s" Target="%s"/></Relationships>`, relType, target)
+}
+
+// xlsxWorkbook lists the summary sheet followed by one sheet per bin.
+func xlsxWorkbook(binCount int) string {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	buf.WriteString(`<sheet name="Summary" sheetId="1" r:id="rId1"/>`)
+	for i := 0; i < binCount; i++ {
+		fmt.Fprintf(&buf, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxSheetName(i), i+2, i+2)
+	}
+	buf.WriteString(`</sheets></workbook>`)
+	return buf.String()
+}
+
+// xlsxWorkbookRels links the workbook to its sheets.
+func xlsxWorkbookRels(binCount int) string {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 0; i <= binCount; i++ {
+		fmt.Fprintf(&buf, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	buf.WriteString(`</Relationships>`)
+	return buf.String()
+}
+
+// xlsxContentTypes declares the content type of every part in the package.
+func xlsxContentTypes(binCount int, withImages bool) string {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	buf.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	buf.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	buf.WriteString(`<Default Extension="png" ContentType="image/png"/>`)
+	buf.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := 0; i <= binCount; i++ {
+		fmt.Fprintf(&buf, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	if withImages {
+		for i := 0; i < binCount; i++ {
+			fmt.Fprintf(&buf, `<Override PartName="/xl/drawings/drawing%d.xml" ContentType="application/vnd.openxmlformats-officedocument.drawing+xml"/>`, i+1)
+		}
+	}
+	buf.WriteString(`</Types>`)
+	return buf.String()
+}
@@ -0,0 +1,69 @@
+package binpacking
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	bin := NewBin(100, 50, nil)
+	bin.Cost, bin.Name, bin.Material = 12.5, "SHEET-7", "birch"
+	spare := NewBin(10, 10, nil) // Too small for either box
+	spare.Cost = 7
+	box := NewBox(40, 40, false)
+	box.ID = "SKU-1"
+	packer := NewPacker([]*Bin{bin, spare})
+	packer.Pack([]*Box{box, NewBox(15, 10, false)}, PackerOptions{})
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, packer.Bins, XLSXOptions{}); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Reading workbook: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Opening %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{
+		"[Content_Types].xml", "xl/workbook.xml",
+		"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml", "xl/worksheets/sheet3.xml",
+		"xl/media/image1.png", "xl/drawings/drawing1.xml",
+	} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Workbook part %q missing", name)
+		}
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], "<v>12.5</v>") {
+		t.Errorf("Summary sheet does not contain the bin cost")
+	}
+	if got, want := strings.Count(parts["xl/worksheets/sheet1.xml"], "<v>12.5</v>"), 2; got != want {
+		t.Errorf("Summary cost cells of 12.5: got %d, want %d (the bin and the total without the empty bin)", got, want)
+	}
+	for _, text := range []string{"<t>Name</t>", "<t>SHEET-7</t>", "<t>Material</t>", "<t>birch</t>"} {
+		if !strings.Contains(parts["xl/worksheets/sheet1.xml"], text) {
+			t.Errorf("Summary sheet does not contain %s", text)
+		}
+	}
+	for _, text := range []string{"<t>ID</t>", "<t>SKU-1</t>"} {
+		if !strings.Contains(parts["xl/worksheets/sheet2.xml"], text) {
+			t.Errorf("Bin sheet does not contain %s", text)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Bin 2"`) {
+		t.Errorf("Workbook does not list a sheet per bin")
+	}
+}